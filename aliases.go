@@ -0,0 +1,117 @@
+package tsunami
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// ErrUnknownName is returned when playing a track by a name not registered.
+var ErrUnknownName = errors.New("unknown track name")
+
+// Aliases maps symbolic names, like "door_creak" or "act1_music", to track
+// numbers, so the application doesn't scatter track numbers around.
+type Aliases map[string]int
+
+// LoadAliases reads aliases as a JSON object, eg.:
+//
+//	{"door_creak": 12, "act1_music": 101}
+func LoadAliases(r io.Reader) (Aliases, error) {
+	var a Aliases
+	if err := json.NewDecoder(r).Decode(&a); err != nil {
+		return nil, err
+	}
+
+	if err := a.validate(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (a Aliases) validate() error {
+	for name, trk := range a {
+		if trk <= 0 || trk > MaxTracks {
+			return fmt.Errorf("alias %q: invalid track %d", name, trk)
+		}
+	}
+
+	return nil
+}
+
+// Track returns the track of the name, or an error wrapping ErrUnknownName.
+func (a Aliases) Track(name string) (int, error) {
+	trk, ok := a[name]
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnknownName, name)
+	}
+
+	return trk, nil
+}
+
+// Name returns the name of the track, if any. With many names for the track
+// the first one in alphabetical order is returned.
+func (a Aliases) Name(trk int) (string, bool) {
+	var names []string
+	for name, t := range a {
+		if t == trk {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "", false
+	}
+
+	sort.Strings(names)
+	return names[0], true
+}
+
+// SetAliases sets the names of the tracks, used by PlayNamed, TrackName and
+// SubscribeNamed. The names are overridden by the ones of the locale, see
+// SetLocale.
+func (t *Tsunami) SetAliases(a Aliases) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.aliases = a
+}
+
+// PlayNamed plays the track with the given name on the output, with
+// TrackPlayPoly. The errors returned mention the name.
+func (t *Tsunami) PlayNamed(name string, out int) error {
+	trk, err := t.names().Track(name)
+	if err != nil {
+		return err
+	}
+
+	if err := t.TrackPlayPoly(trk, out, false); err != nil {
+		return fmt.Errorf("playing %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// TrackName returns the name of the track, see SetAliases, or its number if
+// it has none, for logs and messages.
+func (t *Tsunami) TrackName(trk int) string {
+	if name, ok := t.names().Name(trk); ok {
+		return name
+	}
+
+	return fmt.Sprint(trk)
+}
+
+// SubscribeNamed is like Subscribe for the track reports, with the name of
+// the track, or its number if it has none.
+func (t *Tsunami) SubscribeNamed(fn func(name string, r *protocol.TrackReport)) (cancel func()) {
+	return t.Subscribe(func(msg protocol.Message) {
+		if r, ok := msg.(*protocol.TrackReport); ok {
+			fn(t.TrackName(int(r.Track)), r)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package tsunami
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestLoadAliases(t *testing.T) {
+	a, err := LoadAliases(strings.NewReader(`{"door_creak": 12, "creak": 12, "act1_music": 101}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if trk, err := a.Track("act1_music"); err != nil || trk != 101 {
+		t.Errorf("unexpected track %d, %v", trk, err)
+	}
+
+	if name, ok := a.Name(12); !ok || name != "creak" {
+		t.Errorf("unexpected name %q", name)
+	}
+
+	if _, err := a.Track("thunder"); !errors.Is(err, ErrUnknownName) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, err := LoadAliases(strings.NewReader(`{"door_creak": 0}`)); err == nil {
+		t.Error("invalid track should fail")
+	}
+}
+
+func TestTsunamiPlayNamed(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	ts.SetAliases(Aliases{"door_creak": 12})
+
+	if err := ts.PlayNamed("door_creak", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.PlayNamed("thunder", 0); !errors.Is(err, ErrUnknownName) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	expected := []control{{TRK_PLAY_POLY, 12}}
+	if c := controls(t, port); !reflect.DeepEqual(c, expected) {
+		t.Errorf("unexpected controls %v", c)
+	}
+
+	var names []string
+	ts.SubscribeNamed(func(name string, r *protocol.TrackReport) {
+		names = append(names, name)
+	})
+
+	endTrack(t, ts, port, 12)
+	endTrack(t, ts, port, 13)
+	if expected := []string{"door_creak", "13"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("unexpected names %v", names)
+	}
+}
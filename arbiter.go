@@ -0,0 +1,298 @@
+package tsunami
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// ErrRefused is returned by Arbiter.Play when a track can't be played
+// without exceeding the limits of the arbiter.
+var ErrRefused = errors.New("refused, no voice available")
+
+// Policy is what an Arbiter does with a track requested when its limits are
+// reached.
+type Policy uint8
+
+const (
+	// Steal stops the playing track with the lowest priority, if lower
+	// than the one of the track requested, otherwise the track is refused.
+	Steal Policy = iota
+	// Queue delays the track until a voice is free, the queued tracks
+	// are played by priority.
+	Queue
+	// Drop refuses the track.
+	Drop
+)
+
+var policyNames = map[Policy]string{
+	Steal: "steal",
+	Queue: "queue",
+	Drop:  "drop",
+}
+
+func (p Policy) String() string {
+	if name, ok := policyNames[p]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Policy(%d)", uint8(p))
+}
+
+// ArbiterConfig is the configuration of an Arbiter.
+type ArbiterConfig struct {
+	// Voices is the number of tracks the arbiter plays at the same time,
+	// MAX_NUM_VOICES if zero.
+	Voices int
+	// OutputVoices is the number of tracks the arbiter plays at the same
+	// time on an output, unlimited if zero. One makes every output play a
+	// single track, as usual for announcements.
+	OutputVoices int
+	Policy       Policy
+	// FadeOut is the duration of the fade of the tracks stolen, they are
+	// stopped at once if zero.
+	FadeOut time.Duration
+}
+
+// Arbiter plays tracks by priority. The voice stealing of the board is
+// oblivious to the importance of the tracks, while the arbiter keeps its
+// own limits of voices, deciding by priority what to do when they are
+// reached, see Policy. Only the tracks played through the arbiter are
+// accounted, and they are known to end from the track reports, see
+// Subscribe.
+type Arbiter struct {
+	b      Board
+	cfg    ArbiterConfig
+	fader  *Fader
+	cancel func()
+
+	mu      sync.Mutex
+	playing map[int]arbitrated
+	queue   []arbitrated
+	seq     int
+}
+
+type arbitrated struct {
+	track, output, priority int
+	// seq orders the tracks by request, the oldest is stolen, or played,
+	// first among those of the same priority.
+	seq int
+}
+
+// NewArbiter returns an Arbiter for the given board, subscribed to its
+// messages.
+func NewArbiter(b Board, cfg ArbiterConfig) *Arbiter {
+	if cfg.Voices <= 0 {
+		cfg.Voices = MAX_NUM_VOICES
+	}
+
+	a := &Arbiter{
+		b:       b,
+		cfg:     cfg,
+		fader:   NewFader(b),
+		playing: make(map[int]arbitrated),
+	}
+
+	a.cancel = b.Subscribe(a.handle)
+	return a
+}
+
+// Close unsubscribes the Arbiter from the board, the queued tracks are never
+// played. The tracks being stolen are still faded out and stopped.
+func (a *Arbiter) Close() error {
+	a.cancel()
+	return nil
+}
+
+// Play plays the track on the output with the given priority, the higher the
+// more important. When the limits of the arbiter are reached, the policy
+// decides whether the track is refused with ErrRefused, queued or played
+// stopping another one.
+func (a *Arbiter) Play(trk, out, priority int) error {
+	a.mu.Lock()
+	a.seq++
+	req := arbitrated{track: trk, output: out, priority: priority, seq: a.seq}
+
+	var victim *arbitrated
+	if !a.fits(req) {
+		switch a.cfg.Policy {
+		case Steal:
+			victim = a.victim(req)
+			if victim == nil {
+				a.mu.Unlock()
+				return ErrRefused
+			}
+
+			delete(a.playing, victim.track)
+		case Queue:
+			a.queue = append(a.queue, req)
+			a.mu.Unlock()
+			return nil
+		default:
+			a.mu.Unlock()
+			return ErrRefused
+		}
+	}
+
+	a.playing[trk] = req
+	a.mu.Unlock()
+
+	if victim != nil {
+		a.stop(victim.track)
+	}
+
+	return a.play(req)
+}
+
+// Stop stops the track, or removes it from the queue if it's waiting.
+func (a *Arbiter) Stop(trk int) error {
+	a.mu.Lock()
+	queue := a.queue[:0]
+	for _, req := range a.queue {
+		if req.track != trk {
+			queue = append(queue, req)
+		}
+	}
+
+	a.queue = queue
+	_, playing := a.playing[trk]
+	a.mu.Unlock()
+
+	if !playing {
+		return nil
+	}
+
+	return a.b.TrackStop(trk)
+}
+
+// Playing returns the tracks played through the arbiter, still playing.
+func (a *Arbiter) Playing() []int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return sortedKeys(a.playing)
+}
+
+// Queued returns the number of tracks waiting for a voice.
+func (a *Arbiter) Queued() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return len(a.queue)
+}
+
+// fits reports whether the track can be played within the limits.
+func (a *Arbiter) fits(req arbitrated) bool {
+	if _, ok := a.playing[req.track]; ok {
+		// playing it again takes the same voice.
+		return true
+	}
+
+	if len(a.playing) >= a.cfg.Voices {
+		return false
+	}
+
+	return a.cfg.OutputVoices == 0 || a.onOutput(req.output) < a.cfg.OutputVoices
+}
+
+func (a *Arbiter) onOutput(out int) int {
+	var n int
+	for _, p := range a.playing {
+		if p.output == out {
+			n++
+		}
+	}
+
+	return n
+}
+
+// victim returns the oldest track with the lowest priority, lower than the
+// one requested, whose stop makes room for it.
+func (a *Arbiter) victim(req arbitrated) *arbitrated {
+	outputFull := a.cfg.OutputVoices != 0 && a.onOutput(req.output) >= a.cfg.OutputVoices
+
+	var victim *arbitrated
+	for _, p := range a.playing {
+		if p.priority >= req.priority || (outputFull && p.output != req.output) {
+			continue
+		}
+
+		if victim == nil || p.priority < victim.priority ||
+			(p.priority == victim.priority && p.seq < victim.seq) {
+			p := p
+			victim = &p
+		}
+	}
+
+	return victim
+}
+
+func (a *Arbiter) play(req arbitrated) error {
+	if err := a.b.TrackPlayPoly(req.track, req.output, false); err != nil {
+		a.mu.Lock()
+		if a.playing[req.track].seq == req.seq {
+			delete(a.playing, req.track)
+		}
+		a.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+func (a *Arbiter) stop(trk int) error {
+	if a.cfg.FadeOut == 0 {
+		return a.b.TrackStop(trk)
+	}
+
+	var from Gain
+	if g, ok := a.b.(interface{ TrackGainOf(int) Gain }); ok {
+		from = g.TrackGainOf(trk)
+	}
+
+	a.fader.Track(trk, Fade{From: from, To: MinGain, Duration: a.cfg.FadeOut, Stop: true})
+	return nil
+}
+
+func (a *Arbiter) handle(msg protocol.Message) {
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok || r.Playing {
+		return
+	}
+
+	a.mu.Lock()
+	if _, ok := a.playing[int(r.Track)]; !ok {
+		a.mu.Unlock()
+		return
+	}
+
+	delete(a.playing, int(r.Track))
+
+	var next []arbitrated
+	sort.SliceStable(a.queue, func(i, j int) bool {
+		return a.queue[i].priority > a.queue[j].priority
+	})
+
+	queue := a.queue[:0]
+	for _, req := range a.queue {
+		if a.fits(req) {
+			a.playing[req.track] = req
+			next = append(next, req)
+			continue
+		}
+
+		queue = append(queue, req)
+	}
+
+	a.queue = queue
+	a.mu.Unlock()
+
+	for _, req := range next {
+		a.play(req)
+	}
+}
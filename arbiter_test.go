@@ -0,0 +1,108 @@
+package tsunami
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+type control struct {
+	code  protocol.TrackCode
+	track int
+}
+
+// controls returns the track controls sent since the last call.
+func controls(t *testing.T, port *transport.Loopback) []control {
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c []control
+	for _, m := range msgs {
+		if m, ok := m.(*protocol.TrackControl); ok {
+			c = append(c, control{m.Code, int(m.Track)})
+		}
+	}
+
+	return c
+}
+
+func endTrack(t *testing.T, ts *Tsunami, port *transport.Loopback, trk int) {
+	port.Push(&protocol.TrackReport{Track: uint16(trk), Playing: false})
+	if err := ts.Update(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArbiterSteal(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	a := NewArbiter(ts, ArbiterConfig{Voices: 2, Policy: Steal})
+
+	for _, trk := range []int{1, 2} {
+		if err := a.Play(trk, 0, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := a.Play(3, 0, 1); err != ErrRefused {
+		t.Errorf("same priority should be refused, got %v", err)
+	}
+
+	if err := a.Play(4, 0, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []control{
+		{TRK_PLAY_POLY, 1}, {TRK_PLAY_POLY, 2},
+		{TRK_STOP, 1}, {TRK_PLAY_POLY, 4},
+	}
+
+	if c := controls(t, port); !reflect.DeepEqual(c, expected) {
+		t.Errorf("unexpected controls %v", c)
+	}
+
+	if p := a.Playing(); !reflect.DeepEqual(p, []int{2, 4}) {
+		t.Errorf("unexpected playing %v", p)
+	}
+}
+
+func TestArbiterQueue(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	a := NewArbiter(ts, ArbiterConfig{OutputVoices: 1, Policy: Queue})
+
+	a.Play(1, 0, 1)
+	a.Play(2, 1, 1)
+	a.Play(3, 0, 1)
+	a.Play(4, 0, 9)
+	if a.Queued() != 2 {
+		t.Fatalf("unexpected queued %d", a.Queued())
+	}
+
+	controls(t, port)
+	endTrack(t, ts, port, 1)
+
+	if c := controls(t, port); !reflect.DeepEqual(c, []control{{TRK_PLAY_POLY, 4}}) {
+		t.Errorf("the highest priority should be played first, got %v", c)
+	}
+
+	a.Stop(3)
+	endTrack(t, ts, port, 4)
+	if c := controls(t, port); len(c) != 0 || a.Queued() != 0 {
+		t.Errorf("stopped track should leave the queue, got %v", c)
+	}
+}
+
+func TestArbiterDrop(t *testing.T) {
+	ts := NewTsunamiTransport(transport.NewLoopback(nil))
+	a := NewArbiter(ts, ArbiterConfig{Voices: 1, Policy: Drop})
+
+	a.Play(1, 0, 1)
+	if err := a.Play(2, 0, 9); err != ErrRefused {
+		t.Errorf("unexpected error %v", err)
+	}
+}
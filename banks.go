@@ -0,0 +1,140 @@
+package tsunami
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrInvalidBank is returned for a bank, or a trigger within a bank, out of
+// range.
+var ErrInvalidBank = errors.New("invalid bank")
+
+const (
+	// MinBank and MaxBank are the range of the trigger and MIDI banks.
+	MinBank = 1
+	MaxBank = 32
+)
+
+// BankKind is the kind of the banks of a BankManager.
+type BankKind uint8
+
+const (
+	// TriggerBanks offset the tracks of the trigger inputs by 16 per bank.
+	TriggerBanks BankKind = iota
+	// MidiBanks offset the tracks of the MIDI notes by 128 per bank.
+	MidiBanks
+)
+
+var bankKindNames = map[BankKind]string{
+	TriggerBanks: "trigger",
+	MidiBanks:    "midi",
+}
+
+func (k BankKind) String() string {
+	if name, ok := bankKindNames[k]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("BankKind(%d)", uint8(k))
+}
+
+// Size returns the number of tracks of a bank.
+func (k BankKind) Size() int {
+	if k == MidiBanks {
+		return 128
+	}
+
+	return 16
+}
+
+// BankManager keeps the current trigger or MIDI bank of the board, so
+// bank-local triggers can be translated to the absolute tracks used by the
+// rest of the API.
+type BankManager struct {
+	t    *Tsunami
+	kind BankKind
+
+	mu   sync.Mutex
+	bank int
+}
+
+// NewBankManager returns a manager of the given kind of banks of the board.
+// The current bank is the last one set on the board, bank 1 if none.
+func NewBankManager(t *Tsunami, kind BankKind) *BankManager {
+	t.mu.Lock()
+	bank := t.triggerBank
+	if kind == MidiBanks {
+		bank = t.midiBank
+	}
+	t.mu.Unlock()
+
+	if bank < MinBank || bank > MaxBank {
+		bank = MinBank
+	}
+
+	return &BankManager{t: t, kind: kind, bank: bank}
+}
+
+// Bank returns the current bank.
+func (m *BankManager) Bank() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.bank
+}
+
+// SetBank sets the current bank, from MinBank to MaxBank.
+func (m *BankManager) SetBank(bank int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.set(bank)
+}
+
+// NextBank moves to the next bank, wrapping around to the first one.
+func (m *BankManager) NextBank() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.set(m.bank%MaxBank + 1)
+}
+
+// PrevBank moves to the previous bank, wrapping around to the last one.
+func (m *BankManager) PrevBank() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.set((m.bank+MaxBank-2)%MaxBank + 1)
+}
+
+func (m *BankManager) set(bank int) error {
+	if bank < MinBank || bank > MaxBank {
+		return fmt.Errorf("%w: %s bank %d", ErrInvalidBank, m.kind, bank)
+	}
+
+	var err error
+	if m.kind == MidiBanks {
+		err = m.t.SetMidiBank(bank)
+	} else {
+		err = m.t.SetTriggerBank(bank)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	m.bank = bank
+	return nil
+}
+
+// Track returns the absolute track of the trigger, or MIDI note, n of the
+// current bank, starting at 1.
+func (m *BankManager) Track(n int) (int, error) {
+	size := m.kind.Size()
+	if n < 1 || n > size {
+		return 0, fmt.Errorf("%w: %s %d out of the bank", ErrInvalidBank, m.kind, n)
+	}
+
+	return (m.Bank()-1)*size + n, nil
+}
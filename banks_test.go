@@ -0,0 +1,71 @@
+package tsunami
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestBankManager(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	m := NewBankManager(ts, TriggerBanks)
+
+	if m.Bank() != 1 {
+		t.Errorf("unexpected bank %d", m.Bank())
+	}
+
+	if err := m.PrevBank(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.NextBank(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.NextBank(); err != nil {
+		t.Fatal(err)
+	}
+
+	if trk, err := m.Track(3); err != nil || trk != 19 {
+		t.Errorf("unexpected track %d, %v", trk, err)
+	}
+
+	if _, err := m.Track(17); !errors.Is(err, ErrInvalidBank) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := m.SetBank(33); !errors.Is(err, ErrInvalidBank) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []protocol.Message{
+		&protocol.SetTriggerBank{Bank: 32},
+		&protocol.SetTriggerBank{Bank: 1},
+		&protocol.SetTriggerBank{Bank: 2},
+	}
+
+	if !reflect.DeepEqual(msgs, expected) {
+		t.Errorf("unexpected messages %v", msgs)
+	}
+}
+
+func TestBankManagerMidi(t *testing.T) {
+	ts := NewTsunamiTransport(transport.NewLoopback(nil))
+	if err := ts.SetMidiBank(3); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewBankManager(ts, MidiBanks)
+	if trk, err := m.Track(1); err != nil || trk != 257 {
+		t.Errorf("unexpected track %d, %v", trk, err)
+	}
+}
@@ -0,0 +1,166 @@
+package tsunami
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ErrUnknownBus is returned when referring to a bus not defined.
+var ErrUnknownBus = errors.New("unknown bus")
+
+// Bus is a named output of the board, like "FOH", "sub" or "lobby", so the
+// rest of the program doesn't depend on how the outputs are wired.
+type Bus struct {
+	Name string `json:"name"`
+	// Output is the output of the bus, a stereo pair from 0 to 3 with the
+	// stereo firmware, or a mono output from 0 to 7 with the mono one.
+	Output int `json:"output"`
+	// Stereo makes the bus a pair of mono outputs, Output and Output+1,
+	// with the mono firmware. The tracks are played on both of them.
+	Stereo bool `json:"stereo,omitempty"`
+	// Gain and Pitch, in semitones, are the defaults of the outputs, set
+	// by Buses.Apply.
+	Gain  Gain    `json:"gain,omitempty"`
+	Pitch float64 `json:"pitch,omitempty"`
+}
+
+// Outputs returns the outputs of the bus.
+func (b Bus) Outputs() []int {
+	if b.Stereo {
+		return []int{b.Output, b.Output + 1}
+	}
+
+	return []int{b.Output}
+}
+
+// Buses is a set of buses by name.
+type Buses struct {
+	buses map[string]Bus
+}
+
+// NewBuses returns the set of the given buses, validating their names and
+// outputs.
+func NewBuses(buses ...Bus) (*Buses, error) {
+	b := &Buses{buses: make(map[string]Bus, len(buses))}
+	for _, bus := range buses {
+		if bus.Name == "" {
+			return nil, errors.New("bus without name")
+		}
+
+		if _, ok := b.buses[bus.Name]; ok {
+			return nil, fmt.Errorf("duplicated bus %q", bus.Name)
+		}
+
+		outs := bus.Outputs()
+		if outs[0] < 0 || outs[len(outs)-1] >= MaxOutputs {
+			return nil, fmt.Errorf("bus %q: invalid output %d", bus.Name, bus.Output)
+		}
+
+		b.buses[bus.Name] = bus
+	}
+
+	return b, nil
+}
+
+// LoadBuses reads the buses as a JSON array, eg.:
+//
+//	[
+//	  {"name": "FOH", "output": 0, "stereo": true},
+//	  {"name": "sub", "output": 2, "gain": -6},
+//	  {"name": "lobby", "output": 3, "gain": -12}
+//	]
+func LoadBuses(r io.Reader) (*Buses, error) {
+	var buses []Bus
+	if err := json.NewDecoder(r).Decode(&buses); err != nil {
+		return nil, err
+	}
+
+	return NewBuses(buses...)
+}
+
+// Bus returns the bus with the given name.
+func (b *Buses) Bus(name string) (Bus, error) {
+	bus, ok := b.buses[name]
+	if !ok {
+		return Bus{}, fmt.Errorf("%w %q", ErrUnknownBus, name)
+	}
+
+	return bus, nil
+}
+
+// Output returns the first output of the bus, for the APIs taking a single
+// output.
+func (b *Buses) Output(name string) (int, error) {
+	bus, err := b.Bus(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return bus.Output, nil
+}
+
+// Names returns the names of the buses, sorted.
+func (b *Buses) Names() []string {
+	names := make([]string, 0, len(b.buses))
+	for name := range b.buses {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Apply sets the default gain and pitch of the outputs of every bus.
+func (b *Buses) Apply(p Player) error {
+	for _, name := range b.Names() {
+		bus := b.buses[name]
+		for _, out := range bus.Outputs() {
+			if err := p.MasterGain(out, bus.Gain); err != nil {
+				return fmt.Errorf("bus %q: %w", name, err)
+			}
+
+			if err := p.SamplerateOffset(out, SemitonesToOffset(bus.Pitch)); err != nil {
+				return fmt.Errorf("bus %q: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Play plays the track on the outputs of the bus, with TrackPlayPoly.
+func (b *Buses) Play(p Player, trk int, name string, lock bool) error {
+	bus, err := b.Bus(name)
+	if err != nil {
+		return err
+	}
+
+	for _, out := range bus.Outputs() {
+		if err := p.TrackPlayPoly(trk, out, lock); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Gains returns the default gains of the outputs of the given buses, as
+// expected by DuckConfig.Outputs.
+func (b *Buses) Gains(names ...string) (map[int]Gain, error) {
+	gains := make(map[int]Gain)
+	for _, name := range names {
+		bus, err := b.Bus(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, out := range bus.Outputs() {
+			gains[out] = bus.Gain
+		}
+	}
+
+	return gains, nil
+}
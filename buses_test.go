@@ -0,0 +1,87 @@
+package tsunami
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+const buses = `[
+  {"name": "FOH", "output": 0, "stereo": true},
+  {"name": "lobby", "output": 3, "gain": -12, "pitch": 1}
+]`
+
+func TestLoadBuses(t *testing.T) {
+	b, err := LoadBuses(strings.NewReader(buses))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := b.Names(); !reflect.DeepEqual(names, []string{"FOH", "lobby"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+
+	if out, err := b.Output("lobby"); err != nil || out != 3 {
+		t.Errorf("unexpected output %d, %v", out, err)
+	}
+
+	if _, err := b.Bus("sub"); !errors.Is(err, ErrUnknownBus) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	gains, err := b.Gains("FOH", "lobby")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := map[int]Gain{0: 0, 1: 0, 3: -12}; !reflect.DeepEqual(gains, expected) {
+		t.Errorf("unexpected gains %v", gains)
+	}
+
+	for _, invalid := range []string{
+		`[{"output": 0}]`,
+		`[{"name": "a", "output": 0}, {"name": "a", "output": 1}]`,
+		`[{"name": "a", "output": 7, "stereo": true}]`,
+	} {
+		if _, err := LoadBuses(strings.NewReader(invalid)); err == nil {
+			t.Errorf("%s: should fail", invalid)
+		}
+	}
+}
+
+func TestBusesPlay(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	b, err := LoadBuses(strings.NewReader(buses))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Apply(ts); err != nil {
+		t.Fatal(err)
+	}
+
+	if ts.PitchOf(3) != OffsetToSemitones(SemitonesToOffset(1)) {
+		t.Errorf("unexpected pitch %v", ts.PitchOf(3))
+	}
+
+	if _, err := port.Messages(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Play(ts, 7, "FOH", false); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []control{{TRK_PLAY_POLY, 7}, {TRK_PLAY_POLY, 7}}
+	if c := controls(t, port); !reflect.DeepEqual(c, expected) {
+		t.Errorf("unexpected controls %v", c)
+	}
+
+	if err := b.Play(ts, 7, "sub", false); !errors.Is(err, ErrUnknownBus) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
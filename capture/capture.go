@@ -0,0 +1,257 @@
+// Package capture records the raw bytes exchanged with a Tsunami board and
+// plays them back, so a session can be attached to a bug report and its
+// parsing reproduced exactly.
+//
+// A capture is a text file with one record per line, holding the timestamp,
+// the direction and the bytes in hex:
+//
+//	2021-09-01T10:00:00.000000000Z TX f0aa050155
+//	2021-09-01T10:00:00.012000000Z RX f0aa08820e0010
+//	2021-09-01T10:00:00.017000000Z RX 55
+//
+// Lines starting with '#' are comments.
+package capture
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Direction of the recorded bytes.
+type Direction string
+
+const (
+	// TX bytes were sent to the board.
+	TX Direction = "TX"
+	// RX bytes were received from the board.
+	RX Direction = "RX"
+)
+
+// Record is a chunk of bytes as written or read from the port.
+type Record struct {
+	Time      time.Time
+	Direction Direction
+	Data      []byte
+}
+
+// Writer writes records to a capture file.
+type Writer struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+// NewWriter returns a new Writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, now: time.Now}
+}
+
+// Write records the given bytes with the current time.
+func (w *Writer) Write(dir Direction, data []byte) error {
+	return w.WriteRecord(Record{Time: w.now(), Direction: dir, Data: data})
+}
+
+// WriteRecord writes the given record.
+func (w *Writer) WriteRecord(r Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := fmt.Fprintf(w.w, "%s %s %x\n",
+		r.Time.UTC().Format(time.RFC3339Nano), r.Direction, r.Data,
+	)
+
+	return err
+}
+
+// Reader reads records from a capture file.
+type Reader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// NewReader returns a new Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{s: bufio.NewScanner(r)}
+}
+
+// Read returns the next record, io.EOF is returned at the end of the capture.
+func (r *Reader) Read() (Record, error) {
+	for r.s.Scan() {
+		r.line++
+
+		line := strings.TrimSpace(r.s.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		rec, err := parseRecord(line)
+		if err != nil {
+			return Record{}, fmt.Errorf("line %d: %w", r.line, err)
+		}
+
+		return rec, nil
+	}
+
+	if err := r.s.Err(); err != nil {
+		return Record{}, err
+	}
+
+	return Record{}, io.EOF
+}
+
+// ReadAll returns all the remaining records.
+func (r *Reader) ReadAll() ([]Record, error) {
+	var recs []Record
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return recs, nil
+		}
+
+		if err != nil {
+			return recs, err
+		}
+
+		recs = append(recs, rec)
+	}
+}
+
+func parseRecord(line string) (Record, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return Record{}, fmt.Errorf("malformed record %q", line)
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return Record{}, err
+	}
+
+	dir := Direction(fields[1])
+	if dir != TX && dir != RX {
+		return Record{}, fmt.Errorf("unknown direction %q", fields[1])
+	}
+
+	data, err := hex.DecodeString(fields[2])
+	if err != nil {
+		return Record{}, err
+	}
+
+	return Record{Time: ts, Direction: dir, Data: data}, nil
+}
+
+// Recorder wraps a port, recording every byte written to or read from it.
+type Recorder struct {
+	io.ReadWriteCloser
+	w *Writer
+}
+
+// NewRecorder returns a Recorder of the given port, writing the capture to w.
+func NewRecorder(port io.ReadWriteCloser, w io.Writer) *Recorder {
+	return &Recorder{ReadWriteCloser: port, w: NewWriter(w)}
+}
+
+func (r *Recorder) Read(b []byte) (int, error) {
+	n, err := r.ReadWriteCloser.Read(b)
+	if n > 0 {
+		r.w.Write(RX, b[:n])
+	}
+
+	return n, err
+}
+
+func (r *Recorder) Write(b []byte) (int, error) {
+	n, err := r.ReadWriteCloser.Write(b)
+	if n > 0 {
+		r.w.Write(TX, b[:n])
+	}
+
+	return n, err
+}
+
+// Flush flushes the wrapped port when it buffers the written data, as a
+// transport.Flusher does, and is a no-op otherwise.
+func (r *Recorder) Flush() error {
+	if f, ok := r.ReadWriteCloser.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+// Replayer is a port playing back the received bytes of a capture. Written
+// bytes are discarded. It can be used anywhere a port is expected, feeding a
+// protocol.Decoder or a Tsunami with the exact data seen in the session.
+type Replayer struct {
+	records  []Record
+	realtime bool
+	pending  []byte
+	start    time.Time
+	first    time.Time
+}
+
+// NewReplayer returns a Replayer of the capture read from r. If realtime is
+// true, Read waits to return each record until the same time elapsed since
+// the first read as in the original session.
+func NewReplayer(r io.Reader, realtime bool) (*Replayer, error) {
+	recs, err := NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Replayer{realtime: realtime}
+	for _, rec := range recs {
+		if rec.Direction == RX {
+			p.records = append(p.records, rec)
+		}
+	}
+
+	return p, nil
+}
+
+// Read returns the next received bytes, io.EOF is returned once the capture
+// is exhausted.
+func (p *Replayer) Read(b []byte) (int, error) {
+	if len(p.pending) == 0 {
+		if len(p.records) == 0 {
+			return 0, io.EOF
+		}
+
+		rec := p.records[0]
+		p.records = p.records[1:]
+		p.wait(rec.Time)
+		p.pending = rec.Data
+	}
+
+	n := copy(b, p.pending)
+	p.pending = p.pending[n:]
+	return n, nil
+}
+
+func (p *Replayer) wait(ts time.Time) {
+	if !p.realtime {
+		return
+	}
+
+	if p.start.IsZero() {
+		p.start, p.first = time.Now(), ts
+		return
+	}
+
+	time.Sleep(time.Until(p.start.Add(ts.Sub(p.first))))
+}
+
+// Write discards the given bytes.
+func (p *Replayer) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// Close is a no-op.
+func (p *Replayer) Close() error {
+	return nil
+}
@@ -0,0 +1,123 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+type bufferPort struct {
+	rx []byte
+	tx []byte
+}
+
+func (p *bufferPort) Read(b []byte) (int, error) {
+	n := copy(b, p.rx)
+	p.rx = p.rx[n:]
+	return n, nil
+}
+
+func (p *bufferPort) Write(b []byte) (int, error) {
+	p.tx = append(p.tx, b...)
+	return len(b), nil
+}
+
+func (p *bufferPort) Close() error { return nil }
+
+type flushPort struct {
+	bufferPort
+	flushed int
+}
+
+func (p *flushPort) Flush() error {
+	p.flushed++
+	return nil
+}
+
+func TestRecorderFlush(t *testing.T) {
+	p := &flushPort{}
+	if err := NewRecorder(p, io.Discard).Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.flushed != 1 {
+		t.Errorf("got %d flushes, expected 1", p.flushed)
+	}
+
+	if err := NewRecorder(&bufferPort{}, io.Discard).Flush(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecorderReplayer(t *testing.T) {
+	report := protocol.TrackReport{Track: 19, Voice: 1, Playing: true}.AppendBinary(nil)
+
+	buf := bytes.NewBuffer(nil)
+	r := NewRecorder(&bufferPort{rx: report}, buf)
+	r.Write(protocol.StopAll{}.AppendBinary(nil))
+
+	rxbuf := make([]byte, 4)
+	for {
+		n, _ := r.Read(rxbuf)
+		if n == 0 {
+			break
+		}
+	}
+
+	recs, err := NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recs) != 4 || recs[0].Direction != TX || recs[1].Direction != RX {
+		t.Fatalf("unexpected records %+v", recs)
+	}
+
+	p, err := NewReplayer(buf, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := io.ReadAll(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data, report) {
+		t.Errorf("got % x, expected % x", data, report)
+	}
+}
+
+func TestReaderRead(t *testing.T) {
+	r := NewReader(strings.NewReader(`# comment
+2021-09-01T10:00:00Z TX f0aa050455
+
+2021-09-01T10:00:00.5Z RX f0aa
+`))
+
+	recs, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Record{
+		{time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC), TX, []byte{0xf0, 0xaa, 0x05, 0x04, 0x55}},
+		{time.Date(2021, 9, 1, 10, 0, 0, 5e8, time.UTC), RX, []byte{0xf0, 0xaa}},
+	}
+
+	if !reflect.DeepEqual(recs, expected) {
+		t.Errorf("got %+v, expected %+v", recs, expected)
+	}
+}
+
+func TestReaderReadMalformed(t *testing.T) {
+	_, err := NewReader(strings.NewReader("2021-09-01T10:00:00Z XX f0\n")).Read()
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
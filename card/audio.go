@@ -0,0 +1,216 @@
+package card
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Audio are the decoded samples of a WAV file.
+type Audio struct {
+	SampleRate int
+	Channels   int
+	// Samples are the interleaved samples, from -1 to 1.
+	Samples []float64
+}
+
+// DecodeWAV reads the samples of a WAV file, of PCM samples of 8 to 32 bits
+// or floating point ones.
+func DecodeWAV(r io.ReadSeeker) (*Audio, error) {
+	w, err := ReadWAV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	format := w.AudioFormat
+	if format == FormatExtensible {
+		if format, err = subFormat(r); err != nil {
+			return nil, err
+		}
+	}
+
+	bytes := w.BitsPerSample / 8
+	if w.Channels == 0 || bytes == 0 || w.BlockAlign != bytes*w.Channels {
+		return nil, fmt.Errorf("unsupported format: %s", w)
+	}
+
+	if format == FormatFloat && bytes != 4 && bytes != 8 {
+		return nil, fmt.Errorf("unsupported %d-bit floating point samples", w.BitsPerSample)
+	} else if format != FormatPCM && format != FormatFloat {
+		return nil, fmt.Errorf("unsupported audio format %#x", format)
+	}
+
+	if _, err := r.Seek(w.DataOffset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, w.Frames()*int64(w.BlockAlign))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	a := &Audio{SampleRate: w.SampleRate, Channels: w.Channels, Samples: make([]float64, len(data)/bytes)}
+	for i := range a.Samples {
+		a.Samples[i] = decodeSample(data[i*bytes:(i+1)*bytes], format == FormatFloat)
+	}
+
+	return a, nil
+}
+
+// subFormat reads the audio format of the WAVE_FORMAT_EXTENSIBLE header, in
+// the first bytes of its GUID.
+func subFormat(r io.ReadSeeker) (int, error) {
+	if _, err := r.Seek(12, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return 0, err
+		}
+
+		n := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+		if string(hdr[0:4]) != "fmt " {
+			if _, err := r.Seek(n+n%2, io.SeekCurrent); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		if n < 26 {
+			return 0, fmt.Errorf("invalid extensible fmt chunk of %d bytes", n)
+		}
+
+		f := make([]byte, 26)
+		if _, err := io.ReadFull(r, f); err != nil {
+			return 0, err
+		}
+
+		return int(binary.LittleEndian.Uint16(f[24:26])), nil
+	}
+}
+
+func decodeSample(b []byte, float bool) float64 {
+	switch {
+	case float && len(b) == 4:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	case float:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	case len(b) == 1:
+		// 8-bit samples are unsigned.
+		return float64(int(b[0])-128) / 128
+	}
+
+	// the signed little endian samples are read into the highest bits of
+	// an int32.
+	var v int32
+	for i, c := range b {
+		v |= int32(c) << uint(32-8*len(b)+8*i)
+	}
+
+	return float64(v) / (1 << 31)
+}
+
+// Frames returns the number of sample frames.
+func (a *Audio) Frames() int {
+	return len(a.Samples) / a.Channels
+}
+
+// Remix returns the audio with the given channels, downmixing to mono by
+// averaging the channels, or copying the first ones to the new channels.
+func (a *Audio) Remix(channels int) *Audio {
+	if channels == a.Channels {
+		return a
+	}
+
+	out := &Audio{SampleRate: a.SampleRate, Channels: channels, Samples: make([]float64, a.Frames()*channels)}
+	for f := 0; f < a.Frames(); f++ {
+		frame := a.Samples[f*a.Channels : (f+1)*a.Channels]
+		if channels == 1 {
+			var sum float64
+			for _, s := range frame {
+				sum += s
+			}
+
+			out.Samples[f] = sum / float64(a.Channels)
+			continue
+		}
+
+		for c := 0; c < channels; c++ {
+			out.Samples[f*channels+c] = frame[c%a.Channels]
+		}
+	}
+
+	return out
+}
+
+// Resample returns the audio at the given sample rate, interpolating the
+// samples with a cubic curve. Downsampling isn't filtered, so content above
+// the new Nyquist frequency may alias.
+func (a *Audio) Resample(rate int) *Audio {
+	if rate == a.SampleRate {
+		return a
+	}
+
+	frames := int(int64(a.Frames()) * int64(rate) / int64(a.SampleRate))
+	out := &Audio{SampleRate: rate, Channels: a.Channels, Samples: make([]float64, frames*a.Channels)}
+	at := func(f, c int) float64 {
+		if f < 0 {
+			f = 0
+		} else if f >= a.Frames() {
+			f = a.Frames() - 1
+		}
+
+		return a.Samples[f*a.Channels+c]
+	}
+
+	ratio := float64(a.SampleRate) / float64(rate)
+	for f := 0; f < frames; f++ {
+		pos := float64(f) * ratio
+		i := int(pos)
+		x := pos - float64(i)
+		for c := 0; c < a.Channels; c++ {
+			p0, p1, p2, p3 := at(i-1, c), at(i, c), at(i+1, c), at(i+2, c)
+			// Catmull-Rom spline between p1 and p2.
+			out.Samples[f*a.Channels+c] = p1 + 0.5*x*(p2-p0+x*(2*p0-5*p1+4*p2-p3+x*(3*(p1-p2)+p3-p0)))
+		}
+	}
+
+	return out
+}
+
+// EncodeWAV writes the audio as a plain 16-bit PCM WAV file, without any
+// other chunk, clipping the samples out of range.
+func (a *Audio) EncodeWAV(w io.Writer) error {
+	size := len(a.Samples) * 2
+	hdr := make([]byte, 44)
+	copy(hdr[0:], "RIFF")
+	binary.LittleEndian.PutUint32(hdr[4:], uint32(36+size))
+	copy(hdr[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(hdr[16:], 16)
+	binary.LittleEndian.PutUint16(hdr[20:], FormatPCM)
+	binary.LittleEndian.PutUint16(hdr[22:], uint16(a.Channels))
+	binary.LittleEndian.PutUint32(hdr[24:], uint32(a.SampleRate))
+	binary.LittleEndian.PutUint32(hdr[28:], uint32(a.SampleRate*a.Channels*2))
+	binary.LittleEndian.PutUint16(hdr[32:], uint16(a.Channels*2))
+	binary.LittleEndian.PutUint16(hdr[34:], 16)
+	copy(hdr[36:], "data")
+	binary.LittleEndian.PutUint32(hdr[40:], uint32(size))
+
+	data := make([]byte, size)
+	for i, s := range a.Samples {
+		v := math.Round(s * 32768)
+		v = math.Max(-32768, math.Min(32767, v))
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(int16(v)))
+	}
+
+	if _, err := w.Write(hdr); err != nil {
+		return err
+	}
+
+	_, err := w.Write(data)
+	return err
+}
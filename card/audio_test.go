@@ -0,0 +1,84 @@
+package card
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestDecodeWAV(t *testing.T) {
+	// a stereo frame of 24-bit samples, 0.5 and -0.25.
+	data := []byte{0x00, 0x00, 0x40, 0x00, 0x00, 0xe0}
+	a, err := DecodeWAV(bytes.NewReader(makeWAV(fmtChunk(FormatPCM, 2, 48000, 24), chunk{"data", data})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Channels != 2 || a.Frames() != 1 || a.Samples[0] != 0.5 || a.Samples[1] != -0.25 {
+		t.Errorf("unexpected audio %+v", a)
+	}
+
+	// 8-bit samples are unsigned.
+	a, err = DecodeWAV(bytes.NewReader(makeWAV(fmtChunk(FormatPCM, 1, 8000, 8), chunk{"data", []byte{0, 128}})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Samples[0] != -1 || a.Samples[1] != 0 {
+		t.Errorf("unexpected samples %v", a.Samples)
+	}
+}
+
+func TestAudioEncodeWAV(t *testing.T) {
+	a := &Audio{SampleRate: 44100, Channels: 1, Samples: []float64{0, 0.5, -1, 2}}
+
+	var buf bytes.Buffer
+	if err := a.EncodeWAV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := ReadWAV(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if problems := Mono.Check(w); len(problems) != 0 {
+		t.Errorf("unexpected problems %v", problems)
+	}
+
+	decoded, err := DecodeWAV(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expected := range []float64{0, 0.5, -1, 32767.0 / 32768} {
+		if decoded.Samples[i] != expected {
+			t.Errorf("sample %d: unexpected %v, expected %v", i, decoded.Samples[i], expected)
+		}
+	}
+}
+
+func TestAudioRemixResample(t *testing.T) {
+	a := &Audio{SampleRate: 22050, Channels: 2, Samples: make([]float64, 2*22050)}
+	for f := 0; f < 22050; f++ {
+		s := math.Sin(2 * math.Pi * 441 * float64(f) / 22050)
+		a.Samples[2*f], a.Samples[2*f+1] = s, 0
+	}
+
+	out := a.Remix(1).Resample(44100)
+	if out.Channels != 1 || out.SampleRate != 44100 || out.Frames() != 44100 {
+		t.Fatalf("unexpected audio %d channels, %d Hz, %d frames", out.Channels, out.SampleRate, out.Frames())
+	}
+
+	// the first and last frames lack neighbours to interpolate.
+	for f := 4; f < out.Frames()-4; f++ {
+		expected := math.Sin(2*math.Pi*441*float64(f)/44100) / 2
+		if math.Abs(out.Samples[f]-expected) > 0.001 {
+			t.Fatalf("frame %d: unexpected %v, expected %v", f, out.Samples[f], expected)
+		}
+	}
+
+	if st := out.Remix(2); st.Frames() != 44100 || st.Samples[21] != st.Samples[20] {
+		t.Errorf("mono should be copied to both channels")
+	}
+}
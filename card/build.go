@@ -0,0 +1,265 @@
+package card
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// Builder stages the layout of a card in a directory, from a directory of
+// arbitrarily named WAV files and a mapping of them to tracks.
+type Builder struct {
+	// Source is the directory of the source files.
+	Source string
+	// Mapping are the entries of the source files to stage.
+	Mapping []Entry
+	// Digits is the width of the track numbers, DefaultDigits if zero.
+	Digits int
+	// Require, if not nil, are the requirements the source files are
+	// checked against before staging, see ValidationError.
+	Require *Requirements
+	// Convert, if not nil, converts the source files not meeting the
+	// requirements instead of failing, see DefaultConverter.
+	Convert Converter
+	// Clean removes the WAV files found in the destination before staging,
+	// otherwise a destination with WAV files is an error.
+	Clean bool
+	// Trim, if not nil, trims the silence of the tracks staged.
+	Trim *Trim
+	// Analyze measures the loudness of the tracks staged, see
+	// Manifest.Gains.
+	Analyze bool
+
+	// convert are the source files to convert.
+	convert map[string]bool
+}
+
+// Build stages the card in the destination directory, creating it if
+// needed, and returns its manifest.
+func (b *Builder) Build(dest string) (*Manifest, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := b.prepare(dest); err != nil {
+		return nil, err
+	}
+
+	digits := b.Digits
+	if digits == 0 {
+		digits = DefaultDigits
+	}
+
+	entries := append([]Entry(nil), b.Mapping...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Track < entries[j].Track })
+
+	m := &Manifest{}
+	for _, e := range entries {
+		name := nameOf(e)
+		t := Track{Track: e.Track, Name: name, File: FileName(e.Track, name, digits), Source: e.File}
+		src, dst := filepath.Join(b.Source, e.File), filepath.Join(dest, t.File)
+
+		var err error
+		if t.Converted = b.convert[e.File]; t.Converted {
+			err = b.Convert.Convert(src, dst, *b.Require)
+		} else {
+			err = copyFile(src, dst)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("track %d: %w", e.Track, err)
+		}
+
+		if b.Trim != nil {
+			lead, tail, err := b.Trim.TrimFile(dst)
+			if err != nil {
+				return nil, fmt.Errorf("track %d: %w", e.Track, err)
+			}
+
+			t.Trimmed = (lead + tail).Seconds()
+		}
+
+		if b.Analyze {
+			l, err := FileLoudness(dst)
+			if err != nil {
+				return nil, fmt.Errorf("track %d: %w", e.Track, err)
+			}
+
+			if !math.IsInf(l, -1) {
+				l = math.Round(l*10) / 10
+				t.Loudness = &l
+			}
+		}
+
+		if t.Checksum, err = Checksum(dst); err != nil {
+			return nil, fmt.Errorf("track %d: %w", e.Track, err)
+		}
+
+		m.Tracks = append(m.Tracks, t)
+	}
+
+	unmapped, err := b.unmapped()
+	if err != nil {
+		return nil, err
+	}
+
+	m.Unmapped = unmapped
+	return m, nil
+}
+
+// validate checks the mapping: the track numbers, their uniqueness, the
+// names and files of the tracks, and their format if required.
+func (b *Builder) validate() error {
+	tracks := make(map[int]bool, len(b.Mapping))
+	names := make(map[string]int, len(b.Mapping))
+	for _, e := range b.Mapping {
+		if e.Track <= 0 || e.Track > tsunami.MaxTracks {
+			return fmt.Errorf("%s: invalid track %d", e.File, e.Track)
+		}
+
+		if tracks[e.Track] {
+			return fmt.Errorf("%s: duplicated track %d", e.File, e.Track)
+		}
+
+		tracks[e.Track] = true
+		if name := nameOf(e); name != "" {
+			if trk, ok := names[name]; ok {
+				return fmt.Errorf("%s: name %q already used by track %d", e.File, name, trk)
+			}
+
+			names[name] = e.Track
+		}
+
+		if _, err := os.Stat(filepath.Join(b.Source, e.File)); err != nil {
+			return err
+		}
+	}
+
+	if b.Require == nil {
+		return nil
+	}
+
+	var invalid []Report
+	b.convert = make(map[string]bool)
+	for _, e := range b.Mapping {
+		r, err := b.Require.ValidateFile(filepath.Join(b.Source, e.File))
+		if err != nil {
+			return err
+		}
+
+		if !r.OK() && b.Convert != nil {
+			b.convert[e.File] = true
+		} else if !r.OK() {
+			r.File = e.File
+			invalid = append(invalid, r)
+		}
+	}
+
+	if len(invalid) > 0 {
+		return &ValidationError{Reports: invalid}
+	}
+
+	return nil
+}
+
+// prepare creates the destination, checking or removing its WAV files.
+func (b *Builder) prepare(dest string) error {
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+
+	files, err := wavFiles(dest)
+	if err != nil {
+		return err
+	}
+
+	if len(files) > 0 && !b.Clean {
+		return fmt.Errorf("%s: destination already contains %d WAV files", dest, len(files))
+	}
+
+	for _, f := range files {
+		if err := os.Remove(filepath.Join(dest, f)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unmapped returns the WAV files of the source directory not in the mapping.
+func (b *Builder) unmapped() ([]string, error) {
+	mapped := make(map[string]bool, len(b.Mapping))
+	for _, e := range b.Mapping {
+		mapped[filepath.Clean(e.File)] = true
+	}
+
+	var unmapped []string
+	err := filepath.Walk(b.Source, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !isWAV(path) {
+			return err
+		}
+
+		rel, err := filepath.Rel(b.Source, path)
+		if err != nil {
+			return err
+		}
+
+		if !mapped[rel] {
+			unmapped = append(unmapped, filepath.ToSlash(rel))
+		}
+
+		return nil
+	})
+
+	return unmapped, err
+}
+
+// wavFiles returns the WAV files of the directory, not recursively.
+func wavFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && isWAV(e.Name()) {
+			files = append(files, e.Name())
+		}
+	}
+
+	return files, nil
+}
+
+func isWAV(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".wav")
+}
+
+func copyFile(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	_, err = io.Copy(out, in)
+	return err
+}
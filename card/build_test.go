@@ -0,0 +1,90 @@
+package card
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates the files, with their names as content.
+func writeFiles(t *testing.T, dir string, names ...string) {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBuilderBuild(t *testing.T) {
+	src, dest := t.TempDir(), t.TempDir()
+	writeFiles(t, src, "Door Creak.wav", "music/Overture v3.WAV", "unused.wav", "notes.txt")
+
+	mapping, err := ReadMapping(strings.NewReader(`[
+	  {"track": 101, "name": "act1_music", "file": "music/Overture v3.WAV"},
+	  {"track": 12, "file": "Door Creak.wav"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := &Builder{Source: src, Mapping: mapping}
+	m, err := b.Build(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Manifest{
+		Tracks: []Track{
+			{Track: 12, Name: "door_creak", File: "0012_door_creak.wav", Source: "Door Creak.wav", Checksum: sum("Door Creak.wav")},
+			{Track: 101, Name: "act1_music", File: "0101_act1_music.wav", Source: "music/Overture v3.WAV", Checksum: sum("music/Overture v3.WAV")},
+		},
+		Unmapped: []string{"unused.wav"},
+	}
+
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("unexpected manifest %+v", m)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dest, "0012_door_creak.wav"))
+	if err != nil || string(content) != "Door Creak.wav" {
+		t.Errorf("unexpected content %q, %v", content, err)
+	}
+
+	if _, err := b.Build(dest); err == nil {
+		t.Error("a destination with WAV files should fail")
+	}
+
+	b.Clean = true
+	b.Mapping = mapping[1:]
+	if _, err := b.Build(dest); err != nil {
+		t.Fatal(err)
+	}
+
+	if files, _ := wavFiles(dest); !reflect.DeepEqual(files, []string{"0012_door_creak.wav"}) {
+		t.Errorf("unexpected files %v", files)
+	}
+}
+
+func TestBuilderValidate(t *testing.T) {
+	src := t.TempDir()
+	writeFiles(t, src, "a.wav", "b.wav")
+
+	for _, mapping := range [][]Entry{
+		{{Track: 0, File: "a.wav"}},
+		{{Track: 1, File: "a.wav"}, {Track: 1, File: "b.wav"}},
+		{{Track: 1, Name: "x", File: "a.wav"}, {Track: 2, Name: "x", File: "b.wav"}},
+		{{Track: 1, File: "missing.wav"}},
+	} {
+		b := &Builder{Source: src, Mapping: mapping}
+		if _, err := b.Build(t.TempDir()); err == nil {
+			t.Errorf("%v: should fail", mapping)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package card prepares the contents of the microSD card of the board: the
+// WAV files renamed after their track numbers, and a manifest describing
+// them for the rest of the library.
+package card
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// DefaultDigits is the width of the track numbers of the file names of the
+// Tsunami, the WAV Trigger uses 3.
+const DefaultDigits = 4
+
+// Entry maps a source WAV file to a track.
+type Entry struct {
+	Track int `json:"track"`
+	// Name is the name of the track, the one of the file if empty.
+	Name string `json:"name,omitempty"`
+	// File is the path of the source file, relative to the source
+	// directory.
+	File string `json:"file"`
+}
+
+// ReadMapping reads the entries of a mapping file, a JSON array:
+//
+//	[
+//	  {"track": 1, "file": "Door Creak (final).wav"},
+//	  {"track": 101, "name": "act1_music", "file": "music/overture v3.wav"}
+//	]
+func ReadMapping(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// FileName returns the name of the file of the track on the card, like
+// "0012_door_creak.wav", with the track number padded to the given digits.
+func FileName(trk int, name string, digits int) string {
+	if name = Sanitize(name); name == "" {
+		return fmt.Sprintf("%0*d.wav", digits, trk)
+	}
+
+	return fmt.Sprintf("%0*d_%s.wav", digits, trk, name)
+}
+
+// Sanitize returns the name reduced to lowercase letters, digits, dashes and
+// underscores, safe for the FAT file system of the card and as an alias.
+func Sanitize(name string) string {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			if sep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+
+			b.WriteRune(r)
+			sep = false
+		default:
+			sep = true
+		}
+	}
+
+	return b.String()
+}
+
+// nameOf returns the name of the entry, from its file if not set.
+func nameOf(e Entry) string {
+	if e.Name != "" {
+		return Sanitize(e.Name)
+	}
+
+	base := filepath.Base(e.File)
+	return Sanitize(strings.TrimSuffix(base, filepath.Ext(base)))
+}
+
+// Manifest describes the tracks of a card, as staged by a Builder.
+type Manifest struct {
+	Tracks []Track `json:"tracks"`
+	// Unmapped are the WAV files of the source directory not in the
+	// mapping, left out of the card.
+	Unmapped []string `json:"unmapped,omitempty"`
+}
+
+// Track is a track of a card.
+type Track struct {
+	Track int    `json:"track"`
+	Name  string `json:"name"`
+	// File is the name of the file on the card and Source the path of the
+	// source file it was staged from.
+	File   string `json:"file"`
+	Source string `json:"source"`
+	// Converted is set when the source file was converted to meet the
+	// requirements of the board.
+	Converted bool `json:"converted,omitempty"`
+	// Trimmed is the silence, in seconds, removed from the track.
+	Trimmed float64 `json:"trimmed,omitempty"`
+	// Loudness is the integrated loudness of the track in LUFS, if
+	// analyzed, nil if unknown or silent.
+	Loudness *float64 `json:"loudness,omitempty"`
+	// Checksum is the SHA-256 of the file on the card, in hex, see
+	// Manifest.Verify.
+	Checksum string `json:"sha256,omitempty"`
+}
+
+// ReadManifest reads a manifest written by Manifest.Save.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	var m Manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Save writes the manifest as JSON.
+func (m *Manifest) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// Aliases returns the names of the tracks, see Tsunami.SetAliases.
+func (m *Manifest) Aliases() tsunami.Aliases {
+	a := make(tsunami.Aliases, len(m.Tracks))
+	for _, t := range m.Tracks {
+		if t.Name != "" {
+			a[t.Name] = t.Track
+		}
+	}
+
+	return a
+}
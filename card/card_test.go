@@ -0,0 +1,51 @@
+package card
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+func TestFileName(t *testing.T) {
+	for _, tc := range []struct {
+		trk      int
+		name     string
+		digits   int
+		expected string
+	}{
+		{12, "Door Creak (final)", 4, "0012_door_creak_final.wav"},
+		{7, "act1-music", 3, "007_act1-music.wav"},
+		{1, "¡¿?!", 4, "0001.wav"},
+	} {
+		if name := FileName(tc.trk, tc.name, tc.digits); name != tc.expected {
+			t.Errorf("unexpected name %q, expected %q", name, tc.expected)
+		}
+	}
+}
+
+func TestManifest(t *testing.T) {
+	m := &Manifest{Tracks: []Track{
+		{Track: 1, Name: "door_creak", File: "0001_door_creak.wav", Source: "Door Creak.wav"},
+		{Track: 2, File: "0002.wav", Source: "!!.wav"},
+	}}
+
+	buf := bytes.NewBuffer(nil)
+	if err := m.Save(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadManifest(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(read, m) {
+		t.Errorf("unexpected manifest %+v", read)
+	}
+
+	if a := read.Aliases(); !reflect.DeepEqual(a, tsunami.Aliases{"door_creak": 1}) {
+		t.Errorf("unexpected aliases %v", a)
+	}
+}
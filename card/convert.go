@@ -0,0 +1,95 @@
+package card
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// Converter converts an audio file into a WAV file meeting the requirements.
+type Converter interface {
+	Convert(src, dst string, req Requirements) error
+}
+
+// DefaultConverter returns FFmpeg if the ffmpeg command is found in the PATH,
+// Native otherwise.
+func DefaultConverter() Converter {
+	if path, err := exec.LookPath("ffmpeg"); err == nil {
+		return FFmpeg{Path: path}
+	}
+
+	return Native{}
+}
+
+// Native converts WAV files in pure Go, changing the sample format to 16-bit
+// PCM, the channels and the sample rate as required, and dropping every
+// chunk but fmt and data.
+type Native struct{}
+
+// Convert implements Converter.
+func (Native) Convert(src, dst string, req Requirements) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	a, err := DecodeWAV(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+
+	if req.BitsPerSample != 16 {
+		return fmt.Errorf("unsupported %d-bit samples", req.BitsPerSample)
+	}
+
+	channels := req.Channels
+	if channels == 0 {
+		channels = a.Channels
+		if channels > 2 {
+			channels = 2
+		}
+	}
+
+	a = a.Remix(channels).Resample(req.SampleRate)
+	return writeAudio(dst, a)
+}
+
+func writeAudio(path string, a *Audio) error {
+	var buf bytes.Buffer
+	if err := a.EncodeWAV(&buf); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// FFmpeg converts any audio file supported by ffmpeg.
+type FFmpeg struct {
+	// Path is the path of the ffmpeg command.
+	Path string
+}
+
+// Convert implements Converter.
+func (c FFmpeg) Convert(src, dst string, req Requirements) error {
+	args := []string{
+		"-nostdin", "-loglevel", "error", "-y", "-i", src,
+		"-map_metadata", "-1", "-flags", "+bitexact",
+		"-acodec", fmt.Sprintf("pcm_s%dle", req.BitsPerSample),
+		"-ar", strconv.Itoa(req.SampleRate),
+	}
+
+	if req.Channels != 0 {
+		args = append(args, "-ac", strconv.Itoa(req.Channels))
+	}
+
+	cmd := exec.Command(c.Path, append(args, "-f", "wav", dst)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg: %w: %s", err, bytes.TrimSpace(out))
+	}
+
+	return nil
+}
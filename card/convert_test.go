@@ -0,0 +1,50 @@
+package card
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuilderConvert(t *testing.T) {
+	src, dest := t.TempDir(), t.TempDir()
+	data := makeWAV(fmtChunk(FormatFloat, 2, 48000, 32), chunk{"LIST", []byte("INFO")}, chunk{"data", make([]byte, 4800*8)})
+	if err := os.WriteFile(filepath.Join(src, "hi.wav"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &Builder{Source: src, Require: &Mono, Convert: Native{}, Mapping: []Entry{{Track: 1, File: "hi.wav"}}}
+	m, err := b.Build(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Tracks[0].Converted {
+		t.Error("the track should be converted")
+	}
+
+	r, err := Mono.ValidateFile(filepath.Join(dest, m.Tracks[0].File))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.OK() {
+		t.Errorf("unexpected problems %v", r.Problems)
+	}
+
+	f, err := os.Open(filepath.Join(dest, m.Tracks[0].File))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	w, err := ReadWAV(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Frames() != 4410 {
+		t.Errorf("unexpected frames %d", w.Frames())
+	}
+}
@@ -0,0 +1,67 @@
+package card
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Checksum returns the SHA-256 of the file, in hex, as stored in the
+// manifest.
+func Checksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Verify re-hashes the tracks of the card in dir, usually a mounted card,
+// against the checksums of the manifest, proving the card matches the
+// content it was built with. A report is returned for every track, and for
+// every WAV file on the card not in the manifest.
+func (m *Manifest) Verify(dir string) ([]Report, error) {
+	var reports []Report
+	files := make(map[string]bool, len(m.Tracks))
+	for _, t := range m.Tracks {
+		files[t.File] = true
+
+		r := Report{File: t.File}
+		sum, err := Checksum(filepath.Join(dir, t.File))
+		switch {
+		case os.IsNotExist(err):
+			r.Problems = []string{"missing"}
+		case err != nil:
+			return nil, err
+		case t.Checksum == "":
+			r.Problems = []string{"no checksum in the manifest"}
+		case sum != t.Checksum:
+			r.Problems = []string{"checksum mismatch"}
+		}
+
+		reports = append(reports, r)
+	}
+
+	wavs, err := wavFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range wavs {
+		if !files[name] {
+			reports = append(reports, Report{File: name, Problems: []string{"not in the manifest"}})
+		}
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,57 @@
+package card
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sum returns the checksum of the content.
+func sum(content string) string {
+	h := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(h[:])
+}
+
+func TestManifestVerify(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "0001_door.wav", "0002_music.wav", "0004_extra.wav", "notes.txt")
+	if err := os.WriteFile(filepath.Join(dir, "0002_music.wav"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manifest{Tracks: []Track{
+		{Track: 1, File: "0001_door.wav", Checksum: sum("0001_door.wav")},
+		{Track: 2, File: "0002_music.wav", Checksum: sum("0002_music.wav")},
+		{Track: 3, File: "0003_wind.wav", Checksum: sum("0003_wind.wav")},
+		{Track: 4, File: "0004_extra.wav"},
+	}}
+
+	reports, err := m.Verify(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, r := range reports {
+		got = append(got, r.String())
+	}
+
+	expected := []string{
+		"0001_door.wav: ok",
+		"0002_music.wav: checksum mismatch",
+		"0003_wind.wav: missing",
+		"0004_extra.wav: no checksum in the manifest",
+	}
+
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected reports:\n%s", strings.Join(got, "\n"))
+	}
+
+	m.Tracks = m.Tracks[:1]
+	if reports, _ := m.Verify(dir); len(reports) != 3 || reports[1].String() != "0002_music.wav: not in the manifest" {
+		t.Errorf("unexpected reports %v", reports)
+	}
+}
@@ -0,0 +1,158 @@
+package card
+
+import (
+	"math"
+	"os"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// DefaultTarget is the default loudness target, in LUFS, of the
+// normalization, the one of EBU R128 broadcast.
+const DefaultTarget = -23
+
+// Loudness returns the integrated loudness of the audio in LUFS, as defined
+// by ITU-R BS.1770 and EBU R128: K-weighted and gated in blocks of 400ms.
+// Silent audio returns -Inf.
+func Loudness(a *Audio) float64 {
+	frames := a.Frames()
+	if frames == 0 {
+		return math.Inf(-1)
+	}
+
+	// the K-weighted energy of every frame, summed over the channels.
+	energy := make([]float64, frames)
+	for c := 0; c < a.Channels; c++ {
+		shelf, highpass := kWeighting(float64(a.SampleRate))
+		for f := 0; f < frames; f++ {
+			v := highpass.filter(shelf.filter(a.Samples[f*a.Channels+c]))
+			energy[f] += v * v
+		}
+	}
+
+	// blocks of 400ms overlapping by 75%, or a single one for the shorter
+	// audio.
+	block, step := a.SampleRate*4/10, a.SampleRate/10
+	if block > frames {
+		block = frames
+	}
+
+	var blocks []float64
+	for start := 0; start+block <= frames; start += step {
+		var sum float64
+		for _, e := range energy[start : start+block] {
+			sum += e
+		}
+
+		blocks = append(blocks, sum/float64(block))
+	}
+
+	// absolute gate at -70 LUFS, then relative gate 10 LU below the
+	// loudness of the blocks left.
+	gated := gate(blocks, -70)
+	if len(gated) == 0 {
+		return math.Inf(-1)
+	}
+
+	return lufs(mean(gate(gated, lufs(mean(gated))-10)))
+}
+
+func lufs(energy float64) float64 {
+	return -0.691 + 10*math.Log10(energy)
+}
+
+func gate(blocks []float64, threshold float64) []float64 {
+	var kept []float64
+	for _, b := range blocks {
+		if b > 0 && lufs(b) > threshold {
+			kept = append(kept, b)
+		}
+	}
+
+	return kept
+}
+
+func mean(v []float64) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x
+	}
+
+	return sum / float64(len(v))
+}
+
+// biquad is a second order IIR filter.
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+func (q *biquad) filter(x float64) float64 {
+	y := q.b0*x + q.b1*q.x1 + q.b2*q.x2 - q.a1*q.y1 - q.a2*q.y2
+	q.x2, q.x1 = q.x1, x
+	q.y2, q.y1 = q.y1, y
+	return y
+}
+
+// kWeighting returns the filters of the K-weighting for the sample rate, a
+// high shelf modeling the head followed by a high-pass, as computed by
+// libebur128.
+func kWeighting(rate float64) (*biquad, *biquad) {
+	k := math.Tan(math.Pi * 1681.974450955533 / rate)
+	q := 0.7071752369554196
+	vh := math.Pow(10, 3.999843853973347/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := &biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	k = math.Tan(math.Pi * 38.13547087602444 / rate)
+	q = 0.5003270373238773
+	a0 = 1 + k/q + k*k
+	highpass := &biquad{
+		b0: 1, b1: -2, b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	return shelf, highpass
+}
+
+// FileLoudness returns the integrated loudness of the WAV file, see
+// Loudness.
+func FileLoudness(path string) (float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+
+	defer f.Close()
+
+	a, err := DecodeWAV(f)
+	if err != nil {
+		return 0, err
+	}
+
+	return Loudness(a), nil
+}
+
+// Gains returns the gain offset of every track with a known loudness to play
+// at the target loudness, in LUFS, as expected by Tsunami.SetNormalization.
+// The offsets are limited to the gains of the board.
+func (m *Manifest) Gains(target float64) map[int]tsunami.Gain {
+	gains := make(map[int]tsunami.Gain, len(m.Tracks))
+	for _, t := range m.Tracks {
+		if t.Loudness == nil {
+			continue
+		}
+
+		gains[t.Track] = tsunami.Gain(math.Round(target-*t.Loudness)).Clamp(tsunami.MinGain, tsunami.MaxTrackGain)
+	}
+
+	return gains
+}
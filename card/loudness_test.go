@@ -0,0 +1,73 @@
+package card
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// sine returns a mono sine of 1 kHz of the given amplitude.
+func sine(rate int, amplitude float64, d float64) *Audio {
+	a := &Audio{SampleRate: rate, Channels: 1, Samples: make([]float64, int(float64(rate)*d))}
+	for i := range a.Samples {
+		a.Samples[i] = amplitude * math.Sin(2*math.Pi*1000*float64(i)/float64(rate))
+	}
+
+	return a
+}
+
+func TestLoudness(t *testing.T) {
+	for _, tc := range []struct {
+		audio    *Audio
+		expected float64
+	}{
+		{sine(48000, 1, 3), -3.01},
+		{sine(44100, 0.5, 3), -9.03},
+		{sine(44100, 0.1, 0.2), -23.01},
+	} {
+		if l := Loudness(tc.audio); math.Abs(l-tc.expected) > 0.1 {
+			t.Errorf("unexpected loudness %.2f, expected %.2f", l, tc.expected)
+		}
+	}
+
+	if l := Loudness(&Audio{SampleRate: 44100, Channels: 2, Samples: make([]float64, 44100)}); !math.IsInf(l, -1) {
+		t.Errorf("silence should be -Inf, got %v", l)
+	}
+}
+
+func TestBuilderAnalyze(t *testing.T) {
+	src := t.TempDir()
+	for name, a := range map[string]*Audio{
+		"loud.wav":   sine(44100, 1, 1),
+		"quiet.wav":  sine(44100, 0.1, 1),
+		"silent.wav": {SampleRate: 44100, Channels: 1, Samples: make([]float64, 100)},
+	} {
+		var buf bytes.Buffer
+		if err := a.EncodeWAV(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(src, name), buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := &Builder{Source: src, Analyze: true, Mapping: []Entry{
+		{Track: 1, File: "loud.wav"}, {Track: 2, File: "quiet.wav"}, {Track: 3, File: "silent.wav"},
+	}}
+
+	m, err := b.Build(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[int]tsunami.Gain{1: -20, 2: 0}
+	if gains := m.Gains(DefaultTarget); !reflect.DeepEqual(gains, expected) {
+		t.Errorf("unexpected gains %v", gains)
+	}
+}
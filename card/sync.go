@@ -0,0 +1,120 @@
+package card
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SyncReport is the result of Sync.
+type SyncReport struct {
+	// Copied are the files new or changed, copied to the card.
+	Copied []string
+	// Removed are the WAV files of the card not staged, removed so two
+	// files don't claim the same track.
+	Removed []string
+	// Unchanged are the files already on the card.
+	Unchanged []string
+}
+
+// Sync updates a mounted card with the layout staged by a Builder, copying
+// only the files new or changed, compared by size and content hash, so
+// iterative updates of the content are fast. The WAV files of the card not
+// staged are removed, the rest of the files of the card are left as they
+// are. With dryRun, the report is returned without changing the card.
+func Sync(staged, card string, dryRun bool) (*SyncReport, error) {
+	files, err := os.ReadDir(staged)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &SyncReport{}
+	names := make(map[string]bool, len(files))
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+
+		name := f.Name()
+		names[name] = true
+		same, err := sameFile(filepath.Join(staged, name), filepath.Join(card, name))
+		if err != nil {
+			return nil, err
+		}
+
+		if same {
+			r.Unchanged = append(r.Unchanged, name)
+			continue
+		}
+
+		r.Copied = append(r.Copied, name)
+		if dryRun {
+			continue
+		}
+
+		// the file is replaced once complete, so an interrupted sync
+		// doesn't leave a truncated file behind.
+		tmp := filepath.Join(card, ".sync-"+name)
+		if err := copyFile(filepath.Join(staged, name), tmp); err != nil {
+			os.Remove(tmp)
+			return nil, err
+		}
+
+		if err := os.Rename(tmp, filepath.Join(card, name)); err != nil {
+			return nil, err
+		}
+	}
+
+	wavs, err := wavFiles(card)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range wavs {
+		if names[name] {
+			continue
+		}
+
+		r.Removed = append(r.Removed, name)
+		if dryRun {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(card, name)); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(r.Removed)
+	return r, nil
+}
+
+// sameFile reports whether the files have the same size and content, false
+// if the second one doesn't exist.
+func sameFile(a, b string) (bool, error) {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+
+	ib, err := os.Stat(b)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	if err != nil || ia.Size() != ib.Size() {
+		return false, err
+	}
+
+	ha, err := Checksum(a)
+	if err != nil {
+		return false, err
+	}
+
+	hb, err := Checksum(b)
+	if err != nil {
+		return false, err
+	}
+
+	return ha == hb, nil
+}
@@ -0,0 +1,60 @@
+package card
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSync(t *testing.T) {
+	staged, mount := t.TempDir(), t.TempDir()
+	writeFiles(t, staged, "0001_door.wav", "0002_music.wav", "manifest.json")
+	writeFiles(t, mount, "0001_door.wav", "0002_old_music.wav", "notes.txt")
+
+	// same size, different content.
+	if err := os.WriteFile(filepath.Join(mount, "manifest.json"), []byte("manifest.jsoN"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Sync(staged, mount, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &SyncReport{
+		Copied:    []string{"0002_music.wav", "manifest.json"},
+		Removed:   []string{"0002_old_music.wav"},
+		Unchanged: []string{"0001_door.wav"},
+	}
+
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("unexpected report %+v", r)
+	}
+
+	if _, err := os.Stat(filepath.Join(mount, "0002_music.wav")); !os.IsNotExist(err) {
+		t.Error("a dry run shouldn't copy")
+	}
+
+	if r, err = Sync(staged, mount, false); err != nil || !reflect.DeepEqual(r, expected) {
+		t.Fatalf("unexpected report %+v, %v", r, err)
+	}
+
+	files, err := os.ReadDir(mount)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+
+	if expected := []string{"0001_door.wav", "0002_music.wav", "manifest.json", "notes.txt"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("unexpected files %v", names)
+	}
+
+	if r, err = Sync(staged, mount, false); err != nil || len(r.Copied) != 0 || len(r.Unchanged) != 3 {
+		t.Errorf("unexpected report %+v, %v", r, err)
+	}
+}
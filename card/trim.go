@@ -0,0 +1,103 @@
+package card
+
+import (
+	"math"
+	"os"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// DefaultThreshold is the level, in dBFS, below which the audio is
+// considered silent when trimming.
+const DefaultThreshold = -60
+
+// Trim removes the silence at the start and the end of the audio, so the
+// tracks respond right away when triggered.
+type Trim struct {
+	// Threshold is the level, in dBFS, below which the samples are
+	// silent, DefaultThreshold if zero.
+	Threshold tsunami.Gain
+	// Padding is the silence kept before and after the sound, so soft
+	// attacks and tails aren't cut.
+	Padding time.Duration
+	// KeepTrailing keeps the silence at the end, only trimming the start.
+	KeepTrailing bool
+}
+
+// Apply returns the audio trimmed, and the silence removed at the start and
+// at the end. Silent audio is left as is.
+func (tr Trim) Apply(a *Audio) (out *Audio, lead, tail time.Duration) {
+	threshold := tr.Threshold
+	if threshold == 0 {
+		threshold = DefaultThreshold
+	}
+
+	level := threshold.Linear()
+	frames := a.Frames()
+	loud := func(f int) bool {
+		for _, s := range a.Samples[f*a.Channels : (f+1)*a.Channels] {
+			if math.Abs(s) > level {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	start := 0
+	for start < frames && !loud(start) {
+		start++
+	}
+
+	if start == frames {
+		return a, 0, 0
+	}
+
+	end := frames
+	for !tr.KeepTrailing && !loud(end-1) {
+		end--
+	}
+
+	pad := int(tr.Padding * time.Duration(a.SampleRate) / time.Second)
+	if start -= pad; start < 0 {
+		start = 0
+	}
+
+	if end += pad; end > frames {
+		end = frames
+	}
+
+	if start == 0 && end == frames {
+		return a, 0, 0
+	}
+
+	out = &Audio{SampleRate: a.SampleRate, Channels: a.Channels, Samples: a.Samples[start*a.Channels : end*a.Channels]}
+	return out, a.duration(start), a.duration(frames - end)
+}
+
+func (a *Audio) duration(frames int) time.Duration {
+	return time.Duration(frames) * time.Second / time.Duration(a.SampleRate)
+}
+
+// TrimFile trims the WAV file in place, see Trim.Apply, rewriting it as a
+// 16-bit PCM file only if there was silence to remove.
+func (tr Trim) TrimFile(path string) (lead, tail time.Duration, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	a, err := DecodeWAV(f)
+	f.Close()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	out, lead, tail := tr.Apply(a)
+	if out == a {
+		return 0, 0, nil
+	}
+
+	return lead, tail, writeAudio(path, out)
+}
@@ -0,0 +1,83 @@
+package card
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrimApply(t *testing.T) {
+	// 0.5s of silence, 1s of sound and 0.25s of a faint hiss.
+	a := &Audio{SampleRate: 1000, Channels: 2, Samples: make([]float64, 2*1750)}
+	for f := 500; f < 1500; f++ {
+		a.Samples[2*f+1] = 0.5
+	}
+
+	for f := 1500; f < 1750; f++ {
+		a.Samples[2*f] = 0.0001
+	}
+
+	out, lead, tail := Trim{}.Apply(a)
+	if out.Frames() != 1000 || lead != 500*time.Millisecond || tail != 250*time.Millisecond {
+		t.Errorf("unexpected trim of %d frames, %s, %s", out.Frames(), lead, tail)
+	}
+
+	out, lead, tail = Trim{Padding: 10 * time.Millisecond, KeepTrailing: true}.Apply(a)
+	if out.Frames() != 1260 || lead != 490*time.Millisecond || tail != 0 {
+		t.Errorf("unexpected trim of %d frames, %s, %s", out.Frames(), lead, tail)
+	}
+
+	out, lead, tail = Trim{Threshold: -90}.Apply(a)
+	if out.Frames() != 1250 || lead != 500*time.Millisecond || tail != 0 {
+		t.Errorf("unexpected trim of %d frames, %s, %s", out.Frames(), lead, tail)
+	}
+
+	silent := &Audio{SampleRate: 1000, Channels: 1, Samples: make([]float64, 100)}
+	if out, _, _ := (Trim{}).Apply(silent); out != silent {
+		t.Error("silent audio should be left as is")
+	}
+}
+
+func TestBuilderTrim(t *testing.T) {
+	src := t.TempDir()
+	a := &Audio{SampleRate: 44100, Channels: 1, Samples: make([]float64, 44100)}
+	a.Samples[22050] = 1
+
+	var buf bytes.Buffer
+	if err := a.EncodeWAV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(src, "click.wav"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := t.TempDir()
+	b := &Builder{Source: src, Trim: &Trim{}, Mapping: []Entry{{Track: 1, File: "click.wav"}}}
+	m, err := b.Build(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Tracks[0].Trimmed < 0.99 {
+		t.Errorf("unexpected trimmed %v", m.Tracks[0].Trimmed)
+	}
+
+	f, err := os.Open(filepath.Join(dest, m.Tracks[0].File))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	w, err := ReadWAV(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Frames() != 1 {
+		t.Errorf("unexpected frames %d", w.Frames())
+	}
+}
@@ -0,0 +1,157 @@
+package card
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Requirements are the formats of the WAV files played by the board.
+type Requirements struct {
+	SampleRate    int
+	BitsPerSample int
+	// Channels is the number of channels, 0 accepts mono and stereo.
+	Channels int
+}
+
+var (
+	// Stereo are the requirements of the stereo firmware of the Tsunami.
+	Stereo = Requirements{SampleRate: 44100, BitsPerSample: 16, Channels: 2}
+	// Mono are the requirements of the mono firmware of the Tsunami.
+	Mono = Requirements{SampleRate: 44100, BitsPerSample: 16, Channels: 1}
+)
+
+// Check returns what's wrong with the WAV for the requirements, nothing if
+// the file can be played. Besides the format, the chunks other than fmt and
+// data before the samples, like the LIST or bext metadata written by the
+// audio editors, are reported, since they confuse the firmware.
+func (req Requirements) Check(w *WAV) []string {
+	var problems []string
+	switch w.AudioFormat {
+	case FormatPCM:
+	case FormatExtensible:
+		problems = append(problems, "WAVE_FORMAT_EXTENSIBLE header, expected plain PCM")
+	case FormatFloat:
+		problems = append(problems, "floating point samples, expected PCM")
+	default:
+		problems = append(problems, fmt.Sprintf("audio format %#x, expected PCM", w.AudioFormat))
+	}
+
+	if w.BitsPerSample != req.BitsPerSample {
+		problems = append(problems, fmt.Sprintf("%d-bit samples, expected %d-bit", w.BitsPerSample, req.BitsPerSample))
+	}
+
+	if w.SampleRate != req.SampleRate {
+		problems = append(problems, fmt.Sprintf("sample rate of %d Hz, expected %d Hz", w.SampleRate, req.SampleRate))
+	}
+
+	switch {
+	case req.Channels == 0 && (w.Channels < 1 || w.Channels > 2):
+		problems = append(problems, fmt.Sprintf("%d channels, expected mono or stereo", w.Channels))
+	case req.Channels != 0 && w.Channels != req.Channels:
+		problems = append(problems, fmt.Sprintf("%d channels, expected %d", w.Channels, req.Channels))
+	}
+
+	data := false
+	for _, id := range w.Chunks {
+		switch id {
+		case "data":
+			data = true
+		case "fmt ":
+		default:
+			if !data {
+				problems = append(problems, fmt.Sprintf("%q chunk before the samples", id))
+			}
+		}
+	}
+
+	if !data {
+		problems = append(problems, "missing data chunk")
+	}
+
+	if w.Truncated {
+		problems = append(problems, "truncated file")
+	}
+
+	if w.BlockAlign > 0 && w.DataSize%int64(w.BlockAlign) != 0 {
+		problems = append(problems, "data size not a multiple of the sample frames")
+	}
+
+	return problems
+}
+
+// Report is the result of the validation of a file.
+type Report struct {
+	File     string
+	Problems []string
+}
+
+// OK reports whether the file can be played.
+func (r Report) OK() bool {
+	return len(r.Problems) == 0
+}
+
+func (r Report) String() string {
+	if r.OK() {
+		return r.File + ": ok"
+	}
+
+	return r.File + ": " + strings.Join(r.Problems, ", ")
+}
+
+// ValidationError is returned by Builder.Build with the reports of the files
+// not meeting the requirements.
+type ValidationError struct {
+	Reports []Report
+}
+
+func (e *ValidationError) Error() string {
+	s := make([]string, len(e.Reports))
+	for i, r := range e.Reports {
+		s[i] = r.String()
+	}
+
+	return "invalid WAV files: " + strings.Join(s, "; ")
+}
+
+// ValidateFile checks the WAV file for the requirements.
+func (req Requirements) ValidateFile(path string) (Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Report{}, err
+	}
+
+	defer f.Close()
+
+	r := Report{File: path}
+	w, err := ReadWAV(f)
+	if err != nil {
+		r.Problems = []string{err.Error()}
+		return r, nil
+	}
+
+	r.Problems = req.Check(w)
+	return r, nil
+}
+
+// ValidateDir checks the WAV files of the directory and its subdirectories,
+// returning a report for each of them.
+func (req Requirements) ValidateDir(dir string) ([]Report, error) {
+	var reports []Report
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !isWAV(path) {
+			return err
+		}
+
+		r, err := req.ValidateFile(path)
+		if err != nil {
+			return err
+		}
+
+		reports = append(reports, r)
+		return nil
+	})
+
+	return reports, err
+}
@@ -0,0 +1,85 @@
+package card
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRequirementsCheck(t *testing.T) {
+	for _, tc := range []struct {
+		wav      []byte
+		expected []string
+	}{{
+		makeWAV(fmtChunk(FormatPCM, 2, 44100, 16), chunk{"data", make([]byte, 8)}),
+		nil,
+	}, {
+		makeWAV(fmtChunk(FormatExtensible, 1, 48000, 24), chunk{"data", make([]byte, 9)}),
+		[]string{
+			"WAVE_FORMAT_EXTENSIBLE header, expected plain PCM",
+			"24-bit samples, expected 16-bit",
+			"sample rate of 48000 Hz, expected 44100 Hz",
+			"1 channels, expected 2",
+		},
+	}, {
+		makeWAV(fmtChunk(FormatPCM, 2, 44100, 16), chunk{"bext", make([]byte, 4)}, chunk{"data", make([]byte, 6)}, chunk{"LIST", nil}),
+		[]string{`"bext" chunk before the samples`, "data size not a multiple of the sample frames"},
+	}, {
+		makeWAV(fmtChunk(FormatPCM, 2, 44100, 16)),
+		[]string{"missing data chunk"},
+	}} {
+		w, err := ReadWAV(bytes.NewReader(tc.wav))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if problems := Stereo.Check(w); !reflect.DeepEqual(problems, tc.expected) {
+			t.Errorf("unexpected problems %q", problems)
+		}
+	}
+}
+
+func TestBuilderRequire(t *testing.T) {
+	src := t.TempDir()
+	files := map[string][]byte{
+		"ok.wav":   makeWAV(fmtChunk(FormatPCM, 1, 44100, 16), chunk{"data", make([]byte, 4)}),
+		"hi.wav":   makeWAV(fmtChunk(FormatPCM, 1, 96000, 16), chunk{"data", make([]byte, 4)}),
+		"text.wav": []byte("not a wav"),
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(src, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reports, err := Mono.ValidateDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reports) != 3 {
+		t.Errorf("unexpected reports %v", reports)
+	}
+
+	b := &Builder{Source: src, Require: &Mono, Mapping: []Entry{
+		{Track: 1, File: "ok.wav"}, {Track: 2, File: "hi.wav"}, {Track: 3, File: "text.wav"},
+	}}
+
+	var verr *ValidationError
+	if _, err := b.Build(t.TempDir()); !errors.As(err, &verr) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []Report{
+		{File: "hi.wav", Problems: []string{"sample rate of 96000 Hz, expected 44100 Hz"}},
+		{File: "text.wav", Problems: []string{ErrNotWAV.Error()}},
+	}
+
+	if !reflect.DeepEqual(verr.Reports, expected) {
+		t.Errorf("unexpected reports %v", verr.Reports)
+	}
+}
@@ -0,0 +1,129 @@
+package card
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// The audio formats of the fmt chunk.
+const (
+	FormatPCM        = 1
+	FormatFloat      = 3
+	FormatExtensible = 0xfffe
+)
+
+// ErrNotWAV is returned when reading a file which isn't a RIFF WAVE file.
+var ErrNotWAV = errors.New("not a RIFF WAVE file")
+
+// WAV is the header of a WAV file.
+type WAV struct {
+	AudioFormat   int
+	Channels      int
+	SampleRate    int
+	BitsPerSample int
+	BlockAlign    int
+	// Chunks are the ids of the chunks of the file, in order.
+	Chunks []string
+	// DataOffset and DataSize are the position and size of the samples.
+	DataOffset int64
+	DataSize   int64
+	// Truncated is set when the file is shorter than its chunks claim.
+	Truncated bool
+}
+
+// Frames returns the number of sample frames of the file.
+func (w *WAV) Frames() int64 {
+	if w.BlockAlign == 0 {
+		return 0
+	}
+
+	return w.DataSize / int64(w.BlockAlign)
+}
+
+// Duration returns the duration of the samples.
+func (w *WAV) Duration() time.Duration {
+	if w.SampleRate == 0 {
+		return 0
+	}
+
+	return time.Duration(w.Frames()) * time.Second / time.Duration(w.SampleRate)
+}
+
+func (w *WAV) String() string {
+	return fmt.Sprintf("%d-bit %d Hz %d channels, %s", w.BitsPerSample, w.SampleRate, w.Channels, w.Duration())
+}
+
+// ReadWAV reads the header of a WAV file, walking its chunks.
+func ReadWAV(r io.ReadSeeker) (*WAV, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return nil, ErrNotWAV
+	}
+
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, ErrNotWAV
+	}
+
+	w := &WAV{}
+	fmtFound := false
+	for pos := int64(12); pos+8 <= size; {
+		var hdr [8]byte
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return nil, err
+		}
+
+		id, n := string(hdr[0:4]), int64(binary.LittleEndian.Uint32(hdr[4:8]))
+		w.Chunks = append(w.Chunks, id)
+		body := pos + 8
+		if body+n > size {
+			w.Truncated = true
+			n = size - body
+		}
+
+		switch id {
+		case "fmt ":
+			if n < 16 {
+				return nil, fmt.Errorf("invalid fmt chunk of %d bytes", n)
+			}
+
+			var f [16]byte
+			if _, err := io.ReadFull(r, f[:]); err != nil {
+				return nil, err
+			}
+
+			w.AudioFormat = int(binary.LittleEndian.Uint16(f[0:2]))
+			w.Channels = int(binary.LittleEndian.Uint16(f[2:4]))
+			w.SampleRate = int(binary.LittleEndian.Uint32(f[4:8]))
+			w.BlockAlign = int(binary.LittleEndian.Uint16(f[12:14]))
+			w.BitsPerSample = int(binary.LittleEndian.Uint16(f[14:16]))
+			fmtFound = true
+		case "data":
+			w.DataOffset, w.DataSize = body, n
+		}
+
+		// the chunks are padded to an even size.
+		pos = body + n + n%2
+	}
+
+	if !fmtFound {
+		return nil, errors.New("missing fmt chunk")
+	}
+
+	return w, nil
+}
@@ -0,0 +1,76 @@
+package card
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// chunk is a chunk of a WAV file built by makeWAV.
+type chunk struct {
+	id   string
+	data []byte
+}
+
+// fmtChunk returns the fmt chunk of the given format.
+func fmtChunk(format, channels, rate, bits int) chunk {
+	align := channels * bits / 8
+	data := make([]byte, 16)
+	binary.LittleEndian.PutUint16(data[0:], uint16(format))
+	binary.LittleEndian.PutUint16(data[2:], uint16(channels))
+	binary.LittleEndian.PutUint32(data[4:], uint32(rate))
+	binary.LittleEndian.PutUint32(data[8:], uint32(rate*align))
+	binary.LittleEndian.PutUint16(data[12:], uint16(align))
+	binary.LittleEndian.PutUint16(data[14:], uint16(bits))
+	return chunk{"fmt ", data}
+}
+
+// makeWAV returns a WAV file of the chunks.
+func makeWAV(chunks ...chunk) []byte {
+	var body bytes.Buffer
+	body.WriteString("WAVE")
+	for _, c := range chunks {
+		body.WriteString(c.id)
+		binary.Write(&body, binary.LittleEndian, uint32(len(c.data)))
+		body.Write(c.data)
+		if len(c.data)%2 == 1 {
+			body.WriteByte(0)
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(body.Len()))
+	b.Write(body.Bytes())
+	return b.Bytes()
+}
+
+func TestReadWAV(t *testing.T) {
+	data := makeWAV(
+		fmtChunk(FormatPCM, 2, 44100, 16),
+		chunk{"LIST", []byte("INFOx")},
+		chunk{"data", make([]byte, 44100*4)},
+	)
+
+	w, err := ReadWAV(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Channels != 2 || w.SampleRate != 44100 || w.BitsPerSample != 16 || w.Duration() != time.Second {
+		t.Errorf("unexpected header %s", w)
+	}
+
+	if w.DataOffset != 12+24+8+6+8 || w.Truncated {
+		t.Errorf("unexpected data offset %d", w.DataOffset)
+	}
+
+	if _, err := ReadWAV(bytes.NewReader(data[:len(data)-10])); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadWAV(bytes.NewReader([]byte("ID3 something"))); err != ErrNotWAV {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,356 @@
+// Package catalog describes the tracks of a card: their names, durations,
+// formats and loudness, plus the tags and notes of the metadata sidecars, so
+// the rest of the library, and the user interfaces, know what each track is.
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/card"
+)
+
+// Entry describes a track.
+type Entry struct {
+	Track      int
+	Name       string
+	File       string
+	Duration   time.Duration
+	Channels   int
+	SampleRate int
+	// Loudness is the integrated loudness in LUFS, nil if unknown.
+	Loudness *float64
+	// Output and Gain are the output and gain the track is played at by
+	// default, as written in its sidecar.
+	Output int
+	Gain   tsunami.Gain
+	Tags   []string
+	Notes  string
+}
+
+type entryJSON struct {
+	Track      int          `json:"track"`
+	Name       string       `json:"name,omitempty"`
+	File       string       `json:"file,omitempty"`
+	Duration   string       `json:"duration,omitempty"`
+	Channels   int          `json:"channels,omitempty"`
+	SampleRate int          `json:"sample_rate,omitempty"`
+	Loudness   *float64     `json:"loudness,omitempty"`
+	Output     int          `json:"output,omitempty"`
+	Gain       tsunami.Gain `json:"gain,omitempty"`
+	Tags       []string     `json:"tags,omitempty"`
+	Notes      string       `json:"notes,omitempty"`
+}
+
+// MarshalJSON writes the duration as a string, like "1m30.5s".
+func (e Entry) MarshalJSON() ([]byte, error) {
+	j := entryJSON{
+		Track: e.Track, Name: e.Name, File: e.File, Channels: e.Channels, SampleRate: e.SampleRate,
+		Loudness: e.Loudness, Output: e.Output, Gain: e.Gain, Tags: e.Tags, Notes: e.Notes,
+	}
+
+	if e.Duration > 0 {
+		j.Duration = e.Duration.String()
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	var j entryJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	var d time.Duration
+	if j.Duration != "" {
+		var err error
+		if d, err = time.ParseDuration(j.Duration); err != nil {
+			return fmt.Errorf("track %d: %w", j.Track, err)
+		}
+	}
+
+	*e = Entry{
+		Track: j.Track, Name: j.Name, File: j.File, Duration: d, Channels: j.Channels,
+		SampleRate: j.SampleRate, Loudness: j.Loudness, Output: j.Output, Gain: j.Gain,
+		Tags: j.Tags, Notes: j.Notes,
+	}
+
+	return nil
+}
+
+// HasTag reports whether the entry has the tag.
+func (e *Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Catalog is a set of entries by track number.
+type Catalog struct {
+	entries map[int]*Entry
+}
+
+var _ tsunami.TrackDurations = (*Catalog)(nil)
+
+// New returns a catalog of the entries.
+func New(entries ...Entry) *Catalog {
+	c := &Catalog{entries: make(map[int]*Entry, len(entries))}
+	for _, e := range entries {
+		c.Add(e)
+	}
+
+	return c
+}
+
+// Add adds an entry, replacing the one of the same track.
+func (c *Catalog) Add(e Entry) {
+	c.entries[e.Track] = &e
+}
+
+// Track returns the entry of the track.
+func (c *Catalog) Track(trk int) (Entry, bool) {
+	e, ok := c.entries[trk]
+	if !ok {
+		return Entry{}, false
+	}
+
+	return *e, true
+}
+
+// ByName returns the entry of the track with the name.
+func (c *Catalog) ByName(name string) (Entry, bool) {
+	for _, e := range c.entries {
+		if e.Name == name {
+			return *e, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// Tracks returns the entries, sorted by track.
+func (c *Catalog) Tracks() []Entry {
+	entries := make([]Entry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, *e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Track < entries[j].Track })
+	return entries
+}
+
+// Tagged returns the entries with the tag, sorted by track.
+func (c *Catalog) Tagged(tag string) []Entry {
+	var entries []Entry
+	for _, e := range c.Tracks() {
+		if e.HasTag(tag) {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// Duration implements tsunami.TrackDurations, so the catalog can be used by
+// Tsunami.SetDurations and the validation of the cue sheets.
+func (c *Catalog) Duration(trk int) (time.Duration, bool) {
+	e, ok := c.entries[trk]
+	if !ok || e.Duration == 0 {
+		return 0, false
+	}
+
+	return e.Duration, true
+}
+
+// Aliases returns the names of the tracks, see Tsunami.SetAliases.
+func (c *Catalog) Aliases() tsunami.Aliases {
+	a := make(tsunami.Aliases, len(c.entries))
+	for _, e := range c.entries {
+		if e.Name != "" {
+			a[e.Name] = e.Track
+		}
+	}
+
+	return a
+}
+
+// Gains returns the gain offset of the tracks to play at the target
+// loudness, see card.Manifest.Gains.
+func (c *Catalog) Gains(target float64) map[int]tsunami.Gain {
+	m := &card.Manifest{}
+	for _, e := range c.Tracks() {
+		m.Tracks = append(m.Tracks, card.Track{Track: e.Track, Loudness: e.Loudness})
+	}
+
+	return m.Gains(target)
+}
+
+// Save writes the catalog as JSON.
+func (c *Catalog) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Tracks []Entry `json:"tracks"`
+	}{c.Tracks()})
+}
+
+// Load reads a catalog written by Save.
+func Load(r io.Reader) (*Catalog, error) {
+	var j struct {
+		Tracks []Entry `json:"tracks"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&j); err != nil {
+		return nil, err
+	}
+
+	return New(j.Tracks...), nil
+}
+
+// Sidecar is the metadata of a track written by hand, in a JSON file next
+// to its WAV file with the same name, like 0012_door_creak.json:
+//
+//	{"tags": ["door", "act1"], "notes": "slam at 0.8s", "output": 2, "gain": -6}
+type Sidecar struct {
+	Tags   []string     `json:"tags,omitempty"`
+	Notes  string       `json:"notes,omitempty"`
+	Output int          `json:"output,omitempty"`
+	Gain   tsunami.Gain `json:"gain,omitempty"`
+}
+
+// Scan builds the catalog of a staged card, reading the WAV files named
+// after their tracks, their sidecars, and the manifest written by
+// card.Builder, manifest.json, if found. The files not named after a track
+// are ignored.
+func Scan(dir string) (*Catalog, error) {
+	var m *card.Manifest
+	if f, err := os.Open(filepath.Join(dir, "manifest.json")); err == nil {
+		m, err = card.ReadManifest(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("manifest.json: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	c := New()
+	for _, f := range files {
+		trk, name, ok := ParseFileName(f.Name())
+		if f.IsDir() || !ok {
+			continue
+		}
+
+		e, err := scanFile(dir, f.Name())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name(), err)
+		}
+
+		e.Track, e.Name = trk, name
+		c.Add(e)
+	}
+
+	if m != nil {
+		for _, t := range m.Tracks {
+			e, ok := c.entries[t.Track]
+			if !ok {
+				continue
+			}
+
+			if t.Name != "" {
+				e.Name = t.Name
+			}
+
+			e.Loudness = t.Loudness
+		}
+	}
+
+	return c, nil
+}
+
+// FromManifest returns the catalog of the tracks of a manifest, without
+// reading the card, so the durations and formats are unknown.
+func FromManifest(m *card.Manifest) *Catalog {
+	c := New()
+	for _, t := range m.Tracks {
+		c.Add(Entry{Track: t.Track, Name: t.Name, File: t.File, Loudness: t.Loudness})
+	}
+
+	return c
+}
+
+func scanFile(dir, name string) (Entry, error) {
+	e := Entry{File: name}
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return e, err
+	}
+
+	defer f.Close()
+
+	w, err := card.ReadWAV(f)
+	if err != nil {
+		return e, err
+	}
+
+	e.Duration, e.Channels, e.SampleRate = w.Duration(), w.Channels, w.SampleRate
+
+	sidecar := filepath.Join(dir, strings.TrimSuffix(name, filepath.Ext(name))+".json")
+	data, err := os.ReadFile(sidecar)
+	if os.IsNotExist(err) {
+		return e, nil
+	}
+
+	if err != nil {
+		return e, err
+	}
+
+	var s Sidecar
+	if err := json.Unmarshal(data, &s); err != nil {
+		return e, fmt.Errorf("%s: %w", filepath.Base(sidecar), err)
+	}
+
+	e.Tags, e.Notes, e.Output, e.Gain = s.Tags, s.Notes, s.Output, s.Gain
+	return e, nil
+}
+
+// ParseFileName returns the track and name of a WAV file named after its
+// track, like "0012_door_creak.wav", see card.FileName.
+func ParseFileName(file string) (trk int, name string, ok bool) {
+	ext := filepath.Ext(file)
+	if !strings.EqualFold(ext, ".wav") {
+		return 0, "", false
+	}
+
+	base := strings.TrimSuffix(file, ext)
+	digits := 0
+	for digits < len(base) && base[digits] >= '0' && base[digits] <= '9' {
+		digits++
+	}
+
+	trk, err := strconv.Atoi(base[:digits])
+	if err != nil || trk <= 0 || trk > tsunami.MaxTracks {
+		return 0, "", false
+	}
+
+	return trk, strings.TrimPrefix(base[digits:], "_"), true
+}
@@ -0,0 +1,133 @@
+package catalog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/card"
+)
+
+// stage builds a card with a track of one second and another of half.
+func stage(t *testing.T) string {
+	src, dest := t.TempDir(), t.TempDir()
+	for name, frames := range map[string]int{"door.wav": 44100, "music.wav": 22050} {
+		a := &card.Audio{SampleRate: 44100, Channels: 2, Samples: make([]float64, 2*frames)}
+		for i := range a.Samples {
+			a.Samples[i] = 0.1
+		}
+
+		var buf bytes.Buffer
+		if err := a.EncodeWAV(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(src, name), buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := &card.Builder{Source: src, Analyze: true, Mapping: []card.Entry{
+		{Track: 12, Name: "door_creak", File: "door.wav"},
+		{Track: 101, File: "music.wav"},
+	}}
+
+	m, err := b.Build(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Create(filepath.Join(dest, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+	if err := m.Save(f); err != nil {
+		t.Fatal(err)
+	}
+
+	sidecar := []byte(`{"tags": ["door", "act1"], "notes": "slam at 0.8s", "output": 2, "gain": -6}`)
+	if err := os.WriteFile(filepath.Join(dest, "0012_door_creak.json"), sidecar, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return dest
+}
+
+func TestScan(t *testing.T) {
+	c, err := Scan(stage(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, ok := c.ByName("door_creak")
+	if !ok || e.Track != 12 || e.Duration != time.Second || e.Channels != 2 || e.Notes != "slam at 0.8s" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+
+	if e.Loudness == nil {
+		t.Error("the loudness of the manifest should be used")
+	}
+
+	if d, ok := c.Duration(101); !ok || d != 500*time.Millisecond {
+		t.Errorf("unexpected duration %s", d)
+	}
+
+	if tagged := c.Tagged("act1"); len(tagged) != 1 || tagged[0].Track != 12 {
+		t.Errorf("unexpected tagged %v", tagged)
+	}
+
+	if a := c.Aliases(); !reflect.DeepEqual(a, tsunami.Aliases{"door_creak": 12, "music": 101}) {
+		t.Errorf("unexpected aliases %v", a)
+	}
+
+	if g := c.Gains(card.DefaultTarget); len(g) != 2 {
+		t.Errorf("unexpected gains %v", g)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	c, err := Scan(stage(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(loaded.Tracks(), c.Tracks()) {
+		t.Errorf("unexpected tracks %+v", loaded.Tracks())
+	}
+}
+
+func TestParseFileName(t *testing.T) {
+	for _, tc := range []struct {
+		file string
+		trk  int
+		name string
+		ok   bool
+	}{
+		{"0012_door_creak.wav", 12, "door_creak", true},
+		{"007.WAV", 7, "", true},
+		{"manifest.json", 0, "", false},
+		{"intro.wav", 0, "", false},
+		{"9999_x.wav", 0, "", false},
+	} {
+		trk, name, ok := ParseFileName(tc.file)
+		if trk != tc.trk || name != tc.name || ok != tc.ok {
+			t.Errorf("%s: unexpected %d, %q, %v", tc.file, trk, name, ok)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// Record is an entry as exported for external tools, like lighting desks,
+// documentation or front-ends, with the duration in seconds.
+type Record struct {
+	Track    int          `json:"track"`
+	File     string       `json:"file"`
+	Name     string       `json:"name"`
+	Duration float64      `json:"duration"`
+	Output   int          `json:"output"`
+	Gain     tsunami.Gain `json:"gain"`
+}
+
+// Records returns the records of the entries, sorted by track.
+func (c *Catalog) Records() []Record {
+	entries := c.Tracks()
+	records := make([]Record, len(entries))
+	for i, e := range entries {
+		records[i] = Record{
+			Track: e.Track, File: e.File, Name: e.Name, Duration: e.Duration.Seconds(),
+			Output: e.Output, Gain: e.Gain,
+		}
+	}
+
+	return records
+}
+
+// ExportJSON writes the records of the catalog as a JSON array.
+func (c *Catalog) ExportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c.Records())
+}
+
+// ExportCSV writes the records of the catalog as CSV, with a header row.
+func (c *Catalog) ExportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"track", "file", "name", "duration", "output", "gain"})
+	for _, r := range c.Records() {
+		cw.Write([]string{
+			strconv.Itoa(r.Track), r.File, r.Name,
+			strconv.FormatFloat(r.Duration, 'f', 3, 64),
+			strconv.Itoa(r.Output),
+			strconv.FormatFloat(float64(r.Gain), 'f', -1, 64),
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// FromAliases returns a catalog of the named tracks of the aliases, so the
+// registry of a board can be exported, see Tsunami.SetAliases. The tracks
+// with several names get the first one alphabetically.
+func FromAliases(a tsunami.Aliases) *Catalog {
+	c := New()
+	for _, trk := range a {
+		if _, ok := c.entries[trk]; ok {
+			continue
+		}
+
+		name, _ := a.Name(trk)
+		c.Add(Entry{Track: trk, Name: name})
+	}
+
+	return c
+}
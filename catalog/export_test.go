@@ -0,0 +1,47 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+func TestExportCSV(t *testing.T) {
+	c, err := Scan(stage(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := c.ExportCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "track,file,name,duration,output,gain\n" +
+		"12,0012_door_creak.wav,door_creak,1.000,2,-6\n" +
+		"101,0101_music.wav,music,0.500,0,0\n"
+
+	if buf.String() != expected {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	c := FromAliases(tsunami.Aliases{"door": 12, "door_creak": 12, "music": 101})
+
+	var buf bytes.Buffer
+	if err := c.ExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []Record
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 2 || records[0].Name != "door" || records[1].Track != 101 {
+		t.Errorf("unexpected records %+v", records)
+	}
+}
@@ -0,0 +1,144 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// DefaultProbeTimeout is how long a probed track has to start playing
+// before it's considered missing.
+const DefaultProbeTimeout = 250 * time.Millisecond
+
+// VerifyConfig is the configuration of VerifyCard.
+type VerifyConfig struct {
+	// Tracks are the tracks probed, like the ones referenced by a cue
+	// sheet, every track of the catalog if nil.
+	Tracks []int
+	// Output is the output the tracks are probed on.
+	Output int
+	// Timeout is how long a track has to start playing, DefaultProbeTimeout
+	// if zero.
+	Timeout time.Duration
+}
+
+// Verification is the result of VerifyCard.
+type Verification struct {
+	// NumTracks is the number of tracks of the card, as reported by the
+	// board, and Cataloged the ones of the catalog.
+	NumTracks int
+	Cataloged int
+	// Missing are the tracks probed which didn't play.
+	Missing []int
+	// Gaps are the ranges of track numbers missing from the catalog, from
+	// track 1 to the last track of the catalog.
+	Gaps [][2]int
+}
+
+// OK reports whether the card matches the catalog: the same number of tracks
+// and no track missing. The gaps are reported but they're not an error.
+func (v *Verification) OK() bool {
+	return v.NumTracks == v.Cataloged && len(v.Missing) == 0
+}
+
+func (v *Verification) String() string {
+	var problems []string
+	if v.NumTracks != v.Cataloged {
+		problems = append(problems, fmt.Sprintf("the card has %d tracks, the catalog %d", v.NumTracks, v.Cataloged))
+	}
+
+	if len(v.Missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing tracks %v", v.Missing))
+	}
+
+	if len(problems) == 0 {
+		return "ok"
+	}
+
+	return strings.Join(problems, ", ")
+}
+
+// VerifyCard compares the catalog with the card of the board before the
+// show: the number of tracks reported by the board, and whether the tracks
+// exist, probing them. A track is probed by playing it muted, with the
+// reporting enabled, and waiting for it to be reported as playing. The
+// tracks probed are stopped and left with the gain they had, if known, or
+// 0, so it shouldn't be used while the board is playing.
+func VerifyCard(p tsunami.Player, c *Catalog, cfg VerifyConfig) (*Verification, error) {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = DefaultProbeTimeout
+	}
+
+	tracks := cfg.Tracks
+	if tracks == nil {
+		for _, e := range c.Tracks() {
+			tracks = append(tracks, e.Track)
+		}
+	}
+
+	v := &Verification{NumTracks: p.GetNumTracks(), Cataloged: len(c.entries), Gaps: c.gaps()}
+	if err := p.SetReporting(true); err != nil {
+		return nil, err
+	}
+
+	for _, trk := range tracks {
+		ok, err := probe(p, trk, cfg.Output, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("track %d: %w", trk, err)
+		}
+
+		if !ok {
+			v.Missing = append(v.Missing, trk)
+		}
+	}
+
+	return v, nil
+}
+
+// probe plays the track muted, reporting whether it started.
+func probe(p tsunami.Player, trk, out int, timeout time.Duration) (bool, error) {
+	var gain tsunami.Gain
+	if g, ok := p.(interface{ TrackGainOf(int) tsunami.Gain }); ok {
+		gain = g.TrackGainOf(trk)
+	}
+
+	if err := p.TrackGain(trk, tsunami.MinGain); err != nil {
+		return false, err
+	}
+
+	if err := p.TrackPlayPoly(trk, out, false); err != nil {
+		return false, err
+	}
+
+	playing := false
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if playing = p.IsTrackPlaying(trk); playing {
+			break
+		}
+	}
+
+	if err := p.TrackStop(trk); err != nil {
+		return false, err
+	}
+
+	return playing, p.TrackGain(trk, gain)
+}
+
+// gaps returns the ranges of track numbers not in the catalog, up to its
+// last track.
+func (c *Catalog) gaps() [][2]int {
+	var gaps [][2]int
+	next := 1
+	for _, e := range c.Tracks() {
+		if e.Track > next {
+			gaps = append(gaps, [2]int{next, e.Track - 1})
+		}
+
+		next = e.Track + 1
+	}
+
+	return gaps
+}
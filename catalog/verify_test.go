@@ -0,0 +1,69 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestVerifyCard(t *testing.T) {
+	// a board with tracks 1, 2 and 5 on its card, reporting them as playing
+	// when played.
+	exists := map[uint16]bool{1: true, 2: true, 5: true}
+	port := transport.NewLoopback(func(f protocol.Frame) []protocol.Message {
+		m, err := protocol.Decode(f)
+		if err != nil {
+			return nil
+		}
+
+		switch m := m.(type) {
+		case *protocol.GetSysInfo:
+			return []protocol.Message{&protocol.SystemInfo{Voices: 18, Tracks: uint16(len(exists))}}
+		case *protocol.TrackControl:
+			if m.Code == tsunami.TRK_PLAY_POLY && exists[m.Track] {
+				return []protocol.Message{&protocol.TrackReport{Track: m.Track, Playing: true}}
+			}
+		}
+
+		return nil
+	})
+
+	ts := tsunami.NewTsunamiTransport(port)
+	if err := ts.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	ts.TrackGain(5, -6)
+
+	c := New(Entry{Track: 1}, Entry{Track: 2}, Entry{Track: 5}, Entry{Track: 9})
+	v, err := VerifyCard(ts, c, VerifyConfig{Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Verification{NumTracks: 3, Cataloged: 4, Missing: []int{9}, Gaps: [][2]int{{3, 4}, {6, 8}}}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("unexpected verification %+v", v)
+	}
+
+	if v.OK() || v.String() != "the card has 3 tracks, the catalog 4, missing tracks [9]" {
+		t.Errorf("unexpected result %q", v)
+	}
+
+	if g := ts.TrackGainOf(5); g != -6 {
+		t.Errorf("the gain should be restored, got %s", g)
+	}
+
+	v, err = VerifyCard(ts, New(Entry{Track: 1}, Entry{Track: 2}, Entry{Track: 5}), VerifyConfig{Tracks: []int{5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.OK() {
+		t.Errorf("unexpected result %q", v)
+	}
+}
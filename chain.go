@@ -0,0 +1,137 @@
+package tsunami
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// Chain is a long program split into several tracks played one after the
+// other, see PlayChain.
+type Chain struct {
+	t      *Tsunami
+	tracks []int
+	out    int
+
+	mu      sync.Mutex
+	current int
+	err     error
+	done    chan struct{}
+	cancel  func()
+}
+
+// PlayChain plays the given tracks one after the other on the output, with no
+// gap between them, providing a program longer than a single WAV file. While
+// a track plays the next one is loaded paused, and it's resumed in sync as
+// soon as the report of the end of the current one is received, see
+// Subscribe for the requirements of the track reports.
+func (t *Tsunami) PlayChain(tracks []int, out int) (*Chain, error) {
+	if len(tracks) == 0 {
+		return nil, errors.New("empty chain")
+	}
+
+	c := &Chain{t: t, tracks: tracks, out: out, done: make(chan struct{})}
+	if err := t.TrackPlayPoly(tracks[0], out, false); err != nil {
+		return nil, err
+	}
+
+	if len(tracks) > 1 {
+		if err := t.TrackLoad(tracks[1], out, false); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	c.cancel = t.Subscribe(c.handle)
+	c.mu.Unlock()
+
+	return c, nil
+}
+
+// Current returns the index of the track playing.
+func (c *Chain) Current() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.current
+}
+
+// Done returns a channel closed when the last track ends or the chain is
+// stopped.
+func (c *Chain) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error that interrupted the chain, if any.
+func (c *Chain) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// Stop stops the track playing and the one loaded next.
+func (c *Chain) Stop() error {
+	c.mu.Lock()
+	if c.finished() {
+		c.mu.Unlock()
+		return nil
+	}
+
+	cur := c.current
+	c.end()
+	c.mu.Unlock()
+
+	if err := c.t.TrackStop(c.tracks[cur]); err != nil {
+		return err
+	}
+
+	if cur+1 < len(c.tracks) {
+		return c.t.TrackStop(c.tracks[cur+1])
+	}
+
+	return nil
+}
+
+func (c *Chain) finished() bool {
+	return c.current >= len(c.tracks)
+}
+
+// end marks the chain as finished and unsubscribes it, it's called with the
+// lock held.
+func (c *Chain) end() {
+	c.current = len(c.tracks)
+	close(c.done)
+	c.cancel()
+}
+
+func (c *Chain) handle(msg protocol.Message) {
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok || r.Playing {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.finished() || int(r.Track) != c.tracks[c.current] {
+		return
+	}
+
+	c.current++
+	if c.finished() {
+		c.end()
+		return
+	}
+
+	err := c.t.ResumeAllInSync()
+	if err == nil && c.current+1 < len(c.tracks) {
+		err = c.t.TrackLoad(c.tracks[c.current+1], c.out, false)
+	}
+
+	if err != nil {
+		c.err = err
+		c.end()
+	}
+}
@@ -0,0 +1,72 @@
+package tsunami
+
+import (
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestPlayChain(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+
+	c, err := ts.PlayChain([]int{1, 2, 3}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	end := func(trk uint16) {
+		port.Push(&protocol.TrackReport{Track: trk, Voice: 0, Playing: false})
+		if err := ts.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	end(2) // not the current track
+	end(1)
+	if c.Current() != 1 {
+		t.Errorf("unexpected current %d", c.Current())
+	}
+
+	end(2)
+	end(3)
+
+	select {
+	case <-c.Done():
+	default:
+		t.Fatalf("chain should be done")
+	}
+
+	if len(ts.handlers) != 0 {
+		t.Errorf("the chain should be unsubscribed when done")
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, m := range msgs {
+		got = append(got, protocol.Describe(m))
+	}
+
+	expected := []string{
+		protocol.Describe(&protocol.TrackControl{Code: TRK_PLAY_POLY, Track: 1}),
+		protocol.Describe(&protocol.TrackControl{Code: TRK_LOAD, Track: 2}),
+		protocol.Describe(&protocol.ResumeAllSync{}),
+		protocol.Describe(&protocol.TrackControl{Code: TRK_LOAD, Track: 3}),
+		protocol.Describe(&protocol.ResumeAllSync{}),
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("unexpected commands %q", got)
+	}
+
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("%d: got %q, expected %q", i, got[i], expected[i])
+		}
+	}
+}
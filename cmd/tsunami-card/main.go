@@ -0,0 +1,383 @@
+// Command tsunami-card prepares the microSD card of the board from a
+// directory of arbitrarily named WAV files.
+//
+// Usage:
+//
+//	tsunami-card build [-digits n] [-clean] [-format f [-convert]] [-trim [-threshold db] [-padding d]] [-analyze] [-manifest file] mapping source dest
+//	tsunami-card validate [-format f] file-or-dir...
+//	tsunami-card loudness [-target lufs] file-or-dir...
+//	tsunami-card sync [-n] staged mount
+//	tsunami-card verify [-manifest file] mount
+//	tsunami-card export [-format json|csv] dir
+//
+// build stages the files of the source directory listed in the mapping file
+// into the destination, renamed after their tracks, and writes the manifest
+// of the card, dest/manifest.json by default. See the card package for the
+// format of the mapping.
+//
+// validate checks the WAV files, or the ones in the directories, can be played
+// by the board, reporting what's wrong with each of them. The format is
+// "stereo" or "mono", after the firmware of the board, or "any". With build,
+// the source files are validated when it's given, and the invalid ones are
+// converted with -convert, by ffmpeg if found in the PATH or in pure Go
+// otherwise.
+//
+// With build, -trim removes the silence at the start and the end of the
+// tracks, below the threshold in dBFS, keeping the padding.
+//
+// loudness prints the integrated loudness of the WAV files, and the gain
+// offset to play them at the target loudness. With build, -analyze stores
+// the loudness of the tracks in the manifest, for Manifest.Gains.
+//
+// sync updates the card mounted at mount with the files staged by build,
+// copying only the new or changed ones and removing the WAV files no longer
+// staged. With -n it only prints what would be done.
+//
+// verify re-hashes the tracks of the card mounted at mount against the
+// checksums of the manifest written by build, mount/manifest.json by
+// default, proving the card matches the approved content.
+//
+// export prints the tracks of the card staged in dir, as JSON or CSV, with
+// their files, names, durations and default outputs and gains, for external
+// tools. See the catalog package for the sidecars of the tracks.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/card"
+	"github.com/mcuadros/go-tsunami/catalog"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s build [flags] mapping source dest\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s validate [flags] file-or-dir...\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s loudness [flags] file-or-dir...\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s sync [flags] staged mount\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s verify [flags] mount\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s export [flags] dir\n", os.Args[0])
+	}
+
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "build":
+		err = build(os.Stdout, args)
+	case "validate":
+		err = validate(os.Stdout, args)
+	case "loudness":
+		err = loudness(os.Stdout, args)
+	case "sync":
+		err = sync(os.Stdout, args)
+	case "verify":
+		err = verify(os.Stdout, args)
+	case "export":
+		err = export(os.Stdout, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func build(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	digits := fs.Int("digits", card.DefaultDigits, "width of the track numbers, 3 for the WAV Trigger")
+	clean := fs.Bool("clean", false, "remove the WAV files of the destination first")
+	manifest := fs.String("manifest", "", "manifest file, dest/manifest.json by default")
+	format := fs.String("format", "", "validate the source files: stereo, mono or any")
+	convert := fs.Bool("convert", false, "convert the source files not meeting the format")
+	analyze := fs.Bool("analyze", false, "measure the loudness of the tracks")
+	trim := fs.Bool("trim", false, "trim the silence at the start and the end of the tracks")
+	threshold := fs.Float64("threshold", card.DefaultThreshold, "level of the silence trimmed, in dBFS")
+	padding := fs.Duration("padding", 0, "silence kept when trimming")
+	fs.Parse(args)
+
+	if fs.NArg() != 3 {
+		return fmt.Errorf("expected mapping, source and dest, got %d arguments", fs.NArg())
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	mapping, err := card.ReadMapping(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", fs.Arg(0), err)
+	}
+
+	b := &card.Builder{Source: fs.Arg(1), Mapping: mapping, Digits: *digits, Clean: *clean, Analyze: *analyze}
+	if *trim {
+		b.Trim = &card.Trim{Threshold: tsunami.Gain(*threshold), Padding: *padding}
+	}
+
+	if *format != "" {
+		req, err := requirements(*format)
+		if err != nil {
+			return err
+		}
+
+		b.Require = &req
+		if *convert {
+			b.Convert = card.DefaultConverter()
+		}
+	}
+
+	m, err := b.Build(fs.Arg(2))
+	if err != nil {
+		return err
+	}
+
+	if *manifest == "" {
+		*manifest = filepath.Join(fs.Arg(2), "manifest.json")
+	}
+
+	out, err := os.Create(*manifest)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Save(out); err != nil {
+		out.Close()
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	for _, t := range m.Tracks {
+		var notes []string
+		if t.Converted {
+			notes = append(notes, "converted")
+		}
+
+		if t.Trimmed > 0 {
+			notes = append(notes, fmt.Sprintf("trimmed %.3fs", t.Trimmed))
+		}
+
+		if len(notes) > 0 {
+			fmt.Fprintf(w, "%s <- %s (%s)\n", t.File, t.Source, strings.Join(notes, ", "))
+			continue
+		}
+
+		fmt.Fprintf(w, "%s <- %s\n", t.File, t.Source)
+	}
+
+	for _, f := range m.Unmapped {
+		fmt.Fprintf(w, "unmapped: %s\n", f)
+	}
+
+	return nil
+}
+
+func validate(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	format := fs.String("format", "any", "format of the firmware: stereo, mono or any")
+	fs.Parse(args)
+
+	req, err := requirements(*format)
+	if err != nil {
+		return err
+	}
+
+	invalid := 0
+	for _, path := range fs.Args() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		var reports []card.Report
+		if info.IsDir() {
+			reports, err = req.ValidateDir(path)
+		} else {
+			var r card.Report
+			r, err = req.ValidateFile(path)
+			reports = []card.Report{r}
+		}
+
+		if err != nil {
+			return err
+		}
+
+		for _, r := range reports {
+			fmt.Fprintln(w, r)
+			if !r.OK() {
+				invalid++
+			}
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d invalid files", invalid)
+	}
+
+	return nil
+}
+
+func loudness(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("loudness", flag.ExitOnError)
+	target := fs.Float64("target", card.DefaultTarget, "target loudness, in LUFS")
+	fs.Parse(args)
+
+	var files []string
+	for _, path := range fs.Args() {
+		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() && strings.EqualFold(filepath.Ext(path), ".wav") {
+				files = append(files, path)
+			}
+
+			return err
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, path := range files {
+		l, err := card.FileLoudness(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if math.IsInf(l, -1) {
+			fmt.Fprintf(w, "%s: silent\n", path)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s: %.1f LUFS, gain %+.0f dB\n", path, l, math.Round(*target-l))
+	}
+
+	return nil
+}
+
+func sync(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	dryRun := fs.Bool("n", false, "print what would be done, without changing the card")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		return fmt.Errorf("expected staged and mount, got %d arguments", fs.NArg())
+	}
+
+	r, err := card.Sync(fs.Arg(0), fs.Arg(1), *dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range r.Copied {
+		fmt.Fprintf(w, "copy: %s\n", f)
+	}
+
+	for _, f := range r.Removed {
+		fmt.Fprintf(w, "remove: %s\n", f)
+	}
+
+	fmt.Fprintf(w, "%d copied, %d removed, %d unchanged\n", len(r.Copied), len(r.Removed), len(r.Unchanged))
+	return nil
+}
+
+func verify(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	manifest := fs.String("manifest", "", "manifest file, mount/manifest.json by default")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected mount, got %d arguments", fs.NArg())
+	}
+
+	if *manifest == "" {
+		*manifest = filepath.Join(fs.Arg(0), "manifest.json")
+	}
+
+	f, err := os.Open(*manifest)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	m, err := card.ReadManifest(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *manifest, err)
+	}
+
+	reports, err := m.Verify(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	invalid := 0
+	for _, r := range reports {
+		fmt.Fprintln(w, r)
+		if !r.OK() {
+			invalid++
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d files don't match the manifest", invalid)
+	}
+
+	return nil
+}
+
+func export(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "json", "format of the export: json or csv")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected dir, got %d arguments", fs.NArg())
+	}
+
+	c, err := catalog.Scan(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		return c.ExportJSON(w)
+	case "csv":
+		return c.ExportCSV(w)
+	}
+
+	return fmt.Errorf("unknown format %q", *format)
+}
+
+func requirements(format string) (card.Requirements, error) {
+	switch format {
+	case "stereo":
+		return card.Stereo, nil
+	case "mono":
+		return card.Mono, nil
+	case "any":
+		req := card.Stereo
+		req.Channels = 0
+		return req, nil
+	}
+
+	return card.Requirements{}, fmt.Errorf("unknown format %q", format)
+}
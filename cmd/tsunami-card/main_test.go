@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/card"
+)
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	src, dest := filepath.Join(dir, "src"), filepath.Join(dir, "card")
+	mapping := filepath.Join(dir, "mapping.json")
+
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(src, "Thunder.wav"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(mapping, []byte(`[{"track": 3, "file": "Thunder.wav"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := bytes.NewBuffer(nil)
+	if err := build(out, []string{"-digits", "3", mapping, src, dest}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "003_thunder.wav <- Thunder.wav\n") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	f, err := os.Open(filepath.Join(dest, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	m, err := card.ReadManifest(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if trk, err := m.Aliases().Track("thunder"); err != nil || trk != 3 {
+		t.Errorf("unexpected track %d, %v", trk, err)
+	}
+
+	out.Reset()
+	if err := verify(out, []string{dest}); err != nil || out.String() != "003_thunder.wav: ok\n" {
+		t.Errorf("unexpected output %q, %v", out, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dest, "003_thunder.wav"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verify(out, []string{dest}); err == nil {
+		t.Error("a changed file should fail")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.wav"), []byte("not a wav"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := bytes.NewBuffer(nil)
+	if err := validate(out, []string{"-format", "mono", dir}); err == nil {
+		t.Error("invalid files should fail")
+	}
+
+	if !strings.Contains(out.String(), "a.wav: "+card.ErrNotWAV.Error()) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestSync(t *testing.T) {
+	staged, mount := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(staged, "0001_door.wav"), []byte("door"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(mount, "0002_old.wav"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := bytes.NewBuffer(nil)
+	if err := sync(out, []string{staged, mount}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "copy: 0001_door.wav\nremove: 0002_old.wav\n1 copied, 1 removed, 0 unchanged\n"
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
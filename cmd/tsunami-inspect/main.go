@@ -0,0 +1,150 @@
+// Command tsunami-inspect decodes a capture recorded with the capture
+// package, printing every frame with its timing and any protocol error found.
+//
+// Usage:
+//
+//	tsunami-inspect [-raw] [capture-file]
+//
+// The capture is read from stdin if no file is given.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/capture"
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+func main() {
+	raw := flag.Bool("raw", false, "print the raw bytes of every frame")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-raw] [capture-file]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	in := io.Reader(os.Stdin)
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fatal(err)
+		}
+
+		defer f.Close()
+		in = f
+	}
+
+	if err := inspect(os.Stdout, in, *raw); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
+
+// stream decodes one direction of the capture.
+type stream struct {
+	dir     capture.Direction
+	decoder *protocol.Decoder
+	frames  int
+	last    time.Duration
+}
+
+func inspect(w io.Writer, in io.Reader, raw bool) error {
+	r := capture.NewReader(in)
+	streams := map[capture.Direction]*stream{
+		capture.TX: {dir: capture.TX, decoder: protocol.NewDecoder()},
+		capture.RX: {dir: capture.RX, decoder: protocol.NewDecoder()},
+	}
+
+	var start time.Time
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if start.IsZero() {
+			start = rec.Time
+		}
+
+		s := streams[rec.Direction]
+		s.decoder.Feed(rec.Data)
+		s.flush(w, rec.Time.Sub(start), raw)
+	}
+
+	for _, dir := range []capture.Direction{capture.TX, capture.RX} {
+		s := streams[dir]
+		if n := s.decoder.Buffered(); n > 0 {
+			fmt.Fprintf(w, "%s: %d bytes of incomplete frame at end of capture\n", dir, n)
+		}
+
+		printStats(w, dir, s.decoder.Stats())
+	}
+
+	return nil
+}
+
+func (s *stream) flush(w io.Writer, elapsed time.Duration, raw bool) {
+	for {
+		discarded := s.decoder.Stats().Discarded
+		f, err := s.decoder.NextFrame()
+		if n := s.decoder.Stats().Discarded - discarded; n > 0 {
+			fmt.Fprintf(w, "%12s %s error: discarded %d bytes while resyncing\n",
+				formatElapsed(elapsed), s.dir, n,
+			)
+		}
+
+		if err == io.EOF {
+			return
+		}
+
+		var delta time.Duration
+		if s.frames > 0 {
+			delta = elapsed - s.last
+		}
+
+		s.frames++
+		s.last = elapsed
+
+		fmt.Fprintf(w, "%12s %s (+%s) ", formatElapsed(elapsed), s.dir, delta)
+		if raw {
+			b, _ := f.MarshalBinary()
+			fmt.Fprintf(w, "[% x] ", b)
+		}
+
+		m, err := s.decoder.Decode(f)
+		if err != nil {
+			fmt.Fprintf(w, "%s error: %s\n", protocol.CodeString(f.Code), err)
+			continue
+		}
+
+		fmt.Fprintln(w, protocol.Describe(m))
+	}
+}
+
+func formatElapsed(d time.Duration) string {
+	return fmt.Sprintf("%.6fs", d.Seconds())
+}
+
+func printStats(w io.Writer, dir capture.Direction, s protocol.Stats) {
+	var frames int
+	for _, n := range s.Frames {
+		frames += n
+	}
+
+	fmt.Fprintf(w, "%s: %d frames, %d resyncs, %d bytes discarded, %d bad length, %d bad end, %d malformed, %d unknown\n",
+		dir, frames, s.Resyncs, s.Discarded, s.BadLength, s.BadEnd, s.Malformed, s.Unknown,
+	)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	in := strings.NewReader(`2021-09-01T10:00:00Z TX f0aa050155
+2021-09-01T10:00:00.012Z RX 00f0aa08820e00
+2021-09-01T10:00:00.015Z RX 1055
+`)
+
+	out := bytes.NewBuffer(nil)
+	if err := inspect(out, in, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"0.000000s TX (+0s) CMD_GET_VERSION\n",
+		"0.012000s RX error: discarded 1 bytes while resyncing\n",
+		"0.015000s RX (+0s) RSP_SYSTEM_INFO {Voices:14 Tracks:4096}\n",
+		"RX: 1 frames, 1 resyncs, 1 bytes discarded",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("output doesn't contain %q:\n%s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+//go:build !js && !tinygo
+
+// Command tsunami-soundboard fires the tracks of a board from the keys of
+// the terminal, showing the tracks playing, see the soundboard package.
+//
+// Usage:
+//
+//	tsunami-soundboard [-bindings file] [-out n] [-aliases file] port
+//	tsunami-soundboard [-bindings file] [-out n] [-aliases file] -tcp host:port
+//
+// Without bindings, the keys of the keyboard rows, from 1 to m, play the
+// first 36 tracks on the output.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/card"
+	"github.com/mcuadros/go-tsunami/soundboard"
+)
+
+func main() {
+	file := flag.String("bindings", "", "JSON file with the keys bound to tracks")
+	out := flag.Int("out", 0, "output of the default bindings")
+	aliases := flag.String("aliases", "", "manifest.json of the card, to show the names of the tracks")
+	tcp := flag.String("tcp", "", "address of a TCP-to-serial bridge, instead of a port")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] port\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s [flags] -tcp host:port\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+	if (flag.NArg() == 1) == (*tcp != "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	bindings, err := load(*file, *out)
+	if err != nil {
+		fatal(err)
+	}
+
+	var ts *tsunami.Tsunami
+	if *tcp != "" {
+		ts, err = tsunami.NewTsunamiTCP(*tcp)
+	} else {
+		ts, err = tsunami.NewTsunamiPort(flag.Arg(0))
+	}
+
+	if err != nil {
+		fatal(err)
+	}
+
+	defer ts.Close()
+	if err := setup(ts, *aliases); err != nil {
+		fatal(err)
+	}
+
+	s, err := soundboard.New(ts, bindings...)
+	if err != nil {
+		fatal(err)
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ts.Listen(ctx)
+
+	restore, err := soundboard.MakeRaw(os.Stdin.Fd())
+	if errors.Is(err, soundboard.ErrNotTerminal) {
+		fmt.Fprintln(os.Stderr, "warning: stdin is not a terminal, keys are read after enter")
+		restore = func() error { return nil }
+	} else if err != nil {
+		fatal(err)
+	}
+
+	err = s.Run(ctx, os.Stdin, os.Stdout)
+	restore()
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// load returns the bindings of the file, or the default ones on the output
+// if empty.
+func load(file string, out int) ([]soundboard.Binding, error) {
+	if file == "" {
+		return soundboard.DefaultBindings(out), nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+	b, err := soundboard.LoadBindings(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return b, nil
+}
+
+// setup starts the board, enabling the reporting, and sets the aliases of
+// the manifest, if any.
+func setup(ts *tsunami.Tsunami, manifest string) error {
+	if err := ts.Start(); err != nil {
+		return err
+	}
+
+	if err := ts.SetReporting(true); err != nil {
+		return err
+	}
+
+	if manifest == "" {
+		return nil
+	}
+
+	f, err := os.Open(manifest)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	m, err := card.ReadManifest(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", manifest, err)
+	}
+
+	ts.SetAliases(m.Aliases())
+	return nil
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
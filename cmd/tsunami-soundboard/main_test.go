@@ -0,0 +1,36 @@
+//go:build !js && !tinygo
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/soundboard"
+)
+
+func TestLoad(t *testing.T) {
+	b, err := load("", 2)
+	if err != nil || len(b) != len(soundboard.DefaultKeys) || b[0] != (soundboard.Binding{Key: "1", Track: 1, Output: 2}) {
+		t.Errorf("unexpected default bindings %v, %v", b, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "bindings.json")
+	if err := os.WriteFile(file, []byte(`[{"key": "a", "track": 19, "label": "door"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = load(file, 0)
+	if err != nil || len(b) != 1 || b[0] != (soundboard.Binding{Key: "a", Track: 19, Label: "door"}) {
+		t.Errorf("unexpected bindings %v, %v", b, err)
+	}
+
+	if err := os.WriteFile(file, []byte(`{`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := load(file, 0); err == nil {
+		t.Error("invalid bindings should fail")
+	}
+}
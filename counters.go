@@ -0,0 +1,69 @@
+package tsunami
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Counters are the runtime counters of a connection, for a quick picture of
+// its health, see Tsunami.Counters.
+type Counters struct {
+	// Sent and Received are the frames written to and decoded from the
+	// board.
+	Sent     int64
+	Received int64
+	// WriteErrors and ReadErrors are the failures writing to the board, and
+	// the errors returned by Update.
+	WriteErrors int64
+	ReadErrors  int64
+	// Uptime is the time since the connection was created.
+	Uptime time.Duration
+	// LastEvent is the time of the last frame received, zero if none.
+	LastEvent time.Time
+}
+
+type counters struct {
+	created     time.Time
+	sent        atomic.Int64
+	received    atomic.Int64
+	writeErrors atomic.Int64
+	readErrors  atomic.Int64
+	lastEvent   atomic.Int64
+}
+
+func (c *counters) written(err error) {
+	if err != nil {
+		c.writeErrors.Add(1)
+		return
+	}
+
+	c.sent.Add(1)
+}
+
+func (c *counters) read(frames int, err error) {
+	if err != nil {
+		c.readErrors.Add(1)
+	}
+
+	if frames > 0 {
+		c.received.Add(int64(frames))
+		c.lastEvent.Store(time.Now().UnixNano())
+	}
+}
+
+// Counters returns the runtime counters of the connection.
+func (t *Tsunami) Counters() Counters {
+	c := Counters{
+		Sent:        t.counters.sent.Load(),
+		Received:    t.counters.received.Load(),
+		WriteErrors: t.counters.writeErrors.Load(),
+		ReadErrors:  t.counters.readErrors.Load(),
+		Uptime:      time.Since(t.counters.created),
+	}
+
+	if last := t.counters.lastEvent.Load(); last != 0 {
+		c.LastEvent = time.Unix(0, last)
+	}
+
+	return c
+}
@@ -0,0 +1,37 @@
+package tsunami
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestTsunamiCounters(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+
+	if c := ts.Counters(); c.Sent != 0 || !c.LastEvent.IsZero() {
+		t.Errorf("unexpected counters %+v", c)
+	}
+
+	ts.TrackPlayPoly(1, 0, false)
+	ts.TrackStop(1)
+	port.Push(&protocol.TrackReport{Track: 1})
+	ts.Update()
+
+	c := ts.Counters()
+	if c.Sent != 2 || c.Received != 1 || c.WriteErrors != 0 || c.LastEvent.IsZero() || c.Uptime <= 0 {
+		t.Errorf("unexpected counters %+v", c)
+	}
+
+	var vars map[string]interface{}
+	if err := json.Unmarshal([]byte(ts.Vars().String()), &vars); err != nil {
+		t.Fatal(err)
+	}
+
+	if vars["frames_sent"] != 2.0 || vars["frames_received"] != 1.0 || vars["last_event"] == "" {
+		t.Errorf("unexpected vars %v", vars)
+	}
+}
@@ -0,0 +1,142 @@
+//go:build !tinygo && !js
+
+package tsunami
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// ProbeTimeout is the time Discover waits for every port to answer.
+var ProbeTimeout = 500 * time.Millisecond
+
+// DeviceInfo describes a board found by Discover.
+type DeviceInfo struct {
+	// Port is the name of the serial port, to be used with NewTsunami.
+	Port string
+	// USB holds the USB details of the port, its serial number identifies
+	// the board across reboots, see transport.FindPort.
+	USB transport.PortInfo
+	// Version is the version string reported by the board.
+	Version string
+	// Firmware is the parsed version string.
+	Firmware Version
+}
+
+// Discover looks for boards attached to the serial ports of the system. Every
+// port is opened and asked for its version, the ports answering with a
+// Tsunami version string within ProbeTimeout are returned, sorted by port
+// name. Ports that can't be opened, eg. because they are in use, are skipped.
+//
+// USB ports other than FTDI adapters are not probed, since the board is
+// connected through one. If the USB details are not available in the
+// system, every port is probed.
+func Discover(ctx context.Context) ([]DeviceInfo, error) {
+	ports, err := candidatePorts()
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]*DeviceInfo, len(ports))
+
+	var wg sync.WaitGroup
+	for i, p := range ports {
+		wg.Add(1)
+		go func(i int, p transport.PortInfo) {
+			defer wg.Done()
+
+			port, err := transport.OpenPort(p.Name)
+			if err != nil {
+				return
+			}
+
+			defer port.Close()
+
+			ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+			defer cancel()
+
+			if info, ok := probe(ctx, port); ok {
+				info.Port = p.Name
+				info.USB = p
+				found[i] = &info
+			}
+		}(i, p)
+	}
+
+	wg.Wait()
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i] != nil && (found[j] == nil || found[i].Port < found[j].Port)
+	})
+
+	var devices []DeviceInfo
+	for _, info := range found {
+		if info != nil {
+			devices = append(devices, *info)
+		}
+	}
+
+	return devices, ctx.Err()
+}
+
+// candidatePorts returns the ports to be probed by Discover.
+func candidatePorts() ([]transport.PortInfo, error) {
+	ports, err := transport.ListPortsInfo()
+	if err != nil {
+		names, err := transport.ListPorts()
+		if err != nil {
+			return nil, err
+		}
+
+		ports = make([]transport.PortInfo, len(names))
+		for i, name := range names {
+			ports[i].Name = name
+		}
+
+		return ports, nil
+	}
+
+	var candidates []transport.PortInfo
+	for _, p := range ports {
+		if !p.IsUSB || p.IsFTDI() {
+			candidates = append(candidates, p)
+		}
+	}
+
+	return candidates, nil
+}
+
+// probe asks for the version on the given transport and waits for a Tsunami
+// version string until the context is done.
+func probe(ctx context.Context, tr transport.Transport) (DeviceInfo, bool) {
+	t := NewTsunamiTransport(tr)
+	if err := send(t, protocol.GetVersion{}); err != nil {
+		return DeviceInfo{}, false
+	}
+
+	for {
+		if err := t.update(); err != nil {
+			return DeviceInfo{}, false
+		}
+
+		if v := t.GetVersion(); v != "" {
+			fw, ok := parseFirmware(v)
+			if !ok || fw.Board != "Tsunami" {
+				return DeviceInfo{}, false
+			}
+
+			return DeviceInfo{Version: v, Firmware: fw}, true
+		}
+
+		select {
+		case <-ctx.Done():
+			return DeviceInfo{}, false
+		default:
+		}
+	}
+}
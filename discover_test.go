@@ -0,0 +1,40 @@
+//go:build !tinygo && !js
+
+package tsunami
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+func TestProbe(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		found   bool
+	}{
+		{"Tsunami v1.10s", true},
+		{"WAV Trigger v1.34", false},
+		{"", false},
+	} {
+		port := &bufferPort{}
+		if tc.version != "" {
+			port.rx = protocol.VersionString{Version: tc.version}.AppendBinary(nil)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		info, ok := probe(ctx, port)
+		cancel()
+
+		if ok != tc.found {
+			t.Errorf("%q: found %v, expected %v", tc.version, ok, tc.found)
+			continue
+		}
+
+		if ok && info.Firmware.String() != tc.version {
+			t.Errorf("%q: unexpected firmware %s", tc.version, info.Firmware)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Package dmx triggers the board from DMX512, the protocol of the lighting
+// consoles, so the light cues fire the sound, as many haunted attractions
+// run. The values of the channels are mapped to actions, like playing a
+// track when a channel goes over half, or following the gain of an output
+// with a fader, and are received from the network, Art-Net or sACN, or
+// from a DMX interface.
+//
+// Conversely, the lights can follow the board: a Follower sets the channels
+// of a universe sent by a Sender while the tracks play or the cues fire.
+package dmx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// Channels is the number of channels of a universe.
+const Channels = 512
+
+// DefaultThreshold is the value a channel has to reach to trigger a track.
+const DefaultThreshold = 128
+
+// ActionType is the kind of a Mapping.
+type ActionType string
+
+const (
+	// Play plays the track when the channel reaches the threshold, and
+	// stops it when it drops below if Stop is set.
+	Play ActionType = "play"
+	// MasterGain follows the value of the channel with the gain of the
+	// output, from MinGain at 0 to 0 dB at 255.
+	MasterGain ActionType = "master"
+	// TrackGain follows the value of the channel with the gain of the
+	// track, from MinGain at 0 to 0 dB at 255.
+	TrackGain ActionType = "gain"
+)
+
+// Mapping maps a channel to an action, eg.:
+//
+//	{"channel": 10, "type": "play", "track": 19, "output": 0}
+//	{"channel": 11, "type": "master", "output": 0}
+type Mapping struct {
+	// Universe is the DMX universe of the channel, 0 for the interfaces
+	// with a single one.
+	Universe int `json:"universe,omitempty"`
+	// Channel is the channel, from 1 to 512.
+	Channel int        `json:"channel"`
+	Type    ActionType `json:"type"`
+	Track   int        `json:"track,omitempty"`
+	Output  int        `json:"output,omitempty"`
+	Lock    bool       `json:"lock,omitempty"`
+	// Threshold is the value triggering the track, DefaultThreshold if
+	// zero.
+	Threshold int  `json:"threshold,omitempty"`
+	Stop      bool `json:"stop,omitempty"`
+}
+
+func (m *Mapping) validate() error {
+	if m.Channel < 1 || m.Channel > Channels {
+		return fmt.Errorf("invalid channel %d", m.Channel)
+	}
+
+	if m.Universe < 0 || m.Universe > 0x7fff {
+		return fmt.Errorf("channel %d: invalid universe %d", m.Channel, m.Universe)
+	}
+
+	if m.Output < 0 || m.Output >= tsunami.MaxOutputs {
+		return fmt.Errorf("channel %d: invalid output %d", m.Channel, m.Output)
+	}
+
+	switch m.Type {
+	case Play, TrackGain:
+		if m.Track < 1 || m.Track > tsunami.MaxTracks {
+			return fmt.Errorf("channel %d: invalid track %d", m.Channel, m.Track)
+		}
+	case MasterGain:
+	default:
+		return fmt.Errorf("channel %d: unknown action %q", m.Channel, m.Type)
+	}
+
+	if m.Threshold < 0 || m.Threshold > 255 {
+		return fmt.Errorf("channel %d: invalid threshold %d", m.Channel, m.Threshold)
+	}
+
+	return nil
+}
+
+// LoadMappings reads the mappings as a JSON array.
+func LoadMappings(r io.Reader) ([]Mapping, error) {
+	var m []Mapping
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Gain returns the gain of a channel value, from MinGain at 0 to 0 dB at
+// 255.
+func Gain(v byte) tsunami.Gain {
+	return tsunami.MinGain - tsunami.MinGain*tsunami.Gain(v)/255
+}
+
+// Mapper runs the actions of the mappings on the board, as the values of
+// the channels change.
+type Mapper struct {
+	p        tsunami.Player
+	mappings []Mapping
+
+	mu     sync.Mutex
+	values map[int]*[Channels]byte
+}
+
+// NewMapper returns a mapper of the channels to the board, validating the
+// mappings.
+func NewMapper(p tsunami.Player, mappings ...Mapping) (*Mapper, error) {
+	for i := range mappings {
+		if err := mappings[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Mapper{p: p, mappings: mappings, values: make(map[int]*[Channels]byte)}, nil
+}
+
+// Apply runs the actions of the channels of the universe changed since the
+// last frame, data being the values of the channels starting from the first
+// one. The actions of a universe are evaluated from the first frame
+// received, so a Play mapping triggers if its channel is already over the
+// threshold. Every action is run, the first error is returned.
+func (m *Mapper) Apply(universe int, data []byte) error {
+	if len(data) > Channels {
+		data = data[:Channels]
+	}
+
+	m.mu.Lock()
+	prev, seen := m.values[universe]
+	if !seen {
+		prev = &[Channels]byte{}
+		m.values[universe] = prev
+	}
+
+	old := *prev
+	copy(prev[:], data)
+	m.mu.Unlock()
+
+	var err error
+	for _, mp := range m.mappings {
+		if mp.Universe != universe || mp.Channel > len(data) {
+			continue
+		}
+
+		v, before := data[mp.Channel-1], old[mp.Channel-1]
+		if seen && v == before {
+			continue
+		}
+
+		if e := m.run(mp, v, before, seen); e != nil && err == nil {
+			err = fmt.Errorf("channel %d: %w", mp.Channel, e)
+		}
+	}
+
+	return err
+}
+
+func (m *Mapper) run(mp Mapping, v, before byte, seen bool) error {
+	switch mp.Type {
+	case MasterGain:
+		return m.p.MasterGain(mp.Output, Gain(v))
+	case TrackGain:
+		return m.p.TrackGain(mp.Track, Gain(v))
+	}
+
+	threshold := byte(mp.Threshold)
+	if mp.Threshold == 0 {
+		threshold = DefaultThreshold
+	}
+
+	on, wasOn := v >= threshold, seen && before >= threshold
+	switch {
+	case on && !wasOn:
+		return m.p.TrackPlayPoly(mp.Track, mp.Output, mp.Lock)
+	case !on && wasOn && mp.Stop:
+		return m.p.TrackStop(mp.Track)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package dmx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// commands returns the commands sent since the last call.
+func commands(t *testing.T, port *transport.Loopback) []string {
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, m := range msgs {
+		got = append(got, protocol.Describe(m))
+	}
+
+	return got
+}
+
+func describe(msgs ...protocol.Message) []string {
+	var s []string
+	for _, m := range msgs {
+		s = append(s, protocol.Describe(m))
+	}
+
+	return s
+}
+
+func TestMapper(t *testing.T) {
+	mappings, err := LoadMappings(strings.NewReader(`[
+	  {"channel": 10, "type": "play", "track": 19, "output": 0, "stop": true},
+	  {"channel": 11, "type": "master", "output": 1},
+	  {"universe": 1, "channel": 1, "type": "play", "track": 20, "threshold": 10}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := transport.NewLoopback(nil)
+
+	ts := tsunami.NewTsunamiTransport(port)
+	m, err := NewMapper(ts, mappings...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := make([]byte, 16)
+	data[10] = 255
+	m.Apply(0, data)
+	if got, expected := commands(t, port), describe(&protocol.MasterVolume{Output: 1}); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected commands %q", got)
+	}
+
+	data[9] = 127
+	m.Apply(0, data)
+	data[9] = 128
+	m.Apply(0, data)
+	data[9] = 200
+	m.Apply(0, data)
+	data[9] = 0
+	m.Apply(0, data)
+	m.Apply(1, []byte{10})
+
+	expected := describe(
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 19},
+		&protocol.TrackControl{Code: tsunami.TRK_STOP, Track: 19},
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 20},
+	)
+
+	if got := commands(t, port); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected commands %q", got)
+	}
+}
+
+func TestNewMapperValidate(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(nil))
+	for _, c := range []struct {
+		m   Mapping
+		err string
+	}{
+		{Mapping{Channel: 513, Type: MasterGain}, "invalid channel 513"},
+		{Mapping{Channel: 1, Type: Play}, "channel 1: invalid track 0"},
+		{Mapping{Channel: 1, Type: "jump"}, `channel 1: unknown action "jump"`},
+		{Mapping{Channel: 1, Type: MasterGain, Output: 8}, "channel 1: invalid output 8"},
+	} {
+		if _, err := NewMapper(ts, c.m); err == nil || err.Error() != c.err {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+}
+
+func TestGain(t *testing.T) {
+	if g := Gain(0); g != tsunami.MinGain {
+		t.Errorf("unexpected gain %s", g)
+	}
+
+	if g := Gain(255); g != 0 {
+		t.Errorf("unexpected gain %s", g)
+	}
+}
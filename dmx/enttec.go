@@ -0,0 +1,134 @@
+package dmx
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Labels of the messages of the Enttec DMX USB Pro API.
+const (
+	enttecReceived    = 5 // Received DMX Packet
+	enttecReceiveMode = 8 // Receive DMX on Change
+)
+
+const (
+	enttecStart = 0x7e
+	enttecEnd   = 0xe7
+	// enttecMaxLen is the longest message data, a DMX frame with its status
+	// and start code.
+	enttecMaxLen = Channels + 2
+)
+
+// ErrEnttecOverrun is reported when the interface drops DMX frames, because
+// they weren't read fast enough or arrived malformed.
+var ErrEnttecOverrun = errors.New("enttec: frames lost")
+
+// enttecMessage returns a message of the Enttec API.
+func enttecMessage(label byte, data []byte) []byte {
+	b := []byte{enttecStart, label, 0, 0}
+	binary.LittleEndian.PutUint16(b[2:], uint16(len(data)))
+	b = append(b, data...)
+	return append(b, enttecEnd)
+}
+
+// parseEnttec returns the first message of the Enttec API in b, and the
+// bytes after it. The bytes before the start delimiter are skipped. ok is
+// false if the message isn't complete, rest being the bytes to keep until
+// more arrive.
+func parseEnttec(b []byte) (label byte, data, rest []byte, ok bool) {
+	for {
+		for len(b) > 0 && b[0] != enttecStart {
+			b = b[1:]
+		}
+
+		if len(b) < 4 {
+			return 0, nil, b, false
+		}
+
+		n := int(binary.LittleEndian.Uint16(b[2:]))
+		if n > enttecMaxLen {
+			// not a message, resync on the next start delimiter.
+			b = b[1:]
+			continue
+		}
+
+		if len(b) < n+5 {
+			return 0, nil, b, false
+		}
+
+		if b[n+4] != enttecEnd {
+			b = b[1:]
+			continue
+		}
+
+		return b[1], b[4 : n+4], b[n+5:], true
+	}
+}
+
+// ServeEnttec applies the DMX frames received by an Enttec DMX USB Pro, or
+// a compatible interface, as the given universe of the mapper, until the
+// context is done. The interface is set to send every frame received,
+// the frames with the alternate start codes, like RDM, are ignored. The
+// errors of the actions and the frames lost are passed to onError, if not
+// nil.
+//
+// rw is closed when the context is done if it implements io.Closer, reads
+// of zero bytes, like the ones of the serial ports with a read timeout,
+// are retried.
+func ServeEnttec(ctx context.Context, rw io.ReadWriter, universe int, m *Mapper, onError func(error)) error {
+	if c, ok := rw.(io.Closer); ok {
+		go func() {
+			<-ctx.Done()
+			c.Close()
+		}()
+	}
+
+	if _, err := rw.Write(enttecMessage(enttecReceiveMode, []byte{0})); err != nil {
+		return err
+	}
+
+	report := func(err error) {
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+
+	var pending []byte
+	buf := make([]byte, 1024)
+	for {
+		n, err := rw.Read(buf)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		pending = append(pending, buf[:n]...)
+		for {
+			label, data, rest, ok := parseEnttec(pending)
+			pending = rest
+			if !ok {
+				break
+			}
+
+			if label != enttecReceived || len(data) < 2 {
+				continue
+			}
+
+			if data[0] != 0 {
+				report(ErrEnttecOverrun)
+				continue
+			}
+
+			if data[1] != 0 {
+				continue
+			}
+
+			report(m.Apply(universe, data[2:]))
+		}
+	}
+}
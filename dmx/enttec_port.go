@@ -0,0 +1,22 @@
+//go:build !tinygo && !js
+
+package dmx
+
+import (
+	"context"
+
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// ListenEnttec serves the DMX frames received by the Enttec DMX USB Pro on
+// the serial port with the given name, eg. "/dev/ttyUSB1", see
+// ServeEnttec.
+func ListenEnttec(ctx context.Context, name string, universe int, m *Mapper, onError func(error)) error {
+	port, err := transport.OpenPort(name)
+	if err != nil {
+		return err
+	}
+
+	defer port.Close()
+	return ServeEnttec(ctx, port, universe, m, onError)
+}
@@ -0,0 +1,89 @@
+package dmx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// enttecPort is an interface sending the given chunks, one per Read.
+type enttecPort struct {
+	chunks [][]byte
+	tx     bytes.Buffer
+}
+
+func (p *enttecPort) Read(b []byte) (int, error) {
+	if len(p.chunks) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(b, p.chunks[0])
+	p.chunks = p.chunks[1:]
+	return n, nil
+}
+
+func (p *enttecPort) Write(b []byte) (int, error) {
+	return p.tx.Write(b)
+}
+
+func received(status byte, startCode byte, channels ...byte) []byte {
+	return enttecMessage(enttecReceived, append([]byte{status, startCode}, channels...))
+}
+
+func TestServeEnttec(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	m, _ := NewMapper(ts, Mapping{Universe: 1, Channel: 2, Type: Play, Track: 19, Stop: true})
+
+	frame := received(0, 0, 0, 255)
+	stop := received(0, 0, 0, 0)
+	rw := &enttecPort{chunks: [][]byte{
+		{0x00, 0x12},
+		received(0, 0, 0, 0),
+		frame[:3], frame[3:],
+		received(0, 0xcc, 0, 0), // RDM
+		received(1, 0, 0, 0),
+		{},
+		append(enttecMessage(9, nil), stop...),
+	}}
+
+	var errs []error
+	err := ServeEnttec(context.Background(), rw, 1, m, func(err error) { errs = append(errs, err) })
+	if err != io.EOF {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if len(errs) != 1 || errs[0] != ErrEnttecOverrun {
+		t.Errorf("unexpected errors %v", errs)
+	}
+
+	if !bytes.Equal(rw.tx.Bytes(), []byte{0x7e, 8, 1, 0, 0, 0xe7}) {
+		t.Errorf("unexpected receive mode % x", rw.tx.Bytes())
+	}
+
+	expected := describe(
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 19},
+		&protocol.TrackControl{Code: tsunami.TRK_STOP, Track: 19},
+	)
+
+	if got := commands(t, port); len(got) != 2 || got[0] != expected[0] || got[1] != expected[1] {
+		t.Errorf("unexpected commands %q", got)
+	}
+}
+
+func TestParseEnttec(t *testing.T) {
+	b := append([]byte{0x7e, 5, 0xff, 0xff}, enttecMessage(6, []byte{1, 2})...)
+	label, data, rest, ok := parseEnttec(b)
+	if !ok || label != 6 || !bytes.Equal(data, []byte{1, 2}) || len(rest) != 0 {
+		t.Errorf("unexpected message %d % x, %v", label, data, ok)
+	}
+
+	if _, _, rest, ok := parseEnttec([]byte{0x7e, 5, 2, 0, 1}); ok || len(rest) != 5 {
+		t.Errorf("an incomplete message should be kept, got % x", rest)
+	}
+}
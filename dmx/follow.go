@@ -0,0 +1,184 @@
+package dmx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/show"
+)
+
+// Light sets a channel of a Sender from the board, eg.:
+//
+//	{"track": 19, "channel": 1, "level": 255}
+//	{"cue": "12", "channel": 2, "level": 128}
+type Light struct {
+	// Track sets the channel at the level while the track plays, any
+	// track if zero and Cue is empty.
+	Track int `json:"track,omitempty"`
+	// Cue sets the channel at the level when the cue fires, until another
+	// cue of the channel fires, see Follower.WatchCues.
+	Cue string `json:"cue,omitempty"`
+	// Channel is the channel, from 1 to 512.
+	Channel int `json:"channel"`
+	Level   int `json:"level"`
+}
+
+func (l *Light) validate() error {
+	if l.Channel < 1 || l.Channel > Channels {
+		return fmt.Errorf("invalid channel %d", l.Channel)
+	}
+
+	if l.Track < 0 || l.Track > tsunami.MaxTracks {
+		return fmt.Errorf("channel %d: invalid track %d", l.Channel, l.Track)
+	}
+
+	if l.Track != 0 && l.Cue != "" {
+		return fmt.Errorf("channel %d: both track and cue", l.Channel)
+	}
+
+	if l.Level < 0 || l.Level > 255 {
+		return fmt.Errorf("channel %d: invalid level %d", l.Channel, l.Level)
+	}
+
+	return nil
+}
+
+// LoadLights reads the lights as a JSON array.
+func LoadLights(r io.Reader) ([]Light, error) {
+	var l []Light
+	if err := json.NewDecoder(r).Decode(&l); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Follower sets the channels of a sender as the tracks of a board play and
+// the cues fire, so the lights follow the audio. The channels of several
+// lights take the highest of their levels, as the lighting consoles do. The
+// tracks are followed with the track reports, see tsunami.Tsunami.Subscribe.
+type Follower struct {
+	s      *Sender
+	lights []Light
+	cancel func()
+
+	mu      sync.Mutex
+	playing map[int]bool
+	// cues are the levels of the channels set by the cues.
+	cues    map[int]byte
+	onError func(error)
+	closed  bool
+}
+
+// NewFollower returns a follower of the board setting the lights on the
+// sender, subscribed to its messages.
+func NewFollower(b tsunami.Board, s *Sender, lights ...Light) (*Follower, error) {
+	for i := range lights {
+		if err := lights[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	f := &Follower{s: s, lights: lights, playing: make(map[int]bool), cues: make(map[int]byte)}
+	f.cancel = b.Subscribe(f.handle)
+	return f, nil
+}
+
+// Close unsubscribes the follower from the board, the cues fired afterwards
+// are ignored. The sender is left open, with the last levels set.
+func (f *Follower) Close() error {
+	f.cancel()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	return nil
+}
+
+// WatchCues sets the lights of the cues of the stack when they fire.
+func (f *Follower) WatchCues(s *show.CueStack) {
+	s.OnCue(func(c show.Cue) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if f.closed {
+			return
+		}
+
+		matched := false
+		for _, l := range f.lights {
+			if l.Cue == c.Number {
+				f.cues[l.Channel], matched = byte(l.Level), true
+			}
+		}
+
+		if matched {
+			f.update()
+		}
+	})
+}
+
+// OnError registers a function to be called with the errors sending the
+// universe.
+func (f *Follower) OnError(fn func(error)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.onError = fn
+}
+
+func (f *Follower) handle(msg protocol.Message) {
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return
+	}
+
+	if r.Playing {
+		f.playing[int(r.Track)] = true
+	} else {
+		delete(f.playing, int(r.Track))
+	}
+
+	f.update()
+}
+
+// update sets the channels of the lights, it must be called with the lock
+// held.
+func (f *Follower) update() {
+	values := make(map[int]byte, len(f.lights))
+	for _, l := range f.lights {
+		v := f.cues[l.Channel]
+		if l.Cue == "" && f.active(l.Track) && byte(l.Level) > v {
+			v = byte(l.Level)
+		}
+
+		if v >= values[l.Channel] {
+			values[l.Channel] = v
+		}
+	}
+
+	if err := f.s.SetAll(values); err != nil && f.onError != nil {
+		f.onError(err)
+	}
+}
+
+// active reports whether the track is playing, or any track for zero.
+func (f *Follower) active(trk int) bool {
+	if trk == 0 {
+		return len(f.playing) > 0
+	}
+
+	return f.playing[trk]
+}
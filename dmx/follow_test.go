@@ -0,0 +1,88 @@
+package dmx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/show"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestFollower(t *testing.T) {
+	lights, err := LoadLights(strings.NewReader(`[
+	  {"track": 19, "channel": 1, "level": 255},
+	  {"track": 20, "channel": 1, "level": 100},
+	  {"channel": 2, "level": 50},
+	  {"cue": "1", "channel": 3, "level": 128},
+	  {"cue": "2", "channel": 3, "level": 0}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := transport.NewLoopback(nil)
+
+	ts := tsunami.NewTsunamiTransport(port)
+	s, read := listen(t)
+	f, err := NewFollower(ts, s, lights...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var errs []error
+	f.OnError(func(err error) { errs = append(errs, err) })
+
+	stack := show.NewCueStack(ts, []show.Cue{{Number: "1"}, {Number: "2"}})
+	f.WatchCues(stack)
+
+	for _, c := range []struct {
+		report   *protocol.TrackReport
+		cue      bool
+		expected [3]byte
+	}{
+		{report: &protocol.TrackReport{Track: 20, Playing: true}, expected: [3]byte{100, 50, 0}},
+		{report: &protocol.TrackReport{Track: 19, Playing: true}, expected: [3]byte{255, 50, 0}},
+		{report: &protocol.TrackReport{Track: 19}, expected: [3]byte{100, 50, 0}},
+		{cue: true, expected: [3]byte{100, 50, 128}},
+		{report: &protocol.TrackReport{Track: 20}, expected: [3]byte{0, 0, 128}},
+		{cue: true, expected: [3]byte{0, 0, 0}},
+	} {
+		if c.cue {
+			if err := stack.Go(); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			port.Push(c.report)
+			if err := ts.Update(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if data := read(); [3]byte{data[0], data[1], data[2]} != c.expected {
+			t.Errorf("unexpected values %v, expected %v", data[:3], c.expected)
+		}
+	}
+
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+}
+
+func TestNewFollowerValidate(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(nil))
+	for _, c := range []struct {
+		l   Light
+		err string
+	}{
+		{Light{Channel: 0}, "invalid channel 0"},
+		{Light{Channel: 1, Track: tsunami.MaxTracks + 1}, "channel 1: invalid track 4097"},
+		{Light{Channel: 1, Track: 1, Cue: "1"}, "channel 1: both track and cue"},
+		{Light{Channel: 1, Level: 256}, "channel 1: invalid level 256"},
+	} {
+		if _, err := NewFollower(ts, nil, c.l); err == nil || err.Error() != c.err {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package dmx
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+)
+
+const (
+	// ArtNetPort is the UDP port of Art-Net.
+	ArtNetPort = 6454
+	// SACNPort is the UDP port of sACN, E1.31.
+	SACNPort = 5568
+)
+
+// ErrNotDMX is returned when parsing a packet not carrying DMX data.
+var ErrNotDMX = errors.New("not a DMX packet")
+
+var (
+	artNetID = []byte("Art-Net\x00")
+	acnID    = []byte("ASC-E1.17\x00\x00\x00")
+)
+
+const artDmxOpCode = 0x5000
+
+// ParseArtNet returns the universe and the channels of an ArtDmx packet.
+// The universe is the 15 bits Port-Address, the net, sub-net and universe
+// of Art-Net 4.
+func ParseArtNet(b []byte) (universe int, data []byte, err error) {
+	if len(b) < 18 || !bytes.Equal(b[:8], artNetID) || binary.LittleEndian.Uint16(b[8:]) != artDmxOpCode {
+		return 0, nil, ErrNotDMX
+	}
+
+	universe = int(b[15]&0x7f)<<8 | int(b[14])
+	n := int(binary.BigEndian.Uint16(b[16:]))
+	if n > Channels || len(b) < 18+n {
+		return 0, nil, ErrNotDMX
+	}
+
+	return universe, b[18 : 18+n], nil
+}
+
+// ParseSACN returns the universe and the channels of an E1.31 data packet,
+// with the null start code.
+func ParseSACN(b []byte) (universe int, data []byte, err error) {
+	if len(b) < 126 || !bytes.Equal(b[4:16], acnID) ||
+		binary.BigEndian.Uint32(b[18:]) != 0x04 || // VECTOR_ROOT_E131_DATA
+		binary.BigEndian.Uint32(b[40:]) != 0x02 || // VECTOR_E131_DATA_PACKET
+		b[117] != 0x02 || b[125] != 0x00 { // VECTOR_DMP_SET_PROPERTY, start code
+		return 0, nil, ErrNotDMX
+	}
+
+	universe = int(binary.BigEndian.Uint16(b[113:]))
+	// the count includes the start code.
+	n := int(binary.BigEndian.Uint16(b[123:])) - 1
+	if n < 0 || n > Channels || len(b) < 126+n {
+		return 0, nil, ErrNotDMX
+	}
+
+	return universe, b[126 : 126+n], nil
+}
+
+// ParsePacket parses an Art-Net or sACN packet.
+func ParsePacket(b []byte) (universe int, data []byte, err error) {
+	if bytes.HasPrefix(b, artNetID) {
+		return ParseArtNet(b)
+	}
+
+	return ParseSACN(b)
+}
+
+// Serve applies the DMX packets received by the connection, Art-Net or
+// sACN, to the mapper, until the context is done. The packets not carrying
+// DMX data, like the polls of Art-Net, are ignored. The errors of the
+// actions are passed to onError, if not nil.
+func Serve(ctx context.Context, conn net.PacketConn, m *Mapper, onError func(error)) error {
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		universe, data, err := ParsePacket(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		if err := m.Apply(universe, data); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// ListenArtNet serves the Art-Net packets received on the address, like
+// ":6454", see Serve.
+func ListenArtNet(ctx context.Context, addr string, m *Mapper, onError func(error)) error {
+	conn, err := net.ListenPacket("udp4", addr)
+	if err != nil {
+		return err
+	}
+
+	return Serve(ctx, conn, m, onError)
+}
+
+// ListenSACN serves the sACN packets of the universes, received on their
+// multicast groups on the interface, the default one if nil, see Serve.
+func ListenSACN(ctx context.Context, ifi *net.Interface, universes []int, m *Mapper, onError func(error)) error {
+	if len(universes) == 0 {
+		return errors.New("no universes")
+	}
+
+	addr := &net.UDPAddr{IP: sacnGroup(universes[0]), Port: SACNPort}
+	conn, err := net.ListenMulticastUDP("udp4", ifi, addr)
+	if err != nil {
+		return err
+	}
+
+	// the standard library joins a group per socket, so the rest of the
+	// universes are joined by sockets of their own. The sockets receive the
+	// packets of every group joined, the packets repeated don't change the
+	// values of the channels, so they are harmless.
+	for _, u := range universes[1:] {
+		c, err := net.ListenMulticastUDP("udp4", ifi, &net.UDPAddr{IP: sacnGroup(u), Port: SACNPort})
+		if err != nil {
+			conn.Close()
+			return err
+		}
+
+		go Serve(ctx, c, m, onError)
+	}
+
+	return Serve(ctx, conn, m, onError)
+}
+
+// sacnGroup returns the multicast group of the universe, 239.255.hi.lo.
+func sacnGroup(universe int) net.IP {
+	return net.IPv4(239, 255, byte(universe>>8), byte(universe))
+}
@@ -0,0 +1,88 @@
+package dmx
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func artDmx(universe int, data []byte) []byte {
+	b := append([]byte("Art-Net\x00"), 0x00, 0x50, 0, 14, 0, 0, byte(universe), byte(universe>>8))
+	b = binary.BigEndian.AppendUint16(b, uint16(len(data)))
+	return append(b, data...)
+}
+
+func sacn(universe int, data []byte) []byte {
+	b := make([]byte, 126, 126+len(data))
+	copy(b[4:], acnID)
+	binary.BigEndian.PutUint32(b[18:], 0x04)
+	binary.BigEndian.PutUint32(b[40:], 0x02)
+	binary.BigEndian.PutUint16(b[113:], uint16(universe))
+	b[117] = 0x02
+	binary.BigEndian.PutUint16(b[123:], uint16(len(data)+1))
+	return append(b, data...)
+}
+
+func TestParsePacket(t *testing.T) {
+	for _, p := range [][]byte{artDmx(0x0203, []byte{1, 2, 3}), sacn(0x0203, []byte{1, 2, 3})} {
+		u, data, err := ParsePacket(p)
+		if err != nil || u != 0x0203 || string(data) != "\x01\x02\x03" {
+			t.Errorf("unexpected universe %d, data %v, %v", u, data, err)
+		}
+	}
+
+	poll := append([]byte("Art-Net\x00"), 0x00, 0x20, 0, 14, 0, 0)
+	if _, _, err := ParsePacket(poll); err != ErrNotDMX {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, _, err := ParsePacket(artDmx(1, make([]byte, 513))); err != ErrNotDMX {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestServe(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	m, _ := NewMapper(ts, Mapping{Channel: 2, Type: Play, Track: 19})
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- Serve(ctx, conn, m, nil) }()
+
+	console, err := net.Dial("udp4", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer console.Close()
+	console.Write([]byte("garbage"))
+	console.Write(artDmx(0, []byte{0, 255}))
+
+	deadline := time.Now().Add(time.Second)
+	var got []string
+	for len(got) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		got = commands(t, port)
+	}
+
+	if expected := protocol.Describe(&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 19}); len(got) != 1 || got[0] != expected {
+		t.Errorf("unexpected commands %q", got)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package dmx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RefreshInterval is how often a Sender repeats the values of its universe
+// while unchanged, the receivers of sACN drop a source silent for 2.5s.
+var RefreshInterval = time.Second
+
+// DefaultPriority is the sACN priority of the packets of a Sender.
+const DefaultPriority = 100
+
+// AppendArtNet appends an ArtDmx packet of the universe to dst, the
+// universe being the 15 bits Port-Address.
+func AppendArtNet(dst []byte, universe int, seq byte, data []byte) []byte {
+	dst = append(dst, artNetID...)
+	dst = binary.LittleEndian.AppendUint16(dst, artDmxOpCode)
+	// protocol version 14, the sequence and the physical port.
+	dst = append(dst, 0, 14, seq, 0, byte(universe), byte(universe>>8)&0x7f)
+	dst = binary.BigEndian.AppendUint16(dst, uint16(len(data)))
+	return append(dst, data...)
+}
+
+// AppendSACN appends an E1.31 data packet of the universe to dst, from the
+// source with the given CID and name.
+func AppendSACN(dst []byte, universe int, seq byte, cid [16]byte, source string, data []byte) []byte {
+	n := 126 + len(data)
+	b := make([]byte, n)
+	binary.BigEndian.PutUint16(b[0:], 0x0010) // preamble size
+	copy(b[4:], acnID)
+	binary.BigEndian.PutUint16(b[16:], 0x7000|uint16(n-16))
+	binary.BigEndian.PutUint32(b[18:], 0x04) // VECTOR_ROOT_E131_DATA
+	copy(b[22:], cid[:])
+
+	binary.BigEndian.PutUint16(b[38:], 0x7000|uint16(n-38))
+	binary.BigEndian.PutUint32(b[40:], 0x02) // VECTOR_E131_DATA_PACKET
+	copy(b[44:107], source)
+	b[108] = DefaultPriority
+	b[111] = seq
+	binary.BigEndian.PutUint16(b[113:], uint16(universe))
+
+	binary.BigEndian.PutUint16(b[115:], 0x7000|uint16(n-115))
+	b[117] = 0x02 // VECTOR_DMP_SET_PROPERTY
+	b[118] = 0xa1 // address and data type
+	binary.BigEndian.PutUint16(b[121:], 1)
+	binary.BigEndian.PutUint16(b[123:], uint16(len(data)+1))
+	copy(b[126:], data)
+
+	return append(dst, b...)
+}
+
+// Sender sends the values of a universe, Art-Net or sACN, every time they
+// change and every RefreshInterval while running.
+type Sender struct {
+	conn     net.Conn
+	universe int
+	packet   func(dst []byte, seq byte, data []byte) []byte
+
+	mu     sync.Mutex
+	values [Channels]byte
+	seq    byte
+	buf    []byte
+}
+
+// DialArtNet returns a sender of the universe to the Art-Net node at the
+// address, like "10.0.0.5", or a broadcast address like "2.255.255.255".
+// The port is ArtNetPort if missing.
+func DialArtNet(addr string, universe int) (*Sender, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, strconv.Itoa(ArtNetPort))
+	}
+
+	conn, err := net.Dial("udp4", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSender(conn, universe, func(dst []byte, seq byte, data []byte) []byte {
+		return AppendArtNet(dst, universe, seq, data)
+	}), nil
+}
+
+// DialSACN returns a sender of the universe to its sACN multicast group,
+// with a random CID, and the given source name.
+func DialSACN(universe int, source string) (*Sender, error) {
+	if universe < 1 || universe > 63999 {
+		return nil, fmt.Errorf("invalid sACN universe %d", universe)
+	}
+
+	var cid [16]byte
+	if _, err := rand.Read(cid[:]); err != nil {
+		return nil, err
+	}
+
+	conn, err := net.Dial("udp4", net.JoinHostPort(sacnGroup(universe).String(), strconv.Itoa(SACNPort)))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSender(conn, universe, func(dst []byte, seq byte, data []byte) []byte {
+		return AppendSACN(dst, universe, seq, cid, source, data)
+	}), nil
+}
+
+// NewSender returns a sender of the universe writing the packets built by
+// the given function to the connection, see AppendArtNet and AppendSACN.
+func NewSender(conn net.Conn, universe int, packet func(dst []byte, seq byte, data []byte) []byte) *Sender {
+	return &Sender{conn: conn, universe: universe, packet: packet}
+}
+
+// Universe returns the universe of the sender.
+func (s *Sender) Universe() int {
+	return s.universe
+}
+
+// Set sets the value of the channel, from 1 to 512, sending the universe if
+// it changed.
+func (s *Sender) Set(channel int, v byte) error {
+	return s.SetAll(map[int]byte{channel: v})
+}
+
+// SetAll is like Set, for several channels at once, sent in a single
+// packet.
+func (s *Sender) SetAll(values map[int]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range values {
+		if ch < 1 || ch > Channels {
+			return fmt.Errorf("invalid channel %d", ch)
+		}
+	}
+
+	changed := false
+	for ch, v := range values {
+		if s.values[ch-1] != v {
+			s.values[ch-1], changed = v, true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return s.send()
+}
+
+// Value returns the value of the channel.
+func (s *Sender) Value(channel int) byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if channel < 1 || channel > Channels {
+		return 0
+	}
+
+	return s.values[channel-1]
+}
+
+// Run sends the universe every RefreshInterval, until the context is done.
+func (s *Sender) Run(ctx context.Context) error {
+	ticker := time.NewTicker(RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		s.mu.Lock()
+		err := s.send()
+		s.mu.Unlock()
+
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close closes the connection.
+func (s *Sender) Close() error {
+	return s.conn.Close()
+}
+
+// send sends the values, it must be called with the lock held.
+func (s *Sender) send() error {
+	// the sequence 0 disables the reordering of the Art-Net receivers.
+	if s.seq++; s.seq == 0 {
+		s.seq = 1
+	}
+
+	s.buf = s.packet(s.buf[:0], s.seq, s.values[:])
+	_, err := s.conn.Write(s.buf)
+	return err
+}
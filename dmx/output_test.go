@@ -0,0 +1,98 @@
+package dmx
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestAppendPackets(t *testing.T) {
+	data := []byte{1, 2, 3}
+
+	u, got, err := ParseArtNet(AppendArtNet(nil, 0x0203, 1, data))
+	if err != nil || u != 0x0203 || string(got) != string(data) {
+		t.Errorf("unexpected Art-Net universe %d, data %v, %v", u, got, err)
+	}
+
+	p := AppendSACN(nil, 7, 1, [16]byte{1}, "tsunami", data)
+	u, got, err = ParseSACN(p)
+	if err != nil || u != 7 || string(got) != string(data) {
+		t.Errorf("unexpected sACN universe %d, data %v, %v", u, got, err)
+	}
+
+	if string(p[44:51]) != "tsunami" || p[108] != DefaultPriority || p[22] != 1 {
+		t.Errorf("unexpected sACN framing % x", p[22:115])
+	}
+}
+
+// listen returns a sender of universe 1 to a local connection, and the
+// function reading the values sent.
+func listen(t *testing.T) (*Sender, func() []byte) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	s, err := DialArtNet(conn.LocalAddr().String(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { s.Close() })
+
+	return s, func() []byte {
+		buf := make([]byte, 1024)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		u, data, err := ParsePacket(buf[:n])
+		if err != nil || u != 1 {
+			t.Fatalf("unexpected universe %d, %v", u, err)
+		}
+
+		return data
+	}
+}
+
+func TestSender(t *testing.T) {
+	s, read := listen(t)
+
+	if err := s.Set(1, 255); err != nil {
+		t.Fatal(err)
+	}
+
+	// unchanged, nothing is sent.
+	s.Set(1, 255)
+	s.SetAll(map[int]byte{2: 10, 512: 20})
+
+	if data := read(); len(data) != Channels || data[0] != 255 || data[1] != 0 {
+		t.Errorf("unexpected values %v", data[:2])
+	}
+
+	if data := read(); data[0] != 255 || data[1] != 10 || data[511] != 20 {
+		t.Errorf("unexpected values %v", data[:2])
+	}
+
+	if err := s.Set(513, 1); err == nil {
+		t.Error("the channel should be invalid")
+	}
+
+	defer func(d time.Duration) { RefreshInterval = d }(RefreshInterval)
+	RefreshInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.Run(ctx)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		if data := read(); data[0] != 255 {
+			t.Errorf("unexpected refresh %v", data[:2])
+		}
+	}
+}
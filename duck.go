@@ -0,0 +1,137 @@
+package tsunami
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// DuckConfig is the configuration of a Ducker.
+type DuckConfig struct {
+	// Priority are the tracks triggering the ducking, eg. announcements.
+	Priority []int
+	// Tracks are the tracks ducked, with their nominal gain, by track
+	// number.
+	Tracks map[int]Gain
+	// Outputs are the outputs ducked, with their nominal gain, by output
+	// number.
+	Outputs map[int]Gain
+	// Depth is the gain reduction, in dB, eg. 12.
+	Depth Gain
+	// Attack and Release are the duration of the fades down and up.
+	Attack  time.Duration
+	Release time.Duration
+	// Curve is the curve of the fades.
+	Curve Curve
+}
+
+// Ducker lowers the gain of the music while an announcement is playing: when
+// any of the priority tracks starts, the tracks and outputs configured are
+// faded down, and restored to their nominal gain when the last priority
+// track ends. It follows the track reports, see Subscribe.
+type Ducker struct {
+	cfg      DuckConfig
+	fader    *Fader
+	priority map[int]bool
+	cancel   func()
+
+	mu      sync.Mutex
+	playing map[int]bool
+}
+
+// NewDucker returns a Ducker for the given board, subscribed to its
+// messages.
+func NewDucker(b Board, cfg DuckConfig) *Ducker {
+	d := &Ducker{
+		cfg:      cfg,
+		fader:    NewFader(b),
+		priority: make(map[int]bool, len(cfg.Priority)),
+		playing:  make(map[int]bool),
+	}
+
+	for _, trk := range cfg.Priority {
+		d.priority[trk] = true
+	}
+
+	d.cancel = b.Subscribe(d.handle)
+	return d
+}
+
+// Close unsubscribes the Ducker from the board and cancels the fades in
+// progress, leaving the gains where they are.
+func (d *Ducker) Close() error {
+	d.cancel()
+	for trk := range d.cfg.Tracks {
+		d.fader.CancelTrack(trk)
+	}
+
+	for out := range d.cfg.Outputs {
+		d.fader.CancelOutput(out)
+	}
+
+	return nil
+}
+
+// Ducked reports whether any priority track is playing.
+func (d *Ducker) Ducked() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.playing) > 0
+}
+
+func (d *Ducker) handle(msg protocol.Message) {
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok || !d.priority[int(r.Track)] {
+		return
+	}
+
+	d.mu.Lock()
+	was := len(d.playing) > 0
+	if r.Playing {
+		d.playing[int(r.Track)] = true
+	} else {
+		delete(d.playing, int(r.Track))
+	}
+
+	is := len(d.playing) > 0
+	d.mu.Unlock()
+
+	switch {
+	case is && !was:
+		d.fade(true, d.cfg.Attack)
+	case was && !is:
+		d.fade(false, d.cfg.Release)
+	}
+}
+
+// fade fades every track and output down by the depth, or back up to its
+// nominal gain, starting where any fade in progress was left.
+func (d *Ducker) fade(down bool, dur time.Duration) {
+	levels := func(gain Gain) (from, to Gain) {
+		if down {
+			return gain, gain - d.cfg.Depth
+		}
+
+		return gain - d.cfg.Depth, gain
+	}
+
+	for trk, gain := range d.cfg.Tracks {
+		from, to := levels(gain)
+		if g, ok := d.fader.current(fadeKey{trackFade, trk}); ok {
+			from = Gain(g)
+		}
+
+		d.fader.Track(trk, Fade{From: from, To: to, Duration: dur, Curve: d.cfg.Curve})
+	}
+
+	for out, gain := range d.cfg.Outputs {
+		from, to := levels(gain)
+		if g, ok := d.fader.current(fadeKey{outputFade, out}); ok {
+			from = Gain(g)
+		}
+
+		d.fader.Output(out, Fade{From: from, To: to, Duration: dur, Curve: d.cfg.Curve})
+	}
+}
@@ -0,0 +1,64 @@
+package tsunami
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestDucker(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	d := NewDucker(ts, DuckConfig{
+		Priority: []int{100},
+		Tracks:   map[int]Gain{1: -6},
+		Outputs:  map[int]Gain{2: 0},
+		Depth:    12,
+		Attack:   5 * time.Millisecond,
+		Release:  5 * time.Millisecond,
+	})
+
+	lastGains := func() (track, output int) {
+		msgs, err := port.Messages()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, m := range msgs {
+			switch m := m.(type) {
+			case *protocol.TrackVolume:
+				track = int(m.Gain)
+			case *protocol.MasterVolume:
+				output = int(m.Gain)
+			}
+		}
+
+		return track, output
+	}
+
+	port.Push(&protocol.TrackReport{Track: 100, Voice: 0, Playing: true})
+	if err := ts.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	d.fader.Wait()
+	if !d.Ducked() {
+		t.Errorf("should be ducked")
+	}
+
+	if trk, out := lastGains(); trk != -18 || out != -12 {
+		t.Errorf("unexpected ducked gains %d, %d", trk, out)
+	}
+
+	port.Push(&protocol.TrackReport{Track: 100, Voice: 0, Playing: false})
+	if err := ts.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	d.fader.Wait()
+	if trk, out := lastGains(); trk != -6 || out != 0 {
+		t.Errorf("unexpected restored gains %d, %d", trk, out)
+	}
+}
@@ -0,0 +1,35 @@
+package tsunami
+
+import "math"
+
+// Dynamics maps the intensity of a trigger, like a MIDI velocity or the
+// value of a sensor, to the gain of the track played, giving drum-pad style
+// dynamics to the triggers. A zero Dynamics plays every hit at unity gain.
+type Dynamics struct {
+	// Min and Max are the gains at the lowest and highest intensities.
+	Min, Max Gain
+	// Curve is the shape of the mapping between them, as for the fades.
+	Curve Curve
+}
+
+// Gain returns the gain for the intensity, from 0 to 1, values out of the
+// range are clamped.
+func (d Dynamics) Gain(intensity float64) Gain {
+	x := math.Max(0, math.Min(intensity, 1))
+	return Gain(d.Curve.gain(float64(d.Min), float64(d.Max), x))
+}
+
+// Play sets the gain of the track for the intensity and plays it with
+// TrackPlayPoly.
+func (d Dynamics) Play(p Player, trk, out int, intensity float64) error {
+	if err := p.TrackGain(trk, d.Gain(intensity)); err != nil {
+		return err
+	}
+
+	return p.TrackPlayPoly(trk, out, false)
+}
+
+// Velocity returns the intensity of a MIDI velocity, from 0 to 127.
+func Velocity(v int) float64 {
+	return float64(v) / 127
+}
@@ -0,0 +1,45 @@
+package tsunami
+
+import (
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestDynamicsGain(t *testing.T) {
+	d := Dynamics{Min: -40, Max: 0}
+	for intensity, expected := range map[float64]Gain{0: -40, 0.5: -20, 1: 0, 2: 0, -1: -40} {
+		if g := d.Gain(intensity); g != expected {
+			t.Errorf("intensity %v: got %s, expected %s", intensity, g, expected)
+		}
+	}
+
+	d.Curve = Linear
+	if g := d.Gain(0.5).Int(); g != -6 {
+		t.Errorf("linear curve at half intensity, got %d", g)
+	}
+}
+
+func TestDynamicsPlay(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+
+	d := Dynamics{Min: -30, Max: 0}
+	if err := d.Play(ts, 5, 1, Velocity(127)); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := msgs[0].(*protocol.TrackVolume); !ok || v.Track != 5 || v.Gain != 0 {
+		t.Errorf("unexpected gain %#v", msgs[0])
+	}
+
+	if c, ok := msgs[1].(*protocol.TrackControl); !ok || c.Code != TRK_PLAY_POLY || c.Output != 1 {
+		t.Errorf("unexpected control %#v", msgs[1])
+	}
+}
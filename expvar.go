@@ -0,0 +1,41 @@
+//go:build !tinygo
+
+package tsunami
+
+import (
+	"expvar"
+	"time"
+)
+
+// Vars returns the counters of the connection, and of the decoding of the
+// data received, as an expvar.Var, so they can be published with
+// expvar.Publish and read with GET /debug/vars in deployments without a
+// metrics system.
+func (t *Tsunami) Vars() expvar.Var {
+	return expvar.Func(func() interface{} {
+		c, stats := t.Counters(), t.Stats()
+
+		vars := map[string]interface{}{
+			"frames_sent":     c.Sent,
+			"frames_received": c.Received,
+			"write_errors":    c.WriteErrors,
+			"read_errors":     c.ReadErrors,
+			"resyncs":         stats.Resyncs,
+			"malformed":       stats.Malformed,
+			"uptime":          c.Uptime.Seconds(),
+			"last_event":      "",
+		}
+
+		if !c.LastEvent.IsZero() {
+			vars["last_event"] = c.LastEvent.Format(time.RFC3339Nano)
+		}
+
+		return vars
+	})
+}
+
+// PublishVars publishes the Vars of the connection with the given name. Like
+// expvar.Publish, it panics if the name is already published.
+func (t *Tsunami) PublishVars(name string) {
+	expvar.Publish(name, t.Vars())
+}
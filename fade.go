@@ -0,0 +1,372 @@
+package tsunami
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// DefaultFadeStep is the interval between the gain updates of a software
+// fade.
+const DefaultFadeStep = 20 * time.Millisecond
+
+// Curve is the shape of a software fade.
+type Curve uint8
+
+const (
+	// Exponential changes the gain at a constant rate in dB, the same as
+	// the hardware fades, perceived as a steady change of loudness.
+	Exponential Curve = iota
+	// Linear changes the amplitude at a constant rate, it falls quickly
+	// at the end of a fade out.
+	Linear
+	// SCurve is like Exponential but it starts and ends smoothly.
+	SCurve
+	// EqualPower follows a quarter of a sine in amplitude, two tracks
+	// crossfaded with it keep a constant total power.
+	EqualPower
+)
+
+var curveNames = map[Curve]string{
+	Exponential: "exponential",
+	Linear:      "linear",
+	SCurve:      "s-curve",
+	EqualPower:  "equal-power",
+}
+
+func (c Curve) String() string {
+	if name, ok := curveNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Curve(%d)", uint8(c))
+}
+
+// gain returns the gain, in dB, at the progress x, from 0 to 1, of a fade
+// from and to the given gains.
+func (c Curve) gain(from, to, x float64) float64 {
+	switch c {
+	case Linear:
+		a, b := Gain(from).Linear(), Gain(to).Linear()
+		return float64(GainFromLinear(a + (b-a)*x))
+	case SCurve:
+		x = x * x * (3 - 2*x)
+	case EqualPower:
+		a := x * math.Pi / 2
+		return float64(GainFromLinear(Gain(from).Linear()*math.Cos(a) + Gain(to).Linear()*math.Sin(a)))
+	}
+
+	return from + (to-from)*x
+}
+
+// Fade is a software fade, see Fader.
+type Fade struct {
+	// From and To are the initial and final gains.
+	From, To Gain
+	Duration time.Duration
+	Curve    Curve
+	// Stop stops the track at the end of the fade.
+	Stop bool
+	// Done is called when the fade finishes, or when it's canceled by
+	// another fade of the same track, by Cancel, by Close or by a failure
+	// writing its last step.
+	Done func(canceled bool)
+}
+
+// fadeKind is the setting driven by a fade.
+type fadeKind uint8
+
+const (
+	trackFade fadeKind = iota
+	outputFade
+	pitchGlide
+)
+
+type fadeKey struct {
+	kind fadeKind
+	n    int
+}
+
+type activeFade struct {
+	Fade
+	start time.Time
+	// at returns the value at the progress x, from 0 to 1, and end is the
+	// exact final value.
+	at   func(x float64) float64
+	end  float64
+	last float64
+	sent bool
+	set  func(v float64) error
+	stop func() error
+}
+
+// gainFade returns the activeFade of fd, sending the gains with set.
+func gainFade(fd Fade, set func(Gain) error) *activeFade {
+	return &activeFade{
+		Fade: fd,
+		at: func(x float64) float64 {
+			return fd.Curve.gain(float64(fd.From), float64(fd.To), x)
+		},
+		end: float64(fd.To),
+		set: func(v float64) error { return set(Gain(v)) },
+	}
+}
+
+// Fader drives software fades, stepping the gains at regular intervals.
+// Unlike the hardware fades, which are linear in dB, they support different
+// curves, can be canceled and notify when they are done. If the board has a
+// rate limit, see WithRateLimit, the steps are spaced accordingly.
+type Fader struct {
+	p    Player
+	step time.Duration
+
+	mu      sync.Mutex
+	fades   map[fadeKey]*activeFade
+	running bool
+	closed  bool
+	done    chan struct{}
+}
+
+// NewFader returns a Fader for the given board.
+func NewFader(p Player) *Fader {
+	step := DefaultFadeStep
+	if l, ok := p.(interface{ RateLimit() time.Duration }); ok && l.RateLimit() > step {
+		step = l.RateLimit()
+	}
+
+	return &Fader{
+		p:     p,
+		step:  step,
+		fades: make(map[fadeKey]*activeFade),
+		done:  make(chan struct{}),
+	}
+}
+
+// Track starts a fade of the given track, replacing any fade in progress of
+// the same track.
+func (f *Fader) Track(trk int, fd Fade) {
+	a := gainFade(fd, func(gain Gain) error { return f.p.TrackGain(trk, gain) })
+	a.stop = func() error { return f.p.TrackStop(trk) }
+	f.start(fadeKey{trackFade, trk}, a)
+}
+
+// Output starts a fade of the master gain of the given output, replacing any
+// fade in progress of the same output. Stop is ignored.
+func (f *Fader) Output(out int, fd Fade) {
+	f.start(fadeKey{outputFade, out}, gainFade(fd, func(gain Gain) error {
+		return f.p.MasterGain(out, gain)
+	}))
+}
+
+// Glide ramps linearly the samplerate offset of the given output, replacing
+// any glide in progress of the same output. done, if not nil, is called like
+// Fade.Done.
+func (f *Fader) Glide(out, from, to int, d time.Duration, done func(canceled bool)) {
+	f.start(fadeKey{pitchGlide, out}, &activeFade{
+		Fade: Fade{Duration: d, Done: done},
+		at: func(x float64) float64 {
+			return float64(from) + float64(to-from)*x
+		},
+		end: float64(to),
+		// the output is already at from, the first step is the next value.
+		last: float64(from),
+		sent: true,
+		set:  func(v float64) error { return f.p.SamplerateOffset(out, int(v)) },
+	})
+}
+
+// CancelOutput cancels the fade in progress of the given output, if any.
+func (f *Fader) CancelOutput(out int) {
+	f.cancel(fadeKey{outputFade, out})
+}
+
+// CancelTrack cancels the fade in progress of the given track, if any,
+// leaving the gain where it is.
+func (f *Fader) CancelTrack(trk int) {
+	f.cancel(fadeKey{trackFade, trk})
+}
+
+// CancelGlide cancels the glide in progress of the given output, if any.
+func (f *Fader) CancelGlide(out int) {
+	f.cancel(fadeKey{pitchGlide, out})
+}
+
+// Wait blocks until every fade is done.
+func (f *Fader) Wait() {
+	for {
+		f.mu.Lock()
+		running := f.running
+		f.mu.Unlock()
+
+		if !running {
+			return
+		}
+
+		time.Sleep(f.step)
+	}
+}
+
+// current returns the last value sent by the fade in progress of key, if any.
+func (f *Fader) current(key fadeKey) (float64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fd, ok := f.fades[key]
+	if !ok || !fd.sent {
+		return 0, false
+	}
+
+	return fd.last, true
+}
+
+func (f *Fader) start(key fadeKey, fd *activeFade) {
+	fd.start = time.Now()
+
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		if fd.Done != nil {
+			fd.Done(true)
+		}
+
+		return
+	}
+
+	prev := f.fades[key]
+	f.fades[key] = fd
+	if !f.running {
+		f.running = true
+		go f.run()
+	}
+	f.mu.Unlock()
+
+	if prev != nil && prev.Done != nil {
+		prev.Done(true)
+	}
+}
+
+func (f *Fader) cancel(key fadeKey) {
+	f.mu.Lock()
+	fd := f.fades[key]
+	delete(f.fades, key)
+	f.mu.Unlock()
+
+	if fd != nil && fd.Done != nil {
+		fd.Done(true)
+	}
+}
+
+// Close cancels the fades in progress and stops the Fader, the fades
+// started afterwards are canceled right away.
+func (f *Fader) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+
+	fades := f.fades
+	f.fades = make(map[fadeKey]*activeFade)
+	f.closed = true
+	close(f.done)
+	f.mu.Unlock()
+
+	for _, fd := range fades {
+		if fd.Done != nil {
+			fd.Done(true)
+		}
+	}
+
+	return nil
+}
+
+// run steps the fades until there are none left or the Fader is closed.
+func (f *Fader) run() {
+	ticker := time.NewTicker(f.step)
+	defer ticker.Stop()
+
+	for {
+		f.mu.Lock()
+		if len(f.fades) == 0 {
+			f.running = false
+			f.mu.Unlock()
+			return
+		}
+
+		fades := make(map[fadeKey]*activeFade, len(f.fades))
+		for key, fd := range f.fades {
+			fades[key] = fd
+		}
+		f.mu.Unlock()
+
+		for key, fd := range fades {
+			if done, err := f.stepFade(fd); done {
+				f.finish(key, fd, err != nil)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-f.done:
+			f.mu.Lock()
+			f.running = false
+			f.mu.Unlock()
+			return
+		}
+	}
+}
+
+// stepFade sends the current value of the fade, reporting whether it's done
+// and, if so, the error writing its last step.
+func (f *Fader) stepFade(fd *activeFade) (bool, error) {
+	x := 1.0
+	if fd.Duration > 0 {
+		x = math.Min(float64(time.Since(fd.start))/float64(fd.Duration), 1)
+	}
+
+	// the steps are rounded to whole units, the resolution of the board.
+	v := math.Round(fd.at(x))
+	if x == 1 {
+		v = fd.end
+	}
+
+	if fd.sent && v == fd.last {
+		return x == 1, nil
+	}
+
+	// a failed step is retried on the next one, the fade is given up when
+	// the last one fails.
+	if err := fd.set(v); err != nil {
+		return x == 1, err
+	}
+
+	f.mu.Lock()
+	fd.last, fd.sent = v, true
+	f.mu.Unlock()
+
+	return x == 1, nil
+}
+
+// finish removes the fade, stopping the track and calling Done. A canceled
+// fade doesn't stop the track.
+func (f *Fader) finish(key fadeKey, fd *activeFade, canceled bool) {
+	f.mu.Lock()
+	current := f.fades[key] == fd
+	if current {
+		delete(f.fades, key)
+	}
+	f.mu.Unlock()
+
+	if !current {
+		return
+	}
+
+	if fd.Stop && fd.stop != nil && !canceled {
+		fd.stop()
+	}
+
+	if fd.Done != nil {
+		fd.Done(canceled)
+	}
+}
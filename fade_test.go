@@ -0,0 +1,191 @@
+package tsunami
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestCurveGain(t *testing.T) {
+	for _, c := range []Curve{Exponential, Linear, SCurve, EqualPower} {
+		if g := c.gain(-70, 0, 0); g != -70 {
+			t.Errorf("%s: unexpected start gain %v", c, g)
+		}
+
+		if g := c.gain(-70, 0, 1); math.Abs(g) > 1e-9 {
+			t.Errorf("%s: unexpected end gain %v", c, g)
+		}
+
+		prev := math.Inf(-1)
+		for x := 0.0; x <= 1; x += 0.05 {
+			g := c.gain(-70, 0, x)
+			if g < prev {
+				t.Errorf("%s: fade in should be monotonic, %v after %v", c, g, prev)
+			}
+
+			prev = g
+		}
+	}
+
+	if g := EqualPower.gain(-70, 0, 0.5); math.Abs(g+3.01) > 0.01 {
+		t.Errorf("equal power should be -3dB at the middle, got %v", g)
+	}
+}
+
+func TestFader(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	f := NewFader(NewTsunamiTransport(port))
+
+	done := make(chan bool, 2)
+	f.Track(19, Fade{From: 0, To: -70, Duration: 10 * time.Millisecond, Stop: true,
+		Done: func(canceled bool) { done <- canceled },
+	})
+
+	f.Track(20, Fade{From: -70, To: 0, Duration: time.Hour})
+	f.CancelTrack(20)
+	f.Wait()
+
+	if canceled := <-done; canceled {
+		t.Errorf("fade should finish")
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var first, last protocol.Message
+	for _, m := range msgs {
+		if v, ok := m.(*protocol.TrackVolume); ok && v.Track == 19 {
+			if first == nil {
+				first = m
+			}
+
+			last = m
+		}
+	}
+
+	if v, ok := first.(*protocol.TrackVolume); !ok || v.Gain != 0 {
+		t.Errorf("unexpected first step %v", first)
+	}
+
+	if v, ok := last.(*protocol.TrackVolume); !ok || v.Gain != -70 {
+		t.Errorf("unexpected last step %v", last)
+	}
+
+	stop, ok := msgs[len(msgs)-1].(*protocol.TrackControl)
+	if !ok || stop.Code != TRK_STOP || stop.Track != 19 {
+		t.Errorf("track should be stopped at the end, got %v", msgs[len(msgs)-1])
+	}
+}
+
+func TestFaderFailedWrite(t *testing.T) {
+	f := NewFader(NewTsunamiTransport(failPort{}))
+
+	done := make(chan bool, 1)
+	f.Track(19, Fade{From: 0, To: -70, Duration: 10 * time.Millisecond, Stop: true,
+		Done: func(canceled bool) { done <- canceled },
+	})
+
+	select {
+	case canceled := <-done:
+		if !canceled {
+			t.Errorf("a fade failing its last step should be canceled")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the fade should be given up")
+	}
+
+	f.Wait()
+}
+
+func TestFaderClose(t *testing.T) {
+	f := NewFader(NewTsunamiTransport(discardPort{}))
+
+	done := make(chan bool, 2)
+	f.Track(19, Fade{From: 0, To: -70, Duration: time.Hour,
+		Done: func(canceled bool) { done <- canceled },
+	})
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f.Wait()
+
+	f.Track(20, Fade{From: 0, To: -70, Duration: time.Hour,
+		Done: func(canceled bool) { done <- canceled },
+	})
+
+	for i := 0; i < 2; i++ {
+		if canceled := <-done; !canceled {
+			t.Errorf("fade %d should be canceled", i)
+		}
+	}
+
+	f.Wait()
+}
+
+func TestWithRateLimit(t *testing.T) {
+	ts := NewTsunamiTransport(discardPort{}, WithRateLimit(5*time.Millisecond))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := ts.TrackGain(19, Gain(-i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("commands should be spaced, took %s", elapsed)
+	}
+
+	if f := NewFader(ts); f.step != DefaultFadeStep {
+		t.Errorf("unexpected step %s", f.step)
+	}
+}
+
+func TestMasterFade(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+
+	if err := ts.MasterGain(1, -10); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.MasterFadeAll(MinGain, 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	ts.fader.Wait()
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := make(map[uint8]int16)
+	for _, m := range msgs[1:] {
+		v := m.(*protocol.MasterVolume)
+		if _, ok := first[v.Output]; !ok {
+			first[v.Output] = v.Gain
+		}
+	}
+
+	if len(first) != MaxOutputs || first[0] < -10 || first[1] > -10 || first[1] < -20 {
+		t.Errorf("fades should start from the current gains, got %v", first)
+	}
+
+	for out, gain := range ts.masterGains {
+		if gain != MinGain {
+			t.Errorf("output %d: unexpected gain %s", out, gain)
+		}
+	}
+
+	if err := ts.MasterFade(MaxOutputs, 0, time.Second); err == nil {
+		t.Errorf("expected error on invalid output")
+	}
+}
@@ -0,0 +1,65 @@
+package tsunami
+
+import (
+	"fmt"
+	"math"
+)
+
+// Gain is a gain in dB. The board works with whole dB, the gains are rounded
+// when sent, and clamped to the range of the command: -70 to +10 for tracks
+// and -70 to +4 for outputs. MinGain is silence.
+type Gain float64
+
+const (
+	// MinGain is the lowest gain of the board, a muted output or track.
+	MinGain = -70
+	// MaxTrackGain is the highest gain of a track.
+	MaxTrackGain = 10
+	// MaxMasterGain is the highest gain of an output.
+	MaxMasterGain = 4
+)
+
+// GainFromLinear returns the gain of the given amplitude ratio, eg. 0.5 is
+// about -6 dB. Ratios too low to be represented are MinGain.
+func GainFromLinear(ratio float64) Gain {
+	if ratio <= 0 {
+		return MinGain
+	}
+
+	return Gain(20*math.Log10(ratio)).Clamp(MinGain, math.MaxFloat64)
+}
+
+// Linear returns the amplitude ratio of the gain, MinGain or lower is 0.
+func (g Gain) Linear() float64 {
+	if g <= MinGain {
+		return 0
+	}
+
+	return math.Pow(10, float64(g)/20)
+}
+
+// Add returns the gain increased by d, never lower than MinGain.
+func (g Gain) Add(d Gain) Gain {
+	return (g + d).Clamp(MinGain, math.MaxFloat64)
+}
+
+// Clamp returns the gain limited to the given range.
+func (g Gain) Clamp(min, max Gain) Gain {
+	switch {
+	case g < min:
+		return min
+	case g > max:
+		return max
+	}
+
+	return g
+}
+
+// Int returns the gain rounded to whole dB, as sent to the board.
+func (g Gain) Int() int {
+	return int(math.Round(float64(g)))
+}
+
+func (g Gain) String() string {
+	return fmt.Sprintf("%+.1f dB", float64(g))
+}
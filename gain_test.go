@@ -0,0 +1,66 @@
+package tsunami
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestGain(t *testing.T) {
+	if g := GainFromLinear(0.5); math.Abs(float64(g)+6.02) > 0.01 {
+		t.Errorf("unexpected gain of 0.5: %s", g)
+	}
+
+	if g := GainFromLinear(0); g != MinGain {
+		t.Errorf("silence should be MinGain, got %s", g)
+	}
+
+	if l := Gain(-6.0206).Linear(); math.Abs(l-0.5) > 1e-4 {
+		t.Errorf("unexpected linear %v", l)
+	}
+
+	if l := Gain(MinGain).Linear(); l != 0 {
+		t.Errorf("MinGain should be silence, got %v", l)
+	}
+
+	if g := Gain(-65).Add(-10); g != MinGain {
+		t.Errorf("unexpected sum %s", g)
+	}
+
+	if g := Gain(-3.5).Int(); g != -4 {
+		t.Errorf("unexpected rounding %d", g)
+	}
+
+	if s := Gain(-6).String(); s != "-6.0 dB" {
+		t.Errorf("unexpected string %q", s)
+	}
+}
+
+func TestGainClamped(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	ts.MasterGain(0, 12)
+	ts.TrackGain(19, 12)
+	ts.TrackGain(19, -90.4)
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expected := range []int16{MaxMasterGain, MaxTrackGain, MinGain} {
+		var got int16
+		switch m := msgs[i].(type) {
+		case *protocol.MasterVolume:
+			got = m.Gain
+		case *protocol.TrackVolume:
+			got = m.Gain
+		}
+
+		if got != expected {
+			t.Errorf("%d: got gain %d, expected %d", i, got, expected)
+		}
+	}
+}
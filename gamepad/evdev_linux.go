@@ -0,0 +1,149 @@
+//go:build !tinygo
+
+package gamepad
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// Types of the evdev events.
+const (
+	evKey = 0x01
+	evAbs = 0x03
+)
+
+// eventSize is the size of struct input_event, a timeval followed by the
+// type, the code and the value.
+var eventSize = int(unsafe.Sizeof(syscall.Timeval{})) + 8
+
+// Device is a gamepad, or a joystick, read through its evdev device node.
+type Device struct {
+	f    *os.File
+	name string
+	// ranges are the minimum and maximum of the axes, by code.
+	ranges map[int][2]int32
+	buf    []byte
+}
+
+var _ Source = (*Device)(nil)
+
+// Open opens the evdev device node with the given name, like
+// "/dev/input/event3", see Devices.
+func Open(name string) (*Device, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Device{f: f, ranges: make(map[int][2]int32), buf: make([]byte, eventSize)}
+	var buf [256]byte
+	if err := d.ioctl(ioc(0x06, len(buf)), unsafe.Pointer(&buf[0])); err == nil {
+		d.name = string(buf[:clen(buf[:])])
+	}
+
+	return d, nil
+}
+
+// Devices returns the device nodes of the joysticks and gamepads plugged,
+// by their persistent names.
+func Devices() ([]string, error) {
+	return filepath.Glob("/dev/input/by-id/*-event-joystick")
+}
+
+// Name returns the name of the device, as reported by its driver.
+func (d *Device) Name() string {
+	return d.name
+}
+
+// ReadEvent implements Source. The repeats of the buttons held are
+// skipped.
+func (d *Device) ReadEvent() (Event, error) {
+	for {
+		if _, err := io.ReadFull(d.f, d.buf); err != nil {
+			return Event{}, err
+		}
+
+		data := d.buf[eventSize-8:]
+		typ := binary.LittleEndian.Uint16(data)
+		code := int(binary.LittleEndian.Uint16(data[2:]))
+		value := int32(binary.LittleEndian.Uint32(data[4:]))
+
+		switch {
+		case typ == evKey && value != 2:
+			return Event{Type: Button, Code: code, Value: float64(value)}, nil
+		case typ == evAbs:
+			r := d.axisRange(code)
+			if r[1] <= r[0] {
+				continue
+			}
+
+			pos := float64(value-r[0]) / float64(r[1]-r[0])
+			return Event{Type: Axis, Code: code, Value: pos}, nil
+		}
+	}
+}
+
+// axisRange returns the minimum and maximum of the axis.
+func (d *Device) axisRange(code int) [2]int32 {
+	if r, ok := d.ranges[code]; ok {
+		return r
+	}
+
+	// struct input_absinfo: value, minimum, maximum, fuzz, flat and
+	// resolution.
+	var info [6]int32
+	var r [2]int32
+	if err := d.ioctl(ioc(0x40+code, int(unsafe.Sizeof(info))), unsafe.Pointer(&info)); err == nil {
+		r = [2]int32{info[1], info[2]}
+	}
+
+	d.ranges[code] = r
+	return r
+}
+
+// ioctl runs a request on the device, without setting it to blocking mode,
+// so Close interrupts ReadEvent.
+func (d *Device) ioctl(req uintptr, arg unsafe.Pointer) error {
+	c, err := d.f.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var errno syscall.Errno
+	if err := c.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+	}); err != nil {
+		return err
+	}
+
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// Close closes the device node.
+func (d *Device) Close() error {
+	return d.f.Close()
+}
+
+// ioc returns the number of an evdev read request, _IOR('E', nr, size).
+func ioc(nr, size int) uintptr {
+	return 2<<30 | uintptr(size)<<16 | 'E'<<8 | uintptr(nr)
+}
+
+func clen(b []byte) int {
+	for i, c := range b {
+		if c == 0 {
+			return i
+		}
+	}
+
+	return len(b)
+}
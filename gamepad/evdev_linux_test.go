@@ -0,0 +1,44 @@
+package gamepad
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+)
+
+func TestDeviceReadEvent(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Device{f: r, ranges: map[int][2]int32{AxisY: {-100, 100}}, buf: make([]byte, eventSize)}
+	defer d.Close()
+
+	for _, e := range [][3]int{
+		{evKey, BtnSouth, 1},
+		{evKey, BtnSouth, 2}, // repeat
+		{0, 0, 0},            // EV_SYN
+		{evAbs, AxisY, 50},
+		{evAbs, AxisX, 50}, // range unknown
+		{evKey, BtnSouth, 0},
+	} {
+		b := make([]byte, eventSize)
+		data := b[eventSize-8:]
+		binary.LittleEndian.PutUint16(data, uint16(e[0]))
+		binary.LittleEndian.PutUint16(data[2:], uint16(e[1]))
+		binary.LittleEndian.PutUint32(data[4:], uint32(int32(e[2])))
+		w.Write(b)
+	}
+
+	w.Close()
+	for _, expected := range []Event{
+		{Type: Button, Code: BtnSouth, Value: 1},
+		{Type: Axis, Code: AxisY, Value: 0.75},
+		{Type: Button, Code: BtnSouth, Value: 0},
+	} {
+		if e, err := d.ReadEvent(); err != nil || e != expected {
+			t.Errorf("unexpected event %+v, %v", e, err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+//go:build !linux && !tinygo
+
+package gamepad
+
+import "errors"
+
+// errUnsupported is returned by Open out of Linux.
+var errUnsupported = errors.New("gamepad: evdev is only available on Linux")
+
+// Device is a gamepad, or a joystick, read through its evdev device node.
+type Device struct{}
+
+var _ Source = (*Device)(nil)
+
+// Open opens the evdev device node with the given name, it's only available
+// on Linux.
+func Open(name string) (*Device, error) {
+	return nil, errUnsupported
+}
+
+// Devices returns the device nodes of the joysticks and gamepads plugged,
+// none out of Linux.
+func Devices() ([]string, error) {
+	return nil, nil
+}
+
+// Name returns the name of the device.
+func (d *Device) Name() string {
+	return ""
+}
+
+// ReadEvent implements Source.
+func (d *Device) ReadEvent() (Event, error) {
+	return Event{}, errUnsupported
+}
+
+// Close implements Source.
+func (d *Device) Close() error {
+	return nil
+}
@@ -0,0 +1,275 @@
+// Package gamepad triggers the board from the buttons and axes of a gamepad
+// or a joystick, a cheap and robust remote for the interactive exhibits:
+// the buttons are mapped to tracks, and an axis may follow the gain of an
+// output. The devices are read through evdev, on Linux.
+package gamepad
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// Codes of the common buttons and axes of the gamepads, as defined by the
+// Linux input subsystem.
+const (
+	BtnSouth  = 0x130
+	BtnEast   = 0x131
+	BtnNorth  = 0x133
+	BtnWest   = 0x134
+	BtnTL     = 0x136
+	BtnTR     = 0x137
+	BtnSelect = 0x13a
+	BtnStart  = 0x13b
+
+	AxisX  = 0x00
+	AxisY  = 0x01
+	AxisZ  = 0x02
+	AxisRX = 0x03
+	AxisRY = 0x04
+	AxisRZ = 0x05
+)
+
+// EventType is the kind of an Event.
+type EventType uint8
+
+const (
+	// Button is the press or the release of a button.
+	Button EventType = iota
+	// Axis is the move of an axis.
+	Axis
+)
+
+var eventTypeNames = map[EventType]string{
+	Button: "button",
+	Axis:   "axis",
+}
+
+func (t EventType) String() string {
+	if name, ok := eventTypeNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("EventType(%d)", uint8(t))
+}
+
+// Event is an input of a gamepad.
+type Event struct {
+	Type EventType
+	Code int
+	// Value is 1 for the buttons pressed and 0 for the released ones, and
+	// the position of the axes, from 0 at their minimum to 1 at their
+	// maximum.
+	Value float64
+}
+
+// ActionType is the kind of a Mapping.
+type ActionType string
+
+const (
+	// Play plays the track when the button is pressed, and stops it when
+	// released if Stop is set.
+	Play ActionType = "play"
+	// Toggle plays the track when the button is pressed, or stops it if
+	// it's playing, it requires the reporting of the board.
+	Toggle ActionType = "toggle"
+	// Stop stops the track when the button is pressed.
+	Stop ActionType = "stop"
+	// StopAll stops all the tracks when the button is pressed.
+	StopAll ActionType = "stopall"
+	// MasterGain follows the position of the axis with the gain of the
+	// output, from MinGain at its minimum to 0 dB at its maximum.
+	MasterGain ActionType = "master"
+)
+
+// Mapping maps a button, or an axis, to an action, eg.:
+//
+//	{"button": 304, "type": "play", "track": 19, "output": 0}
+//	{"axis": 1, "type": "master", "output": 0, "invert": true}
+type Mapping struct {
+	// Button is the code of the button, like BtnSouth.
+	Button int `json:"button,omitempty"`
+	// Axis is the code of the axis, like AxisY, of the MasterGain
+	// mappings.
+	Axis   int        `json:"axis,omitempty"`
+	Type   ActionType `json:"type"`
+	Track  int        `json:"track,omitempty"`
+	Output int        `json:"output,omitempty"`
+	Lock   bool       `json:"lock,omitempty"`
+	// Stop stops the track of Play when the button is released.
+	Stop bool `json:"stop,omitempty"`
+	// Invert inverts the axis, the gamepads usually report the sticks up
+	// as their minimum.
+	Invert bool `json:"invert,omitempty"`
+}
+
+func (m *Mapping) validate() error {
+	if m.Output < 0 || m.Output >= tsunami.MaxOutputs {
+		return fmt.Errorf("%s: invalid output %d", m.input(), m.Output)
+	}
+
+	switch m.Type {
+	case Play, Toggle, Stop:
+		if m.Track < 1 || m.Track > tsunami.MaxTracks {
+			return fmt.Errorf("%s: invalid track %d", m.input(), m.Track)
+		}
+	case StopAll, MasterGain:
+	default:
+		return fmt.Errorf("%s: unknown action %q", m.input(), m.Type)
+	}
+
+	if m.Type != MasterGain && m.Button == 0 {
+		return fmt.Errorf("%s action without button", m.Type)
+	}
+
+	return nil
+}
+
+// input describes the button or axis of the mapping, for the errors.
+func (m *Mapping) input() string {
+	if m.Type == MasterGain {
+		return fmt.Sprintf("axis %#x", m.Axis)
+	}
+
+	return fmt.Sprintf("button %#x", m.Button)
+}
+
+// LoadMappings reads the mappings as a JSON array.
+func LoadMappings(r io.Reader) ([]Mapping, error) {
+	var m []Mapping
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Gain returns the gain of an axis position, from MinGain at 0 to 0 dB at
+// 1.
+func Gain(pos float64) tsunami.Gain {
+	return tsunami.Gain(tsunami.MinGain*(1-pos)).Clamp(tsunami.MinGain, 0)
+}
+
+// Mapper runs the actions of the mappings on the board, as the events of
+// the gamepad arrive.
+type Mapper struct {
+	p        tsunami.Player
+	mappings []Mapping
+
+	mu sync.Mutex
+	// gains are the gains sent by the axes, by output, so the moves below
+	// a decibel aren't sent.
+	gains map[int]int
+}
+
+// NewMapper returns a mapper running the mappings on the player.
+func NewMapper(p tsunami.Player, mappings ...Mapping) (*Mapper, error) {
+	for i := range mappings {
+		if err := mappings[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Mapper{p: p, mappings: mappings, gains: make(map[int]int)}, nil
+}
+
+// Handle runs the actions mapped to the event.
+func (m *Mapper) Handle(e Event) error {
+	var err error
+	for _, mp := range m.mappings {
+		var e2 error
+		switch {
+		case e.Type == Button && mp.Type != MasterGain && mp.Button == e.Code:
+			e2 = m.button(mp, e.Value != 0)
+		case e.Type == Axis && mp.Type == MasterGain && mp.Axis == e.Code:
+			e2 = m.axis(mp, e.Value)
+		default:
+			continue
+		}
+
+		if e2 != nil && err == nil {
+			err = fmt.Errorf("%s: %w", mp.input(), e2)
+		}
+	}
+
+	return err
+}
+
+func (m *Mapper) button(mp Mapping, pressed bool) error {
+	if !pressed {
+		if mp.Type == Play && mp.Stop {
+			return m.p.TrackStop(mp.Track)
+		}
+
+		return nil
+	}
+
+	switch mp.Type {
+	case Toggle:
+		if m.p.IsTrackPlaying(mp.Track) {
+			return m.p.TrackStop(mp.Track)
+		}
+	case Stop:
+		return m.p.TrackStop(mp.Track)
+	case StopAll:
+		return m.p.StopAllTracks()
+	}
+
+	return m.p.TrackPlayPoly(mp.Track, mp.Output, mp.Lock)
+}
+
+func (m *Mapper) axis(mp Mapping, pos float64) error {
+	if mp.Invert {
+		pos = 1 - pos
+	}
+
+	g := Gain(pos)
+
+	m.mu.Lock()
+	last, ok := m.gains[mp.Output]
+	if ok && last == g.Int() {
+		m.mu.Unlock()
+		return nil
+	}
+
+	m.gains[mp.Output] = g.Int()
+	m.mu.Unlock()
+
+	return m.p.MasterGain(mp.Output, g)
+}
+
+// Source is a source of gamepad events, like a Device.
+type Source interface {
+	// ReadEvent blocks until the next event.
+	ReadEvent() (Event, error)
+	Close() error
+}
+
+// Serve runs the events read from the source on the mapper until the
+// context is done, closing the source, or reading fails. The errors of the
+// actions are passed to onError, if not nil.
+func Serve(ctx context.Context, src Source, m *Mapper, onError func(error)) error {
+	go func() {
+		<-ctx.Done()
+		src.Close()
+	}()
+
+	for {
+		e, err := src.ReadEvent()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		if err := m.Handle(e); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
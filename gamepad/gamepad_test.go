@@ -0,0 +1,102 @@
+package gamepad
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// events is a Source of the given events.
+type events []Event
+
+func (e *events) ReadEvent() (Event, error) {
+	if len(*e) == 0 {
+		return Event{}, io.EOF
+	}
+
+	ev := (*e)[0]
+	*e = (*e)[1:]
+	return ev, nil
+}
+
+func (e *events) Close() error { return nil }
+
+func TestMapper(t *testing.T) {
+	mappings, err := LoadMappings(strings.NewReader(`[
+	  {"button": 304, "type": "play", "track": 19, "output": 1, "stop": true},
+	  {"button": 305, "type": "toggle", "track": 20},
+	  {"button": 315, "type": "stopall"},
+	  {"axis": 1, "type": "master", "output": 2, "invert": true}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	m, err := NewMapper(ts, mappings...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := &events{
+		{Type: Button, Code: BtnSouth, Value: 1},
+		{Type: Button, Code: BtnSouth, Value: 0},
+		{Type: Button, Code: BtnEast, Value: 1},
+		{Type: Axis, Code: AxisY, Value: 0},
+		{Type: Axis, Code: AxisY, Value: 0.001},
+		{Type: Axis, Code: AxisY, Value: 1},
+		{Type: Axis, Code: AxisX, Value: 1},
+		{Type: Button, Code: BtnStart, Value: 1},
+	}
+
+	if err := Serve(context.Background(), src, m, nil); err != io.EOF {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	msgs, _ := port.Messages()
+	var got []string
+	for _, msg := range msgs {
+		got = append(got, protocol.Describe(msg))
+	}
+
+	expected := []protocol.Message{
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 19, Output: 1},
+		&protocol.TrackControl{Code: tsunami.TRK_STOP, Track: 19},
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 20},
+		&protocol.MasterVolume{Output: 2},
+		&protocol.MasterVolume{Output: 2, Gain: tsunami.MinGain},
+		&protocol.StopAll{},
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("unexpected commands %q", got)
+	}
+
+	for i, msg := range expected {
+		if got[i] != protocol.Describe(msg) {
+			t.Errorf("%d: got %q, expected %q", i, got[i], protocol.Describe(msg))
+		}
+	}
+}
+
+func TestNewMapperValidate(t *testing.T) {
+	for _, c := range []struct {
+		m   Mapping
+		err string
+	}{
+		{Mapping{Button: BtnSouth, Type: Play}, "button 0x130: invalid track 0"},
+		{Mapping{Button: BtnSouth, Type: "jump"}, `button 0x130: unknown action "jump"`},
+		{Mapping{Axis: AxisY, Type: MasterGain, Output: 8}, "axis 0x1: invalid output 8"},
+		{Mapping{Type: StopAll}, "stopall action without button"},
+	} {
+		if _, err := NewMapper(nil, c.m); err == nil || err.Error() != c.err {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+}
@@ -2,9 +2,12 @@ module github.com/mcuadros/go-tsunami
 
 go 1.19
 
+require (
+	github.com/tarm/serial v0.0.0-20180830185346-98f6abe2eb07
+	go.bug.st/serial v1.4.1
+)
+
 require (
 	github.com/creack/goselect v0.1.2 // indirect
-	github.com/tarm/serial v0.0.0-20180830185346-98f6abe2eb07 // indirect
-	go.bug.st/serial v1.4.1 // indirect
 	golang.org/x/sys v0.0.0-20210823070655-63515b42dcdf // indirect
 )
@@ -0,0 +1,228 @@
+// Package gpio drives digital outputs, like a "busy" lamp or a relay
+// holding a door, while the tracks of a board play, as the animatronic props
+// usually require. The state of the tracks is followed with the track
+// reports, see tsunami.Tsunami.Subscribe.
+package gpio
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// Pin is a digital output. It's implemented by the sysfs pins of Linux, see
+// OpenSysfs, and any other GPIO library can be adapted with PinFunc.
+type Pin interface {
+	Out(high bool) error
+}
+
+// PinFunc is a function implementing Pin, eg. for the pins of periph.io:
+//
+//	gpio.PinFunc(func(high bool) error { return p.Out(periphgpio.Level(high)) })
+type PinFunc func(high bool) error
+
+// Out implements Pin.
+func (f PinFunc) Out(high bool) error {
+	return f(high)
+}
+
+// Output is a pin active while the tracks play.
+type Output struct {
+	// Name identifies the output in the errors.
+	Name string
+	Pin  Pin
+	// Tracks are the tracks activating the output, any track if empty.
+	Tracks []int
+	// ActiveLow drives the pin low while active, as many relay boards
+	// require.
+	ActiveLow bool
+	// Hold keeps the output active after the tracks end, so it doesn't
+	// flicker between tracks played one after the other.
+	Hold time.Duration
+}
+
+// Status drives the outputs with the state of the tracks of a board.
+type Status struct {
+	outputs []*output
+	cancel  func()
+
+	mu      sync.Mutex
+	playing map[int]bool
+	onError func(error)
+	closed  bool
+}
+
+type output struct {
+	Output
+	tracks map[int]bool
+	active bool
+	hold   *time.Timer
+}
+
+// NewStatus returns the status of the board driving the outputs, all of
+// them inactive, subscribed to its messages.
+func NewStatus(b tsunami.Board, outputs ...Output) (*Status, error) {
+	s := &Status{playing: make(map[int]bool)}
+	for i, o := range outputs {
+		if o.Name == "" {
+			o.Name = fmt.Sprintf("output %d", i)
+		}
+
+		if o.Pin == nil {
+			return nil, fmt.Errorf("%s: missing pin", o.Name)
+		}
+
+		out := &output{Output: o, tracks: make(map[int]bool, len(o.Tracks))}
+		for _, trk := range o.Tracks {
+			if trk <= 0 || trk > tsunami.MaxTracks {
+				return nil, fmt.Errorf("%s: invalid track %d", o.Name, trk)
+			}
+
+			out.tracks[trk] = true
+		}
+
+		if err := out.set(false); err != nil {
+			return nil, err
+		}
+
+		s.outputs = append(s.outputs, out)
+	}
+
+	s.cancel = b.Subscribe(s.handle)
+	return s, nil
+}
+
+// OnError registers a function to be called with the errors driving the
+// pins.
+func (s *Status) OnError(fn func(error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onError = fn
+}
+
+// Active reports whether the output with the given name is active.
+func (s *Status) Active(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, o := range s.outputs {
+		if o.Name == name {
+			return o.active
+		}
+	}
+
+	return false
+}
+
+// Close deactivates the outputs and unsubscribes the Status from the board.
+func (s *Status) Close() error {
+	s.cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	var err error
+	for _, o := range s.outputs {
+		if o.hold != nil {
+			o.hold.Stop()
+			o.hold = nil
+		}
+
+		if e := o.set(false); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+func (s *Status) handle(msg protocol.Message) {
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	if r.Playing {
+		s.playing[int(r.Track)] = true
+	} else {
+		delete(s.playing, int(r.Track))
+	}
+
+	for _, o := range s.outputs {
+		s.update(o)
+	}
+}
+
+// update drives the output with the tracks playing.
+func (s *Status) update(o *output) {
+	active := s.activates(o)
+	if active && o.hold != nil {
+		o.hold.Stop()
+		o.hold = nil
+	}
+
+	if active == o.active || (!active && o.hold != nil) {
+		return
+	}
+
+	if !active && o.Hold > 0 {
+		var hold *time.Timer
+		hold = time.AfterFunc(o.Hold, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			// the hold may have been canceled, or replaced, meanwhile.
+			if o.hold == hold {
+				o.hold = nil
+				s.fail(o.set(false))
+			}
+		})
+
+		o.hold = hold
+		return
+	}
+
+	s.fail(o.set(active))
+}
+
+// activates reports whether any of the tracks of the output is playing.
+func (s *Status) activates(o *output) bool {
+	if len(o.tracks) == 0 {
+		return len(s.playing) > 0
+	}
+
+	for trk := range s.playing {
+		if o.tracks[trk] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Status) fail(err error) {
+	if err != nil && s.onError != nil {
+		s.onError(err)
+	}
+}
+
+func (o *output) set(active bool) error {
+	if err := o.Pin.Out(active != o.ActiveLow); err != nil {
+		return fmt.Errorf("%s: %w", o.Name, err)
+	}
+
+	o.active = active
+	return nil
+}
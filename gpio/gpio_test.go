@@ -0,0 +1,154 @@
+package gpio
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// pin records the levels driven.
+type pin struct {
+	mu     sync.Mutex
+	levels []bool
+}
+
+func (p *pin) Out(high bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.levels = append(p.levels, high)
+	return nil
+}
+
+func (p *pin) Levels() []bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]bool(nil), p.levels...)
+}
+
+func report(t *testing.T, ts *tsunami.Tsunami, port *transport.Loopback, trk int, playing bool) {
+	port.Push(&protocol.TrackReport{Track: uint16(trk), Playing: playing})
+	if err := ts.Update(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+
+	busy, door := &pin{}, &pin{}
+	s, err := NewStatus(ts,
+		Output{Name: "busy", Pin: busy},
+		Output{Name: "door", Pin: door, Tracks: []int{19, 20}, ActiveLow: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report(t, ts, port, 1, true)
+	report(t, ts, port, 19, true)
+	if !s.Active("busy") || !s.Active("door") {
+		t.Errorf("the outputs should be active")
+	}
+
+	report(t, ts, port, 1, false)
+	report(t, ts, port, 20, true)
+	report(t, ts, port, 19, false)
+	report(t, ts, port, 20, false)
+
+	if s.Active("busy") || s.Active("door") {
+		t.Errorf("the outputs should be inactive")
+	}
+
+	if got := busy.Levels(); !reflect.DeepEqual(got, []bool{false, true, false}) {
+		t.Errorf("unexpected busy levels %v", got)
+	}
+
+	if got := door.Levels(); !reflect.DeepEqual(got, []bool{true, false, true}) {
+		t.Errorf("unexpected door levels %v", got)
+	}
+}
+
+func TestStatusHold(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+
+	lamp := &pin{}
+	s, _ := NewStatus(ts, Output{Name: "lamp", Pin: lamp, Hold: 20 * time.Millisecond})
+
+	report(t, ts, port, 1, true)
+	report(t, ts, port, 1, false)
+	report(t, ts, port, 2, true)
+	report(t, ts, port, 2, false)
+	if !s.Active("lamp") {
+		t.Errorf("the lamp should be held")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if s.Active("lamp") {
+		t.Errorf("the lamp should be released after the hold")
+	}
+
+	if got := lamp.Levels(); !reflect.DeepEqual(got, []bool{false, true, false}) {
+		t.Errorf("unexpected levels %v", got)
+	}
+
+	report(t, ts, port, 1, true)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	report(t, ts, port, 2, true)
+	if got := lamp.Levels(); !reflect.DeepEqual(got, []bool{false, true, false, true, false}) {
+		t.Errorf("unexpected levels after close %v", got)
+	}
+}
+
+func TestStatusErrors(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+
+	if _, err := NewStatus(ts, Output{Pin: &pin{}, Tracks: []int{0}}); err == nil || err.Error() != "output 0: invalid track 0" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, err := NewStatus(ts, Output{Name: "lamp"}); err == nil || err.Error() != "lamp: missing pin" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	fail := true
+	s, err := NewStatus(ts, Output{Name: "relay", Pin: PinFunc(func(bool) error {
+		if fail {
+			return errors.New("busy")
+		}
+
+		return nil
+	})})
+	if err == nil || err.Error() != "relay: busy" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	fail = false
+	s, _ = NewStatus(ts, Output{Name: "relay", Pin: PinFunc(func(high bool) error {
+		if high {
+			return errors.New("busy")
+		}
+
+		return nil
+	})})
+
+	var errs []error
+	s.OnError(func(err error) { errs = append(errs, err) })
+	report(t, ts, port, 1, true)
+	if len(errs) != 1 || errs[0].Error() != "relay: busy" || s.Active("relay") {
+		t.Errorf("unexpected errors %v", errs)
+	}
+}
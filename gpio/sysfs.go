@@ -0,0 +1,67 @@
+package gpio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// sysfsRoot is the directory of the sysfs GPIO interface.
+var sysfsRoot = "/sys/class/gpio"
+
+// sysfsExportWait is how long the pin directory is waited for after the
+// export, udev may take a while to set its permissions.
+const sysfsExportWait = time.Second
+
+// SysfsPin is an output pin of the sysfs GPIO interface of Linux, deprecated
+// by the kernel but available on most single-board computers.
+type SysfsPin struct {
+	value *os.File
+}
+
+// OpenSysfs exports the GPIO with the given number, if it isn't yet, and
+// sets it as an output.
+func OpenSysfs(n int) (*SysfsPin, error) {
+	dir := filepath.Join(sysfsRoot, fmt.Sprintf("gpio%d", n))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.WriteFile(filepath.Join(sysfsRoot, "export"), []byte(fmt.Sprint(n)), 0); err != nil {
+			return nil, err
+		}
+	}
+
+	direction := filepath.Join(dir, "direction")
+	var err error
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if err = os.WriteFile(direction, []byte("out"), 0); err == nil || time.Since(start) > sysfsExportWait {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, "value"), os.O_WRONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SysfsPin{value: f}, nil
+}
+
+// Out implements Pin.
+func (p *SysfsPin) Out(high bool) error {
+	v := "0"
+	if high {
+		v = "1"
+	}
+
+	_, err := p.value.WriteAt([]byte(v), 0)
+	return err
+}
+
+// Close closes the pin, it's left exported and at its last level.
+func (p *SysfsPin) Close() error {
+	return p.value.Close()
+}
@@ -0,0 +1,42 @@
+package gpio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenSysfs(t *testing.T) {
+	root := t.TempDir()
+	defer func(r string) { sysfsRoot = r }(sysfsRoot)
+	sysfsRoot = root
+
+	dir := filepath.Join(root, "gpio17")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "value"), []byte("0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := OpenSysfs(17)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer p.Close()
+	if err := p.Out(true); err != nil {
+		t.Fatal(err)
+	}
+
+	for file, expected := range map[string]string{"direction": "out", "value": "1"} {
+		if b, _ := os.ReadFile(filepath.Join(dir, file)); string(b) != expected {
+			t.Errorf("%s: unexpected content %q", file, b)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "export")); !os.IsNotExist(err) {
+		t.Errorf("an exported pin shouldn't be exported again")
+	}
+}
@@ -0,0 +1,111 @@
+package tsunami
+
+import "fmt"
+
+type group struct {
+	tracks map[int]bool
+	trim   Gain
+}
+
+// SetGroup assigns the given tracks to the gain group with the given name,
+// creating it if needed. A track can belong to several groups, and the trims
+// of all of them are added to its gain, like the VCA groups of a mixing desk.
+func (t *Tsunami) SetGroup(name string, tracks ...int) error {
+	t.mu.Lock()
+	if t.groups == nil {
+		t.groups = make(map[string]*group)
+	}
+
+	g, ok := t.groups[name]
+	if !ok {
+		g = &group{tracks: make(map[int]bool)}
+		t.groups[name] = g
+	}
+
+	for _, trk := range tracks {
+		g.tracks[trk] = true
+	}
+
+	t.updateTrims()
+	t.mu.Unlock()
+
+	if g.trim == 0 {
+		return nil
+	}
+
+	return t.resendGains(tracks)
+}
+
+// GroupTrim sets the trim of the group, a relative gain in dB added to the
+// gain of every track of the group. The resulting gains are sent to the
+// board right away, while TrackGain and TrackFade keep taking the gains
+// before the trims.
+func (t *Tsunami) GroupTrim(name string, trim Gain) error {
+	t.mu.Lock()
+	g, ok := t.groups[name]
+	if !ok {
+		t.mu.Unlock()
+		return fmt.Errorf("unknown group %q", name)
+	}
+
+	g.trim = trim
+	t.updateTrims()
+
+	tracks := make([]int, 0, len(g.tracks))
+	for trk := range g.tracks {
+		tracks = append(tracks, trk)
+	}
+	t.mu.Unlock()
+
+	return t.resendGains(tracks)
+}
+
+// GroupTrimOf returns the trim of the group.
+func (t *Tsunami) GroupTrimOf(name string) Gain {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if g, ok := t.groups[name]; ok {
+		return g.trim
+	}
+
+	return 0
+}
+
+// updateTrims computes the total trim of every track, with its
+// normalization, must be called with the lock held.
+func (t *Tsunami) updateTrims() {
+	t.trims = make(map[int]Gain)
+	for _, g := range t.groups {
+		for trk := range g.tracks {
+			t.trims[trk] += g.trim
+		}
+	}
+
+	for trk, g := range t.normGains {
+		t.trims[trk] += g
+	}
+}
+
+// trimmed returns the gain of the track with the trims of its groups.
+func (t *Tsunami) trimmed(trk int, gain Gain) Gain {
+	t.mu.Lock()
+	trim := t.trims[trk]
+	t.mu.Unlock()
+
+	if trim == 0 || gain <= MinGain {
+		return gain
+	}
+
+	return gain.Add(trim)
+}
+
+func (t *Tsunami) resendGains(tracks []int) error {
+	for _, trk := range tracks {
+		if err := t.TrackGain(trk, t.TrackGainOf(trk)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package tsunami
+
+import (
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestGroupTrim(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	ts.TrackGain(1, -10)
+	ts.TrackGain(2, 5)
+
+	if err := ts.SetGroup("sfx", 1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.SetGroup("music", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	port.Frames()
+	if err := ts.GroupTrim("sfx", -6); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.GroupTrim("music", 8); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.TrackGain(1, MinGain); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gains := map[uint16][]int16{}
+	for _, m := range msgs {
+		v := m.(*protocol.TrackVolume)
+		gains[v.Track] = append(gains[v.Track], v.Gain)
+	}
+
+	if g := gains[1]; len(g) != 2 || g[0] != -16 || g[1] != MinGain {
+		t.Errorf("unexpected gains of track 1 %v", g)
+	}
+
+	if g := gains[2]; len(g) != 2 || g[0] != -1 || g[1] != 7 {
+		t.Errorf("unexpected gains of track 2 %v", g)
+	}
+
+	if ts.TrackGainOf(2) != 5 || ts.GroupTrimOf("sfx") != -6 {
+		t.Errorf("the gains before the trims should be kept")
+	}
+
+	if err := ts.GroupTrim("foo", 1); err == nil {
+		t.Errorf("expected error on unknown group")
+	}
+}
@@ -0,0 +1,22 @@
+module github.com/mcuadros/go-tsunami/grpcapi
+
+go 1.19
+
+require (
+	github.com/mcuadros/go-tsunami v0.0.0
+	google.golang.org/grpc v1.58.3
+	google.golang.org/protobuf v1.31.0
+)
+
+require (
+	github.com/creack/goselect v0.1.2 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/tarm/serial v0.0.0-20180830185346-98f6abe2eb07 // indirect
+	go.bug.st/serial v1.4.1 // indirect
+	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230711160842-782d3b101e98 // indirect
+)
+
+replace github.com/mcuadros/go-tsunami => ../
@@ -0,0 +1,341 @@
+// Package grpcapi exposes the control surface of a board as a gRPC service,
+// for show-control systems integrating the board over gRPC. The service is
+// defined in proto/tsunami.proto, the client and the server interfaces are
+// generated from it, and Server implements it over a tsunami.Player:
+//
+//	srv := grpc.NewServer()
+//	grpcapi.RegisterTsunamiServer(srv, grpcapi.New(ts))
+//
+// The events of the board, the tracks starting and stopping, the changes of
+// the voice table and the health of the connection, are streamed by the
+// Events RPC, from the same monitor.Monitor as the /events of the httpapi
+// package.
+//
+// The package is a module of its own, so the gRPC and protobuf modules are
+// only required by the programs using it.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/monitor"
+	"github.com/mcuadros/go-tsunami/show"
+)
+
+// Server implements the Tsunami service over a player. The events are only
+// streamed for players implementing tsunami.Board, updated with Update or
+// Listen.
+type Server struct {
+	UnimplementedTsunamiServer
+
+	p   tsunami.Player
+	mon *monitor.Monitor
+}
+
+// New returns the server of the player.
+func New(p tsunami.Player) *Server {
+	return &Server{p: p, mon: monitor.New(p)}
+}
+
+// ListenAndServe serves the service on the address until the context is
+// done, then stops gracefully.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, l)
+}
+
+// Serve is like ListenAndServe, on the listener.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	srv := grpc.NewServer()
+	RegisterTsunamiServer(srv, s)
+
+	go func() {
+		<-ctx.Done()
+		// the event streams don't end on their own, GracefulStop would wait
+		// for them forever.
+		s.mon.Disconnect()
+		srv.GracefulStop()
+	}()
+
+	return srv.Serve(l)
+}
+
+// Close unsubscribes the server from the board and ends the calls to
+// Events.
+func (s *Server) Close() error {
+	s.mon.Close()
+	return nil
+}
+
+// SetCueStack streams the cues fired by the stack as cue events.
+func (s *Server) SetCueStack(c *show.CueStack) {
+	c.OnCue(func(cue show.Cue) {
+		s.mon.Cue(cue.Number)
+	})
+}
+
+// SysInfo implements TsunamiServer. Voices is zero for the players not
+// reporting it.
+func (s *Server) SysInfo(ctx context.Context, _ *emptypb.Empty) (*SysInfoResponse, error) {
+	info := &SysInfoResponse{Version: s.p.GetVersion(), Tracks: int32(s.p.GetNumTracks())}
+	if v, ok := s.p.(interface{ GetNumVoices() int }); ok {
+		info.Voices = int32(v.GetNumVoices())
+	}
+
+	return info, nil
+}
+
+// Voices implements TsunamiServer. Playing is empty for the players not able
+// to report it.
+func (s *Server) Voices(ctx context.Context, _ *emptypb.Empty) (*VoicesResponse, error) {
+	resp := &VoicesResponse{}
+	if n, ok := s.p.(interface{ GetNumVoices() int }); ok {
+		resp.Voices = int32(n.GetNumVoices())
+	}
+
+	st, ok := s.p.(interface {
+		Status(ctx context.Context) ([]int, error)
+	})
+
+	if !ok {
+		return resp, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	tracks, err := st.Status(ctx)
+	if err != nil {
+		return nil, s.result(err)
+	}
+
+	for _, trk := range tracks {
+		resp.Playing = append(resp.Playing, int32(trk))
+	}
+
+	return resp, nil
+}
+
+// Play implements TsunamiServer.
+func (s *Server) Play(ctx context.Context, req *PlayRequest) (*emptypb.Empty, error) {
+	if err := validatePlay(req); err != nil {
+		return nil, err
+	}
+
+	trk, out := int(req.Track), int(req.Output)
+	if req.Solo {
+		return s.reply(s.p.TrackPlaySolo(trk, out, req.Lock))
+	}
+
+	return s.reply(s.p.TrackPlayPoly(trk, out, req.Lock))
+}
+
+// Load implements TsunamiServer, Solo is ignored.
+func (s *Server) Load(ctx context.Context, req *PlayRequest) (*emptypb.Empty, error) {
+	if err := validatePlay(req); err != nil {
+		return nil, err
+	}
+
+	return s.reply(s.p.TrackLoad(int(req.Track), int(req.Output), req.Lock))
+}
+
+// Stop implements TsunamiServer.
+func (s *Server) Stop(ctx context.Context, req *TrackRequest) (*emptypb.Empty, error) {
+	if err := validateTrack(req.Track); err != nil {
+		return nil, err
+	}
+
+	return s.reply(s.p.TrackStop(int(req.Track)))
+}
+
+// Pause implements TsunamiServer.
+func (s *Server) Pause(ctx context.Context, req *TrackRequest) (*emptypb.Empty, error) {
+	if err := validateTrack(req.Track); err != nil {
+		return nil, err
+	}
+
+	return s.reply(s.p.TrackPause(int(req.Track)))
+}
+
+// Resume implements TsunamiServer.
+func (s *Server) Resume(ctx context.Context, req *TrackRequest) (*emptypb.Empty, error) {
+	if err := validateTrack(req.Track); err != nil {
+		return nil, err
+	}
+
+	return s.reply(s.p.TrackResume(int(req.Track)))
+}
+
+// TrackGain implements TsunamiServer.
+func (s *Server) TrackGain(ctx context.Context, req *GainRequest) (*emptypb.Empty, error) {
+	if err := validateTrack(req.Track); err != nil {
+		return nil, err
+	}
+
+	return s.reply(s.p.TrackGain(int(req.Track), tsunami.Gain(req.Gain)))
+}
+
+// Fade implements TsunamiServer.
+func (s *Server) Fade(ctx context.Context, req *FadeRequest) (*emptypb.Empty, error) {
+	if err := validateTrack(req.Track); err != nil {
+		return nil, err
+	}
+
+	if err := req.Duration.CheckValid(); err != nil || req.Duration.AsDuration() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid duration %v", req.Duration)
+	}
+
+	d := req.Duration.AsDuration()
+	return s.reply(s.p.TrackFade(int(req.Track), tsunami.Gain(req.Gain), d, req.Stop))
+}
+
+// Loop implements TsunamiServer.
+func (s *Server) Loop(ctx context.Context, req *LoopRequest) (*emptypb.Empty, error) {
+	if err := validateTrack(req.Track); err != nil {
+		return nil, err
+	}
+
+	return s.reply(s.p.TrackLoop(int(req.Track), req.Loop))
+}
+
+// TrackStatus implements TsunamiServer.
+func (s *Server) TrackStatus(ctx context.Context, req *TrackRequest) (*TrackStatusResponse, error) {
+	if err := validateTrack(req.Track); err != nil {
+		return nil, err
+	}
+
+	return &TrackStatusResponse{Track: req.Track, Playing: s.p.IsTrackPlaying(int(req.Track))}, nil
+}
+
+// StopAll implements TsunamiServer.
+func (s *Server) StopAll(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	return s.reply(s.p.StopAllTracks())
+}
+
+// ResumeAll implements TsunamiServer.
+func (s *Server) ResumeAll(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	return s.reply(s.p.ResumeAllInSync())
+}
+
+// MasterGain implements TsunamiServer.
+func (s *Server) MasterGain(ctx context.Context, req *OutputGainRequest) (*emptypb.Empty, error) {
+	if req.Output < 0 || req.Output >= tsunami.MaxOutputs {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid output %d", req.Output)
+	}
+
+	return s.reply(s.p.MasterGain(int(req.Output), tsunami.Gain(req.Gain)))
+}
+
+// Events implements TsunamiServer, streaming the health of the connection
+// and the voices in use, followed by the events of the board, until the
+// client cancels the call.
+func (s *Server) Events(_ *emptypb.Empty, srv Tsunami_EventsServer) error {
+	w := s.mon.Watch()
+	defer w.Stop()
+
+	for {
+		e, err := w.Next(srv.Context())
+		switch {
+		case errors.Is(err, monitor.ErrClosed):
+			return status.Error(codes.Unavailable, "server shutting down")
+		case err != nil:
+			return nil
+		}
+
+		if err := srv.Send(event(e)); err != nil {
+			return err
+		}
+	}
+}
+
+func validateTrack(trk int32) error {
+	if trk < 1 || trk > tsunami.MaxTracks {
+		return status.Errorf(codes.InvalidArgument, "invalid track %d", trk)
+	}
+
+	return nil
+}
+
+func validatePlay(req *PlayRequest) error {
+	if err := validateTrack(req.Track); err != nil {
+		return err
+	}
+
+	if req.Output < 0 || req.Output >= tsunami.MaxOutputs {
+		return status.Errorf(codes.InvalidArgument, "invalid output %d", req.Output)
+	}
+
+	return nil
+}
+
+// result records the result of a command sent to the board, returning it as
+// a status: the commands not supported by the board fail their
+// precondition, the rest of the errors make the board unavailable.
+func (s *Server) result(err error) error {
+	s.mon.Result(err)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, tsunami.ErrUnsupportedFirmware):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// reply returns the response of a command, see result.
+func (s *Server) reply(err error) (*emptypb.Empty, error) {
+	if err := s.result(err); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// event returns the message of an event of the monitor.
+func event(e monitor.Event) *Event {
+	msg := &Event{Time: timestamppb.New(e.Time)}
+	switch e.Type {
+	case monitor.TrackEvent:
+		msg.Event = &Event_Track{Track: &TrackEvent{Track: int32(e.Track), Voice: int32(e.Voice), Playing: e.Playing}}
+	case monitor.VoicesEvent:
+		voices := &VoicesEvent{}
+		for _, v := range e.Voices {
+			voices.Voices = append(voices.Voices, &VoiceStatus{Voice: int32(v.Voice), Track: int32(v.Track)})
+		}
+
+		msg.Event = &Event_Voices{Voices: voices}
+	case monitor.HealthEvent:
+		health := &Health{
+			Ok:        e.Health.OK,
+			LastError: e.Health.LastError,
+			Resyncs:   int32(e.Health.Resyncs),
+			Malformed: int32(e.Health.Malformed),
+		}
+
+		if !e.Health.LastMessage.IsZero() {
+			health.LastMessage = timestamppb.New(e.Health.LastMessage)
+		}
+
+		msg.Event = &Event_Health{Health: health}
+	case monitor.CueEvent:
+		msg.Event = &Event_Cue{Cue: &CueEvent{Number: e.Cue}}
+	}
+
+	return msg
+}
@@ -0,0 +1,152 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// dial serves s in memory, returning a client connected to it.
+func dial(t *testing.T, ctx context.Context, s *Server) TsunamiClient {
+	l := bufconn.Listen(1 << 16)
+	go s.Serve(ctx, l)
+
+	conn, err := grpc.DialContext(ctx, "bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return l.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+	return NewTsunamiClient(conn)
+}
+
+func TestServerCommands(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	port := transport.NewLoopback(transport.Script{
+		protocol.CMD_GET_VERSION: {&protocol.VersionString{Version: "Tsunami v1.10"}},
+	}.Respond)
+
+	ts := tsunami.NewTsunamiTransport(port)
+	c := dial(t, ctx, New(ts))
+
+	if _, err := c.Play(ctx, &PlayRequest{Track: 12, Output: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	fade := &FadeRequest{Track: 12, Gain: -70, Duration: durationpb.New(2 * time.Second), Stop: true}
+	if _, err := c.Fade(ctx, fade); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.MasterGain(ctx, &OutputGainRequest{Output: 3, Gain: -6}); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, m := range msgs {
+		got = append(got, protocol.Describe(m))
+	}
+
+	expected := []string{
+		protocol.Describe(&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 12, Output: 1}),
+		protocol.Describe(&protocol.TrackFade{Track: 12, Gain: -70, Millis: 2000, Stop: true}),
+		protocol.Describe(&protocol.MasterVolume{Output: 3, Gain: -6}),
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("unexpected commands %q", got)
+	}
+
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("command %d: got %s, expected %s", i, got[i], expected[i])
+		}
+	}
+
+	for _, call := range []func() error{
+		func() error { _, err := c.Play(ctx, &PlayRequest{Track: 0}); return err },
+		func() error { _, err := c.Load(ctx, &PlayRequest{Track: 1, Output: tsunami.MaxOutputs}); return err },
+		func() error { _, err := c.Fade(ctx, &FadeRequest{Track: 1}); return err },
+		func() error { _, err := c.MasterGain(ctx, &OutputGainRequest{Output: -1}); return err },
+	} {
+		if err := call(); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	s := New(ts)
+	defer s.Close()
+
+	port.Push(&protocol.TrackReport{Track: 3, Voice: 1, Playing: true})
+	ts.Update()
+
+	stream, err := dial(t, ctx, s).Events(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := func() *Event {
+		e, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return e
+	}
+
+	if h := next().GetHealth(); h == nil || !h.Ok || h.LastMessage == nil {
+		t.Errorf("unexpected health %v", h)
+	}
+
+	if v := next().GetVoices(); v == nil || len(v.Voices) != 1 || v.Voices[0].Voice != 1 || v.Voices[0].Track != 3 {
+		t.Errorf("unexpected voices %v", v)
+	}
+
+	port.Push(&protocol.TrackReport{Track: 3, Voice: 1, Playing: false})
+	ts.Update()
+
+	if e := next().GetTrack(); e == nil || e.Track != 3 || e.Voice != 1 || e.Playing {
+		t.Errorf("unexpected track event %v", e)
+	}
+
+	if v := next().GetVoices(); v == nil || len(v.Voices) != 0 {
+		t.Errorf("unexpected voices %v", v)
+	}
+
+	// the server stops with the streams open.
+	cancel()
+	if _, err := stream.Recv(); err == nil {
+		t.Errorf("the stream should end with the server")
+	}
+}
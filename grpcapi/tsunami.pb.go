@@ -0,0 +1,1420 @@
+// The control API of a board, the same surface as the httpapi package, for
+// show-control systems integrating the board over gRPC.
+//
+// The Go server and client of the grpcapi package are generated with
+// protoc-gen-go and protoc-gen-go-grpc, from the root of the repository:
+//
+//	protoc --go_out=. --go_opt=module=github.com/mcuadros/go-tsunami \
+//	    --go-grpc_out=. --go-grpc_opt=module=github.com/mcuadros/go-tsunami \
+//	    proto/tsunami.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: proto/tsunami.proto
+
+package grpcapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SysInfoResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Tracks  int32  `protobuf:"varint,2,opt,name=tracks,proto3" json:"tracks,omitempty"`
+	Voices  int32  `protobuf:"varint,3,opt,name=voices,proto3" json:"voices,omitempty"`
+}
+
+func (x *SysInfoResponse) Reset() {
+	*x = SysInfoResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SysInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SysInfoResponse) ProtoMessage() {}
+
+func (x *SysInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SysInfoResponse.ProtoReflect.Descriptor instead.
+func (*SysInfoResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SysInfoResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *SysInfoResponse) GetTracks() int32 {
+	if x != nil {
+		return x.Tracks
+	}
+	return 0
+}
+
+func (x *SysInfoResponse) GetVoices() int32 {
+	if x != nil {
+		return x.Voices
+	}
+	return 0
+}
+
+type VoicesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Voices  int32   `protobuf:"varint,1,opt,name=voices,proto3" json:"voices,omitempty"`
+	Playing []int32 `protobuf:"varint,2,rep,packed,name=playing,proto3" json:"playing,omitempty"`
+}
+
+func (x *VoicesResponse) Reset() {
+	*x = VoicesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VoicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoicesResponse) ProtoMessage() {}
+
+func (x *VoicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoicesResponse.ProtoReflect.Descriptor instead.
+func (*VoicesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VoicesResponse) GetVoices() int32 {
+	if x != nil {
+		return x.Voices
+	}
+	return 0
+}
+
+func (x *VoicesResponse) GetPlaying() []int32 {
+	if x != nil {
+		return x.Playing
+	}
+	return nil
+}
+
+type TrackRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Track int32 `protobuf:"varint,1,opt,name=track,proto3" json:"track,omitempty"`
+}
+
+func (x *TrackRequest) Reset() {
+	*x = TrackRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackRequest) ProtoMessage() {}
+
+func (x *TrackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackRequest.ProtoReflect.Descriptor instead.
+func (*TrackRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TrackRequest) GetTrack() int32 {
+	if x != nil {
+		return x.Track
+	}
+	return 0
+}
+
+type PlayRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Track  int32 `protobuf:"varint,1,opt,name=track,proto3" json:"track,omitempty"`
+	Output int32 `protobuf:"varint,2,opt,name=output,proto3" json:"output,omitempty"`
+	Lock   bool  `protobuf:"varint,3,opt,name=lock,proto3" json:"lock,omitempty"`
+	// solo stops the rest of the tracks, ignored by Load.
+	Solo bool `protobuf:"varint,4,opt,name=solo,proto3" json:"solo,omitempty"`
+}
+
+func (x *PlayRequest) Reset() {
+	*x = PlayRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayRequest) ProtoMessage() {}
+
+func (x *PlayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayRequest.ProtoReflect.Descriptor instead.
+func (*PlayRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PlayRequest) GetTrack() int32 {
+	if x != nil {
+		return x.Track
+	}
+	return 0
+}
+
+func (x *PlayRequest) GetOutput() int32 {
+	if x != nil {
+		return x.Output
+	}
+	return 0
+}
+
+func (x *PlayRequest) GetLock() bool {
+	if x != nil {
+		return x.Lock
+	}
+	return false
+}
+
+func (x *PlayRequest) GetSolo() bool {
+	if x != nil {
+		return x.Solo
+	}
+	return false
+}
+
+type GainRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Track int32   `protobuf:"varint,1,opt,name=track,proto3" json:"track,omitempty"`
+	Gain  float64 `protobuf:"fixed64,2,opt,name=gain,proto3" json:"gain,omitempty"`
+}
+
+func (x *GainRequest) Reset() {
+	*x = GainRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GainRequest) ProtoMessage() {}
+
+func (x *GainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GainRequest.ProtoReflect.Descriptor instead.
+func (*GainRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GainRequest) GetTrack() int32 {
+	if x != nil {
+		return x.Track
+	}
+	return 0
+}
+
+func (x *GainRequest) GetGain() float64 {
+	if x != nil {
+		return x.Gain
+	}
+	return 0
+}
+
+type OutputGainRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Output int32   `protobuf:"varint,1,opt,name=output,proto3" json:"output,omitempty"`
+	Gain   float64 `protobuf:"fixed64,2,opt,name=gain,proto3" json:"gain,omitempty"`
+}
+
+func (x *OutputGainRequest) Reset() {
+	*x = OutputGainRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutputGainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutputGainRequest) ProtoMessage() {}
+
+func (x *OutputGainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutputGainRequest.ProtoReflect.Descriptor instead.
+func (*OutputGainRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OutputGainRequest) GetOutput() int32 {
+	if x != nil {
+		return x.Output
+	}
+	return 0
+}
+
+func (x *OutputGainRequest) GetGain() float64 {
+	if x != nil {
+		return x.Gain
+	}
+	return 0
+}
+
+type FadeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Track    int32                `protobuf:"varint,1,opt,name=track,proto3" json:"track,omitempty"`
+	Gain     float64              `protobuf:"fixed64,2,opt,name=gain,proto3" json:"gain,omitempty"`
+	Duration *durationpb.Duration `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Stop     bool                 `protobuf:"varint,4,opt,name=stop,proto3" json:"stop,omitempty"`
+}
+
+func (x *FadeRequest) Reset() {
+	*x = FadeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FadeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FadeRequest) ProtoMessage() {}
+
+func (x *FadeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FadeRequest.ProtoReflect.Descriptor instead.
+func (*FadeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FadeRequest) GetTrack() int32 {
+	if x != nil {
+		return x.Track
+	}
+	return 0
+}
+
+func (x *FadeRequest) GetGain() float64 {
+	if x != nil {
+		return x.Gain
+	}
+	return 0
+}
+
+func (x *FadeRequest) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *FadeRequest) GetStop() bool {
+	if x != nil {
+		return x.Stop
+	}
+	return false
+}
+
+type LoopRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Track int32 `protobuf:"varint,1,opt,name=track,proto3" json:"track,omitempty"`
+	Loop  bool  `protobuf:"varint,2,opt,name=loop,proto3" json:"loop,omitempty"`
+}
+
+func (x *LoopRequest) Reset() {
+	*x = LoopRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoopRequest) ProtoMessage() {}
+
+func (x *LoopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoopRequest.ProtoReflect.Descriptor instead.
+func (*LoopRequest) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LoopRequest) GetTrack() int32 {
+	if x != nil {
+		return x.Track
+	}
+	return 0
+}
+
+func (x *LoopRequest) GetLoop() bool {
+	if x != nil {
+		return x.Loop
+	}
+	return false
+}
+
+type TrackStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Track   int32 `protobuf:"varint,1,opt,name=track,proto3" json:"track,omitempty"`
+	Playing bool  `protobuf:"varint,2,opt,name=playing,proto3" json:"playing,omitempty"`
+}
+
+func (x *TrackStatusResponse) Reset() {
+	*x = TrackStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackStatusResponse) ProtoMessage() {}
+
+func (x *TrackStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackStatusResponse.ProtoReflect.Descriptor instead.
+func (*TrackStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TrackStatusResponse) GetTrack() int32 {
+	if x != nil {
+		return x.Track
+	}
+	return 0
+}
+
+func (x *TrackStatusResponse) GetPlaying() bool {
+	if x != nil {
+		return x.Playing
+	}
+	return false
+}
+
+type VoiceStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Voice int32 `protobuf:"varint,1,opt,name=voice,proto3" json:"voice,omitempty"`
+	Track int32 `protobuf:"varint,2,opt,name=track,proto3" json:"track,omitempty"`
+}
+
+func (x *VoiceStatus) Reset() {
+	*x = VoiceStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VoiceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoiceStatus) ProtoMessage() {}
+
+func (x *VoiceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoiceStatus.ProtoReflect.Descriptor instead.
+func (*VoiceStatus) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *VoiceStatus) GetVoice() int32 {
+	if x != nil {
+		return x.Voice
+	}
+	return 0
+}
+
+func (x *VoiceStatus) GetTrack() int32 {
+	if x != nil {
+		return x.Track
+	}
+	return 0
+}
+
+type Health struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ok          bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	LastError   string                 `protobuf:"bytes,2,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	LastMessage *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"`
+	Resyncs     int32                  `protobuf:"varint,4,opt,name=resyncs,proto3" json:"resyncs,omitempty"`
+	Malformed   int32                  `protobuf:"varint,5,opt,name=malformed,proto3" json:"malformed,omitempty"`
+}
+
+func (x *Health) Reset() {
+	*x = Health{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Health) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Health) ProtoMessage() {}
+
+func (x *Health) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Health.ProtoReflect.Descriptor instead.
+func (*Health) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Health) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *Health) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *Health) GetLastMessage() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastMessage
+	}
+	return nil
+}
+
+func (x *Health) GetResyncs() int32 {
+	if x != nil {
+		return x.Resyncs
+	}
+	return 0
+}
+
+func (x *Health) GetMalformed() int32 {
+	if x != nil {
+		return x.Malformed
+	}
+	return 0
+}
+
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// Types that are assignable to Event:
+	//	*Event_Track
+	//	*Event_Voices
+	//	*Event_Health
+	//	*Event_Cue
+	Event isEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (m *Event) GetEvent() isEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *Event) GetTrack() *TrackEvent {
+	if x, ok := x.GetEvent().(*Event_Track); ok {
+		return x.Track
+	}
+	return nil
+}
+
+func (x *Event) GetVoices() *VoicesEvent {
+	if x, ok := x.GetEvent().(*Event_Voices); ok {
+		return x.Voices
+	}
+	return nil
+}
+
+func (x *Event) GetHealth() *Health {
+	if x, ok := x.GetEvent().(*Event_Health); ok {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *Event) GetCue() *CueEvent {
+	if x, ok := x.GetEvent().(*Event_Cue); ok {
+		return x.Cue
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_Track struct {
+	Track *TrackEvent `protobuf:"bytes,2,opt,name=track,proto3,oneof"`
+}
+
+type Event_Voices struct {
+	Voices *VoicesEvent `protobuf:"bytes,3,opt,name=voices,proto3,oneof"`
+}
+
+type Event_Health struct {
+	Health *Health `protobuf:"bytes,4,opt,name=health,proto3,oneof"`
+}
+
+type Event_Cue struct {
+	Cue *CueEvent `protobuf:"bytes,5,opt,name=cue,proto3,oneof"`
+}
+
+func (*Event_Track) isEvent_Event() {}
+
+func (*Event_Voices) isEvent_Event() {}
+
+func (*Event_Health) isEvent_Event() {}
+
+func (*Event_Cue) isEvent_Event() {}
+
+type TrackEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Track   int32 `protobuf:"varint,1,opt,name=track,proto3" json:"track,omitempty"`
+	Voice   int32 `protobuf:"varint,2,opt,name=voice,proto3" json:"voice,omitempty"`
+	Playing bool  `protobuf:"varint,3,opt,name=playing,proto3" json:"playing,omitempty"`
+}
+
+func (x *TrackEvent) Reset() {
+	*x = TrackEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrackEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackEvent) ProtoMessage() {}
+
+func (x *TrackEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackEvent.ProtoReflect.Descriptor instead.
+func (*TrackEvent) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TrackEvent) GetTrack() int32 {
+	if x != nil {
+		return x.Track
+	}
+	return 0
+}
+
+func (x *TrackEvent) GetVoice() int32 {
+	if x != nil {
+		return x.Voice
+	}
+	return 0
+}
+
+func (x *TrackEvent) GetPlaying() bool {
+	if x != nil {
+		return x.Playing
+	}
+	return false
+}
+
+type VoicesEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Voices []*VoiceStatus `protobuf:"bytes,1,rep,name=voices,proto3" json:"voices,omitempty"`
+}
+
+func (x *VoicesEvent) Reset() {
+	*x = VoicesEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VoicesEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoicesEvent) ProtoMessage() {}
+
+func (x *VoicesEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoicesEvent.ProtoReflect.Descriptor instead.
+func (*VoicesEvent) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *VoicesEvent) GetVoices() []*VoiceStatus {
+	if x != nil {
+		return x.Voices
+	}
+	return nil
+}
+
+type CueEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Number string `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+}
+
+func (x *CueEvent) Reset() {
+	*x = CueEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_tsunami_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CueEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CueEvent) ProtoMessage() {}
+
+func (x *CueEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_tsunami_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CueEvent.ProtoReflect.Descriptor instead.
+func (*CueEvent) Descriptor() ([]byte, []int) {
+	return file_proto_tsunami_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CueEvent) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+var File_proto_tsunami_proto protoreflect.FileDescriptor
+
+var file_proto_tsunami_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76,
+	0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x5b, 0x0a, 0x0f, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x74, 0x72,
+	0x61, 0x63, 0x6b, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x22, 0x42, 0x0a, 0x0e,
+	0x56, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x69, 0x6e,
+	0x67, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x69, 0x6e, 0x67,
+	0x22, 0x24, 0x0a, 0x0c, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x22, 0x63, 0x0a, 0x0b, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x04, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x6c, 0x6f, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x6f, 0x6c, 0x6f, 0x22, 0x37, 0x0a, 0x0b, 0x47,
+	0x61, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72,
+	0x61, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b,
+	0x12, 0x12, 0x0a, 0x04, 0x67, 0x61, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04,
+	0x67, 0x61, 0x69, 0x6e, 0x22, 0x3f, 0x0a, 0x11, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x47, 0x61,
+	0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x61, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x04, 0x67, 0x61, 0x69, 0x6e, 0x22, 0x82, 0x01, 0x0a, 0x0b, 0x46, 0x61, 0x64, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x67,
+	0x61, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x67, 0x61, 0x69, 0x6e, 0x12,
+	0x35, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x6f, 0x70, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x74, 0x6f, 0x70, 0x22, 0x37, 0x0a, 0x0b, 0x4c, 0x6f,
+	0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x61,
+	0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x12,
+	0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6f, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6c,
+	0x6f, 0x6f, 0x70, 0x22, 0x45, 0x0a, 0x13, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72,
+	0x61, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b,
+	0x12, 0x18, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x69, 0x6e, 0x67, 0x22, 0x39, 0x0a, 0x0b, 0x56, 0x6f,
+	0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x6f, 0x69,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x74, 0x72, 0x61, 0x63, 0x6b, 0x22, 0xae, 0x01, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68,
+	0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x6f, 0x6b,
+	0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x3d, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x72, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x72, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x61, 0x6c, 0x66,
+	0x6f, 0x72, 0x6d, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x61, 0x6c,
+	0x66, 0x6f, 0x72, 0x6d, 0x65, 0x64, 0x22, 0xfb, 0x01, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x2e, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61,
+	0x63, 0x6b, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b,
+	0x12, 0x31, 0x0a, 0x06, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x6f,
+	0x69, 0x63, 0x65, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x06, 0x76, 0x6f, 0x69,
+	0x63, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31,
+	0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74,
+	0x68, 0x12, 0x28, 0x0a, 0x03, 0x63, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x75, 0x65, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x03, 0x63, 0x75, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x22, 0x52, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x6f, 0x69, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x70, 0x6c, 0x61, 0x79, 0x69, 0x6e, 0x67, 0x22, 0x3e, 0x0a, 0x0b, 0x56, 0x6f, 0x69, 0x63,
+	0x65, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x76, 0x6f, 0x69, 0x63, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d,
+	0x69, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x22, 0x22, 0x0a, 0x08, 0x43, 0x75, 0x65, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x32, 0x98, 0x07, 0x0a,
+	0x07, 0x54, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x12, 0x3e, 0x0a, 0x07, 0x53, 0x79, 0x73, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1b, 0x2e, 0x74, 0x73,
+	0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x06, 0x56, 0x6f, 0x69, 0x63,
+	0x65, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1a, 0x2e, 0x74, 0x73, 0x75,
+	0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x04, 0x50, 0x6c, 0x61, 0x79, 0x12, 0x17,
+	0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12,
+	0x37, 0x0a, 0x04, 0x4c, 0x6f, 0x61, 0x64, 0x12, 0x17, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d,
+	0x69, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x38, 0x0a, 0x04, 0x53, 0x74, 0x6f, 0x70,
+	0x12, 0x18, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72,
+	0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x39, 0x0a, 0x05, 0x50, 0x61, 0x75, 0x73, 0x65, 0x12, 0x18, 0x2e, 0x74, 0x73,
+	0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3a, 0x0a,
+	0x06, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x12, 0x18, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d,
+	0x69, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3c, 0x0a, 0x09, 0x54, 0x72, 0x61,
+	0x63, 0x6b, 0x47, 0x61, 0x69, 0x6e, 0x12, 0x17, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x37, 0x0a, 0x04, 0x46, 0x61, 0x64, 0x65, 0x12,
+	0x17, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x64,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x12, 0x37, 0x0a, 0x04, 0x4c, 0x6f, 0x6f, 0x70, 0x12, 0x17, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61,
+	0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x48, 0x0a, 0x0b, 0x54, 0x72, 0x61,
+	0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61,
+	0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x72, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x07, 0x53, 0x74, 0x6f, 0x70, 0x41, 0x6c, 0x6c, 0x12, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3b,
+	0x0a, 0x09, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x41, 0x6c, 0x6c, 0x12, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x43, 0x0a, 0x0a, 0x4d,
+	0x61, 0x73, 0x74, 0x65, 0x72, 0x47, 0x61, 0x69, 0x6e, 0x12, 0x1d, 0x2e, 0x74, 0x73, 0x75, 0x6e,
+	0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x47, 0x61, 0x69,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x12, 0x35, 0x0a, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x1a, 0x11, 0x2e, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x28, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x63, 0x75, 0x61, 0x64, 0x72, 0x6f, 0x73, 0x2f, 0x67,
+	0x6f, 0x2d, 0x74, 0x73, 0x75, 0x6e, 0x61, 0x6d, 0x69, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x61, 0x70,
+	0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_tsunami_proto_rawDescOnce sync.Once
+	file_proto_tsunami_proto_rawDescData = file_proto_tsunami_proto_rawDesc
+)
+
+func file_proto_tsunami_proto_rawDescGZIP() []byte {
+	file_proto_tsunami_proto_rawDescOnce.Do(func() {
+		file_proto_tsunami_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_tsunami_proto_rawDescData)
+	})
+	return file_proto_tsunami_proto_rawDescData
+}
+
+var file_proto_tsunami_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_proto_tsunami_proto_goTypes = []interface{}{
+	(*SysInfoResponse)(nil),       // 0: tsunami.v1.SysInfoResponse
+	(*VoicesResponse)(nil),        // 1: tsunami.v1.VoicesResponse
+	(*TrackRequest)(nil),          // 2: tsunami.v1.TrackRequest
+	(*PlayRequest)(nil),           // 3: tsunami.v1.PlayRequest
+	(*GainRequest)(nil),           // 4: tsunami.v1.GainRequest
+	(*OutputGainRequest)(nil),     // 5: tsunami.v1.OutputGainRequest
+	(*FadeRequest)(nil),           // 6: tsunami.v1.FadeRequest
+	(*LoopRequest)(nil),           // 7: tsunami.v1.LoopRequest
+	(*TrackStatusResponse)(nil),   // 8: tsunami.v1.TrackStatusResponse
+	(*VoiceStatus)(nil),           // 9: tsunami.v1.VoiceStatus
+	(*Health)(nil),                // 10: tsunami.v1.Health
+	(*Event)(nil),                 // 11: tsunami.v1.Event
+	(*TrackEvent)(nil),            // 12: tsunami.v1.TrackEvent
+	(*VoicesEvent)(nil),           // 13: tsunami.v1.VoicesEvent
+	(*CueEvent)(nil),              // 14: tsunami.v1.CueEvent
+	(*durationpb.Duration)(nil),   // 15: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 17: google.protobuf.Empty
+}
+var file_proto_tsunami_proto_depIdxs = []int32{
+	15, // 0: tsunami.v1.FadeRequest.duration:type_name -> google.protobuf.Duration
+	16, // 1: tsunami.v1.Health.last_message:type_name -> google.protobuf.Timestamp
+	16, // 2: tsunami.v1.Event.time:type_name -> google.protobuf.Timestamp
+	12, // 3: tsunami.v1.Event.track:type_name -> tsunami.v1.TrackEvent
+	13, // 4: tsunami.v1.Event.voices:type_name -> tsunami.v1.VoicesEvent
+	10, // 5: tsunami.v1.Event.health:type_name -> tsunami.v1.Health
+	14, // 6: tsunami.v1.Event.cue:type_name -> tsunami.v1.CueEvent
+	9,  // 7: tsunami.v1.VoicesEvent.voices:type_name -> tsunami.v1.VoiceStatus
+	17, // 8: tsunami.v1.Tsunami.SysInfo:input_type -> google.protobuf.Empty
+	17, // 9: tsunami.v1.Tsunami.Voices:input_type -> google.protobuf.Empty
+	3,  // 10: tsunami.v1.Tsunami.Play:input_type -> tsunami.v1.PlayRequest
+	3,  // 11: tsunami.v1.Tsunami.Load:input_type -> tsunami.v1.PlayRequest
+	2,  // 12: tsunami.v1.Tsunami.Stop:input_type -> tsunami.v1.TrackRequest
+	2,  // 13: tsunami.v1.Tsunami.Pause:input_type -> tsunami.v1.TrackRequest
+	2,  // 14: tsunami.v1.Tsunami.Resume:input_type -> tsunami.v1.TrackRequest
+	4,  // 15: tsunami.v1.Tsunami.TrackGain:input_type -> tsunami.v1.GainRequest
+	6,  // 16: tsunami.v1.Tsunami.Fade:input_type -> tsunami.v1.FadeRequest
+	7,  // 17: tsunami.v1.Tsunami.Loop:input_type -> tsunami.v1.LoopRequest
+	2,  // 18: tsunami.v1.Tsunami.TrackStatus:input_type -> tsunami.v1.TrackRequest
+	17, // 19: tsunami.v1.Tsunami.StopAll:input_type -> google.protobuf.Empty
+	17, // 20: tsunami.v1.Tsunami.ResumeAll:input_type -> google.protobuf.Empty
+	5,  // 21: tsunami.v1.Tsunami.MasterGain:input_type -> tsunami.v1.OutputGainRequest
+	17, // 22: tsunami.v1.Tsunami.Events:input_type -> google.protobuf.Empty
+	0,  // 23: tsunami.v1.Tsunami.SysInfo:output_type -> tsunami.v1.SysInfoResponse
+	1,  // 24: tsunami.v1.Tsunami.Voices:output_type -> tsunami.v1.VoicesResponse
+	17, // 25: tsunami.v1.Tsunami.Play:output_type -> google.protobuf.Empty
+	17, // 26: tsunami.v1.Tsunami.Load:output_type -> google.protobuf.Empty
+	17, // 27: tsunami.v1.Tsunami.Stop:output_type -> google.protobuf.Empty
+	17, // 28: tsunami.v1.Tsunami.Pause:output_type -> google.protobuf.Empty
+	17, // 29: tsunami.v1.Tsunami.Resume:output_type -> google.protobuf.Empty
+	17, // 30: tsunami.v1.Tsunami.TrackGain:output_type -> google.protobuf.Empty
+	17, // 31: tsunami.v1.Tsunami.Fade:output_type -> google.protobuf.Empty
+	17, // 32: tsunami.v1.Tsunami.Loop:output_type -> google.protobuf.Empty
+	8,  // 33: tsunami.v1.Tsunami.TrackStatus:output_type -> tsunami.v1.TrackStatusResponse
+	17, // 34: tsunami.v1.Tsunami.StopAll:output_type -> google.protobuf.Empty
+	17, // 35: tsunami.v1.Tsunami.ResumeAll:output_type -> google.protobuf.Empty
+	17, // 36: tsunami.v1.Tsunami.MasterGain:output_type -> google.protobuf.Empty
+	11, // 37: tsunami.v1.Tsunami.Events:output_type -> tsunami.v1.Event
+	23, // [23:38] is the sub-list for method output_type
+	8,  // [8:23] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_proto_tsunami_proto_init() }
+func file_proto_tsunami_proto_init() {
+	if File_proto_tsunami_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_tsunami_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SysInfoResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VoicesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrackRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GainRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutputGainRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FadeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoopRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrackStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VoiceStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Health); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrackEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VoicesEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_tsunami_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CueEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_proto_tsunami_proto_msgTypes[11].OneofWrappers = []interface{}{
+		(*Event_Track)(nil),
+		(*Event_Voices)(nil),
+		(*Event_Health)(nil),
+		(*Event_Cue)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_tsunami_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_tsunami_proto_goTypes,
+		DependencyIndexes: file_proto_tsunami_proto_depIdxs,
+		MessageInfos:      file_proto_tsunami_proto_msgTypes,
+	}.Build()
+	File_proto_tsunami_proto = out.File
+	file_proto_tsunami_proto_rawDesc = nil
+	file_proto_tsunami_proto_goTypes = nil
+	file_proto_tsunami_proto_depIdxs = nil
+}
@@ -0,0 +1,678 @@
+// The control API of a board, the same surface as the httpapi package, for
+// show-control systems integrating the board over gRPC.
+//
+// The Go server and client of the grpcapi package are generated with
+// protoc-gen-go and protoc-gen-go-grpc, from the root of the repository:
+//
+//	protoc --go_out=. --go_opt=module=github.com/mcuadros/go-tsunami \
+//	    --go-grpc_out=. --go-grpc_opt=module=github.com/mcuadros/go-tsunami \
+//	    proto/tsunami.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: proto/tsunami.proto
+
+package grpcapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Tsunami_SysInfo_FullMethodName     = "/tsunami.v1.Tsunami/SysInfo"
+	Tsunami_Voices_FullMethodName      = "/tsunami.v1.Tsunami/Voices"
+	Tsunami_Play_FullMethodName        = "/tsunami.v1.Tsunami/Play"
+	Tsunami_Load_FullMethodName        = "/tsunami.v1.Tsunami/Load"
+	Tsunami_Stop_FullMethodName        = "/tsunami.v1.Tsunami/Stop"
+	Tsunami_Pause_FullMethodName       = "/tsunami.v1.Tsunami/Pause"
+	Tsunami_Resume_FullMethodName      = "/tsunami.v1.Tsunami/Resume"
+	Tsunami_TrackGain_FullMethodName   = "/tsunami.v1.Tsunami/TrackGain"
+	Tsunami_Fade_FullMethodName        = "/tsunami.v1.Tsunami/Fade"
+	Tsunami_Loop_FullMethodName        = "/tsunami.v1.Tsunami/Loop"
+	Tsunami_TrackStatus_FullMethodName = "/tsunami.v1.Tsunami/TrackStatus"
+	Tsunami_StopAll_FullMethodName     = "/tsunami.v1.Tsunami/StopAll"
+	Tsunami_ResumeAll_FullMethodName   = "/tsunami.v1.Tsunami/ResumeAll"
+	Tsunami_MasterGain_FullMethodName  = "/tsunami.v1.Tsunami/MasterGain"
+	Tsunami_Events_FullMethodName      = "/tsunami.v1.Tsunami/Events"
+)
+
+// TsunamiClient is the client API for Tsunami service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TsunamiClient interface {
+	// SysInfo returns the version, number of tracks and voices of the board.
+	SysInfo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SysInfoResponse, error)
+	// Voices returns the tracks playing.
+	Voices(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VoicesResponse, error)
+	Play(ctx context.Context, in *PlayRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Load(ctx context.Context, in *PlayRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Stop(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Pause(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Resume(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	TrackGain(ctx context.Context, in *GainRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Fade(ctx context.Context, in *FadeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Loop(ctx context.Context, in *LoopRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	TrackStatus(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*TrackStatusResponse, error)
+	// StopAll stops every track, ResumeAll resumes the paused ones in sync.
+	StopAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ResumeAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// MasterGain sets the gain of an output.
+	MasterGain(ctx context.Context, in *OutputGainRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Events streams the events of the board, starting with the health of the
+	// connection and the voices in use, as the /events of the httpapi.
+	Events(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Tsunami_EventsClient, error)
+}
+
+type tsunamiClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTsunamiClient(cc grpc.ClientConnInterface) TsunamiClient {
+	return &tsunamiClient{cc}
+}
+
+func (c *tsunamiClient) SysInfo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*SysInfoResponse, error) {
+	out := new(SysInfoResponse)
+	err := c.cc.Invoke(ctx, Tsunami_SysInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) Voices(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*VoicesResponse, error) {
+	out := new(VoicesResponse)
+	err := c.cc.Invoke(ctx, Tsunami_Voices_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) Play(ctx context.Context, in *PlayRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_Play_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) Load(ctx context.Context, in *PlayRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_Load_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) Stop(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_Stop_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) Pause(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_Pause_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) Resume(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_Resume_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) TrackGain(ctx context.Context, in *GainRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_TrackGain_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) Fade(ctx context.Context, in *FadeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_Fade_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) Loop(ctx context.Context, in *LoopRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_Loop_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) TrackStatus(ctx context.Context, in *TrackRequest, opts ...grpc.CallOption) (*TrackStatusResponse, error) {
+	out := new(TrackStatusResponse)
+	err := c.cc.Invoke(ctx, Tsunami_TrackStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) StopAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_StopAll_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) ResumeAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_ResumeAll_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) MasterGain(ctx context.Context, in *OutputGainRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Tsunami_MasterGain_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tsunamiClient) Events(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Tsunami_EventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Tsunami_ServiceDesc.Streams[0], Tsunami_Events_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tsunamiEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Tsunami_EventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type tsunamiEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *tsunamiEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TsunamiServer is the server API for Tsunami service.
+// All implementations must embed UnimplementedTsunamiServer
+// for forward compatibility
+type TsunamiServer interface {
+	// SysInfo returns the version, number of tracks and voices of the board.
+	SysInfo(context.Context, *emptypb.Empty) (*SysInfoResponse, error)
+	// Voices returns the tracks playing.
+	Voices(context.Context, *emptypb.Empty) (*VoicesResponse, error)
+	Play(context.Context, *PlayRequest) (*emptypb.Empty, error)
+	Load(context.Context, *PlayRequest) (*emptypb.Empty, error)
+	Stop(context.Context, *TrackRequest) (*emptypb.Empty, error)
+	Pause(context.Context, *TrackRequest) (*emptypb.Empty, error)
+	Resume(context.Context, *TrackRequest) (*emptypb.Empty, error)
+	TrackGain(context.Context, *GainRequest) (*emptypb.Empty, error)
+	Fade(context.Context, *FadeRequest) (*emptypb.Empty, error)
+	Loop(context.Context, *LoopRequest) (*emptypb.Empty, error)
+	TrackStatus(context.Context, *TrackRequest) (*TrackStatusResponse, error)
+	// StopAll stops every track, ResumeAll resumes the paused ones in sync.
+	StopAll(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	ResumeAll(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// MasterGain sets the gain of an output.
+	MasterGain(context.Context, *OutputGainRequest) (*emptypb.Empty, error)
+	// Events streams the events of the board, starting with the health of the
+	// connection and the voices in use, as the /events of the httpapi.
+	Events(*emptypb.Empty, Tsunami_EventsServer) error
+	mustEmbedUnimplementedTsunamiServer()
+}
+
+// UnimplementedTsunamiServer must be embedded to have forward compatible implementations.
+type UnimplementedTsunamiServer struct {
+}
+
+func (UnimplementedTsunamiServer) SysInfo(context.Context, *emptypb.Empty) (*SysInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SysInfo not implemented")
+}
+func (UnimplementedTsunamiServer) Voices(context.Context, *emptypb.Empty) (*VoicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Voices not implemented")
+}
+func (UnimplementedTsunamiServer) Play(context.Context, *PlayRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Play not implemented")
+}
+func (UnimplementedTsunamiServer) Load(context.Context, *PlayRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Load not implemented")
+}
+func (UnimplementedTsunamiServer) Stop(context.Context, *TrackRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+func (UnimplementedTsunamiServer) Pause(context.Context, *TrackRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pause not implemented")
+}
+func (UnimplementedTsunamiServer) Resume(context.Context, *TrackRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (UnimplementedTsunamiServer) TrackGain(context.Context, *GainRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TrackGain not implemented")
+}
+func (UnimplementedTsunamiServer) Fade(context.Context, *FadeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Fade not implemented")
+}
+func (UnimplementedTsunamiServer) Loop(context.Context, *LoopRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Loop not implemented")
+}
+func (UnimplementedTsunamiServer) TrackStatus(context.Context, *TrackRequest) (*TrackStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TrackStatus not implemented")
+}
+func (UnimplementedTsunamiServer) StopAll(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopAll not implemented")
+}
+func (UnimplementedTsunamiServer) ResumeAll(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeAll not implemented")
+}
+func (UnimplementedTsunamiServer) MasterGain(context.Context, *OutputGainRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MasterGain not implemented")
+}
+func (UnimplementedTsunamiServer) Events(*emptypb.Empty, Tsunami_EventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Events not implemented")
+}
+func (UnimplementedTsunamiServer) mustEmbedUnimplementedTsunamiServer() {}
+
+// UnsafeTsunamiServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TsunamiServer will
+// result in compilation errors.
+type UnsafeTsunamiServer interface {
+	mustEmbedUnimplementedTsunamiServer()
+}
+
+func RegisterTsunamiServer(s grpc.ServiceRegistrar, srv TsunamiServer) {
+	s.RegisterService(&Tsunami_ServiceDesc, srv)
+}
+
+func _Tsunami_SysInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).SysInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_SysInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).SysInfo(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_Voices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).Voices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_Voices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).Voices(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_Play_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).Play(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_Play_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).Play(ctx, req.(*PlayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_Load_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).Load(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_Load_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).Load(ctx, req.(*PlayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_Stop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).Stop(ctx, req.(*TrackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_Pause_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).Pause(ctx, req.(*TrackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_Resume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).Resume(ctx, req.(*TrackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_TrackGain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).TrackGain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_TrackGain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).TrackGain(ctx, req.(*GainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_Fade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FadeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).Fade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_Fade_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).Fade(ctx, req.(*FadeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_Loop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).Loop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_Loop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).Loop(ctx, req.(*LoopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_TrackStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).TrackStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_TrackStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).TrackStatus(ctx, req.(*TrackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_StopAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).StopAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_StopAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).StopAll(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_ResumeAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).ResumeAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_ResumeAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).ResumeAll(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_MasterGain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutputGainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TsunamiServer).MasterGain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Tsunami_MasterGain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TsunamiServer).MasterGain(ctx, req.(*OutputGainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tsunami_Events_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TsunamiServer).Events(m, &tsunamiEventsServer{stream})
+}
+
+type Tsunami_EventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type tsunamiEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *tsunamiEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Tsunami_ServiceDesc is the grpc.ServiceDesc for Tsunami service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Tsunami_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tsunami.v1.Tsunami",
+	HandlerType: (*TsunamiServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SysInfo",
+			Handler:    _Tsunami_SysInfo_Handler,
+		},
+		{
+			MethodName: "Voices",
+			Handler:    _Tsunami_Voices_Handler,
+		},
+		{
+			MethodName: "Play",
+			Handler:    _Tsunami_Play_Handler,
+		},
+		{
+			MethodName: "Load",
+			Handler:    _Tsunami_Load_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _Tsunami_Stop_Handler,
+		},
+		{
+			MethodName: "Pause",
+			Handler:    _Tsunami_Pause_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _Tsunami_Resume_Handler,
+		},
+		{
+			MethodName: "TrackGain",
+			Handler:    _Tsunami_TrackGain_Handler,
+		},
+		{
+			MethodName: "Fade",
+			Handler:    _Tsunami_Fade_Handler,
+		},
+		{
+			MethodName: "Loop",
+			Handler:    _Tsunami_Loop_Handler,
+		},
+		{
+			MethodName: "TrackStatus",
+			Handler:    _Tsunami_TrackStatus_Handler,
+		},
+		{
+			MethodName: "StopAll",
+			Handler:    _Tsunami_StopAll_Handler,
+		},
+		{
+			MethodName: "ResumeAll",
+			Handler:    _Tsunami_ResumeAll_Handler,
+		},
+		{
+			MethodName: "MasterGain",
+			Handler:    _Tsunami_MasterGain_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Events",
+			Handler:       _Tsunami_Events_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/tsunami.proto",
+}
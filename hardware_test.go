@@ -0,0 +1,171 @@
+//go:build hardware
+
+package tsunami_test
+
+// Conformance suite run against a real board, with a SD card containing at
+// least one track. Run it with:
+//
+//	TSUNAMI_PORT=/dev/ttyUSB0 go test -tags hardware -run Hardware -v
+//
+// TSUNAMI_TRACK selects the track used by the playback tests, by default 1.
+// Quirks found in the firmware are reported in the test log.
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+func openHardware(t *testing.T) (*tsunami.Tsunami, int) {
+	port := os.Getenv("TSUNAMI_PORT")
+	if port == "" {
+		t.Skip("TSUNAMI_PORT not set")
+	}
+
+	trk := 1
+	if s := os.Getenv("TSUNAMI_TRACK"); s != "" {
+		var err error
+		if trk, err = strconv.Atoi(s); err != nil {
+			t.Fatalf("invalid TSUNAMI_TRACK: %s", err)
+		}
+	}
+
+	ts, err := tsunami.NewTsunami(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		ts.StopAllTracks()
+		ts.Close()
+	})
+
+	if err := ts.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return ts, trk
+}
+
+// waitFor polls cond until it's true or the timeout expires, returning the
+// time it took.
+func waitFor(timeout time.Duration, cond func() bool) (time.Duration, bool) {
+	start := time.Now()
+	for time.Since(start) < timeout {
+		if cond() {
+			return time.Since(start), true
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	return timeout, false
+}
+
+func TestHardwareVersion(t *testing.T) {
+	ts, _ := openHardware(t)
+
+	if _, ok := waitFor(time.Second, func() bool { return ts.GetVersion() != "" }); !ok {
+		t.Fatal("no version string received")
+	}
+
+	v, ok := ts.Firmware()
+	if !ok {
+		t.Errorf("unparseable version string %q", ts.GetVersion())
+	}
+
+	t.Logf("firmware: %s", v)
+}
+
+func TestHardwareSysInfo(t *testing.T) {
+	ts, trk := openHardware(t)
+
+	if _, ok := waitFor(time.Second, func() bool { return ts.GetNumTracks() > 0 }); !ok {
+		t.Fatal("no system info received, or the SD card has no tracks")
+	}
+
+	if ts.GetNumTracks() < trk {
+		t.Errorf("track %d not in card, only %d tracks", trk, ts.GetNumTracks())
+	}
+}
+
+func TestHardwarePlayStop(t *testing.T) {
+	ts, trk := openHardware(t)
+
+	if err := ts.SetReporting(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.TrackPlayPoly(trk, 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	d, ok := waitFor(time.Second, func() bool { return ts.IsTrackPlaying(trk) })
+	if !ok {
+		t.Fatal("no start report received")
+	}
+
+	t.Logf("start reported after %s", d)
+
+	if err := ts.TrackStop(trk); err != nil {
+		t.Fatal(err)
+	}
+
+	d, ok = waitFor(time.Second, func() bool { return !ts.IsTrackPlaying(trk) })
+	if !ok {
+		t.Fatal("no stop report received")
+	}
+
+	t.Logf("stop reported after %s", d)
+}
+
+func TestHardwareFade(t *testing.T) {
+	ts, trk := openHardware(t)
+
+	if err := ts.SetReporting(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.TrackPlayPoly(trk, 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := waitFor(time.Second, func() bool { return ts.IsTrackPlaying(trk) }); !ok {
+		t.Fatal("no start report received")
+	}
+
+	fade := 500 * time.Millisecond
+	if err := ts.TrackFade(trk, -70, fade, true); err != nil {
+		t.Fatal(err)
+	}
+
+	d, ok := waitFor(fade*4, func() bool { return !ts.IsTrackPlaying(trk) })
+	if !ok {
+		t.Fatal("track not stopped after fade")
+	}
+
+	if d < fade/2 {
+		t.Logf("quirk: fade of %s stopped the track after only %s", fade, d)
+	}
+
+	t.Logf("fade of %s stopped the track after %s", fade, d)
+}
+
+func TestHardwareStatus(t *testing.T) {
+	ts, _ := openHardware(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	tracks, err := ts.Status(ctx)
+	if err != nil {
+		t.Logf("quirk: status request not answered: %s", err)
+		return
+	}
+
+	t.Logf("status: %v", tracks)
+}
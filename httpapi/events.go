@@ -0,0 +1,123 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/mcuadros/go-tsunami/monitor"
+)
+
+// EventType is the type of an Event.
+type EventType = monitor.EventType
+
+const (
+	// TrackEvent is sent when a track starts or stops.
+	TrackEvent = monitor.TrackEvent
+	// VoicesEvent is sent when the voice table changes, with the tracks
+	// playing by voice.
+	VoicesEvent = monitor.VoicesEvent
+	// HealthEvent is sent every monitor.HealthInterval, and on connection,
+	// with the health of the connection to the board.
+	HealthEvent = monitor.HealthEvent
+	// CueEvent is sent when a cue of the stack is fired, see
+	// Server.SetCueStack.
+	CueEvent = monitor.CueEvent
+)
+
+// Event is a message of the event stream, GET /events, sent as JSON over a
+// WebSocket. Only the fields of its type are set.
+type Event = monitor.Event
+
+// VoiceStatus is a voice playing a track.
+type VoiceStatus = monitor.VoiceStatus
+
+// Health is the health of the connection to the board, as seen by the
+// server.
+type Health = monitor.Health
+
+// client is a connection to the event stream.
+type client struct {
+	conn net.Conn
+
+	wmu sync.Mutex
+}
+
+func (c *client) write(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	return writeFrame(c.conn, op, payload)
+}
+
+// events streams the events to a WebSocket client, starting with the health
+// of the connection and the state of the voices.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"method " + r.Method + " not allowed"})
+		return
+	}
+
+	if !s.allowedOrigin(r) {
+		writeJSON(w, http.StatusForbidden, errorResponse{"origin " + r.Header.Get("Origin") + " not allowed"})
+		return
+	}
+
+	conn, br, err := upgrade(w, r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	watcher := s.mon.Watch()
+	defer watcher.Stop()
+
+	c := &client{conn: conn}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer cancel()
+		s.read(c, br)
+	}()
+
+	for {
+		e, err := watcher.Next(ctx)
+		if err != nil {
+			return
+		}
+
+		data, err := json.Marshal(e)
+		if err != nil {
+			return
+		}
+
+		if err := c.write(opText, data); err != nil {
+			return
+		}
+	}
+}
+
+// read answers the pings of the client until it closes the connection.
+func (s *Server) read(c *client, br *bufio.Reader) {
+	for {
+		op, payload, err := readFrame(br)
+		if err != nil {
+			return
+		}
+
+		switch op {
+		case opPing:
+			if c.write(opPong, payload) != nil {
+				return
+			}
+		case opClose:
+			c.write(opClose, nil)
+			return
+		}
+	}
+}
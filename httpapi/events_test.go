@@ -0,0 +1,141 @@
+package httpapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// dial connects to the event stream of the server.
+func dial(t *testing.T, srv *httptest.Server) (net.Conn, *bufio.Reader) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := "GET /events HTTP/1.1\r\nHost: tsunami\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the sample handshake of RFC 6455.
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected response %d %v", resp.StatusCode, resp.Header)
+	}
+
+	return conn, br
+}
+
+func next(t *testing.T, conn net.Conn, br *bufio.Reader) Event {
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	op, payload, err := readFrame(br)
+	if err != nil || op != opText {
+		t.Fatalf("unexpected frame %d, %v", op, err)
+	}
+
+	var e Event
+	if err := json.Unmarshal(payload, &e); err != nil {
+		t.Fatal(err)
+	}
+
+	return e
+}
+
+func TestServerEvents(t *testing.T) {
+	port := transport.NewLoopback(board.Respond)
+	ts := tsunami.NewTsunamiTransport(port)
+	srv := httptest.NewServer(New(ts))
+	defer srv.Close()
+
+	port.Push(&protocol.TrackReport{Track: 3, Voice: 1, Playing: true})
+	ts.Update()
+
+	conn, br := dial(t, srv)
+	defer conn.Close()
+
+	if e := next(t, conn, br); e.Type != HealthEvent || !e.Health.OK || e.Health.LastMessage.IsZero() {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	if e := next(t, conn, br); e.Type != VoicesEvent || !reflect.DeepEqual(e.Voices, []VoiceStatus{{Voice: 1, Track: 3}}) {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	port.Push(&protocol.TrackReport{Track: 3, Voice: 1, Playing: false})
+	ts.Update()
+
+	if e := next(t, conn, br); e.Type != TrackEvent || e.Track != 3 || e.Voice != 1 || e.Playing {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	if e := next(t, conn, br); e.Type != VoicesEvent || len(e.Voices) != 0 {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	// a masked close frame, as sent by the browsers.
+	conn.Write([]byte{0x80 | opClose, 0x80, 1, 2, 3, 4})
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if op, _, err := readFrame(br); err != nil || op != opClose {
+		t.Errorf("the close should be answered, got %d, %v", op, err)
+	}
+}
+
+func TestServerEventsHandshake(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(board.Respond))
+	w := do(New(ts), http.MethodGet, "/events", "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body)
+	}
+}
+
+func TestServerEventsOrigin(t *testing.T) {
+	s := New(tsunami.NewTsunamiTransport(transport.NewLoopback(board.Respond)))
+	s.SetAllowedOrigins("https://panel.example.com/")
+
+	for origin, allowed := range map[string]bool{
+		"":                          true,
+		"http://tsunami":            true,
+		"https://panel.example.com": true,
+		"https://evil.example.com":  false,
+		"null":                      false,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/events", nil)
+		r.Host = "tsunami"
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, r)
+
+		// the handshake is incomplete, the allowed origins are answered with
+		// Bad Request instead of Forbidden.
+		if got := w.Code != http.StatusForbidden; got != allowed {
+			t.Errorf("%q: unexpected response %d %s", origin, w.Code, w.Body)
+		}
+	}
+
+	s.SetAllowedOrigins("*")
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+	r.Header.Set("Origin", "https://evil.example.com")
+	if !s.allowedOrigin(r) {
+		t.Errorf("any origin should be allowed")
+	}
+}
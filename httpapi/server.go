@@ -0,0 +1,505 @@
+// Package httpapi exposes the control surface of a board as a REST API, so
+// front-ends not written in Go, or curl scripts, can drive it. The requests
+// and responses are JSON, and errors are returned as {"error": "..."}:
+//
+//	GET  /sysinfo                version, number of tracks and voices
+//	GET  /voices                 number of voices and tracks playing
+//	POST /stop                   stops every track
+//	POST /resume                 resumes every paused track in sync
+//	PUT  /outputs/{n}/gain       {"gain": -6}
+//	GET  /tracks/{n}             {"track": 12, "playing": true}
+//	POST /tracks/{n}/play        {"output": 0, "lock": false, "solo": false}
+//	POST /tracks/{n}/load        {"output": 0, "lock": false}
+//	POST /tracks/{n}/stop
+//	POST /tracks/{n}/pause
+//	POST /tracks/{n}/resume
+//	PUT  /tracks/{n}/gain        {"gain": -6}
+//	POST /tracks/{n}/fade        {"gain": -70, "duration": "2s", "stop": true}
+//	PUT  /tracks/{n}/loop        {"loop": true}
+//
+// The events of the board, the tracks starting and stopping, the changes of
+// the voice table and the health of the connection, are streamed as JSON
+// over a WebSocket, see Event:
+//
+//	GET  /events
+//
+// The browsers are only allowed to open the stream from the pages served by
+// the server itself, see Server.SetAllowedOrigins.
+//
+// With a cue stack, see Server.SetCueStack, the cues are fired by the API,
+// and every cue fired is streamed as a CueEvent:
+//
+//	GET  /cues                   {"standby": {...}, "cues": [...]}
+//	POST /cues/go
+//	POST /cues/back
+//
+// Any other path serves the control panel embedded, a page to drive the
+// board from a browser.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/monitor"
+	"github.com/mcuadros/go-tsunami/show"
+)
+
+// ShutdownTimeout is the time given to the requests in flight to finish when
+// the server is shut down.
+const ShutdownTimeout = 5 * time.Second
+
+var (
+	// errBadRequest wraps the errors caused by the request.
+	errBadRequest = errors.New("bad request")
+	// errNotFound wraps the errors of the resources not available.
+	errNotFound = errors.New("not found")
+)
+
+// Server serves the REST API of a player.
+type Server struct {
+	p   tsunami.Player
+	mux *http.ServeMux
+	mon *monitor.Monitor
+
+	mu      sync.Mutex
+	cues    *show.CueStack
+	origins map[string]bool
+}
+
+// New returns the server of the player. The events are only streamed for
+// players implementing tsunami.Board, updated with Update or Listen.
+func New(p tsunami.Player) *Server {
+	s := &Server{p: p, mux: http.NewServeMux(), mon: monitor.New(p)}
+	s.mux.HandleFunc("/sysinfo", s.handle(http.MethodGet, s.sysinfo))
+	s.mux.HandleFunc("/voices", s.handle(http.MethodGet, s.voices))
+	s.mux.HandleFunc("/stop", s.handle(http.MethodPost, s.stopAll))
+	s.mux.HandleFunc("/resume", s.handle(http.MethodPost, s.resumeAll))
+	s.mux.HandleFunc("/outputs/", s.output)
+	s.mux.HandleFunc("/tracks/", s.track)
+	s.mux.HandleFunc("/events", s.events)
+	s.mux.HandleFunc("/cues", s.handle(http.MethodGet, s.cueList))
+	s.mux.HandleFunc("/cues/go", s.handle(http.MethodPost, s.cueGo))
+	s.mux.HandleFunc("/cues/back", s.handle(http.MethodPost, s.cueBack))
+	s.mux.Handle("/", uiHandler())
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves the API on the address until the context is done,
+// shutting down gracefully, giving the requests in flight ShutdownTimeout to
+// finish.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, l)
+}
+
+// Close unsubscribes the server from the board and disconnects the clients
+// of the event stream.
+func (s *Server) Close() error {
+	s.mon.Close()
+	return nil
+}
+
+// Serve is like ListenAndServe, on the listener.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	srv := &http.Server{Handler: s}
+	// the event streams are hijacked connections, not closed by Shutdown.
+	srv.RegisterOnShutdown(s.mon.Disconnect)
+	done := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+
+		sctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+		done <- srv.Shutdown(sctx)
+	}()
+
+	if err := srv.Serve(l); err != http.ErrServerClosed {
+		return err
+	}
+
+	return <-done
+}
+
+type handlerFunc func(r *http.Request) (interface{}, error)
+
+// handle returns a handler calling fn for the requests of the method,
+// writing its result as JSON.
+func (s *Server) handle(method string, fn handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{fmt.Sprintf("method %s not allowed", r.Method)})
+			return
+		}
+
+		v, err := fn(r)
+		if !errors.Is(err, errBadRequest) && !errors.Is(err, errNotFound) {
+			s.mon.Result(err)
+		}
+
+		switch {
+		case errors.Is(err, errBadRequest):
+			writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		case errors.Is(err, errNotFound):
+			writeJSON(w, http.StatusNotFound, errorResponse{err.Error()})
+		case err != nil:
+			writeJSON(w, http.StatusInternalServerError, errorResponse{err.Error()})
+		case v == nil:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeJSON(w, http.StatusOK, v)
+		}
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// SysInfo is the response of GET /sysinfo. Voices is zero for the players
+// not reporting it.
+type SysInfo struct {
+	Version string `json:"version"`
+	Tracks  int    `json:"tracks"`
+	Voices  int    `json:"voices,omitempty"`
+}
+
+func (s *Server) sysinfo(r *http.Request) (interface{}, error) {
+	info := SysInfo{Version: s.p.GetVersion(), Tracks: s.p.GetNumTracks()}
+	if v, ok := s.p.(interface{ GetNumVoices() int }); ok {
+		info.Voices = v.GetNumVoices()
+	}
+
+	return info, nil
+}
+
+// Voices is the response of GET /voices, Playing is nil for the players not
+// able to report it.
+type Voices struct {
+	Voices  int   `json:"voices,omitempty"`
+	Playing []int `json:"playing"`
+}
+
+func (s *Server) voices(r *http.Request) (interface{}, error) {
+	var v Voices
+	if n, ok := s.p.(interface{ GetNumVoices() int }); ok {
+		v.Voices = n.GetNumVoices()
+	}
+
+	st, ok := s.p.(interface {
+		Status(ctx context.Context) ([]int, error)
+	})
+
+	if !ok {
+		return v, nil
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+	defer cancel()
+
+	tracks, err := st.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v.Playing = tracks
+	if v.Playing == nil {
+		v.Playing = []int{}
+	}
+
+	return v, nil
+}
+
+func (s *Server) stopAll(r *http.Request) (interface{}, error) {
+	return nil, s.p.StopAllTracks()
+}
+
+func (s *Server) resumeAll(r *http.Request) (interface{}, error) {
+	return nil, s.p.ResumeAllInSync()
+}
+
+// GainRequest is the body of the gain requests.
+type GainRequest struct {
+	Gain tsunami.Gain `json:"gain"`
+}
+
+func (s *Server) output(w http.ResponseWriter, r *http.Request) {
+	out, action, err := route(r.URL.Path, "/outputs/", 0, tsunami.MaxOutputs-1)
+	if err != nil || action != "gain" {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.handle(http.MethodPut, func(r *http.Request) (interface{}, error) {
+		var req GainRequest
+		if err := decode(r, &req); err != nil {
+			return nil, err
+		}
+
+		return nil, s.p.MasterGain(out, req.Gain)
+	})(w, r)
+}
+
+// TrackStatus is the response of GET /tracks/{n}.
+type TrackStatus struct {
+	Track   int  `json:"track"`
+	Playing bool `json:"playing"`
+}
+
+// PlayRequest is the body of the play and load requests.
+type PlayRequest struct {
+	Output int  `json:"output"`
+	Lock   bool `json:"lock"`
+	// Solo stops the rest of the tracks, ignored by load.
+	Solo bool `json:"solo"`
+}
+
+// FadeRequest is the body of the fade requests, the duration is a string
+// like "1.5s".
+type FadeRequest struct {
+	Gain     tsunami.Gain `json:"gain"`
+	Duration string       `json:"duration"`
+	Stop     bool         `json:"stop"`
+}
+
+// LoopRequest is the body of the loop requests.
+type LoopRequest struct {
+	Loop bool `json:"loop"`
+}
+
+func (s *Server) track(w http.ResponseWriter, r *http.Request) {
+	trk, action, err := route(r.URL.Path, "/tracks/", 1, tsunami.MaxTracks)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var method string
+	var fn handlerFunc
+	switch action {
+	case "":
+		method, fn = http.MethodGet, func(*http.Request) (interface{}, error) {
+			return TrackStatus{Track: trk, Playing: s.p.IsTrackPlaying(trk)}, nil
+		}
+	case "play", "load":
+		method, fn = http.MethodPost, func(r *http.Request) (interface{}, error) {
+			var req PlayRequest
+			if err := decode(r, &req); err != nil {
+				return nil, err
+			}
+
+			if req.Output < 0 || req.Output >= tsunami.MaxOutputs {
+				return nil, fmt.Errorf("%w: invalid output %d", errBadRequest, req.Output)
+			}
+
+			switch {
+			case action == "load":
+				return nil, s.p.TrackLoad(trk, req.Output, req.Lock)
+			case req.Solo:
+				return nil, s.p.TrackPlaySolo(trk, req.Output, req.Lock)
+			}
+
+			return nil, s.p.TrackPlayPoly(trk, req.Output, req.Lock)
+		}
+	case "stop":
+		method, fn = http.MethodPost, func(*http.Request) (interface{}, error) {
+			return nil, s.p.TrackStop(trk)
+		}
+	case "pause":
+		method, fn = http.MethodPost, func(*http.Request) (interface{}, error) {
+			return nil, s.p.TrackPause(trk)
+		}
+	case "resume":
+		method, fn = http.MethodPost, func(*http.Request) (interface{}, error) {
+			return nil, s.p.TrackResume(trk)
+		}
+	case "gain":
+		method, fn = http.MethodPut, func(r *http.Request) (interface{}, error) {
+			var req GainRequest
+			if err := decode(r, &req); err != nil {
+				return nil, err
+			}
+
+			return nil, s.p.TrackGain(trk, req.Gain)
+		}
+	case "fade":
+		method, fn = http.MethodPost, func(r *http.Request) (interface{}, error) {
+			var req FadeRequest
+			if err := decode(r, &req); err != nil {
+				return nil, err
+			}
+
+			d, err := time.ParseDuration(req.Duration)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("%w: invalid duration %q", errBadRequest, req.Duration)
+			}
+
+			return nil, s.p.TrackFade(trk, req.Gain, d, req.Stop)
+		}
+	case "loop":
+		method, fn = http.MethodPut, func(r *http.Request) (interface{}, error) {
+			var req LoopRequest
+			if err := decode(r, &req); err != nil {
+				return nil, err
+			}
+
+			return nil, s.p.TrackLoop(trk, req.Loop)
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	s.handle(method, fn)(w, r)
+}
+
+// route parses paths like /tracks/12/play, returning the number, between min
+// and max, and the action, empty if none.
+func route(path, prefix string, min, max int) (int, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)
+	n, err := strconv.Atoi(parts[0])
+	if err != nil || n < min || n > max {
+		return 0, "", fmt.Errorf("invalid number %q", parts[0])
+	}
+
+	if len(parts) == 1 {
+		return n, "", nil
+	}
+
+	return n, parts[1], nil
+}
+
+// decode reads the JSON body of the request, an empty body is valid.
+func decode(r *http.Request, v interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("%w: %s", errBadRequest, err)
+	}
+
+	return nil
+}
+
+// SetCueStack sets the cue stack fired by the API, streaming its cues.
+func (s *Server) SetCueStack(c *show.CueStack) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cues = c
+	c.OnCue(func(cue show.Cue) {
+		s.mon.Cue(cue.Number)
+	})
+}
+
+// SetAllowedOrigins sets the origins, like "https://panel.example.com",
+// allowed to open the event stream besides the server itself, "*" allows
+// any of them. The requests without Origin, not made by browsers, are always
+// allowed.
+func (s *Server) SetAllowedOrigins(origins ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.origins = make(map[string]bool, len(origins))
+	for _, o := range origins {
+		s.origins[strings.ToLower(strings.TrimSuffix(o, "/"))] = true
+	}
+}
+
+// allowedOrigin reports whether the request comes from the same host or an
+// allowed origin, so other sites can't open the stream from a browser.
+func (s *Server) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.origins["*"] || s.origins[strings.ToLower(origin)]
+}
+
+func (s *Server) cueStack() (*show.CueStack, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cues == nil {
+		return nil, fmt.Errorf("%w: no cue stack", errNotFound)
+	}
+
+	return s.cues, nil
+}
+
+// Cues is the response of GET /cues, Standby is nil at the end of the stack.
+type Cues struct {
+	Standby *show.Cue  `json:"standby"`
+	Cues    []show.Cue `json:"cues"`
+}
+
+func (s *Server) cueList(r *http.Request) (interface{}, error) {
+	c, err := s.cueStack()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := Cues{Cues: c.Cues()}
+	if cue, ok := c.Standby(); ok {
+		resp.Standby = &cue
+	}
+
+	return resp, nil
+}
+
+func (s *Server) cueGo(r *http.Request) (interface{}, error) {
+	c, err := s.cueStack()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Go()
+	if err == show.ErrEndOfStack {
+		return nil, fmt.Errorf("%w: %s", errBadRequest, err)
+	}
+
+	return nil, err
+}
+
+func (s *Server) cueBack(r *http.Request) (interface{}, error) {
+	c, err := s.cueStack()
+	if err != nil {
+		return nil, err
+	}
+
+	c.Back()
+	return nil, nil
+}
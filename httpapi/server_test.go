@@ -0,0 +1,194 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// board answers the queries of the server like a board with track 7 playing.
+var board = transport.Script{
+	protocol.CMD_GET_VERSION:  {&protocol.VersionString{Version: "Tsunami v1.10"}},
+	protocol.CMD_GET_SYS_INFO: {&protocol.SystemInfo{Voices: 18, Tracks: 40}},
+	protocol.CMD_GET_STATUS:   {&protocol.Status{Tracks: []uint16{7}}},
+}
+
+func do(s *Server, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestServerTracks(t *testing.T) {
+	port := transport.NewLoopback(board.Respond)
+	ts := tsunami.NewTsunamiTransport(port)
+	s := New(ts)
+
+	requests := []struct{ method, path, body string }{
+		{http.MethodPost, "/tracks/12/play", `{"output": 2, "lock": true}`},
+		{http.MethodPost, "/tracks/12/play", `{"solo": true}`},
+		{http.MethodPost, "/tracks/13/load", ""},
+		{http.MethodPut, "/tracks/12/gain", `{"gain": -6}`},
+		{http.MethodPost, "/tracks/12/fade", `{"gain": -70, "duration": "2s", "stop": true}`},
+		{http.MethodPut, "/tracks/12/loop", `{"loop": true}`},
+		{http.MethodPost, "/tracks/12/pause", ""},
+		{http.MethodPost, "/tracks/12/resume", ""},
+		{http.MethodPost, "/tracks/12/stop", ""},
+		{http.MethodPut, "/outputs/3/gain", `{"gain": 2}`},
+		{http.MethodPost, "/stop", ""},
+	}
+
+	for _, r := range requests {
+		if w := do(s, r.method, r.path, r.body); w.Code != http.StatusNoContent {
+			t.Fatalf("%s %s: unexpected response %d %s", r.method, r.path, w.Code, w.Body)
+		}
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, m := range msgs {
+		got = append(got, protocol.Describe(m))
+	}
+
+	expected := []protocol.Message{
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 12, Output: 2, Flags: 1},
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_SOLO, Track: 12},
+		&protocol.TrackControl{Code: tsunami.TRK_LOAD, Track: 13},
+		&protocol.TrackVolume{Track: 12, Gain: -6},
+		&protocol.TrackFade{Track: 12, Gain: -70, Millis: 2000, Stop: true},
+		&protocol.TrackControl{Code: tsunami.TRK_LOOP_ON, Track: 12},
+		&protocol.TrackControl{Code: tsunami.TRK_PAUSE, Track: 12},
+		&protocol.TrackControl{Code: tsunami.TRK_RESUME, Track: 12},
+		&protocol.TrackControl{Code: tsunami.TRK_STOP, Track: 12},
+		&protocol.MasterVolume{Output: 3, Gain: 2},
+		&protocol.StopAll{},
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("unexpected commands %q", got)
+	}
+
+	for i, m := range expected {
+		if got[i] != protocol.Describe(m) {
+			t.Errorf("%d: got %q, expected %q", i, got[i], protocol.Describe(m))
+		}
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(board.Respond))
+	s := New(ts)
+
+	for _, r := range []struct {
+		method, path, body string
+		code               int
+	}{
+		{http.MethodGet, "/tracks/12/play", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/tracks/0/play", "", http.StatusNotFound},
+		{http.MethodPost, "/tracks/12/jump", "", http.StatusNotFound},
+		{http.MethodPost, "/tracks/12/play", `{"output": 8}`, http.StatusBadRequest},
+		{http.MethodPost, "/tracks/12/play", `{"output": "left"}`, http.StatusBadRequest},
+		{http.MethodPost, "/tracks/12/fade", `{"gain": -70}`, http.StatusBadRequest},
+		{http.MethodPut, "/outputs/8/gain", `{"gain": 0}`, http.StatusNotFound},
+	} {
+		w := do(s, r.method, r.path, r.body)
+		if w.Code != r.code {
+			t.Errorf("%s %s: unexpected response %d %s", r.method, r.path, w.Code, w.Body)
+		}
+	}
+
+	w := do(s, http.MethodPost, "/tracks/12/play", `{"output": 8}`)
+	if body := strings.TrimSpace(w.Body.String()); body != `{"error":"bad request: invalid output 8"}` {
+		t.Errorf("unexpected body %s", body)
+	}
+}
+
+func TestServerInfo(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(board.Respond))
+	if err := ts.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(ts)
+
+	var info SysInfo
+	w := do(s, http.MethodGet, "/sysinfo", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (SysInfo{Version: "Tsunami v1.10", Tracks: 40, Voices: 18}); info != expected {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	var v Voices
+	w = do(s, http.MethodGet, "/voices", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (Voices{Voices: 18, Playing: []int{7}}); !reflect.DeepEqual(v, expected) {
+		t.Errorf("unexpected voices %+v", v)
+	}
+
+	if w := do(s, http.MethodGet, "/tracks/7", ""); strings.TrimSpace(w.Body.String()) != `{"track":7,"playing":false}` {
+		t.Errorf("unexpected status %s", w.Body)
+	}
+}
+
+func TestServerShutdown(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(board.Respond))
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- New(ts).Serve(ctx, l) }()
+
+	resp, err := http.Post("http://"+l.Addr().String()+"/stop", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+// controls returns the codes of the track controls sent since the last call.
+func controls(t *testing.T, port *transport.Loopback) []protocol.TrackCode {
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var codes []protocol.TrackCode
+	for _, m := range msgs {
+		if c, ok := m.(*protocol.TrackControl); ok {
+			codes = append(codes, c.Code)
+		}
+	}
+
+	return codes
+}
@@ -0,0 +1,22 @@
+package httpapi
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed ui
+var ui embed.FS
+
+// uiHandler serves the control panel, a single page driving the board from
+// a browser: a grid of the tracks, the gains of the outputs, the voices in
+// use and the GO of the cue stack, if any.
+func uiHandler() http.Handler {
+	sub, err := fs.Sub(ui, "ui")
+	if err != nil {
+		panic(err)
+	}
+
+	return http.FileServer(http.FS(sub))
+}
@@ -0,0 +1,60 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/show"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestServerUI(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(board.Respond))
+	w := do(New(ts), http.MethodGet, "/", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "<title>Tsunami</title>") {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body)
+	}
+}
+
+func TestServerCues(t *testing.T) {
+	port := transport.NewLoopback(board.Respond)
+	ts := tsunami.NewTsunamiTransport(port)
+	s := New(ts)
+
+	if w := do(s, http.MethodPost, "/cues/go", ""); w.Code != http.StatusNotFound {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body)
+	}
+
+	s.SetCueStack(show.NewCueStack(ts, []show.Cue{
+		{Number: "1", Actions: []show.Action{{Type: show.Play, Track: 4}}},
+		{Number: "2"},
+	}))
+
+	for _, path := range []string{"/cues/go", "/cues/go", "/cues/back"} {
+		if w := do(s, http.MethodPost, path, ""); w.Code != http.StatusNoContent {
+			t.Fatalf("%s: unexpected response %d %s", path, w.Code, w.Body)
+		}
+	}
+
+	var c Cues
+	w := do(s, http.MethodGet, "/cues", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &c); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.Cues) != 2 || c.Standby == nil || c.Standby.Number != "2" {
+		t.Errorf("unexpected cues %+v", c)
+	}
+
+	do(s, http.MethodPost, "/cues/go", "")
+	if w := do(s, http.MethodPost, "/cues/go", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("unexpected response %d %s", w.Code, w.Body)
+	}
+
+	if c := controls(t, port); len(c) != 1 || c[0] != tsunami.TRK_PLAY_POLY {
+		t.Errorf("unexpected controls %v", c)
+	}
+}
@@ -0,0 +1,486 @@
+// Package jsonrpc exposes the command set of a board as a JSON-RPC 2.0
+// service over TCP, for integrators whose platforms speak JSON-RPC but not
+// gRPC or HTTP comfortably. Every request, or batch, is a line of JSON, and
+// so is every response:
+//
+//	--> {"jsonrpc": "2.0", "method": "play", "params": {"track": 12, "output": 0}, "id": 1}
+//	<-- {"jsonrpc":"2.0","result":null,"id":1}
+//
+// The methods, with their parameters by name, are:
+//
+//	play         track, output, lock, solo
+//	load         track, output, lock
+//	stop         track
+//	pause        track
+//	resume       track
+//	gain         track, gain
+//	fade         track, gain, duration (like "1.5s"), stop
+//	loop         track, loop
+//	is_playing   track, returning a boolean
+//	stop_all
+//	resume_all
+//	master_gain  output, gain
+//	sysinfo      returning {"version", "tracks", "voices"}
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// The error codes of JSON-RPC 2.0.
+const (
+	ParseError     = -32700
+	InvalidRequest = -32600
+	MethodNotFound = -32601
+	InvalidParams  = -32602
+	// BoardError is returned when the command fails on the board.
+	BoardError = -32000
+)
+
+// maxLineSize is the size of the longest request accepted.
+const maxLineSize = 1 << 20
+
+// Error is the error of a response.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Message, e.Code)
+}
+
+func invalidParams(format string, args ...interface{}) *Error {
+	return &Error{Code: InvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+type request struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type response struct {
+	Version string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// MarshalJSON omits the result of the errors, as required by the spec.
+func (r response) MarshalJSON() ([]byte, error) {
+	type plain response
+	if r.Error == nil {
+		return json.Marshal(plain(r))
+	}
+
+	return json.Marshal(struct {
+		Version string          `json:"jsonrpc"`
+		Error   *Error          `json:"error"`
+		ID      json.RawMessage `json:"id"`
+	}{r.Version, r.Error, r.ID})
+}
+
+type method func(params json.RawMessage) (interface{}, error)
+
+// Server serves the JSON-RPC service of a player.
+type Server struct {
+	p       tsunami.Player
+	methods map[string]method
+
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+}
+
+// New returns the server of the player.
+func New(p tsunami.Player) *Server {
+	s := &Server{p: p, conns: make(map[net.Conn]bool)}
+	s.methods = map[string]method{
+		"play":        s.play,
+		"load":        s.load,
+		"stop":        trackMethod(p.TrackStop),
+		"pause":       trackMethod(p.TrackPause),
+		"resume":      trackMethod(p.TrackResume),
+		"gain":        s.gain,
+		"fade":        s.fade,
+		"loop":        s.loop,
+		"is_playing":  s.isPlaying,
+		"stop_all":    noParams(p.StopAllTracks),
+		"resume_all":  noParams(p.ResumeAllInSync),
+		"master_gain": s.masterGain,
+		"sysinfo":     s.sysinfo,
+	}
+
+	return s
+}
+
+// ListenAndServe serves the requests of the connections to the address
+// until the context is done, then closes them.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, l)
+}
+
+// Serve is like ListenAndServe, on the listener.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for c := range s.conns {
+			c.Close()
+		}
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+
+		go func() {
+			s.ServeConn(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+// ServeConn serves the requests read from the connection, one per line,
+// until it's closed.
+func (s *Server) ServeConn(rw io.ReadWriter) error {
+	scanner := bufio.NewScanner(rw)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		resp := s.Handle(line)
+		if resp == nil {
+			continue
+		}
+
+		if _, err := rw.Write(append(resp, '\n')); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Handle runs a request, or a batch of them, returning the response, nil
+// for notifications.
+func (s *Server) Handle(data []byte) []byte {
+	if len(data) > 0 && data[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return marshal(errorResponse(nil, ParseError, err.Error()))
+		}
+
+		if len(batch) == 0 {
+			return marshal(errorResponse(nil, InvalidRequest, "empty batch"))
+		}
+
+		var responses []*response
+		for _, req := range batch {
+			if r := s.handle(req); r != nil {
+				responses = append(responses, r)
+			}
+		}
+
+		if len(responses) == 0 {
+			return nil
+		}
+
+		return marshal(responses)
+	}
+
+	if r := s.handle(data); r != nil {
+		return marshal(r)
+	}
+
+	return nil
+}
+
+func marshal(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(errorResponse(nil, BoardError, err.Error()))
+	}
+
+	return data
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *response {
+	return &response{Version: "2.0", Error: &Error{Code: code, Message: msg}, ID: nullID(id)}
+}
+
+func nullID(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return json.RawMessage("null")
+	}
+
+	return id
+}
+
+func (s *Server) handle(data []byte) *response {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		if _, ok := err.(*json.SyntaxError); ok {
+			return errorResponse(nil, ParseError, err.Error())
+		}
+
+		return errorResponse(nil, InvalidRequest, err.Error())
+	}
+
+	if req.Version != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, InvalidRequest, "invalid request")
+	}
+
+	result, err := s.call(req)
+	if req.ID == nil {
+		// a notification, never answered.
+		return nil
+	}
+
+	if err != nil {
+		rerr, ok := err.(*Error)
+		if !ok {
+			rerr = &Error{Code: BoardError, Message: err.Error()}
+		}
+
+		return &response{Version: "2.0", Error: rerr, ID: req.ID}
+	}
+
+	return &response{Version: "2.0", Result: result, ID: req.ID}
+}
+
+func (s *Server) call(req request) (interface{}, error) {
+	m, ok := s.methods[req.Method]
+	if !ok {
+		return nil, &Error{Code: MethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+
+	return m(req.Params)
+}
+
+// params decodes the parameters, by name, validating the track and output
+// if present.
+func params(data json.RawMessage, v interface{}) error {
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, v); err != nil {
+			return invalidParams("%s", err)
+		}
+	}
+
+	if p, ok := v.(interface{ validate() error }); ok {
+		return p.validate()
+	}
+
+	return nil
+}
+
+type trackParams struct {
+	Track int `json:"track"`
+}
+
+func (p *trackParams) validate() error {
+	if p.Track < 1 || p.Track > tsunami.MaxTracks {
+		return invalidParams("invalid track %d", p.Track)
+	}
+
+	return nil
+}
+
+type playParams struct {
+	Track  int  `json:"track"`
+	Output int  `json:"output"`
+	Lock   bool `json:"lock"`
+	Solo   bool `json:"solo"`
+}
+
+func (p *playParams) validate() error {
+	if err := (&trackParams{p.Track}).validate(); err != nil {
+		return err
+	}
+
+	if p.Output < 0 || p.Output >= tsunami.MaxOutputs {
+		return invalidParams("invalid output %d", p.Output)
+	}
+
+	return nil
+}
+
+func (s *Server) play(data json.RawMessage) (interface{}, error) {
+	var p playParams
+	if err := params(data, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Solo {
+		return nil, s.p.TrackPlaySolo(p.Track, p.Output, p.Lock)
+	}
+
+	return nil, s.p.TrackPlayPoly(p.Track, p.Output, p.Lock)
+}
+
+func (s *Server) load(data json.RawMessage) (interface{}, error) {
+	var p playParams
+	if err := params(data, &p); err != nil {
+		return nil, err
+	}
+
+	return nil, s.p.TrackLoad(p.Track, p.Output, p.Lock)
+}
+
+func trackMethod(fn func(trk int) error) method {
+	return func(data json.RawMessage) (interface{}, error) {
+		var p trackParams
+		if err := params(data, &p); err != nil {
+			return nil, err
+		}
+
+		return nil, fn(p.Track)
+	}
+}
+
+func noParams(fn func() error) method {
+	return func(json.RawMessage) (interface{}, error) {
+		return nil, fn()
+	}
+}
+
+type gainParams struct {
+	Track int          `json:"track"`
+	Gain  tsunami.Gain `json:"gain"`
+}
+
+func (s *Server) gain(data json.RawMessage) (interface{}, error) {
+	var p gainParams
+	if err := params(data, &p); err != nil {
+		return nil, err
+	}
+
+	if err := (&trackParams{p.Track}).validate(); err != nil {
+		return nil, err
+	}
+
+	return nil, s.p.TrackGain(p.Track, p.Gain)
+}
+
+type fadeParams struct {
+	Track    int          `json:"track"`
+	Gain     tsunami.Gain `json:"gain"`
+	Duration string       `json:"duration"`
+	Stop     bool         `json:"stop"`
+}
+
+func (s *Server) fade(data json.RawMessage) (interface{}, error) {
+	var p fadeParams
+	if err := params(data, &p); err != nil {
+		return nil, err
+	}
+
+	if err := (&trackParams{p.Track}).validate(); err != nil {
+		return nil, err
+	}
+
+	d, err := time.ParseDuration(p.Duration)
+	if err != nil || d <= 0 {
+		return nil, invalidParams("invalid duration %q", p.Duration)
+	}
+
+	return nil, s.p.TrackFade(p.Track, p.Gain, d, p.Stop)
+}
+
+type loopParams struct {
+	Track int  `json:"track"`
+	Loop  bool `json:"loop"`
+}
+
+func (s *Server) loop(data json.RawMessage) (interface{}, error) {
+	var p loopParams
+	if err := params(data, &p); err != nil {
+		return nil, err
+	}
+
+	if err := (&trackParams{p.Track}).validate(); err != nil {
+		return nil, err
+	}
+
+	return nil, s.p.TrackLoop(p.Track, p.Loop)
+}
+
+func (s *Server) isPlaying(data json.RawMessage) (interface{}, error) {
+	var p trackParams
+	if err := params(data, &p); err != nil {
+		return nil, err
+	}
+
+	return s.p.IsTrackPlaying(p.Track), nil
+}
+
+type masterGainParams struct {
+	Output int          `json:"output"`
+	Gain   tsunami.Gain `json:"gain"`
+}
+
+func (s *Server) masterGain(data json.RawMessage) (interface{}, error) {
+	var p masterGainParams
+	if err := params(data, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Output < 0 || p.Output >= tsunami.MaxOutputs {
+		return nil, invalidParams("invalid output %d", p.Output)
+	}
+
+	return nil, s.p.MasterGain(p.Output, p.Gain)
+}
+
+// SysInfo is the result of the sysinfo method. Voices is zero for the
+// players not reporting it.
+type SysInfo struct {
+	Version string `json:"version"`
+	Tracks  int    `json:"tracks"`
+	Voices  int    `json:"voices,omitempty"`
+}
+
+func (s *Server) sysinfo(json.RawMessage) (interface{}, error) {
+	info := SysInfo{Version: s.p.GetVersion(), Tracks: s.p.GetNumTracks()}
+	if v, ok := s.p.(interface{ GetNumVoices() int }); ok {
+		info.Voices = v.GetNumVoices()
+	}
+
+	return info, nil
+}
@@ -0,0 +1,107 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestServerHandle(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	s := New(ts)
+
+	for _, c := range []struct{ req, resp string }{
+		{`{"jsonrpc": "2.0", "method": "play", "params": {"track": 12, "output": 1}, "id": 1}`,
+			`{"jsonrpc":"2.0","result":null,"id":1}`},
+		{`{"jsonrpc": "2.0", "method": "fade", "params": {"track": 12, "gain": -70, "duration": "2s", "stop": true}, "id": "a"}`,
+			`{"jsonrpc":"2.0","result":null,"id":"a"}`},
+		{`{"jsonrpc": "2.0", "method": "is_playing", "params": {"track": 12}, "id": 2}`,
+			`{"jsonrpc":"2.0","result":false,"id":2}`},
+		{`{"jsonrpc": "2.0", "method": "stop_all"}`, ``},
+		{`[{"jsonrpc": "2.0", "method": "gain", "params": {"track": 3, "gain": -6}, "id": 3}, {"jsonrpc": "2.0", "method": "stop", "params": {"track": 3}}]`,
+			`[{"jsonrpc":"2.0","result":null,"id":3}]`},
+		{`{"jsonrpc": "2.0", "method": "play", "params": {"track": 0}, "id": 4}`,
+			`{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid track 0"},"id":4}`},
+		{`{"jsonrpc": "2.0", "method": "master_gain", "params": {"output": 8}, "id": 5}`,
+			`{"jsonrpc":"2.0","error":{"code":-32602,"message":"invalid output 8"},"id":5}`},
+		{`{"jsonrpc": "2.0", "method": "jump", "id": 6}`,
+			`{"jsonrpc":"2.0","error":{"code":-32601,"message":"method \"jump\" not found"},"id":6}`},
+		{`{"method": "play", "id": 7}`,
+			`{"jsonrpc":"2.0","error":{"code":-32600,"message":"invalid request"},"id":7}`},
+		{`{"jsonrpc": "2.0", "method"`,
+			`{"jsonrpc":"2.0","error":{"code":-32700,"message":"unexpected end of JSON input"},"id":null}`},
+		{`[]`, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"empty batch"},"id":null}`},
+	} {
+		if resp := string(s.Handle([]byte(c.req))); resp != c.resp {
+			t.Errorf("%s:\n got %s\nwant %s", c.req, resp, c.resp)
+		}
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, m := range msgs {
+		got = append(got, protocol.Describe(m))
+	}
+
+	expected := []protocol.Message{
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 12, Output: 1},
+		&protocol.TrackFade{Track: 12, Gain: -70, Millis: 2000, Stop: true},
+		&protocol.StopAll{},
+		&protocol.TrackVolume{Track: 3, Gain: -6},
+		&protocol.TrackControl{Code: tsunami.TRK_STOP, Track: 3},
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("unexpected commands %q", got)
+	}
+
+	for i, m := range expected {
+		if got[i] != protocol.Describe(m) {
+			t.Errorf("%d: got %q, expected %q", i, got[i], protocol.Describe(m))
+		}
+	}
+}
+
+func TestServerServe(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(nil))
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- New(ts).Serve(ctx, l) }()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+	conn.Write([]byte("{\"jsonrpc\": \"2.0\", \"method\": \"stop_all\", \"id\": 1}\n\n"))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || line != "{\"jsonrpc\":\"2.0\",\"result\":null,\"id\":1}\n" {
+		t.Errorf("unexpected response %q, %v", line, err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Error("the connections should be closed")
+	}
+}
@@ -0,0 +1,92 @@
+package tsunami
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrUnknownLocale is returned when setting a locale without aliases.
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// Locales are parallel sets of aliases by language, mapping the same names
+// to different tracks, usually in different banks, eg. the narration of a
+// museum recorded in several languages.
+type Locales map[string]Aliases
+
+// LoadLocales reads locales as a JSON object of aliases by locale, eg.:
+//
+//	{"en": {"welcome": 101}, "fr": {"welcome": 201}}
+func LoadLocales(r io.Reader) (Locales, error) {
+	var l Locales
+	if err := json.NewDecoder(r).Decode(&l); err != nil {
+		return nil, err
+	}
+
+	for locale, a := range l {
+		if err := a.validate(); err != nil {
+			return nil, fmt.Errorf("locale %q: %w", locale, err)
+		}
+	}
+
+	return l, nil
+}
+
+// SetLocales sets the aliases of every locale, see SetLocale. The locale
+// set, if any, is kept if still defined.
+func (t *Tsunami) SetLocales(l Locales) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.locales = l
+	if _, ok := l[t.locale]; !ok {
+		t.locale = ""
+	}
+}
+
+// SetLocale re-targets the name based playback, PlayNamed, TrackName and
+// SubscribeNamed, to the aliases of the locale, falling back to the ones of
+// SetAliases for the names not localized. An empty locale only uses the
+// aliases of SetAliases.
+func (t *Tsunami) SetLocale(locale string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.locales[locale]; !ok && locale != "" {
+		return fmt.Errorf("%w %q", ErrUnknownLocale, locale)
+	}
+
+	t.locale = locale
+	return nil
+}
+
+// Locale returns the locale set, empty if none.
+func (t *Tsunami) Locale() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.locale
+}
+
+// names returns the aliases in use, the ones of the locale over the rest.
+func (t *Tsunami) names() Aliases {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	localized := t.locales[t.locale]
+	if len(localized) == 0 {
+		return t.aliases
+	}
+
+	a := make(Aliases, len(t.aliases)+len(localized))
+	for name, trk := range t.aliases {
+		a[name] = trk
+	}
+
+	for name, trk := range localized {
+		a[name] = trk
+	}
+
+	return a
+}
@@ -0,0 +1,65 @@
+package tsunami
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestLoadLocales(t *testing.T) {
+	l, err := LoadLocales(strings.NewReader(`{"en": {"welcome": 101}, "fr": {"welcome": 201}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if trk, err := l["fr"].Track("welcome"); err != nil || trk != 201 {
+		t.Errorf("unexpected track %d, %v", trk, err)
+	}
+
+	if _, err := LoadLocales(strings.NewReader(`{"fr": {"welcome": 5000}}`)); err == nil {
+		t.Error("invalid track should fail")
+	}
+}
+
+func TestTsunamiSetLocale(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	ts.SetAliases(Aliases{"welcome": 101, "chime": 1})
+	ts.SetLocales(Locales{"fr": {"welcome": 201}, "de": {"welcome": 301}})
+
+	if err := ts.SetLocale("es"); !errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	for _, locale := range []string{"fr", "de", ""} {
+		if err := ts.SetLocale(locale); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ts.PlayNamed("welcome", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ts.PlayNamed("chime", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []control{{TRK_PLAY_POLY, 201}, {TRK_PLAY_POLY, 301}, {TRK_PLAY_POLY, 101}, {TRK_PLAY_POLY, 1}}
+	if c := controls(t, port); !reflect.DeepEqual(c, expected) {
+		t.Errorf("unexpected controls %v", c)
+	}
+
+	ts.SetLocale("fr")
+	if name := ts.TrackName(201); name != "welcome" {
+		t.Errorf("unexpected name %q", name)
+	}
+
+	ts.SetLocales(Locales{"de": {"welcome": 301}})
+	if locale := ts.Locale(); locale != "" {
+		t.Errorf("a locale removed should be unset, got %q", locale)
+	}
+}
@@ -0,0 +1,78 @@
+package tsunami
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// LogLevel is the severity of a log entry.
+type LogLevel int8
+
+const (
+	// LevelDebug logs every frame sent to and received from the board.
+	LevelDebug LogLevel = iota
+	// LevelInfo logs every command sent to the board.
+	LevelInfo
+	// LevelError logs the failures writing to the board, and the data
+	// received that couldn't be decoded, otherwise skipped silently.
+	LevelError
+)
+
+var levelNames = map[LogLevel]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelError: "ERROR",
+}
+
+func (l LogLevel) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("LogLevel(%d)", int8(l))
+}
+
+// Logger receives the log entries of a connection, see WithLogger. The
+// attributes are key and value pairs, like the ones of log/slog, so a
+// slog.Logger can be adapted easily.
+type Logger interface {
+	Log(level LogLevel, msg string, attrs ...interface{})
+}
+
+// WithLogger logs the activity of the connection to l.
+func WithLogger(l Logger) Option {
+	return func(t *Tsunami) {
+		t.log = l
+	}
+}
+
+// NewStdLogger returns a Logger writing the entries of the given level and
+// above to w, as lines like:
+//
+//	2024/01/02 15:04:05 INFO command sent command="CMD_TRACK_CONTROL {...}"
+func NewStdLogger(w io.Writer, level LogLevel) Logger {
+	return &stdLogger{l: log.New(w, "", log.LstdFlags), level: level}
+}
+
+type stdLogger struct {
+	l     *log.Logger
+	level LogLevel
+}
+
+func (s *stdLogger) Log(level LogLevel, msg string, attrs ...interface{}) {
+	if level < s.level {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(level.String())
+	b.WriteByte(' ')
+	b.WriteString(msg)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		fmt.Fprintf(&b, " %v=%q", attrs[i], fmt.Sprint(attrs[i+1]))
+	}
+
+	s.l.Print(b.String())
+}
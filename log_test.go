@@ -0,0 +1,71 @@
+package tsunami
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+type logEntry struct {
+	level LogLevel
+	msg   string
+	attrs []interface{}
+}
+
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) Log(level LogLevel, msg string, attrs ...interface{}) {
+	l.entries = append(l.entries, logEntry{level, msg, attrs})
+}
+
+func TestWithLogger(t *testing.T) {
+	port := &bufferPort{}
+	// a truncated track report, followed by a valid one.
+	port.rx = append([]byte{0xf0, 0xaa, 0x07, 0x84, 0x12, 0x00, 0x55},
+		protocol.TrackReport{Track: 19, Voice: 1, Playing: true}.AppendBinary(nil)...)
+
+	l := &testLogger{}
+	ts := newTestTsunami()
+	ts.port = port
+	WithLogger(l)(ts)
+
+	if err := ts.TrackGain(19, -6); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range l.entries {
+		got = append(got, fmt.Sprintf("%s %s %v", e.level, e.msg, e.attrs[:2]))
+	}
+
+	expected := []string{
+		"DEBUG frame sent [frame f0 aa 09 08 13 00 fa ff 55]",
+		"INFO command sent [command CMD_TRACK_VOLUME {Track:19 Gain:-6}]",
+		"ERROR frame skipped [frame f0 aa 07 84 12 00 55]",
+		"DEBUG frame received [frame f0 aa 09 84 12 00 01 01 55]",
+	}
+
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected entries:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := NewStdLogger(buf, LevelInfo)
+	l.Log(LevelDebug, "frame sent", "frame", "f0")
+	l.Log(LevelError, "write failed", "command", "CMD_STOP_ALL", "error", "closed")
+
+	if s := buf.String(); !strings.HasSuffix(s, ` ERROR write failed command="CMD_STOP_ALL" error="closed"`+"\n") || strings.Count(s, "\n") != 1 {
+		t.Errorf("unexpected log %q", s)
+	}
+}
@@ -0,0 +1,96 @@
+package tsunami
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultPreload is how long before the end of a track GaplessLoop loads
+// the next copy.
+const DefaultPreload = 200 * time.Millisecond
+
+// GaplessLoop loops a track seamlessly, alternating two copies of it stored
+// as different tracks on the card. While a copy plays, the other one is
+// loaded paused with TrackLoad and started with ResumeAllInSync when the
+// first one ends, as timed from the duration of the WAV file. It avoids the
+// clicks the loop flag produces at the seam with some content.
+//
+// ResumeAllInSync resumes every paused track, so no other track should be
+// left paused on the board while the loop is running.
+type GaplessLoop struct {
+	// Tracks are the track numbers of the two copies.
+	Tracks [2]int
+	Output int
+	Lock   bool
+	// Length is the exact duration of the track.
+	Length time.Duration
+	// Preload is how long before the end the next copy is loaded,
+	// DefaultPreload if zero.
+	Preload time.Duration
+}
+
+// Run plays the loop on the board until the context is done, then both
+// copies are stopped.
+func (l *GaplessLoop) Run(ctx context.Context, p Player) error {
+	if l.Length <= 0 {
+		return fmt.Errorf("invalid loop length %s", l.Length)
+	}
+
+	preload := l.Preload
+	if preload == 0 {
+		preload = DefaultPreload
+	}
+
+	if preload > l.Length {
+		preload = l.Length
+	}
+
+	defer func() {
+		p.TrackStop(l.Tracks[0])
+		p.TrackStop(l.Tracks[1])
+	}()
+
+	if err := p.TrackLoad(l.Tracks[0], l.Output, l.Lock); err != nil {
+		return err
+	}
+
+	if err := p.ResumeAllInSync(); err != nil {
+		return err
+	}
+
+	// the copies are scheduled from the start, so the errors of the timers
+	// don't accumulate over long sessions.
+	start := time.Now()
+	for i := 1; ; i++ {
+		next := start.Add(time.Duration(i) * l.Length)
+		if err := sleepUntil(ctx, next.Add(-preload)); err != nil {
+			return err
+		}
+
+		if err := p.TrackLoad(l.Tracks[i%2], l.Output, l.Lock); err != nil {
+			return err
+		}
+
+		if err := sleepUntil(ctx, next); err != nil {
+			return err
+		}
+
+		if err := p.ResumeAllInSync(); err != nil {
+			return err
+		}
+	}
+}
+
+// sleepUntil waits until the given time or until the context is done.
+func sleepUntil(ctx context.Context, t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
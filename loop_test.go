@@ -0,0 +1,75 @@
+package tsunami
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestGaplessLoop(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+
+	l := &GaplessLoop{
+		Tracks:  [2]int{10, 11},
+		Output:  1,
+		Length:  20 * time.Millisecond,
+		Preload: 5 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Millisecond)
+	defer cancel()
+
+	if err := l.Run(ctx, ts); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, m := range msgs {
+		got = append(got, protocol.Describe(m))
+	}
+
+	load := func(trk uint16) string {
+		return protocol.Describe(&protocol.TrackControl{Code: TRK_LOAD, Track: trk, Output: 1})
+	}
+
+	resume := protocol.Describe(&protocol.ResumeAllSync{})
+	expected := []string{load(10), resume, load(11), resume, load(10), resume}
+	if len(got) < len(expected)+2 {
+		t.Fatalf("unexpected commands %q", got)
+	}
+
+	for i, e := range expected {
+		if got[i] != e {
+			t.Errorf("%d: got %q, expected %q", i, got[i], e)
+		}
+	}
+
+	stops := got[len(got)-2:]
+	if stops[0] != protocol.Describe(&protocol.TrackControl{Code: TRK_STOP, Track: 10}) {
+		t.Errorf("copies should be stopped at the end, got %q", stops)
+	}
+}
+
+func TestGaplessLoopInvalidLength(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+
+	l := &GaplessLoop{Tracks: [2]int{10, 11}}
+	if err := l.Run(context.Background(), ts); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if frames := port.Frames(); len(frames) != 0 {
+		t.Errorf("unexpected frames %v", frames)
+	}
+}
@@ -0,0 +1,224 @@
+package tsunami
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Manager handles a set of boards by name, so the application refers to
+// them as "lobby" or "theater" instead of by port names, which may change
+// across reboots.
+type Manager struct {
+	mu      sync.Mutex
+	boards  map[string]*Tsunami
+	latency map[string]time.Duration
+}
+
+// NewManager returns a new empty Manager.
+func NewManager() *Manager {
+	return &Manager{
+		boards:  make(map[string]*Tsunami),
+		latency: make(map[string]time.Duration),
+	}
+}
+
+// Add registers the board with the given name, replacing any board with the
+// same name.
+func (m *Manager) Add(name string, t *Tsunami) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.boards[name] = t
+}
+
+// Get returns the board with the given name.
+func (m *Manager) Get(name string) (*Tsunami, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.boards[name]
+	return t, ok
+}
+
+// Remove unregisters the board with the given name, without closing it.
+func (m *Manager) Remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.boards, name)
+	delete(m.latency, name)
+}
+
+// Names returns the sorted names of the boards registered.
+func (m *Manager) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.boards))
+	for name := range m.boards {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Close closes every board registered, returning the first error found.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var err error
+	for name, t := range m.boards {
+		if cerr := t.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("%s: %w", name, cerr)
+		}
+
+		delete(m.boards, name)
+	}
+
+	return err
+}
+
+// DeviceConfig describes a board of a Manager. The board is located by the
+// serial number of its USB adapter, if given, or by its port name otherwise.
+type DeviceConfig struct {
+	Name   string `json:"name"`
+	Port   string `json:"port,omitempty"`
+	Serial string `json:"serial,omitempty"`
+	Profile
+}
+
+// Profile is the initial setup of a board, applied right after the
+// connection is started, so a board power-cycled always comes up with the
+// intended mix, see WithProfile.
+type Profile struct {
+	// MasterGains is the gain of every output, by output number.
+	MasterGains map[int]Gain `json:"master_gains,omitempty"`
+	// TrackGains is the gain of every track, by track number.
+	TrackGains map[int]Gain `json:"track_gains,omitempty"`
+	// Loops is the loop flag of every track, by track number.
+	Loops map[int]bool `json:"loops,omitempty"`
+	// Pitches is the pitch shift, in semitones, of every output, by output
+	// number, see SetPitch.
+	Pitches map[int]float64 `json:"pitches,omitempty"`
+	// TriggerBank is the trigger bank, it's left unchanged if zero.
+	TriggerBank int `json:"trigger_bank,omitempty"`
+}
+
+// LoadProfile reads a profile in JSON, eg.:
+//
+//	{
+//	  "master_gains": {"0": -6, "1": -6},
+//	  "track_gains": {"12": -3, "101": -10},
+//	  "loops": {"101": true},
+//	  "pitches": {"3": -0.5}
+//	}
+func LoadProfile(r io.Reader) (*Profile, error) {
+	var p Profile
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, err
+	}
+
+	return &p, p.validate()
+}
+
+func (p *Profile) validate() error {
+	for _, outs := range [][]int{sortedKeys(p.MasterGains), sortedKeys(p.Pitches)} {
+		for _, out := range outs {
+			if out < 0 || out >= MaxOutputs {
+				return fmt.Errorf("invalid output %d", out)
+			}
+		}
+	}
+
+	for _, trks := range [][]int{sortedKeys(p.TrackGains), sortedKeys(p.Loops)} {
+		for _, trk := range trks {
+			if trk <= 0 || trk > MaxTracks {
+				return fmt.Errorf("invalid track %d", trk)
+			}
+		}
+	}
+
+	if p.TriggerBank < 0 || p.TriggerBank > MaxBank {
+		return fmt.Errorf("invalid trigger bank %d", p.TriggerBank)
+	}
+
+	return nil
+}
+
+// Apply sends the profile to the board.
+func (p *Profile) Apply(t *Tsunami) error {
+	for _, out := range sortedKeys(p.MasterGains) {
+		if err := t.MasterGain(out, p.MasterGains[out]); err != nil {
+			return err
+		}
+	}
+
+	for _, out := range sortedKeys(p.Pitches) {
+		if err := t.SetPitch(out, p.Pitches[out]); err != nil {
+			return err
+		}
+	}
+
+	for _, trk := range sortedKeys(p.TrackGains) {
+		if err := t.TrackGain(trk, p.TrackGains[trk]); err != nil {
+			return err
+		}
+	}
+
+	for _, trk := range sortedKeys(p.Loops) {
+		if err := t.TrackLoop(trk, p.Loops[trk]); err != nil {
+			return err
+		}
+	}
+
+	if p.TriggerBank != 0 {
+		return t.SetTriggerBank(p.TriggerBank)
+	}
+
+	return nil
+}
+
+// LoadDevices reads a JSON array of device configs, eg.:
+//
+//	[
+//	  {"name": "lobby", "serial": "A50285BI", "master_gains": {"0": -6}},
+//	  {"name": "theater", "port": "/dev/ttyUSB1", "trigger_bank": 2}
+//	]
+func LoadDevices(r io.Reader) ([]DeviceConfig, error) {
+	var devices []DeviceConfig
+	if err := json.NewDecoder(r).Decode(&devices); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		switch {
+		case d.Name == "":
+			return nil, fmt.Errorf("device without name")
+		case seen[d.Name]:
+			return nil, fmt.Errorf("duplicated device %q", d.Name)
+		case d.Port == "" && d.Serial == "":
+			return nil, fmt.Errorf("device %q: port or serial is required", d.Name)
+		}
+
+		seen[d.Name] = true
+	}
+
+	return devices, nil
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,113 @@
+package tsunami
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestManager(t *testing.T) {
+	m := NewManager()
+	lobby, theater := newTestTsunami(), newTestTsunami()
+	m.Add("theater", theater)
+	m.Add("lobby", lobby)
+
+	if names := m.Names(); !reflect.DeepEqual(names, []string{"lobby", "theater"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+
+	if got, ok := m.Get("lobby"); !ok || got != lobby {
+		t.Errorf("unexpected board for lobby")
+	}
+
+	m.Remove("lobby")
+	if _, ok := m.Get("lobby"); ok {
+		t.Errorf("lobby should be removed")
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m.Names()) != 0 {
+		t.Errorf("boards should be unregistered on close")
+	}
+}
+
+func TestLoadDevices(t *testing.T) {
+	devices, err := LoadDevices(strings.NewReader(`[
+		{"name": "lobby", "serial": "A50285BI", "master_gains": {"0": -6}},
+		{"name": "theater", "port": "/dev/ttyUSB1", "trigger_bank": 2}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []DeviceConfig{
+		{Name: "lobby", Serial: "A50285BI", Profile: Profile{MasterGains: map[int]Gain{0: -6}}},
+		{Name: "theater", Port: "/dev/ttyUSB1", Profile: Profile{TriggerBank: 2}},
+	}
+
+	if !reflect.DeepEqual(devices, expected) {
+		t.Errorf("unexpected devices %+v", devices)
+	}
+
+	for _, invalid := range []string{
+		`[{"port": "/dev/ttyUSB0"}]`,
+		`[{"name": "lobby"}]`,
+		`[{"name": "a", "port": "x"}, {"name": "a", "port": "y"}]`,
+	} {
+		if _, err := LoadDevices(strings.NewReader(invalid)); err == nil {
+			t.Errorf("%s: expected error", invalid)
+		}
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	p, err := LoadProfile(strings.NewReader(`{
+	  "master_gains": {"0": -6},
+	  "track_gains": {"12": -3},
+	  "loops": {"101": true},
+	  "pitches": {"3": -0.5}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port, WithProfile(p))
+	if err := ts.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []protocol.Message{
+		&protocol.GetVersion{},
+		&protocol.GetSysInfo{},
+		&protocol.MasterVolume{Output: 0, Gain: -6},
+		&protocol.SamplerateOffset{Output: 3, Offset: int16(SemitonesToOffset(-0.5))},
+		&protocol.TrackVolume{Track: 12, Gain: -3},
+		&protocol.TrackControl{Code: TRK_LOOP_ON, Track: 101},
+	}
+
+	if !reflect.DeepEqual(msgs, expected) {
+		t.Errorf("unexpected messages %v", msgs)
+	}
+
+	for _, invalid := range []string{
+		`{"master_gains": {"8": 0}}`,
+		`{"loops": {"0": true}}`,
+		`{"trigger_bank": 33}`,
+	} {
+		if _, err := LoadProfile(strings.NewReader(invalid)); err == nil {
+			t.Errorf("%s: should fail", invalid)
+		}
+	}
+}
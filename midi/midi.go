@@ -0,0 +1,302 @@
+// Package midi controls the board through its DIN MIDI input, wired to a
+// USB-MIDI interface, the alternative when the serial port is taken, by a
+// show controller or the boot loader, or not wired at all.
+//
+// The board is played as its default MIDI implementation does: a Note-On
+// starts the track of the note in the current MIDI bank, on the output of
+// the channel, at the gain of the velocity; a Note-Off stops it, if the
+// tracks are set to stop on release in the init file; a Program Change
+// selects the MIDI bank. MIDI is one-way, so the board can't report the
+// tracks playing nor its version, and only a subset of the tsunami.Player
+// interface is implemented, the rest returns ErrUnsupported.
+//
+// The package plays Standard MIDI Files as well, see Performer, triggering
+// the tracks of any tsunami.Player with the notes of a sequence.
+package midi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// ErrUnsupported is returned by the methods of the tsunami.Player interface
+// with no MIDI equivalent.
+var ErrUnsupported = errors.New("not supported over MIDI")
+
+// Status bytes of the channel messages, to be or-ed with the channel.
+const (
+	noteOff       = 0x80
+	noteOn        = 0x90
+	controlChange = 0xb0
+	programChange = 0xc0
+)
+
+// Controllers used by Controller.
+const (
+	// VolumeCC is the controller setting the gain of the output of the
+	// channel, the channel volume.
+	VolumeCC = 7
+	// AllNotesOffCC is the controller stopping the notes of the channel.
+	AllNotesOffCC = 123
+)
+
+// NotesPerBank is the number of tracks of a MIDI bank, the track of a note
+// is its number plus NotesPerBank times the bank minus one.
+const NotesPerBank = 128
+
+// Controller plays the board sending MIDI messages to a writer, like a
+// USB-MIDI interface opened with Open.
+type Controller struct {
+	mu   sync.Mutex
+	w    io.Writer
+	bank int
+	// gains are the gains of the tracks, played at their velocity.
+	gains map[int]tsunami.Gain
+}
+
+var _ tsunami.Player = (*Controller)(nil)
+
+// New returns a controller writing the MIDI messages to w. The writer is
+// closed by Close if it implements io.Closer.
+func New(w io.Writer) *Controller {
+	return &Controller{w: w, gains: make(map[int]tsunami.Gain)}
+}
+
+// Open returns a controller writing to the raw MIDI device with the given
+// name, eg. "/dev/snd/midiC1D0" or "/dev/midi1" on Linux.
+func Open(name string) (*Controller, error) {
+	f, err := os.OpenFile(name, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(f), nil
+}
+
+// Start selects the first MIDI bank, the board keeps the bank selected by
+// earlier sessions.
+func (c *Controller) Start() error {
+	return c.SetMidiBank(1)
+}
+
+// SetMidiBank selects the MIDI bank, from 1 to 32, with a Program Change.
+// The bank is selected as well when playing a track of another bank.
+func (c *Controller) SetMidiBank(bank int) error {
+	if bank < 1 || bank > tsunami.MaxBank {
+		return fmt.Errorf("%w: MIDI bank %d", tsunami.ErrInvalidBank, bank)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.setBank(bank)
+}
+
+func (c *Controller) setBank(bank int) error {
+	if err := c.send(programChange, byte(bank-1)); err != nil {
+		return err
+	}
+
+	c.bank = bank
+	return nil
+}
+
+// TrackPlayPoly starts track number trk on the output out, with a Note-On
+// on the channel of the output, out plus one. The gain of the track is sent
+// as the velocity, see TrackGain, and the lock is ignored.
+func (c *Controller) TrackPlayPoly(trk, out int, lock bool) error {
+	bank, note, err := noteOf(trk)
+	if err != nil {
+		return err
+	}
+
+	if out < 0 || out >= tsunami.MaxOutputs {
+		return fmt.Errorf("invalid output %d", out)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if bank != c.bank {
+		if err := c.setBank(bank); err != nil {
+			return err
+		}
+	}
+
+	return c.send(noteOn|byte(out), note, Velocity(c.gains[trk]))
+}
+
+// TrackStop stops track number trk with a Note-Off, it requires the track
+// to be set to stop on release in the init file of the board. The Note-Off
+// is sent on the first channel, the board ignores the channel.
+func (c *Controller) TrackStop(trk int) error {
+	bank, note, err := noteOf(trk)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if bank != c.bank {
+		if err := c.setBank(bank); err != nil {
+			return err
+		}
+	}
+
+	return c.send(noteOff, note, 0)
+}
+
+// TrackGain sets the gain track number trk is played at the next time,
+// sent as the velocity of its Note-On, the gain of a track playing isn't
+// changed.
+func (c *Controller) TrackGain(trk int, gain tsunami.Gain) error {
+	if _, _, err := noteOf(trk); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gains[trk] = gain
+	return nil
+}
+
+// MasterGain sets the gain of the output, with the channel volume of its
+// channel. The range for gain is -70 to 0, the highest volume.
+func (c *Controller) MasterGain(out int, gain tsunami.Gain) error {
+	if out < 0 || out >= tsunami.MaxOutputs {
+		return fmt.Errorf("invalid output %d", out)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.send(controlChange|byte(out), VolumeCC, Velocity(gain))
+}
+
+// StopAllTracks sends All Notes Off on the channels of every output.
+func (c *Controller) StopAllTracks() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for out := 0; out < tsunami.MaxOutputs; out++ {
+		if err := c.send(controlChange|byte(out), AllNotesOffCC, 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TrackPlaySolo is not supported.
+func (c *Controller) TrackPlaySolo(trk, out int, lock bool) error {
+	return unsupported("TrackPlaySolo")
+}
+
+// TrackLoad is not supported.
+func (c *Controller) TrackLoad(trk, out int, lock bool) error {
+	return unsupported("TrackLoad")
+}
+
+// TrackPause is not supported.
+func (c *Controller) TrackPause(trk int) error {
+	return unsupported("TrackPause")
+}
+
+// TrackResume is not supported.
+func (c *Controller) TrackResume(trk int) error {
+	return unsupported("TrackResume")
+}
+
+// TrackLoop is not supported, the loop flag of the tracks is set in the
+// init file of the board.
+func (c *Controller) TrackLoop(trk int, enable bool) error {
+	return unsupported("TrackLoop")
+}
+
+// TrackFade is not supported.
+func (c *Controller) TrackFade(trk int, gain tsunami.Gain, d time.Duration, stopFlag bool) error {
+	return unsupported("TrackFade")
+}
+
+// ResumeAllInSync is not supported.
+func (c *Controller) ResumeAllInSync() error {
+	return unsupported("ResumeAllInSync")
+}
+
+// SamplerateOffset is not supported.
+func (c *Controller) SamplerateOffset(out, offset int) error {
+	return unsupported("SamplerateOffset")
+}
+
+// SetReporting is not supported, MIDI is one-way.
+func (c *Controller) SetReporting(enable bool) error {
+	return unsupported("SetReporting")
+}
+
+// SetTriggerBank is not supported, see SetMidiBank.
+func (c *Controller) SetTriggerBank(bank int) error {
+	return unsupported("SetTriggerBank")
+}
+
+// IsTrackPlaying always returns false, the board can't report the tracks
+// playing over MIDI.
+func (c *Controller) IsTrackPlaying(trk int) bool {
+	return false
+}
+
+// GetVersion returns an empty string, the version is unknown over MIDI.
+func (c *Controller) GetVersion() string {
+	return ""
+}
+
+// GetNumTracks returns zero, the number of tracks is unknown over MIDI.
+func (c *Controller) GetNumTracks() int {
+	return 0
+}
+
+// Close closes the writer if it implements io.Closer.
+func (c *Controller) Close() error {
+	if closer, ok := c.w.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+func (c *Controller) send(msg ...byte) error {
+	_, err := c.w.Write(msg)
+	return err
+}
+
+// Velocity returns the velocity playing a track at the gain, from 1 at
+// MinGain to 127 at 0 dB, the gains over 0 dB are played at 127.
+func Velocity(gain tsunami.Gain) byte {
+	g := gain.Clamp(tsunami.MinGain, 0)
+	return byte(1 + (g-tsunami.MinGain)*126/-tsunami.MinGain + 0.5)
+}
+
+// noteOf returns the MIDI bank and the note of the track.
+func noteOf(trk int) (bank int, note byte, err error) {
+	if trk <= 0 || trk > tsunami.MaxTracks {
+		return 0, 0, fmt.Errorf("invalid track %d", trk)
+	}
+
+	bank = trk/NotesPerBank + 1
+	if bank > tsunami.MaxBank {
+		return 0, 0, fmt.Errorf("%w: track %d is out of the MIDI banks", tsunami.ErrInvalidBank, trk)
+	}
+
+	return bank, byte(trk % NotesPerBank), nil
+}
+
+func unsupported(method string) error {
+	return fmt.Errorf("%s: %w", method, ErrUnsupported)
+}
@@ -0,0 +1,59 @@
+package midi
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+func TestController(t *testing.T) {
+	var buf bytes.Buffer
+	c := New(&buf)
+
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.TrackGain(19, -70)
+	c.TrackPlayPoly(19, 2, false)
+	c.TrackPlayPoly(130, 0, false)
+	c.TrackStop(130)
+	c.MasterGain(1, 0)
+
+	expected := []byte{
+		0xc0, 0,
+		0x92, 19, 1,
+		0xc0, 1, 0x90, 2, 127,
+		0x80, 2, 0,
+		0xb1, 7, 127,
+	}
+
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("unexpected messages % x", buf.Bytes())
+	}
+}
+
+func TestControllerErrors(t *testing.T) {
+	c := New(&bytes.Buffer{})
+	if err := c.TrackPlayPoly(4096, 0, false); !errors.Is(err, tsunami.ErrInvalidBank) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := c.TrackPlayPoly(1, 8, false); err == nil {
+		t.Error("the output should be invalid")
+	}
+
+	if err := c.TrackLoad(1, 0, false); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestVelocity(t *testing.T) {
+	for gain, v := range map[tsunami.Gain]byte{-80: 1, -70: 1, -35: 64, 0: 127, 10: 127} {
+		if got := Velocity(gain); got != v {
+			t.Errorf("%s: got %d, expected %d", gain, got, v)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package midi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// Mapping maps the notes of a MIDI file to the tracks of the board.
+type Mapping struct {
+	// Tracks maps the notes to the tracks, the notes not mapped are
+	// skipped. If nil, every note plays the track of its number, but note
+	// 0.
+	Tracks map[int]int
+	// Outputs maps the channels, from 0 to 15, to the outputs, the channels
+	// not mapped play on the output of their number if it exists, or on the
+	// first one.
+	Outputs map[int]int
+	// FixedGain plays the tracks at their gain, ignoring the velocity of
+	// the notes, otherwise the gain of the track is set before playing it,
+	// see GainOf.
+	FixedGain bool
+	// StopOnNoteOff stops the tracks on the Note-Offs, otherwise the tracks
+	// play to their end, as the percussive ones usually do.
+	StopOnNoteOff bool
+}
+
+func (m *Mapping) track(note int) (int, bool) {
+	if m.Tracks == nil {
+		return note, note > 0
+	}
+
+	trk, ok := m.Tracks[note]
+	return trk, ok
+}
+
+func (m *Mapping) output(channel int) int {
+	if out, ok := m.Outputs[channel]; ok {
+		return out
+	}
+
+	if channel < tsunami.MaxOutputs {
+		return channel
+	}
+
+	return 0
+}
+
+// GainOf returns the gain of a velocity, the inverse of Velocity, from
+// MinGain at 1 to 0 dB at 127.
+func GainOf(velocity int) tsunami.Gain {
+	if velocity < 1 {
+		velocity = 1
+	}
+
+	if velocity > 127 {
+		velocity = 127
+	}
+
+	return tsunami.MinGain - tsunami.MinGain*tsunami.Gain(velocity-1)/126
+}
+
+// Performer plays the notes of a MIDI file on a board, in tempo, triggering
+// the tracks mapped to them.
+type Performer struct {
+	p tsunami.Player
+	f *File
+	m Mapping
+
+	mu      sync.Mutex
+	onError func(error)
+}
+
+// NewPerformer returns a performer of the file on the board.
+func NewPerformer(p tsunami.Player, f *File, m Mapping) *Performer {
+	return &Performer{p: p, f: f, m: m}
+}
+
+// OnError registers a function to be called with the errors of the notes
+// played, the performance goes on.
+func (p *Performer) OnError(fn func(error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.onError = fn
+}
+
+// Play plays the file from its start, blocking until its end or until the
+// context is done. The notes are scheduled from the start of the
+// performance, so the delays of the board don't accumulate, the notes late
+// are played at once.
+func (p *Performer) Play(ctx context.Context) error {
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	for _, e := range p.f.Events {
+		if d := time.Until(start.Add(e.Time)); d > 0 {
+			timer.Reset(d)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := p.play(e); err != nil {
+			p.mu.Lock()
+			fn := p.onError
+			p.mu.Unlock()
+
+			if fn != nil {
+				fn(fmt.Errorf("note %d at %s: %w", e.Note, e.Time, err))
+			}
+		}
+	}
+
+	if d := time.Until(start.Add(p.f.Duration)); d > 0 {
+		timer.Reset(d)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return nil
+}
+
+func (p *Performer) play(e Event) error {
+	trk, ok := p.m.track(e.Note)
+	if !ok {
+		return nil
+	}
+
+	if e.Off {
+		if !p.m.StopOnNoteOff {
+			return nil
+		}
+
+		return p.p.TrackStop(trk)
+	}
+
+	if !p.m.FixedGain {
+		if err := p.p.TrackGain(trk, GainOf(e.Velocity)); err != nil {
+			return err
+		}
+	}
+
+	return p.p.TrackPlayPoly(trk, p.m.output(e.Channel), false)
+}
@@ -0,0 +1,78 @@
+package midi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestPerformer(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+
+	f := &File{
+		Events: []Event{
+			{Channel: 9, Note: 36, Velocity: 127},
+			{Time: 10 * time.Millisecond, Channel: 1, Note: 38, Velocity: 1},
+			{Time: 10 * time.Millisecond, Channel: 9, Note: 36, Off: true},
+			{Time: 20 * time.Millisecond, Channel: 1, Note: 50, Velocity: 1},
+		},
+		Duration: 30 * time.Millisecond,
+	}
+
+	m := Mapping{Tracks: map[int]int{36: 1, 38: 2}, Outputs: map[int]int{9: 3}, StopOnNoteOff: true}
+	start := time.Now()
+	if err := NewPerformer(ts, f, m).Play(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := time.Since(start); d < 30*time.Millisecond {
+		t.Errorf("the file should be played in tempo, took %s", d)
+	}
+
+	msgs, _ := port.Messages()
+	var got []string
+	for _, msg := range msgs {
+		got = append(got, protocol.Describe(msg))
+	}
+
+	expected := []string{
+		protocol.Describe(&protocol.TrackVolume{Track: 1}),
+		protocol.Describe(&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 1, Output: 3}),
+		protocol.Describe(&protocol.TrackVolume{Track: 2, Gain: tsunami.MinGain}),
+		protocol.Describe(&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 2, Output: 1}),
+		protocol.Describe(&protocol.TrackControl{Code: tsunami.TRK_STOP, Track: 1}),
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("unexpected commands %q", got)
+	}
+
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("%d: got %q, expected %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestPerformerCancel(t *testing.T) {
+	f := &File{Events: []Event{{Time: time.Hour, Note: 1, Velocity: 1}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := NewPerformer(New(nil), f, Mapping{}).Play(ctx); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestGainOf(t *testing.T) {
+	for v := 1; v <= 127; v++ {
+		if got := Velocity(GainOf(v)); int(got) != v {
+			t.Errorf("%d: got %d", v, got)
+		}
+	}
+}
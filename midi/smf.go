@@ -0,0 +1,303 @@
+package midi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// ErrInvalidSMF is returned when reading a file that isn't a valid Standard
+// MIDI File.
+var ErrInvalidSMF = errors.New("invalid MIDI file")
+
+// defaultTempo is the tempo of a file until its first tempo change, 120
+// BPM, in microseconds per quarter note.
+const defaultTempo = 500000
+
+// Event is a note of a MIDI file, at its time from the start of the file.
+type Event struct {
+	Time time.Duration
+	// Channel is the MIDI channel, from 0 to 15.
+	Channel int
+	Note    int
+	// Velocity is the velocity of the note, zero for the Note-Offs.
+	Velocity int
+	Off      bool
+}
+
+// File is a Standard MIDI File, reduced to its notes.
+type File struct {
+	// Format is the format of the file, 0 for a single track, 1 for
+	// simultaneous tracks, 2 for independent ones, played as 1.
+	Format int
+	// Events are the notes of all the tracks, by time.
+	Events []Event
+	// Duration is the time of the end of the last track.
+	Duration time.Duration
+}
+
+// ReadFile reads the MIDI file with the given name, see ReadSMF.
+func ReadFile(name string) (*File, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+	return ReadSMF(f)
+}
+
+// smfEvent is an event of a track, at its tick from the start.
+type smfEvent struct {
+	tick  int64
+	event Event
+	// tempo is the new tempo of a tempo change, zero for the notes.
+	tempo int
+}
+
+// ReadSMF reads a Standard MIDI File, its notes with their times following
+// the tempo changes, the rest of the messages are skipped.
+func ReadSMF(r io.Reader) (*File, error) {
+	br := bufio.NewReader(r)
+	id, header, err := readChunk(br)
+	if err != nil {
+		return nil, err
+	}
+
+	if id != "MThd" || len(header) < 6 {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidSMF)
+	}
+
+	format := int(binary.BigEndian.Uint16(header))
+	ntracks := int(binary.BigEndian.Uint16(header[2:]))
+	division := binary.BigEndian.Uint16(header[4:])
+	if division == 0 || division&0x8000 != 0 && division&0xff == 0 {
+		return nil, fmt.Errorf("%w: invalid division %#04x", ErrInvalidSMF, division)
+	}
+
+	var events []smfEvent
+	var end int64
+	for n := 0; n < ntracks; {
+		id, data, err := readChunk(br)
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: %d tracks of %d", ErrInvalidSMF, n, ntracks)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		// unknown chunks are skipped, as the specification requires.
+		if id != "MTrk" {
+			continue
+		}
+
+		trk, last, err := parseTrack(data)
+		if err != nil {
+			return nil, fmt.Errorf("track %d: %w", n, err)
+		}
+
+		events = append(events, trk...)
+		if last > end {
+			end = last
+		}
+
+		n++
+	}
+
+	// the events of the tracks are merged, the earlier tracks first at the
+	// same tick, so the tempo changes of the first one apply to the rest.
+	sort.SliceStable(events, func(i, j int) bool { return events[i].tick < events[j].tick })
+
+	f := &File{Format: format}
+	clock := newTickClock(division)
+	for _, e := range events {
+		t := clock.time(e.tick)
+		if e.tempo != 0 {
+			clock.setTempo(e.tick, e.tempo)
+			continue
+		}
+
+		e.event.Time = t
+		f.Events = append(f.Events, e.event)
+	}
+
+	f.Duration = clock.time(end)
+	return f, nil
+}
+
+func readChunk(r io.Reader) (id string, data []byte, err error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = fmt.Errorf("%w: truncated chunk", ErrInvalidSMF)
+		}
+
+		return "", nil, err
+	}
+
+	// the data is copied as it's read, so a bogus length can't allocate more
+	// than the size of the file.
+	size := int64(binary.BigEndian.Uint32(header[4:]))
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, size); err != nil {
+		return "", nil, fmt.Errorf("%w: truncated chunk %q", ErrInvalidSMF, header[:4])
+	}
+
+	return string(header[:4]), buf.Bytes(), nil
+}
+
+// parseTrack returns the notes and tempo changes of a track chunk, and the
+// tick of its end.
+func parseTrack(data []byte) ([]smfEvent, int64, error) {
+	var events []smfEvent
+	var tick int64
+	var status byte
+	for len(data) > 0 {
+		delta, n := readVarInt(data)
+		if n == 0 {
+			return nil, 0, fmt.Errorf("%w: truncated delta time", ErrInvalidSMF)
+		}
+
+		tick, data = tick+int64(delta), data[n:]
+		if len(data) == 0 {
+			return nil, 0, fmt.Errorf("%w: truncated event", ErrInvalidSMF)
+		}
+
+		if data[0]&0x80 != 0 {
+			status, data = data[0], data[1:]
+		} else if status == 0 {
+			return nil, 0, fmt.Errorf("%w: running status without status", ErrInvalidSMF)
+		}
+
+		switch {
+		case status == 0xff:
+			if len(data) == 0 {
+				return nil, 0, fmt.Errorf("%w: truncated meta event", ErrInvalidSMF)
+			}
+
+			typ := data[0]
+			l, n := readVarInt(data[1:])
+			if n == 0 || len(data) < 1+n+l {
+				return nil, 0, fmt.Errorf("%w: truncated meta event", ErrInvalidSMF)
+			}
+
+			meta := data[1+n : 1+n+l]
+			data = data[1+n+l:]
+			status = 0
+
+			switch {
+			case typ == 0x2f: // end of track
+				return events, tick, nil
+			case typ == 0x51 && l == 3: // set tempo
+				tempo := int(meta[0])<<16 | int(meta[1])<<8 | int(meta[2])
+				if tempo > 0 {
+					events = append(events, smfEvent{tick: tick, tempo: tempo})
+				}
+			}
+		case status == 0xf0 || status == 0xf7:
+			l, n := readVarInt(data)
+			if n == 0 || len(data) < n+l {
+				return nil, 0, fmt.Errorf("%w: truncated system exclusive", ErrInvalidSMF)
+			}
+
+			data = data[n+l:]
+			status = 0
+		default:
+			l := 2
+			if kind := status & 0xf0; kind == 0xc0 || kind == 0xd0 {
+				l = 1
+			}
+
+			if len(data) < l {
+				return nil, 0, fmt.Errorf("%w: truncated event", ErrInvalidSMF)
+			}
+
+			msg := data[:l]
+			data = data[l:]
+
+			kind := status & 0xf0
+			if kind != noteOn && kind != noteOff {
+				continue
+			}
+
+			e := Event{Channel: int(status & 0x0f), Note: int(msg[0])}
+			if kind == noteOn && msg[1] > 0 {
+				e.Velocity = int(msg[1])
+			} else {
+				e.Off = true
+			}
+
+			events = append(events, smfEvent{tick: tick, event: e})
+		}
+	}
+
+	return events, tick, nil
+}
+
+// readVarInt reads a variable-length quantity, returning the bytes read,
+// zero if truncated.
+func readVarInt(b []byte) (v int, n int) {
+	for n < len(b) && n < 4 {
+		c := b[n]
+		v = v<<7 | int(c&0x7f)
+		n++
+		if c&0x80 == 0 {
+			return v, n
+		}
+	}
+
+	return 0, 0
+}
+
+// tickClock converts the ticks of a file to time, following its tempo
+// changes.
+type tickClock struct {
+	// perTick is the duration of a tick at the current tempo, in
+	// nanoseconds.
+	perTick float64
+	// ticksPerBeat is zero for the timecode divisions.
+	ticksPerBeat int
+	// tick and at are the tick and time of the last tempo change.
+	tick int64
+	at   time.Duration
+}
+
+func newTickClock(division uint16) *tickClock {
+	c := &tickClock{}
+	if division&0x8000 != 0 {
+		// SMPTE frames per second, negative, and ticks per frame.
+		fps := float64(-int(int8(division >> 8)))
+		if fps == 29 {
+			// drop frame.
+			fps = 29.97
+		}
+
+		c.perTick = 1e9 / (fps * float64(division&0xff))
+		return c
+	}
+
+	c.ticksPerBeat = int(division)
+	c.perTick = defaultTempo * 1e3 / float64(c.ticksPerBeat)
+	return c
+}
+
+func (c *tickClock) time(tick int64) time.Duration {
+	return c.at + time.Duration(float64(tick-c.tick)*c.perTick)
+}
+
+// setTempo changes the tempo at the tick, ignored by the timecode divisions.
+func (c *tickClock) setTempo(tick int64, tempo int) {
+	if c.ticksPerBeat == 0 {
+		return
+	}
+
+	c.at, c.tick = c.time(tick), tick
+	c.perTick = float64(tempo) * 1e3 / float64(c.ticksPerBeat)
+}
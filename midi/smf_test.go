@@ -0,0 +1,98 @@
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// smf returns a MIDI file of the given division and track chunks.
+func smf(format int, division uint16, tracks ...[]byte) []byte {
+	b := []byte("MThd\x00\x00\x00\x06")
+	b = binary.BigEndian.AppendUint16(b, uint16(format))
+	b = binary.BigEndian.AppendUint16(b, uint16(len(tracks)))
+	b = binary.BigEndian.AppendUint16(b, division)
+	for _, trk := range tracks {
+		b = append(b, "MTrk"...)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(trk)))
+		b = append(b, trk...)
+	}
+
+	return b
+}
+
+func TestReadSMF(t *testing.T) {
+	// 96 ticks per beat, the tempo doubles to 240 BPM at the second beat.
+	tempo := []byte{
+		0x00, 0xff, 0x51, 0x03, 0x07, 0xa1, 0x20,
+		0x60, 0xff, 0x51, 0x03, 0x03, 0xd0, 0x90,
+		0x00, 0xff, 0x2f, 0x00,
+	}
+
+	notes := []byte{
+		0x00, 0x99, 36, 100,
+		0x30, 38, 64, // running status
+		0x00, 0xf0, 0x02, 0x01, 0xf7, // system exclusive
+		0x30, 0x89, 36, 0,
+		0x00, 0xc0, 5, // program change
+		0x81, 0x40, 0x90, 40, 0, // note on with zero velocity, 192 ticks later
+		0x60, 0xff, 0x2f, 0x00,
+	}
+
+	f, err := ReadSMF(bytes.NewReader(smf(1, 96, tempo, notes)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &File{
+		Format: 1,
+		Events: []Event{
+			{Time: 0, Channel: 9, Note: 36, Velocity: 100},
+			{Time: 250 * time.Millisecond, Channel: 9, Note: 38, Velocity: 64},
+			{Time: 500 * time.Millisecond, Channel: 9, Note: 36, Off: true},
+			{Time: time.Second, Channel: 0, Note: 40, Off: true},
+		},
+		Duration: 1250 * time.Millisecond,
+	}
+
+	if !reflect.DeepEqual(f, expected) {
+		t.Errorf("unexpected file %+v", f)
+	}
+}
+
+func TestReadSMFTimecode(t *testing.T) {
+	// 25 fps and 40 ticks per frame, a millisecond per tick.
+	f, err := ReadSMF(bytes.NewReader(smf(0, 0xe728, []byte{0x81, 0x70, 0x90, 60, 1})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(f.Events) != 1 || f.Events[0].Time != 240*time.Millisecond {
+		t.Errorf("unexpected events %+v", f.Events)
+	}
+}
+
+func TestReadSMFInvalid(t *testing.T) {
+	for _, b := range [][]byte{
+		[]byte("RIFF\x00\x00\x00\x00"),
+		smf(0, 0),
+		smf(0, 96, []byte{0x00, 40, 100}),
+		smf(0, 96, []byte{0x00, 0x90, 40}),
+		smf(0, 96)[:12],
+		smf(0, 96, []byte{0x00, 0x90, 40, 100})[:24],
+		[]byte("MThd\x00\x00\x00\x060000000000z\x00\x00\x00"),
+	} {
+		if _, err := ReadSMF(bytes.NewReader(b)); !errors.Is(err, ErrInvalidSMF) {
+			t.Errorf("% x: unexpected error %v", b, err)
+		}
+	}
+
+	b := smf(0, 96)
+	b[11] = 1
+	if _, err := ReadSMF(bytes.NewReader(b)); !errors.Is(err, ErrInvalidSMF) {
+		t.Errorf("missing track: unexpected error %v", err)
+	}
+}
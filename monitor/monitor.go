@@ -0,0 +1,265 @@
+// Package monitor follows the state of a board streamed by the API servers,
+// httpapi and grpcapi: the tracks starting and stopping, the voices in use
+// and the health of the connection. A Monitor keeps that state and
+// broadcasts its changes to every Watcher, a client of the servers.
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// HealthInterval is the interval between the health events sent to every
+// Watcher.
+var HealthInterval = 5 * time.Second
+
+// eventBuffer is the number of events queued for a Watcher, the events to a
+// client not keeping up are dropped.
+const eventBuffer = 64
+
+// ErrClosed is returned by Watcher.Next once the Monitor is closed or its
+// watchers disconnected.
+var ErrClosed = errors.New("monitor closed")
+
+// EventType is the type of an Event.
+type EventType string
+
+const (
+	// TrackEvent is sent when a track starts or stops.
+	TrackEvent EventType = "track"
+	// VoicesEvent is sent when the voice table changes, with the tracks
+	// playing by voice.
+	VoicesEvent EventType = "voices"
+	// HealthEvent is sent periodically, and on connection, with the health
+	// of the connection to the board.
+	HealthEvent EventType = "health"
+	// CueEvent is sent when a cue is fired, see Monitor.Cue.
+	CueEvent EventType = "cue"
+)
+
+// Event is an event of the board. Only the fields of its type are set.
+type Event struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+	// Track, Voice and Playing are the report of a TrackEvent.
+	Track   int  `json:"track,omitempty"`
+	Voice   int  `json:"voice,omitempty"`
+	Playing bool `json:"playing,omitempty"`
+	// Voices are the voices in use of a VoicesEvent, sorted by voice.
+	Voices []VoiceStatus `json:"voices,omitempty"`
+	// Health is the health of a HealthEvent.
+	Health *Health `json:"health,omitempty"`
+	// Cue is the number of the cue of a CueEvent.
+	Cue string `json:"cue,omitempty"`
+}
+
+// VoiceStatus is a voice playing a track.
+type VoiceStatus struct {
+	Voice int `json:"voice"`
+	Track int `json:"track"`
+}
+
+// Health is the health of the connection to the board, as seen by the
+// servers.
+type Health struct {
+	// OK is false when the last command sent to the board failed.
+	OK        bool   `json:"ok"`
+	LastError string `json:"last_error,omitempty"`
+	// LastMessage is the time of the last message received from the board,
+	// zero if none.
+	LastMessage time.Time `json:"last_message"`
+	// Resyncs and Malformed are the decoding errors of the frames received,
+	// for the boards reporting them, see Tsunami.Stats.
+	Resyncs   int `json:"resyncs"`
+	Malformed int `json:"malformed"`
+}
+
+// Monitor keeps the state of a board and the watchers of its events.
+type Monitor struct {
+	p      tsunami.Player
+	cancel func()
+
+	mu          sync.Mutex
+	watchers    map[*Watcher]bool
+	voices      map[int]int
+	lastError   error
+	lastMessage time.Time
+}
+
+// New returns the Monitor of the player. The tracks are only followed for
+// players implementing tsunami.Board, see tsunami.Tsunami.Subscribe.
+func New(p tsunami.Player) *Monitor {
+	m := &Monitor{p: p, cancel: func() {}, watchers: make(map[*Watcher]bool), voices: make(map[int]int)}
+	if b, ok := p.(tsunami.Board); ok {
+		m.cancel = b.Subscribe(m.handle)
+	}
+
+	return m
+}
+
+// Close unsubscribes the Monitor from the board and disconnects the
+// watchers.
+func (m *Monitor) Close() {
+	m.cancel()
+	m.Disconnect()
+}
+
+// Disconnect ends every Watcher, their Next returns ErrClosed.
+func (m *Monitor) Disconnect() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for w := range m.watchers {
+		close(w.done)
+		delete(m.watchers, w)
+	}
+}
+
+// Result records the result of a command sent to the board, reported by the
+// health events.
+func (m *Monitor) Result(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastError = err
+}
+
+// Cue broadcasts the CueEvent of the cue with the given number.
+func (m *Monitor) Cue(number string) {
+	m.broadcast(Event{Type: CueEvent, Time: time.Now(), Cue: number})
+}
+
+// Watch returns a new Watcher, starting with the health of the connection
+// and the state of the voices.
+func (m *Monitor) Watch() *Watcher {
+	w := &Watcher{
+		m:      m,
+		events: make(chan Event, eventBuffer),
+		done:   make(chan struct{}),
+		ticker: time.NewTicker(HealthInterval),
+	}
+
+	now := time.Now()
+	w.events <- m.health(now)
+
+	m.mu.Lock()
+	w.events <- m.voicesEvent(now)
+	m.watchers[w] = true
+	m.mu.Unlock()
+
+	return w
+}
+
+func (m *Monitor) handle(msg protocol.Message) {
+	now := time.Now()
+
+	m.mu.Lock()
+	m.lastMessage = now
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+
+	voice, trk := int(r.Voice), int(r.Track)
+	changed := true
+	switch {
+	case r.Playing:
+		changed = m.voices[voice] != trk
+		m.voices[voice] = trk
+	case m.voices[voice] == trk:
+		delete(m.voices, voice)
+	default:
+		changed = false
+	}
+
+	events := []Event{{Type: TrackEvent, Time: now, Track: trk, Voice: voice, Playing: r.Playing}}
+	if changed {
+		events = append(events, m.voicesEvent(now))
+	}
+	m.mu.Unlock()
+
+	for _, e := range events {
+		m.broadcast(e)
+	}
+}
+
+// voicesEvent returns the event of the voices in use, it must be called with
+// the lock held.
+func (m *Monitor) voicesEvent(now time.Time) Event {
+	e := Event{Type: VoicesEvent, Time: now, Voices: []VoiceStatus{}}
+	for voice, trk := range m.voices {
+		e.Voices = append(e.Voices, VoiceStatus{Voice: voice, Track: trk})
+	}
+
+	sort.Slice(e.Voices, func(i, j int) bool { return e.Voices[i].Voice < e.Voices[j].Voice })
+	return e
+}
+
+func (m *Monitor) health(now time.Time) Event {
+	m.mu.Lock()
+	health := &Health{OK: m.lastError == nil, LastMessage: m.lastMessage}
+	if m.lastError != nil {
+		health.LastError = m.lastError.Error()
+	}
+	m.mu.Unlock()
+
+	if s, ok := m.p.(interface{ Stats() protocol.Stats }); ok {
+		stats := s.Stats()
+		health.Resyncs, health.Malformed = stats.Resyncs, stats.Malformed
+	}
+
+	return Event{Type: HealthEvent, Time: now, Health: health}
+}
+
+func (m *Monitor) broadcast(e Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for w := range m.watchers {
+		select {
+		case w.events <- e:
+		default:
+		}
+	}
+}
+
+// Watcher receives the events of a Monitor, and the health of the
+// connection every HealthInterval.
+type Watcher struct {
+	m      *Monitor
+	events chan Event
+	done   chan struct{}
+	ticker *time.Ticker
+}
+
+// Next waits for the next event, until the context is done or the Monitor
+// disconnects the watcher, returning ErrClosed.
+func (w *Watcher) Next(ctx context.Context) (Event, error) {
+	select {
+	case e := <-w.events:
+		return e, nil
+	case now := <-w.ticker.C:
+		return w.m.health(now), nil
+	case <-w.done:
+		return Event{}, ErrClosed
+	case <-ctx.Done():
+		return Event{}, ctx.Err()
+	}
+}
+
+// Stop removes the watcher from the Monitor.
+func (w *Watcher) Stop() {
+	w.ticker.Stop()
+
+	w.m.mu.Lock()
+	defer w.m.mu.Unlock()
+
+	delete(w.m.watchers, w)
+}
@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestMonitor(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	m := New(ts)
+	defer m.Close()
+
+	port.Push(&protocol.TrackReport{Track: 3, Voice: 1, Playing: true})
+	ts.Update()
+
+	w := m.Watch()
+	defer w.Stop()
+
+	next := func() Event {
+		e, err := w.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return e
+	}
+
+	if e := next(); e.Type != HealthEvent || !e.Health.OK || e.Health.LastMessage.IsZero() {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	if e := next(); e.Type != VoicesEvent || !reflect.DeepEqual(e.Voices, []VoiceStatus{{Voice: 1, Track: 3}}) {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	// a second report of the same track doesn't change the voices.
+	port.Push(&protocol.TrackReport{Track: 3, Voice: 1, Playing: true})
+	port.Push(&protocol.TrackReport{Track: 3, Voice: 1, Playing: false})
+	ts.Update()
+
+	if e := next(); e.Type != TrackEvent || e.Track != 3 || !e.Playing {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	if e := next(); e.Type != TrackEvent || e.Track != 3 || e.Playing {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	if e := next(); e.Type != VoicesEvent || len(e.Voices) != 0 {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	m.Result(errors.New("write failed"))
+	m.Cue("12")
+	if e := next(); e.Type != CueEvent || e.Cue != "12" {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	if e := m.health(time.Now()); e.Health.OK || e.Health.LastError != "write failed" {
+		t.Errorf("unexpected health %+v", e.Health)
+	}
+
+	m.Disconnect()
+	if _, err := w.Next(context.Background()); err != ErrClosed {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestWatcherContext(t *testing.T) {
+	m := New(tsunami.NewTsunamiTransport(transport.NewLoopback(nil)))
+	w := m.Watch()
+	defer w.Stop()
+
+	// the health and the voices.
+	for i := 0; i < 2; i++ {
+		if _, err := w.Next(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := w.Next(ctx); err != context.Canceled {
+		t.Errorf("unexpected error %v", err)
+	}
+}
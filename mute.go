@@ -0,0 +1,118 @@
+package tsunami
+
+import "fmt"
+
+// MasterGainOf returns the gain of the output, as last set with MasterGain,
+// 0 by default.
+func (t *Tsunami) MasterGainOf(out int) Gain {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if out < 0 || out >= MaxOutputs {
+		return 0
+	}
+
+	return t.masterGains[out]
+}
+
+// TrackGainOf returns the gain of the track, as last set with TrackGain or
+// TrackFade, 0 by default.
+func (t *Tsunami) TrackGainOf(trk int) Gain {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.trackGains[trk]
+}
+
+// Mute drops the gain of the output to the minimum, remembering its current
+// gain to be restored by Unmute. Muting an output already muted does
+// nothing.
+func (t *Tsunami) Mute(out int) error {
+	if out < 0 || out >= MaxOutputs {
+		return fmt.Errorf("invalid output %d", out)
+	}
+
+	t.mu.Lock()
+	if t.muted == nil {
+		t.muted = make(map[int]Gain)
+	}
+
+	if _, ok := t.muted[out]; ok {
+		t.mu.Unlock()
+		return nil
+	}
+
+	t.muted[out] = t.masterGains[out]
+	t.mu.Unlock()
+
+	return t.MasterGain(out, MinGain)
+}
+
+// Unmute restores the gain the output had when it was muted.
+func (t *Tsunami) Unmute(out int) error {
+	t.mu.Lock()
+	gain, ok := t.muted[out]
+	delete(t.muted, out)
+	t.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return t.MasterGain(out, gain)
+}
+
+// MuteAll mutes every output, and every track whose gain was set, so the
+// board is silent even for the tracks started later on. UnmuteAll restores
+// all the gains.
+func (t *Tsunami) MuteAll() error {
+	for out := 0; out < MaxOutputs; out++ {
+		if err := t.Mute(out); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	if t.mutedTracks == nil {
+		t.mutedTracks = make(map[int]Gain)
+	}
+
+	var tracks []int
+	for trk, gain := range t.trackGains {
+		if _, ok := t.mutedTracks[trk]; !ok {
+			t.mutedTracks[trk] = gain
+			tracks = append(tracks, trk)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, trk := range tracks {
+		if err := t.TrackGain(trk, MinGain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnmuteAll restores the gains of every output and track muted.
+func (t *Tsunami) UnmuteAll() error {
+	t.mu.Lock()
+	tracks := t.mutedTracks
+	t.mutedTracks = nil
+	t.mu.Unlock()
+
+	for _, trk := range sortedKeys(tracks) {
+		if err := t.TrackGain(trk, tracks[trk]); err != nil {
+			return err
+		}
+	}
+
+	for out := 0; out < MaxOutputs; out++ {
+		if err := t.Unmute(out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
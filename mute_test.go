@@ -0,0 +1,47 @@
+package tsunami
+
+import (
+	"testing"
+)
+
+func TestMute(t *testing.T) {
+	ts := newTestTsunami()
+	ts.MasterGain(0, -6)
+	ts.TrackGain(19, -3)
+
+	if err := ts.Mute(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.Mute(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if g := ts.MasterGainOf(0); g != MinGain {
+		t.Errorf("output should be muted, got %s", g)
+	}
+
+	if err := ts.Unmute(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if g := ts.MasterGainOf(0); g != -6 {
+		t.Errorf("gain should be restored, got %s", g)
+	}
+
+	if err := ts.MuteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if ts.MasterGainOf(0) != MinGain || ts.MasterGainOf(3) != MinGain || ts.TrackGainOf(19) != MinGain {
+		t.Errorf("everything should be muted")
+	}
+
+	if err := ts.UnmuteAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if ts.MasterGainOf(0) != -6 || ts.MasterGainOf(3) != 0 || ts.TrackGainOf(19) != -3 {
+		t.Errorf("gains should be restored")
+	}
+}
@@ -0,0 +1,36 @@
+package tsunami
+
+// SetNormalization sets a gain offset per track, by track number, like the
+// ones computed from the loudness of the tracks by the card package, so the
+// tracks play at the same loudness. The offsets are added to the gains sent,
+// like the trims of the groups, and the resulting gains are sent to the
+// board right away, to be used the next time the tracks start.
+func (t *Tsunami) SetNormalization(gains map[int]Gain) error {
+	t.mu.Lock()
+	tracks := make([]int, 0, len(gains)+len(t.normGains))
+	for trk := range t.normGains {
+		if _, ok := gains[trk]; !ok {
+			tracks = append(tracks, trk)
+		}
+	}
+
+	t.normGains = make(map[int]Gain, len(gains))
+	for trk, g := range gains {
+		t.normGains[trk] = g
+		tracks = append(tracks, trk)
+	}
+
+	t.updateTrims()
+	t.mu.Unlock()
+
+	return t.resendGains(tracks)
+}
+
+// NormalizationOf returns the gain offset of the track set with
+// SetNormalization.
+func (t *Tsunami) NormalizationOf(trk int) Gain {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.normGains[trk]
+}
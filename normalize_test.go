@@ -0,0 +1,45 @@
+package tsunami
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestSetNormalization(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+
+	if err := ts.SetNormalization(map[int]Gain{3: -6}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.TrackGain(3, -2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.SetNormalization(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []protocol.Message{
+		&protocol.TrackVolume{Track: 3, Gain: -6},
+		&protocol.TrackVolume{Track: 3, Gain: -8},
+		&protocol.TrackVolume{Track: 3, Gain: -2},
+	}
+
+	if !reflect.DeepEqual(msgs, expected) {
+		t.Errorf("unexpected messages %v", msgs)
+	}
+
+	if g := ts.TrackGainOf(3); g != -2 {
+		t.Errorf("unexpected gain %s", g)
+	}
+}
@@ -0,0 +1,116 @@
+package tsunami
+
+import (
+	"sync"
+	"time"
+)
+
+// hookInterval is the interval between the checks of the progress hooks.
+const hookInterval = 10 * time.Millisecond
+
+// trackHook is a function called based on the position of a track.
+type trackHook struct {
+	trk    int
+	before time.Duration
+	every  time.Duration
+	fn     func(pos, remaining time.Duration)
+
+	seq  int
+	last time.Duration
+}
+
+// NotifyBefore registers fn to be called when the track is the given time
+// away from its end, eg. to pre-arm the next cue or start a crossfade 5
+// seconds before a track ends. It's called once every time the track is
+// played. It relies on the estimation of Remaining, so the durations of the
+// tracks must be known, see SetDurations. The returned function unregisters
+// the hook.
+func (t *Tsunami) NotifyBefore(trk int, before time.Duration, fn func()) (cancel func()) {
+	return t.addHook(&trackHook{trk: trk, before: before, fn: func(_, _ time.Duration) {
+		fn()
+	}})
+}
+
+// NotifyProgress registers fn to be called with the position and remaining
+// time of the track every given interval while it plays, eg. to update a
+// progress bar. The remaining time is zero if the duration is unknown. The
+// returned function unregisters the hook.
+func (t *Tsunami) NotifyProgress(trk int, every time.Duration, fn func(pos, remaining time.Duration)) (cancel func()) {
+	return t.addHook(&trackHook{trk: trk, every: every, fn: fn})
+}
+
+func (t *Tsunami) addHook(h *trackHook) func() {
+	t.mu.Lock()
+	t.hooks = append(t.hooks, h)
+	if !t.hooksActive {
+		t.hooksActive = true
+		go t.runHooks()
+	}
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			for i, hook := range t.hooks {
+				if hook == h {
+					t.hooks = append(t.hooks[:i:i], t.hooks[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// runHooks checks the hooks until there are none left.
+func (t *Tsunami) runHooks() {
+	ticker := time.NewTicker(hookInterval)
+	defer ticker.Stop()
+
+	type call struct {
+		fn             func(pos, remaining time.Duration)
+		pos, remaining time.Duration
+	}
+
+	for range ticker.C {
+		var calls []call
+
+		t.mu.Lock()
+		if len(t.hooks) == 0 {
+			t.hooksActive = false
+			t.mu.Unlock()
+			return
+		}
+
+		for _, h := range t.hooks {
+			p, ok := t.playbacks[h.trk]
+			if !ok {
+				continue
+			}
+
+			pos, _ := t.position(h.trk)
+			rem, known := t.remaining(h.trk, pos)
+			if h.seq != p.seq {
+				h.seq, h.last = p.seq, -1
+			}
+
+			switch {
+			case h.every > 0:
+				if h.last < 0 || pos-h.last >= h.every || pos < h.last {
+					h.last = pos
+					calls = append(calls, call{h.fn, pos, rem})
+				}
+			case known && rem <= h.before && h.last < 0:
+				h.last = pos
+				calls = append(calls, call{h.fn, pos, rem})
+			}
+		}
+		t.mu.Unlock()
+
+		for _, c := range calls {
+			c.fn(c.pos, c.remaining)
+		}
+	}
+}
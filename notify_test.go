@@ -0,0 +1,45 @@
+package tsunami
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNotifyBefore(t *testing.T) {
+	ts := newTestTsunami()
+	ts.SetDurations(Durations{19: 60 * time.Millisecond})
+
+	var fired, progress int32
+	cancel := ts.NotifyBefore(19, 30*time.Millisecond, func() {
+		atomic.AddInt32(&fired, 1)
+	})
+
+	defer cancel()
+
+	cancelProgress := ts.NotifyProgress(19, 10*time.Millisecond, func(pos, rem time.Duration) {
+		atomic.AddInt32(&progress, 1)
+	})
+
+	ts.TrackPlayPoly(19, 0, false)
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt32(&fired) != 0 {
+		t.Errorf("hook fired too early")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if atomic.LoadInt32(&fired) != 1 {
+		t.Errorf("hook should fire once, fired %d", fired)
+	}
+
+	cancelProgress()
+	if atomic.LoadInt32(&progress) < 2 {
+		t.Errorf("progress should be notified while playing, got %d", progress)
+	}
+
+	ts.TrackPlayPoly(19, 0, false)
+	time.Sleep(50 * time.Millisecond)
+	if atomic.LoadInt32(&fired) != 2 {
+		t.Errorf("hook should fire again when replayed, fired %d", fired)
+	}
+}
@@ -0,0 +1,96 @@
+//go:build !tinygo && !js
+
+package tsunami
+
+import (
+	"fmt"
+
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// NewTsunami returns a new Tsuanmi connection to the given port.
+func NewTsunami(portName string, opts ...Option) (*Tsunami, error) {
+	port, err := transport.OpenSerial(portName)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTsunamiTransport(port, opts...), nil
+}
+
+// NewTsunamiPort is like NewTsunami but opens the port with the
+// go.bug.st/serial backend, see transport.Port. The available ports can be
+// listed with transport.ListPorts.
+func NewTsunamiPort(portName string, opts ...Option) (*Tsunami, error) {
+	port, err := transport.OpenPort(portName)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTsunamiTransport(port, opts...), nil
+}
+
+// NewTsunamiTCP returns a new Tsunami connection to a board attached to a
+// remote machine, through a raw TCP-to-serial bridge at the given address,
+// eg. "host:4001". Use NewTsunamiRFC2217 for bridges speaking RFC 2217.
+func NewTsunamiTCP(addr string, opts ...Option) (*Tsunami, error) {
+	tr, err := transport.DialTCP(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTsunamiTransport(tr, opts...), nil
+}
+
+// NewTsunamiRFC2217 returns a new Tsunami connection to a board attached to
+// a remote serial port exposed with RFC 2217, like ser2net in telnet mode.
+func NewTsunamiRFC2217(addr string, opts ...Option) (*Tsunami, error) {
+	tr, err := transport.DialRFC2217(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTsunamiTransport(tr, opts...), nil
+}
+
+// Open connects to the board described by the config, starts it, applies
+// its profile and registers it with the config name.
+func (m *Manager) Open(cfg DeviceConfig, opts ...Option) (*Tsunami, error) {
+	port := cfg.Port
+	if cfg.Serial != "" {
+		var err error
+		if port, err = transport.FindPort(cfg.Serial); err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.Name, err)
+		}
+	}
+
+	t, err := NewTsunami(port, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cfg.Name, err)
+	}
+
+	if err := t.Start(); err != nil {
+		t.Close()
+		return nil, fmt.Errorf("%s: %w", cfg.Name, err)
+	}
+
+	if err := cfg.Profile.Apply(t); err != nil {
+		t.Close()
+		return nil, fmt.Errorf("%s: %w", cfg.Name, err)
+	}
+
+	m.Add(cfg.Name, t)
+	return t, nil
+}
+
+// OpenAll opens every board of the given configs, see Open. If any board
+// fails, the boards already opened are kept registered.
+func (m *Manager) OpenAll(cfgs []DeviceConfig, opts ...Option) error {
+	for _, cfg := range cfgs {
+		if _, err := m.Open(cfg, opts...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
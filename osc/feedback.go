@@ -0,0 +1,134 @@
+package osc
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// DefaultPrefix is the prefix of the addresses of the feedback messages.
+const DefaultPrefix = "/tsunami"
+
+// Feedback is a board sending the changes of its state as OSC messages to a
+// destination, so the tablets light the buttons of the tracks playing and move
+// the faders of the gains. The messages, with the default prefix, are:
+//
+//	/tsunami/track/{n} 1.0         when the track starts, 0.0 when it ends
+//	/tsunami/track/{n}/gain -6.0   when the gain of the track is set or faded
+//	/tsunami/output/{n}/gain -6.0  when the gain of the output is set
+//
+// The starts and ends of the tracks are taken from the track reports, see
+// tsunami.Tsunami.Subscribe. The gains are the ones of the commands sent
+// through the Feedback, the target gain for the fades.
+type Feedback struct {
+	tsunami.Board
+	prefix string
+	cancel func()
+
+	mu   sync.Mutex
+	conn net.Conn
+	err  error
+}
+
+var _ tsunami.Board = (*Feedback)(nil)
+
+// NewFeedback returns a Feedback of the board sending the messages to the
+// UDP address, like "192.168.1.20:9000", with the prefix, DefaultPrefix if
+// empty.
+func NewFeedback(b tsunami.Board, addr, prefix string) (*Feedback, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	f := &Feedback{Board: b, prefix: prefix, conn: conn}
+	f.cancel = b.Subscribe(f.handle)
+	return f, nil
+}
+
+func (f *Feedback) handle(msg protocol.Message) {
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok {
+		return
+	}
+
+	var v float32
+	if r.Playing {
+		v = 1
+	}
+
+	f.send(fmt.Sprintf("%s/track/%d", f.prefix, r.Track), v)
+}
+
+// TrackGain implements tsunami.Player, sending the gain of the track.
+func (f *Feedback) TrackGain(trk int, gain tsunami.Gain) error {
+	if err := f.Board.TrackGain(trk, gain); err != nil {
+		return err
+	}
+
+	f.send(fmt.Sprintf("%s/track/%d/gain", f.prefix, trk), float32(gain))
+	return nil
+}
+
+// TrackFade implements tsunami.Player, sending the target gain of the track.
+func (f *Feedback) TrackFade(trk int, gain tsunami.Gain, d time.Duration, stopFlag bool) error {
+	if err := f.Board.TrackFade(trk, gain, d, stopFlag); err != nil {
+		return err
+	}
+
+	f.send(fmt.Sprintf("%s/track/%d/gain", f.prefix, trk), float32(gain))
+	return nil
+}
+
+// MasterGain implements tsunami.Player, sending the gain of the output.
+func (f *Feedback) MasterGain(out int, gain tsunami.Gain) error {
+	if err := f.Board.MasterGain(out, gain); err != nil {
+		return err
+	}
+
+	f.send(fmt.Sprintf("%s/output/%d/gain", f.prefix, out), float32(gain))
+	return nil
+}
+
+// Err returns the last error sending a message, the errors sending the
+// feedback don't fail the commands.
+func (f *Feedback) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.err
+}
+
+// Close closes the connection of the feedback, and the board.
+func (f *Feedback) Close() error {
+	f.cancel()
+
+	f.mu.Lock()
+	f.conn.Close()
+	f.mu.Unlock()
+
+	return f.Board.Close()
+}
+
+func (f *Feedback) send(addr string, v float32) {
+	data, err := Message{Address: addr, Args: []interface{}{v}}.MarshalBinary()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		_, err = f.conn.Write(data)
+	}
+
+	if err != nil {
+		f.err = err
+	}
+}
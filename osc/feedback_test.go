@@ -0,0 +1,63 @@
+package osc
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestFeedback(t *testing.T) {
+	tablet, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer tablet.Close()
+
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	f, err := NewFeedback(ts, tablet.LocalAddr().String(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	port.Push(&protocol.TrackReport{Track: 19, Playing: true})
+	ts.Update()
+	f.TrackGain(19, -6)
+	f.TrackFade(19, -70, time.Second, true)
+	f.MasterGain(2, 3)
+	port.Push(&protocol.TrackReport{Track: 19, Playing: false})
+	ts.Update()
+
+	expected := []Message{
+		{Address: "/tsunami/track/19", Args: []interface{}{float32(1)}},
+		{Address: "/tsunami/track/19/gain", Args: []interface{}{float32(-6)}},
+		{Address: "/tsunami/track/19/gain", Args: []interface{}{float32(-70)}},
+		{Address: "/tsunami/output/2/gain", Args: []interface{}{float32(3)}},
+		{Address: "/tsunami/track/19", Args: []interface{}{float32(0)}},
+	}
+
+	buf := make([]byte, 512)
+	for _, m := range expected {
+		tablet.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := tablet.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if data, _ := m.MarshalBinary(); !bytes.Equal(buf[:n], data) {
+			t.Errorf("unexpected packet %q, expected %s", buf[:n], m.Address)
+		}
+	}
+
+	if err := f.Err(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package osc speaks Open Sound Control to the tablets and controllers, like
+// TouchOSC or Lemur, reflecting the state of the board on them.
+package osc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Message is an OSC message. The arguments are int32, float32, string or
+// bool values.
+type Message struct {
+	Address string
+	Args    []interface{}
+}
+
+// MarshalBinary encodes the message as an OSC packet.
+func (m Message) MarshalBinary() ([]byte, error) {
+	return m.AppendBinary(nil)
+}
+
+// AppendBinary appends the OSC packet of the message to dst.
+func (m Message) AppendBinary(dst []byte) ([]byte, error) {
+	tags := []byte{','}
+	for _, arg := range m.Args {
+		switch v := arg.(type) {
+		case int32:
+			tags = append(tags, 'i')
+		case float32:
+			tags = append(tags, 'f')
+		case string:
+			tags = append(tags, 's')
+		case bool:
+			if v {
+				tags = append(tags, 'T')
+			} else {
+				tags = append(tags, 'F')
+			}
+		default:
+			return nil, fmt.Errorf("unsupported OSC argument %T", arg)
+		}
+	}
+
+	dst = appendString(dst, m.Address)
+	dst = appendString(dst, string(tags))
+	for _, arg := range m.Args {
+		switch v := arg.(type) {
+		case int32:
+			dst = binary.BigEndian.AppendUint32(dst, uint32(v))
+		case float32:
+			dst = binary.BigEndian.AppendUint32(dst, math.Float32bits(v))
+		case string:
+			dst = appendString(dst, v)
+		}
+	}
+
+	return dst, nil
+}
+
+// appendString appends the string null terminated, padded to 4 bytes.
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, s...)
+	for n := 4 - len(s)%4; n > 0; n-- {
+		dst = append(dst, 0)
+	}
+
+	return dst
+}
@@ -0,0 +1,33 @@
+package osc
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMessageMarshalBinary(t *testing.T) {
+	data, err := Message{Address: "/oscillator/4/frequency", Args: []interface{}{float32(440)}}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the example of the OSC 1.0 specification.
+	expected := []byte("/oscillator/4/frequency\x00,f\x00\x00\x43\xdc\x00\x00")
+	if !bytes.Equal(data, expected) {
+		t.Errorf("unexpected packet % x", data)
+	}
+
+	data, err = Message{Address: "/foo", Args: []interface{}{int32(1000), int32(-1), "hello", true}}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = []byte("/foo\x00\x00\x00\x00,iisT\x00\x00\x00\x00\x00\x03\xe8\xff\xff\xff\xffhello\x00\x00\x00")
+	if !bytes.Equal(data, expected) {
+		t.Errorf("unexpected packet % x", data)
+	}
+
+	if _, err := (Message{Address: "/foo", Args: []interface{}{1}}).MarshalBinary(); err == nil {
+		t.Error("unsupported argument should fail")
+	}
+}
@@ -0,0 +1,22 @@
+module github.com/mcuadros/go-tsunami/oteltrace
+
+go 1.19
+
+require (
+	github.com/mcuadros/go-tsunami v0.0.0
+	go.opentelemetry.io/otel v1.17.0
+	go.opentelemetry.io/otel/sdk v1.17.0
+	go.opentelemetry.io/otel/trace v1.17.0
+)
+
+require (
+	github.com/creack/goselect v0.1.2 // indirect
+	github.com/go-logr/logr v1.2.4 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/tarm/serial v0.0.0-20180830185346-98f6abe2eb07 // indirect
+	go.bug.st/serial v1.4.1 // indirect
+	go.opentelemetry.io/otel/metric v1.17.0 // indirect
+	golang.org/x/sys v0.11.0 // indirect
+)
+
+replace github.com/mcuadros/go-tsunami => ../
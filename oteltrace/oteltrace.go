@@ -0,0 +1,76 @@
+// Package oteltrace traces the commands sent to a board and the cues fired
+// with OpenTelemetry, adapting a trace.Tracer to a tsunami.Tracer:
+//
+//	tr := oteltrace.New(otel.Tracer("show"))
+//	ts, err := tsunami.NewTsunami("/dev/ttyUSB0", tsunami.WithTracer(tr))
+//	...
+//	stack.SetTracer(tr)
+//
+// Every cue fired is then traced as a span with the commands of its actions
+// as children, and the write of each command to the port as their child.
+//
+// The package is a module of its own, so the OpenTelemetry modules are only
+// required by the programs using it.
+package oteltrace
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// New returns a tsunami.Tracer starting the spans with tr.
+func New(tr trace.Tracer) tsunami.Tracer {
+	return &tracer{tr: tr}
+}
+
+type tracer struct {
+	tr trace.Tracer
+}
+
+func (t *tracer) Start(ctx context.Context, name string, attrs ...interface{}) (context.Context, tsunami.Span) {
+	ctx, s := t.tr.Start(ctx, name, trace.WithAttributes(attributes(attrs)...))
+	return ctx, span{s}
+}
+
+type span struct {
+	s trace.Span
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.s.RecordError(err)
+		s.s.SetStatus(codes.Error, err.Error())
+	}
+
+	s.s.End()
+}
+
+// attributes converts the key and value pairs to attributes.
+func attributes(attrs []interface{}) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs)/2)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		key := attribute.Key(fmt.Sprint(attrs[i]))
+		switch v := attrs[i+1].(type) {
+		case string:
+			kvs = append(kvs, key.String(v))
+		case int:
+			kvs = append(kvs, key.Int(v))
+		case int64:
+			kvs = append(kvs, key.Int64(v))
+		case float64:
+			kvs = append(kvs, key.Float64(v))
+		case bool:
+			kvs = append(kvs, key.Bool(v))
+		default:
+			kvs = append(kvs, key.String(fmt.Sprint(v)))
+		}
+	}
+
+	return kvs
+}
@@ -0,0 +1,66 @@
+package oteltrace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/show"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestTracer(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tr := New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test"))
+
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(nil), tsunami.WithTracer(tr))
+	s := show.NewCueStack(ts, []show.Cue{{Number: "1", Name: "intro", Actions: []show.Action{{Type: show.StopAll}}}})
+	s.SetTracer(tr)
+
+	if err := s.GoContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("unexpected spans %v", spans)
+	}
+
+	write, command, cue := spans[0], spans[1], spans[2]
+	if cue.Name() != "show.cue" || command.Name() != "tsunami.command" || write.Name() != "tsunami.write" {
+		t.Fatalf("unexpected spans %s, %s and %s", cue.Name(), command.Name(), write.Name())
+	}
+
+	if command.Parent().SpanID() != cue.SpanContext().SpanID() ||
+		write.Parent().SpanID() != command.SpanContext().SpanID() {
+		t.Errorf("the spans should be nested")
+	}
+
+	attrs := cue.Attributes()
+	if len(attrs) != 2 || attrs[0] != attribute.String("cue", "1") || attrs[1] != attribute.String("name", "intro") {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+}
+
+func TestSpanEndError(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tr := New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test"))
+
+	_, span := tr.Start(context.Background(), "tsunami.write", "bytes", 5)
+	span.End(errors.New("broken pipe"))
+
+	s := rec.Ended()[0]
+	if s.Status().Code != codes.Error || s.Status().Description != "broken pipe" {
+		t.Errorf("unexpected status %v", s.Status())
+	}
+
+	if attrs := s.Attributes(); len(attrs) != 1 || attrs[0] != attribute.Int("bytes", 5) {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+}
@@ -0,0 +1,135 @@
+package tsunami
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrStereoOutputs is returned when panning across outputs of a board
+// running the stereo firmware.
+var ErrStereoOutputs = errors.New("board configured for stereo outputs")
+
+// PanGains returns the gains of the left and right outputs for the pan, from
+// -1 (left) to 1 (right), with an equal-power law, so the loudness stays the
+// same across the stereo image: -3 dB on both outputs at the center.
+func PanGains(pan float64) (left, right Gain) {
+	pan = math.Max(-1, math.Min(1, pan))
+	a := (pan + 1) * math.Pi / 4
+	return GainFromLinear(math.Cos(a)), GainFromLinear(math.Sin(a))
+}
+
+// PlayPanned plays the track on a pair of mono outputs, setting the master
+// gain of both outputs to place it at the pan, from -1 (left) to 1 (right).
+// The master gains affect every track playing on the outputs, see Panner to
+// move the pan over time.
+//
+// ErrStereoOutputs is returned if the board is known to run the stereo
+// firmware.
+func (t *Tsunami) PlayPanned(trk, leftOut, rightOut int, pan float64) error {
+	t.mu.Lock()
+	stereo := t.firmwareOK && t.firmware.Variant == "s"
+	t.mu.Unlock()
+
+	if stereo {
+		return ErrStereoOutputs
+	}
+
+	return NewPanner(t, leftOut, rightOut).Play(trk, pan)
+}
+
+// Panner emulates a pan control across a pair of mono outputs, balancing
+// their master gains. The track is played on both outputs, taking two
+// voices.
+type Panner struct {
+	p           Player
+	f           *Fader
+	left, right int
+
+	mu  sync.Mutex
+	pan float64
+}
+
+// NewPanner returns a Panner of the given outputs, centered.
+func NewPanner(p Player, leftOut, rightOut int) *Panner {
+	return &Panner{p: p, f: NewFader(p), left: leftOut, right: rightOut}
+}
+
+// Pan returns the current pan, the target one if moving.
+func (p *Panner) Pan() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.pan
+}
+
+// Play sets the pan and plays the track on both outputs.
+func (p *Panner) Play(trk int, pan float64) error {
+	if err := p.SetPan(pan); err != nil {
+		return err
+	}
+
+	if err := p.p.TrackPlayPoly(trk, p.left, false); err != nil {
+		return err
+	}
+
+	return p.p.TrackPlayPoly(trk, p.right, false)
+}
+
+// SetPan sets the pan immediately, canceling any move in progress.
+func (p *Panner) SetPan(pan float64) error {
+	p.f.CancelOutput(p.left)
+	p.f.CancelOutput(p.right)
+
+	l, r := PanGains(pan)
+	if err := p.p.MasterGain(p.left, l); err != nil {
+		return err
+	}
+
+	if err := p.p.MasterGain(p.right, r); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.pan = math.Max(-1, math.Min(1, pan))
+	p.mu.Unlock()
+	return nil
+}
+
+// PanTo moves the pan from the current one to the given one over the
+// duration, keeping the equal-power law along the way, like an auto-pan.
+func (p *Panner) PanTo(pan float64, d time.Duration) {
+	pan = math.Max(-1, math.Min(1, pan))
+
+	p.mu.Lock()
+	from := p.pan
+	p.pan = pan
+	p.mu.Unlock()
+
+	at := func(x float64) float64 { return from + (pan-from)*x }
+	p.f.start(fadeKey{outputFade, p.left}, p.move(p.left, at, d, func(pan float64) Gain {
+		l, _ := PanGains(pan)
+		return l
+	}))
+
+	p.f.start(fadeKey{outputFade, p.right}, p.move(p.right, at, d, func(pan float64) Gain {
+		_, r := PanGains(pan)
+		return r
+	}))
+}
+
+// Wait blocks until the pan move in progress is done.
+func (p *Panner) Wait() {
+	p.f.Wait()
+}
+
+// move returns the fade of the master gain of the output following the pan.
+func (p *Panner) move(out int, at func(float64) float64, d time.Duration, gain func(float64) Gain) *activeFade {
+	return &activeFade{
+		Fade: Fade{Duration: d},
+		at:   func(x float64) float64 { return float64(gain(at(x))) },
+		end:  math.Round(float64(gain(at(1)))),
+		set:  func(v float64) error { return p.p.MasterGain(out, Gain(v)) },
+	}
+}
@@ -0,0 +1,80 @@
+package tsunami
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestPanGains(t *testing.T) {
+	for _, tc := range []struct {
+		pan         float64
+		left, right int
+	}{
+		{-1, 0, MinGain},
+		{0, -3, -3},
+		{1, MinGain, 0},
+		{2, MinGain, 0},
+	} {
+		l, r := PanGains(tc.pan)
+		if l.Int() != tc.left || r.Int() != tc.right {
+			t.Errorf("pan %v: unexpected gains %s, %s", tc.pan, l, r)
+		}
+	}
+}
+
+func TestTsunamiPlayPanned(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+
+	if err := ts.PlayPanned(5, 2, 3, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []protocol.Message{
+		&protocol.MasterVolume{Output: 2, Gain: 0},
+		&protocol.MasterVolume{Output: 3, Gain: MinGain},
+		&protocol.TrackControl{Code: TRK_PLAY_POLY, Track: 5, Output: 2},
+		&protocol.TrackControl{Code: TRK_PLAY_POLY, Track: 5, Output: 3},
+	}
+
+	if !reflect.DeepEqual(msgs, expected) {
+		t.Errorf("unexpected messages %v", msgs)
+	}
+
+	ts.firmware, ts.firmwareOK = Version{Board: "Tsunami", Major: 1, Minor: 10, Variant: "s"}, true
+	if err := ts.PlayPanned(5, 2, 3, 0); !errors.Is(err, ErrStereoOutputs) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestPannerPanTo(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	p := NewPanner(ts, 0, 1)
+
+	if err := p.SetPan(-1); err != nil {
+		t.Fatal(err)
+	}
+
+	p.PanTo(1, 60*time.Millisecond)
+	p.Wait()
+
+	if p.Pan() != 1 {
+		t.Errorf("unexpected pan %v", p.Pan())
+	}
+
+	if ts.masterGains[0] != MinGain || ts.masterGains[1] != 0 {
+		t.Errorf("unexpected gains %v", ts.masterGains)
+	}
+
+}
@@ -0,0 +1,62 @@
+package tsunami
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// MaxSamplerateOffset is the highest samplerate offset, one octave up, the
+// lowest one, -MaxSamplerateOffset, is one octave down.
+const MaxSamplerateOffset = 32767
+
+// SemitonesToOffset converts a pitch shift in semitones, which may include
+// cents as a fraction, into a samplerate offset. The range is one octave
+// up or down, shifts out of it are clamped.
+func SemitonesToOffset(semitones float64) int {
+	offset := math.Round(semitones / 12 * MaxSamplerateOffset)
+	return int(math.Max(-MaxSamplerateOffset, math.Min(offset, MaxSamplerateOffset)))
+}
+
+// OffsetToSemitones converts a samplerate offset into a pitch shift in
+// semitones.
+func OffsetToSemitones(offset int) float64 {
+	return float64(offset) * 12 / MaxSamplerateOffset
+}
+
+// SetPitch shifts the pitch, and so the speed, of the output by the given
+// semitones, eg. 7 is a fifth up and -0.5 is a quarter tone down. The range is
+// one octave up or down, see SamplerateOffset.
+func (t *Tsunami) SetPitch(out int, semitones float64) error {
+	return t.SamplerateOffset(out, SemitonesToOffset(semitones))
+}
+
+// PitchOf returns the pitch shift of the output in semitones, as last set with
+// SetPitch or SamplerateOffset, 0 by default.
+func (t *Tsunami) PitchOf(out int) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if out < 0 || out >= MaxOutputs {
+		return 0
+	}
+
+	return OffsetToSemitones(t.offsets[out])
+}
+
+// PitchGlide ramps the samplerate offset of the output from its current
+// value to the given one in the given duration, for engine revs or tape stop
+// effects. The ramp is done in software, it returns immediately and replaces
+// any glide in progress of the output.
+func (t *Tsunami) PitchGlide(out, offset int, d time.Duration) error {
+	if out < 0 || out >= MaxOutputs {
+		return fmt.Errorf("invalid output %d", out)
+	}
+
+	t.mu.Lock()
+	from := t.offsets[out]
+	t.mu.Unlock()
+
+	t.fader.Glide(out, from, offset, d, nil)
+	return nil
+}
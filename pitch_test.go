@@ -0,0 +1,81 @@
+package tsunami
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestSemitonesToOffset(t *testing.T) {
+	for _, tc := range []struct {
+		semitones float64
+		offset    int
+	}{
+		{0, 0},
+		{12, MaxSamplerateOffset},
+		{-12, -MaxSamplerateOffset},
+		{24, MaxSamplerateOffset},
+		{6, 16384},
+	} {
+		if offset := SemitonesToOffset(tc.semitones); offset != tc.offset {
+			t.Errorf("%v semitones: got offset %d, expected %d", tc.semitones, offset, tc.offset)
+		}
+	}
+
+	if st := OffsetToSemitones(SemitonesToOffset(-0.37)); math.Abs(st+0.37) > 0.001 {
+		t.Errorf("unexpected round trip %v", st)
+	}
+}
+
+func TestSetPitch(t *testing.T) {
+	ts := newTestTsunami()
+	if err := ts.SetPitch(2, 7); err != nil {
+		t.Fatal(err)
+	}
+
+	if st := ts.PitchOf(2); math.Abs(st-7) > 0.001 {
+		t.Errorf("unexpected pitch %v", st)
+	}
+}
+
+func TestPitchGlide(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	ts.SamplerateOffset(3, 1000)
+
+	if err := ts.PitchGlide(3, -1000, 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	ts.fader.Wait()
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(msgs) < 3 {
+		t.Fatalf("expected several steps, got %v", msgs)
+	}
+
+	prev := 1001
+	for _, m := range msgs {
+		o := m.(*protocol.SamplerateOffset)
+		if o.Output != 3 {
+			t.Errorf("unexpected output %d", o.Output)
+		}
+
+		if int(o.Offset) >= prev {
+			t.Errorf("offset should decrease, %d after %d", o.Offset, prev)
+		}
+
+		prev = int(o.Offset)
+	}
+
+	if prev != -1000 || ts.PitchOf(3) != OffsetToSemitones(-1000) {
+		t.Errorf("glide should end at the target, got %d", prev)
+	}
+}
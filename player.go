@@ -0,0 +1,46 @@
+package tsunami
+
+import (
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// Player is the common interface of the boards supported by the library, so
+// applications can target any of them. It is implemented by Tsunami and by
+// the WAV Trigger driver in the wavtrigger package. Boards without multiple
+// outputs ignore the output arguments.
+type Player interface {
+	Start() error
+	TrackPlaySolo(trk, out int, lock bool) error
+	TrackPlayPoly(trk, out int, lock bool) error
+	TrackLoad(trk, out int, lock bool) error
+	TrackStop(trk int) error
+	TrackPause(trk int) error
+	TrackResume(trk int) error
+	TrackLoop(trk int, enable bool) error
+	TrackGain(trk int, gain Gain) error
+	TrackFade(trk int, gain Gain, d time.Duration, stopFlag bool) error
+	StopAllTracks() error
+	ResumeAllInSync() error
+	MasterGain(out int, gain Gain) error
+	SamplerateOffset(out, offset int) error
+	SetReporting(enable bool) error
+	SetTriggerBank(bank int) error
+	IsTrackPlaying(trk int) bool
+	GetVersion() string
+	GetNumTracks() int
+	Close() error
+}
+
+var _ Player = (*Tsunami)(nil)
+
+// Board is a Player delivering the messages received from the board, like
+// the track reports, to the subscribed functions. It is implemented by
+// Tsunami and by the WAV Trigger driver.
+type Board interface {
+	Player
+	Subscribe(fn func(protocol.Message)) (cancel func())
+}
+
+var _ Board = (*Tsunami)(nil)
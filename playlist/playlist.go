@@ -0,0 +1,341 @@
+// Package playlist plays ordered lists of tracks on a board, the usual need
+// of continuous background music. The playlist is driven by the track
+// reports of the board, see tsunami.Tsunami.Subscribe.
+package playlist
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// Mode is the repeat mode of a playlist.
+type Mode uint8
+
+const (
+	// Once plays the items in order, and stops at the end.
+	Once Mode = iota
+	// Loop plays the items in order, starting over at the end.
+	Loop
+	// Shuffle plays the items in random order, reshuffling them every time
+	// all of them are played.
+	Shuffle
+)
+
+var modeNames = map[Mode]string{
+	Once:    "once",
+	Loop:    "loop",
+	Shuffle: "shuffle",
+}
+
+func (m Mode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Mode(%d)", uint8(m))
+}
+
+// Item is an entry of a playlist.
+type Item struct {
+	Track  int
+	Output int
+	Gain   tsunami.Gain
+	// Gap is the silence after the item, before the next one starts.
+	Gap time.Duration
+	// FadeIn is the duration of the fade at the start of the item.
+	FadeIn time.Duration
+	// FadeOut is the duration of the fade at the end of the item. The end
+	// of the track is only anticipated by boards knowing its duration, see
+	// Tsunami.SetDurations, otherwise it's only applied when the item is
+	// skipped or the playlist stopped.
+	FadeOut time.Duration
+}
+
+// ErrEmpty is returned when starting a playlist without items.
+var ErrEmpty = errors.New("empty playlist")
+
+// Playlist plays a list of items, one after the other.
+type Playlist struct {
+	b     tsunami.Board
+	items []Item
+	mode  Mode
+	rand  *rand.Rand
+
+	mu      sync.Mutex
+	order   []int
+	pos     int
+	active  bool
+	playing bool
+	gap     *time.Timer
+	gapEnd  time.Time
+	cancel  func()
+	unsub   func()
+	done    chan struct{}
+	err     error
+}
+
+// New returns a playlist of the given items for the board, it's subscribed to
+// the messages of the board while it's playing.
+func New(b tsunami.Board, items []Item, mode Mode) *Playlist {
+	return &Playlist{
+		b:     b,
+		items: items,
+		mode:  mode,
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Start starts playing the playlist from its first item, stopping any item
+// playing.
+func (p *Playlist) Start() error {
+	if len(p.items) == 0 {
+		return ErrEmpty
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stop(0)
+	p.finish(nil)
+	p.active, p.done, p.err = true, make(chan struct{}), nil
+	p.unsub = p.b.Subscribe(p.handle)
+	p.order = p.newOrder()
+	p.pos = 0
+	return p.play()
+}
+
+// Stop stops the playlist, fading out the item playing.
+func (p *Playlist) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.active {
+		return nil
+	}
+
+	err := p.stop(p.items[p.order[p.pos]].FadeOut)
+	p.finish(nil)
+	return err
+}
+
+// Next skips to the next item, fading out the current one, the gap is not
+// respected.
+func (p *Playlist) Next() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.active {
+		return nil
+	}
+
+	if err := p.stop(p.items[p.order[p.pos]].FadeOut); err != nil {
+		return err
+	}
+
+	return p.advance()
+}
+
+// Current returns the item playing, or waiting for its gap to finish.
+func (p *Playlist) Current() (Item, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.active {
+		return Item{}, false
+	}
+
+	return p.items[p.order[p.pos]], true
+}
+
+// NextAt returns the estimated time the next item will start, after the end
+// of the current one and its gap, false if unknown. It requires a board
+// estimating the end of the tracks, like a Tsunami knowing the durations of
+// the tracks, see Tsunami.EndsAt.
+func (p *Playlist) NextAt() (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.active || (p.mode == Once && p.pos == len(p.order)-1) {
+		return time.Time{}, false
+	}
+
+	it := p.items[p.order[p.pos]]
+	if !p.playing {
+		// waiting for the gap to finish.
+		return p.gapEnd, !p.gapEnd.IsZero()
+	}
+
+	b, ok := p.b.(interface {
+		GapAfter(trk int, gap time.Duration) (time.Time, bool)
+	})
+
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return b.GapAfter(it.Track, it.Gap)
+}
+
+// Done returns a channel closed when the playlist ends or is stopped, nil if
+// it wasn't started.
+func (p *Playlist) Done() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.done
+}
+
+// Err returns the error that stopped the playlist, if any.
+func (p *Playlist) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+func (p *Playlist) handle(msg protocol.Message) {
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok || r.Playing {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.active || !p.playing || int(r.Track) != p.items[p.order[p.pos]].Track {
+		return
+	}
+
+	p.ended()
+	gap := p.items[p.order[p.pos]].Gap
+	if gap <= 0 {
+		p.fail(p.advance())
+		return
+	}
+
+	p.gapEnd = time.Now().Add(gap)
+	p.gap = time.AfterFunc(gap, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if p.gap != nil {
+			p.gap = nil
+			p.fail(p.advance())
+		}
+	})
+}
+
+// advance plays the next item, or finishes the playlist.
+func (p *Playlist) advance() error {
+	p.pos++
+	if p.pos == len(p.order) {
+		if p.mode == Once {
+			p.pos--
+			p.finish(nil)
+			return nil
+		}
+
+		p.order, p.pos = p.newOrder(), 0
+	}
+
+	return p.play()
+}
+
+func (p *Playlist) play() error {
+	it := p.items[p.order[p.pos]]
+
+	gain := it.Gain
+	if it.FadeIn > 0 {
+		gain = tsunami.MinGain
+	}
+
+	if err := p.b.TrackGain(it.Track, gain); err != nil {
+		return err
+	}
+
+	if err := p.b.TrackPlayPoly(it.Track, it.Output, false); err != nil {
+		return err
+	}
+
+	p.playing = true
+	if it.FadeIn > 0 {
+		if err := p.b.TrackFade(it.Track, it.Gain, it.FadeIn, false); err != nil {
+			return err
+		}
+	}
+
+	if n, ok := p.b.(interface {
+		NotifyBefore(trk int, before time.Duration, fn func()) func()
+	}); ok && it.FadeOut > 0 {
+		p.cancel = n.NotifyBefore(it.Track, it.FadeOut, func() {
+			p.b.TrackFade(it.Track, tsunami.MinGain, it.FadeOut, false)
+		})
+	}
+
+	return nil
+}
+
+// ended releases the state of the item playing.
+func (p *Playlist) ended() {
+	p.playing = false
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+}
+
+// stop stops the item playing, fading it out during the given time.
+func (p *Playlist) stop(fade time.Duration) error {
+	if p.gap != nil {
+		p.gap.Stop()
+		p.gap = nil
+	}
+
+	if !p.playing {
+		return nil
+	}
+
+	trk := p.items[p.order[p.pos]].Track
+	p.ended()
+	if fade > 0 {
+		return p.b.TrackFade(trk, tsunami.MinGain, fade, true)
+	}
+
+	return p.b.TrackStop(trk)
+}
+
+func (p *Playlist) fail(err error) {
+	if err != nil {
+		p.finish(err)
+	}
+}
+
+func (p *Playlist) finish(err error) {
+	if !p.active {
+		return
+	}
+
+	p.active, p.err = false, err
+	close(p.done)
+	p.unsub()
+}
+
+// newOrder returns the order of the items for the next cycle.
+func (p *Playlist) newOrder() []int {
+	if p.mode == Shuffle {
+		return p.rand.Perm(len(p.items))
+	}
+
+	order := make([]int, len(p.items))
+	for i := range order {
+		order[i] = i
+	}
+
+	return order
+}
@@ -0,0 +1,178 @@
+package playlist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// end reports the end of the track to the board.
+func end(t *testing.T, ts *tsunami.Tsunami, port *transport.Loopback, trk int) {
+	port.Push(&protocol.TrackReport{Track: uint16(trk), Playing: false})
+	if err := ts.Update(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// played returns the tracks started since the last call.
+func played(t *testing.T, port *transport.Loopback) []int {
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tracks []int
+	for _, m := range msgs {
+		if c, ok := m.(*protocol.TrackControl); ok && c.Code == tsunami.TRK_PLAY_POLY {
+			tracks = append(tracks, int(c.Track))
+		}
+	}
+
+	return tracks
+}
+
+func TestPlaylistOnce(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	p := New(ts, []Item{{Track: 1}, {Track: 2, Gap: 10 * time.Millisecond}, {Track: 3}}, Once)
+
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	end(t, ts, port, 1)
+	end(t, ts, port, 2)
+	if got := played(t, port); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("unexpected tracks played %v", got)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	end(t, ts, port, 3)
+
+	if got := played(t, port); len(got) != 1 || got[0] != 3 {
+		t.Fatalf("item should start after the gap, got %v", got)
+	}
+
+	select {
+	case <-p.Done():
+	default:
+		t.Fatalf("playlist should be done")
+	}
+
+	if _, ok := p.Current(); ok {
+		t.Errorf("nothing should be playing")
+	}
+}
+
+func TestPlaylistLoop(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	p := New(ts, []Item{{Track: 1}, {Track: 2}}, Loop)
+	p.Start()
+
+	for _, trk := range []int{1, 2, 1, 2} {
+		end(t, ts, port, trk)
+	}
+
+	if got := played(t, port); len(got) != 5 {
+		t.Errorf("playlist should loop, got %v", got)
+	}
+
+	if err := p.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, _ := port.Messages()
+	if c, ok := msgs[len(msgs)-1].(*protocol.TrackControl); !ok || c.Code != tsunami.TRK_STOP || c.Track != 1 {
+		t.Errorf("current item should be stopped, got %v", msgs)
+	}
+}
+
+func TestPlaylistShuffle(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	items := []Item{{Track: 1}, {Track: 2}, {Track: 3}, {Track: 4}}
+	p := New(ts, items, Shuffle)
+	p.Start()
+
+	for i := 0; i < len(items)-1; i++ {
+		it, _ := p.Current()
+		end(t, ts, port, it.Track)
+	}
+
+	seen := make(map[int]bool)
+	for _, trk := range played(t, port) {
+		seen[trk] = true
+	}
+
+	if len(seen) != len(items) {
+		t.Errorf("every item should be played once per cycle, got %v", seen)
+	}
+}
+
+func TestPlaylistFade(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	p := New(ts, []Item{{Track: 1, Gain: -6, FadeIn: time.Second, FadeOut: time.Second}, {Track: 2}}, Once)
+	p.Start()
+	p.Next()
+
+	var got []string
+	msgs, _ := port.Messages()
+	for _, m := range msgs {
+		got = append(got, protocol.Describe(m))
+	}
+
+	expected := []string{
+		protocol.Describe(&protocol.TrackVolume{Track: 1, Gain: tsunami.MinGain}),
+		protocol.Describe(&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 1}),
+		protocol.Describe(&protocol.TrackFade{Track: 1, Gain: -6, Millis: 1000}),
+		protocol.Describe(&protocol.TrackFade{Track: 1, Gain: tsunami.MinGain, Millis: 1000, Stop: true}),
+		protocol.Describe(&protocol.TrackVolume{Track: 2}),
+		protocol.Describe(&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 2}),
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("unexpected commands %q", got)
+	}
+
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("%d: got %q, expected %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestPlaylistNextAt(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	ts.SetDurations(tsunami.Durations{1: time.Minute})
+	p := New(ts, []Item{{Track: 1, Gap: 10 * time.Millisecond}, {Track: 2, Gap: 20 * time.Millisecond}, {Track: 3}}, Once)
+
+	if err := p.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	next, ok := p.NextAt()
+	if d := time.Until(next); !ok || d < time.Minute || d > time.Minute+10*time.Millisecond {
+		t.Errorf("unexpected next %s", d)
+	}
+
+	end(t, ts, port, 1)
+	if next, ok := p.NextAt(); !ok || time.Until(next) > 10*time.Millisecond {
+		t.Errorf("the next item should start after the gap, got %s", next)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := p.NextAt(); ok {
+		t.Error("the duration of the track is unknown")
+	}
+
+	end(t, ts, port, 2)
+	if next, ok := p.NextAt(); !ok || time.Until(next) > 20*time.Millisecond {
+		t.Errorf("the next item should start after the gap, got %s", next)
+	}
+}
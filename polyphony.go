@@ -0,0 +1,112 @@
+package tsunami
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Scheduled is a track scheduled at an offset from the start of a show, for
+// PlanVoices.
+type Scheduled struct {
+	Track int
+	At    time.Duration
+	// Until is the offset at which the track is stopped, zero if it plays
+	// to its end. It's required for the tracks looping.
+	Until time.Duration
+}
+
+// Overload is a period of a show needing more voices than the board has.
+type Overload struct {
+	Start, End time.Duration
+	// Voices are the voices needed at the peak of the period.
+	Voices int
+	// Tracks are the tracks playing during the period.
+	Tracks []int
+}
+
+func (o Overload) String() string {
+	return fmt.Sprintf("%d voices needed from %s to %s, tracks %v", o.Voices, o.Start, o.End, o.Tracks)
+}
+
+// PlanVoices returns the periods of the scheduled tracks needing more than the
+// given number of voices, from the durations of the tracks, so a show can be
+// checked before it's played. An error is returned if the duration of a track
+// ending on its own is unknown.
+func PlanVoices(plays []Scheduled, d TrackDurations, voices int) ([]Overload, error) {
+	type event struct {
+		at    time.Duration
+		delta int
+		i     int
+	}
+
+	events := make([]event, 0, len(plays)*2)
+	for i, p := range plays {
+		end := p.Until
+		if end == 0 {
+			length, ok := d.Duration(p.Track)
+			if !ok {
+				return nil, fmt.Errorf("unknown duration of track %d", p.Track)
+			}
+
+			end = p.At + length
+		}
+
+		if end <= p.At {
+			continue
+		}
+
+		events = append(events, event{p.At, 1, i}, event{end, -1, i})
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].at < events[j].at })
+
+	var overloads []Overload
+	var current *Overload
+	playing := make(map[int]bool)
+	for n, e := range events {
+		if e.delta > 0 {
+			playing[e.i] = true
+		} else {
+			delete(playing, e.i)
+		}
+
+		// the count is only checked once every event at the same time is
+		// applied.
+		if n+1 < len(events) && events[n+1].at == e.at {
+			continue
+		}
+
+		switch {
+		case len(playing) > voices:
+			if current == nil {
+				current = &Overload{Start: e.at}
+			}
+
+			if len(playing) > current.Voices {
+				current.Voices = len(playing)
+			}
+
+			for i := range playing {
+				current.Tracks = appendTrack(current.Tracks, plays[i].Track)
+			}
+		case current != nil:
+			current.End = e.at
+			sort.Ints(current.Tracks)
+			overloads = append(overloads, *current)
+			current = nil
+		}
+	}
+
+	return overloads, nil
+}
+
+func appendTrack(tracks []int, trk int) []int {
+	for _, t := range tracks {
+		if t == trk {
+			return tracks
+		}
+	}
+
+	return append(tracks, trk)
+}
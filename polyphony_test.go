@@ -0,0 +1,65 @@
+package tsunami
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestPlanVoices(t *testing.T) {
+	d := Durations{1: 10 * time.Second, 2: 4 * time.Second, 3: 2 * time.Second}
+	overloads, err := PlanVoices([]Scheduled{
+		{Track: 1, At: 0},
+		{Track: 2, At: time.Second},
+		{Track: 3, At: 2 * time.Second},
+		// starts when track 3 ends, taking the same voice.
+		{Track: 3, At: 4 * time.Second},
+		{Track: 4, At: 4 * time.Second, Until: 8 * time.Second},
+	}, d, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Overload{
+		{Start: 2 * time.Second, End: 6 * time.Second, Voices: 4, Tracks: []int{1, 2, 3, 4}},
+	}
+
+	if !reflect.DeepEqual(overloads, expected) {
+		t.Errorf("unexpected overloads %v", overloads)
+	}
+
+	if _, err := PlanVoices([]Scheduled{{Track: 9}}, d, 2); err == nil {
+		t.Errorf("unknown duration should fail")
+	}
+}
+
+func TestVoiceManagerOnNearLimit(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	m := NewVoiceManager(ts, 4)
+
+	var warnings []int
+	m.OnNearLimit(1, func(used, size int) { warnings = append(warnings, used) })
+
+	report := func(voice int, playing bool) {
+		port.Push(&protocol.TrackReport{Track: uint16(voice + 1), Voice: uint8(voice), Playing: playing})
+		if err := ts.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report(0, true)
+	report(1, true)
+	report(2, true)
+	report(3, true)
+	report(3, false)
+	report(2, false)
+	report(2, true)
+
+	if !reflect.DeepEqual(warnings, []int{3, 3}) {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+}
@@ -0,0 +1,179 @@
+package tsunami
+
+import (
+	"math"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// TrackDurations provides the duration of the tracks of the card.
+type TrackDurations interface {
+	// Duration returns the duration of the track, false if it's unknown.
+	Duration(trk int) (time.Duration, bool)
+}
+
+// Durations is a TrackDurations backed by a map, by track number.
+type Durations map[int]time.Duration
+
+// Duration implements TrackDurations.
+func (d Durations) Duration(trk int) (time.Duration, bool) {
+	v, ok := d[trk]
+	return v, ok
+}
+
+// playback is the estimated state of a track started through the library.
+type playback struct {
+	seq    int
+	out    int
+	pos    time.Duration
+	since  time.Time
+	paused bool
+}
+
+// SetDurations sets the durations of the tracks, used by Position and
+// Remaining.
+func (t *Tsunami) SetDurations(d TrackDurations) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.durations = d
+}
+
+// Position returns the estimated playback position of the track, false if
+// the track isn't playing or paused. The board doesn't report positions, so
+// it's computed from the time the track was started, paused and resumed
+// through the library, and the samplerate offset of its output. The end of
+// the tracks is detected by the track reports, if enabled; knowing the
+// durations of the tracks, see SetDurations, the position is bounded to the
+// duration, or wrapped around it if the loop flag is set.
+func (t *Tsunami) Position(trk int) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.position(trk)
+}
+
+// Remaining returns the estimated time left until the end of the track, false
+// if the track isn't playing or its duration is unknown. It takes into
+// account the samplerate offset, but not the loop flag.
+func (t *Tsunami) Remaining(trk int) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pos, ok := t.position(trk)
+	if !ok {
+		return 0, false
+	}
+
+	return t.remaining(trk, pos)
+}
+
+func (t *Tsunami) remaining(trk int, pos time.Duration) (time.Duration, bool) {
+	if t.durations == nil {
+		return 0, false
+	}
+
+	d, ok := t.durations.Duration(trk)
+	if !ok {
+		return 0, false
+	}
+
+	speed := t.speed(t.playbacks[trk].out)
+	return time.Duration(float64(d-pos) / speed), true
+}
+
+func (t *Tsunami) position(trk int) (time.Duration, bool) {
+	p, ok := t.playbacks[trk]
+	if !ok {
+		return 0, false
+	}
+
+	pos := p.pos
+	if !p.paused {
+		pos += time.Duration(float64(time.Since(p.since)) * t.speed(p.out))
+	}
+
+	if t.durations == nil {
+		return pos, true
+	}
+
+	d, ok := t.durations.Duration(trk)
+	switch {
+	case !ok || d <= 0 || pos < d:
+		return pos, true
+	case t.loops[trk]:
+		return pos % d, true
+	}
+
+	return d, true
+}
+
+// speed returns the playback speed of the output, due to its samplerate
+// offset: one octave up, twice as fast.
+func (t *Tsunami) speed(out int) float64 {
+	if out < 0 || out >= MaxOutputs || t.offsets[out] == 0 {
+		return 1
+	}
+
+	return math.Pow(2, OffsetToSemitones(t.offsets[out])/12)
+}
+
+// notePlayback updates the playback state after a track control command,
+// must be called with the lock held.
+func (t *Tsunami) notePlayback(trk int, code protocol.TrackCode, out int) {
+	now := time.Now()
+	switch code {
+	case TRK_PLAY_SOLO, TRK_PLAY_POLY, TRK_LOAD:
+		if code == TRK_PLAY_SOLO {
+			t.clearPlaybacks()
+		}
+
+		p, ok := t.playbacks[trk]
+		if !ok {
+			p = &playback{}
+			t.playbacks[trk] = p
+		}
+
+		t.playSeq++
+		*p = playback{seq: t.playSeq, out: out, since: now, paused: code == TRK_LOAD}
+	case TRK_STOP:
+		delete(t.playbacks, trk)
+	case TRK_PAUSE:
+		if p, ok := t.playbacks[trk]; ok && !p.paused {
+			p.pos += time.Duration(float64(now.Sub(p.since)) * t.speed(p.out))
+			p.paused = true
+		}
+	case TRK_RESUME:
+		if p, ok := t.playbacks[trk]; ok && p.paused {
+			p.since, p.paused = now, false
+		}
+	}
+}
+
+func (t *Tsunami) clearPlaybacks() {
+	for trk := range t.playbacks {
+		delete(t.playbacks, trk)
+	}
+}
+
+func (t *Tsunami) resumePlaybacks() {
+	now := time.Now()
+	for _, p := range t.playbacks {
+		if p.paused {
+			p.since, p.paused = now, false
+		}
+	}
+}
+
+// rebasePlaybacks accounts the time played so far by the tracks of the
+// output, before its speed changes.
+func (t *Tsunami) rebasePlaybacks(out int) {
+	now := time.Now()
+	for _, p := range t.playbacks {
+		if p.out == out && !p.paused {
+			p.pos += time.Duration(float64(now.Sub(p.since)) * t.speed(out))
+			p.since = now
+		}
+	}
+}
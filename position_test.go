@@ -0,0 +1,80 @@
+package tsunami
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestPosition(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	ts.SetDurations(Durations{19: time.Hour})
+
+	if _, ok := ts.Position(19); ok {
+		t.Errorf("track shouldn't be playing")
+	}
+
+	ts.TrackLoad(19, 0, false)
+	time.Sleep(10 * time.Millisecond)
+	if pos, ok := ts.Position(19); !ok || pos != 0 {
+		t.Errorf("loaded track should be at the start, got %s", pos)
+	}
+
+	ts.ResumeAllInSync()
+	time.Sleep(20 * time.Millisecond)
+	ts.TrackPause(19)
+
+	pos, ok := ts.Position(19)
+	if !ok || pos < 20*time.Millisecond || pos > 40*time.Millisecond {
+		t.Errorf("unexpected position %s", pos)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if paused, _ := ts.Position(19); paused != pos {
+		t.Errorf("paused track shouldn't advance, got %s", paused)
+	}
+
+	ts.SamplerateOffset(0, MaxSamplerateOffset)
+	ts.TrackResume(19)
+	time.Sleep(20 * time.Millisecond)
+
+	if fast, _ := ts.Position(19); fast-pos < 40*time.Millisecond {
+		t.Errorf("track should advance at double speed, got %s", fast-pos)
+	}
+
+	if rem, ok := ts.Remaining(19); !ok || rem > 30*time.Minute {
+		t.Errorf("unexpected remaining %s", rem)
+	}
+
+	port.Push(&protocol.TrackReport{Track: 19, Voice: 0, Playing: false})
+	ts.Update()
+
+	if _, ok := ts.Position(19); ok {
+		t.Errorf("track should be ended")
+	}
+}
+
+func TestPositionBounded(t *testing.T) {
+	ts := newTestTsunami()
+	ts.SetDurations(Durations{1: 10 * time.Millisecond, 2: 10 * time.Millisecond})
+	ts.TrackLoop(2, true)
+	ts.TrackPlayPoly(1, 0, false)
+	ts.TrackPlayPoly(2, 0, false)
+	time.Sleep(15 * time.Millisecond)
+
+	if pos, _ := ts.Position(1); pos != 10*time.Millisecond {
+		t.Errorf("position should be bounded to the duration, got %s", pos)
+	}
+
+	if pos, _ := ts.Position(2); pos >= 10*time.Millisecond {
+		t.Errorf("position of looping track should wrap, got %s", pos)
+	}
+
+	ts.TrackPlaySolo(3, 0, false)
+	if _, ok := ts.Position(1); ok {
+		t.Errorf("solo play should stop the rest of the tracks")
+	}
+}
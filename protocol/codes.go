@@ -0,0 +1,180 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Command is the code of a message sent to the board.
+type Command uint8
+
+const (
+	CMD_GET_VERSION       Command = 1
+	CMD_GET_SYS_INFO      Command = 2
+	CMD_TRACK_CONTROL     Command = 3
+	CMD_STOP_ALL          Command = 4
+	CMD_MASTER_VOLUME     Command = 5
+	CMD_GET_STATUS        Command = 7
+	CMD_TRACK_VOLUME      Command = 8
+	CMD_TRACK_FADE        Command = 10
+	CMD_RESUME_ALL_SYNC   Command = 11
+	CMD_SAMPLERATE_OFFSET Command = 12
+	CMD_SET_REPORTING     Command = 13
+	CMD_SET_TRIGGER_BANK  Command = 14
+	CMD_SET_INPUT_MIX     Command = 15
+	CMD_SET_MIDI_BANK     Command = 16
+)
+
+var commandNames = map[Command]string{
+	CMD_GET_VERSION:       "CMD_GET_VERSION",
+	CMD_GET_SYS_INFO:      "CMD_GET_SYS_INFO",
+	CMD_TRACK_CONTROL:     "CMD_TRACK_CONTROL",
+	CMD_STOP_ALL:          "CMD_STOP_ALL",
+	CMD_MASTER_VOLUME:     "CMD_MASTER_VOLUME",
+	CMD_GET_STATUS:        "CMD_GET_STATUS",
+	CMD_TRACK_VOLUME:      "CMD_TRACK_VOLUME",
+	CMD_TRACK_FADE:        "CMD_TRACK_FADE",
+	CMD_RESUME_ALL_SYNC:   "CMD_RESUME_ALL_SYNC",
+	CMD_SAMPLERATE_OFFSET: "CMD_SAMPLERATE_OFFSET",
+	CMD_SET_REPORTING:     "CMD_SET_REPORTING",
+	CMD_SET_TRIGGER_BANK:  "CMD_SET_TRIGGER_BANK",
+	CMD_SET_INPUT_MIX:     "CMD_SET_INPUT_MIX",
+	CMD_SET_MIDI_BANK:     "CMD_SET_MIDI_BANK",
+}
+
+func (c Command) String() string {
+	if name, ok := commandNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Command(%d)", uint8(c))
+}
+
+// Commands returns all the known commands, sorted by code.
+func Commands() []Command {
+	cmds := make([]Command, 0, len(commandNames))
+	for c := range commandNames {
+		cmds = append(cmds, c)
+	}
+
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i] < cmds[j] })
+	return cmds
+}
+
+// ParseCommand returns the command with the given name, eg. "CMD_STOP_ALL".
+func ParseCommand(name string) (Command, bool) {
+	for c, n := range commandNames {
+		if n == name {
+			return c, true
+		}
+	}
+
+	return 0, false
+}
+
+// Response is the code of a message sent by the board.
+type Response uint8
+
+const (
+	RSP_VERSION_STRING Response = 129
+	RSP_SYSTEM_INFO    Response = 130
+	RSP_STATUS         Response = 131
+	RSP_TRACK_REPORT   Response = 132
+)
+
+var responseNames = map[Response]string{
+	RSP_VERSION_STRING: "RSP_VERSION_STRING",
+	RSP_SYSTEM_INFO:    "RSP_SYSTEM_INFO",
+	RSP_STATUS:         "RSP_STATUS",
+	RSP_TRACK_REPORT:   "RSP_TRACK_REPORT",
+}
+
+func (r Response) String() string {
+	if name, ok := responseNames[r]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Response(%d)", uint8(r))
+}
+
+// Responses returns all the known responses, sorted by code.
+func Responses() []Response {
+	rsps := make([]Response, 0, len(responseNames))
+	for r := range responseNames {
+		rsps = append(rsps, r)
+	}
+
+	sort.Slice(rsps, func(i, j int) bool { return rsps[i] < rsps[j] })
+	return rsps
+}
+
+// ParseResponse returns the response with the given name, eg. "RSP_STATUS".
+func ParseResponse(name string) (Response, bool) {
+	for r, n := range responseNames {
+		if n == name {
+			return r, true
+		}
+	}
+
+	return 0, false
+}
+
+// TrackCode is the action performed by a TrackControl command.
+type TrackCode uint8
+
+const (
+	TRK_PLAY_SOLO TrackCode = 0
+	TRK_PLAY_POLY TrackCode = 1
+	TRK_PAUSE     TrackCode = 2
+	TRK_RESUME    TrackCode = 3
+	TRK_STOP      TrackCode = 4
+	TRK_LOOP_ON   TrackCode = 5
+	TRK_LOOP_OFF  TrackCode = 6
+	TRK_LOAD      TrackCode = 7
+)
+
+var trackCodeNames = map[TrackCode]string{
+	TRK_PLAY_SOLO: "TRK_PLAY_SOLO",
+	TRK_PLAY_POLY: "TRK_PLAY_POLY",
+	TRK_PAUSE:     "TRK_PAUSE",
+	TRK_RESUME:    "TRK_RESUME",
+	TRK_STOP:      "TRK_STOP",
+	TRK_LOOP_ON:   "TRK_LOOP_ON",
+	TRK_LOOP_OFF:  "TRK_LOOP_OFF",
+	TRK_LOAD:      "TRK_LOAD",
+}
+
+func (c TrackCode) String() string {
+	if name, ok := trackCodeNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("TrackCode(%d)", uint8(c))
+}
+
+// ParseTrackCode returns the track code with the given name, eg. "TRK_STOP".
+func ParseTrackCode(name string) (TrackCode, bool) {
+	for c, n := range trackCodeNames {
+		if n == name {
+			return c, true
+		}
+	}
+
+	return 0, false
+}
+
+// IsResponse reports whether the given frame code belongs to a response, sent
+// by the board, rather than to a command.
+func IsResponse(code byte) bool {
+	return code&0x80 != 0
+}
+
+// CodeString returns the name of the command or response with the given
+// frame code.
+func CodeString(code byte) string {
+	if IsResponse(code) {
+		return Response(code).String()
+	}
+
+	return Command(code).String()
+}
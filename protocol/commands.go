@@ -0,0 +1,313 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// GetVersion requests the firmware version string, answered with a
+// VersionString response.
+type GetVersion struct{}
+
+func (m GetVersion) Frame() Frame { return frameOf(m) }
+
+func (GetVersion) AppendBinary(dst []byte) []byte {
+	return AppendFrame(dst, byte(CMD_GET_VERSION))
+}
+
+func (*GetVersion) decode(data []byte) error { return nil }
+
+// GetSysInfo requests the system information, answered with a SystemInfo
+// response.
+type GetSysInfo struct{}
+
+func (m GetSysInfo) Frame() Frame { return frameOf(m) }
+
+func (GetSysInfo) AppendBinary(dst []byte) []byte {
+	return AppendFrame(dst, byte(CMD_GET_SYS_INFO))
+}
+
+func (*GetSysInfo) decode(data []byte) error { return nil }
+
+// TrackControl performs the action given by Code on a track, routed to the
+// given output.
+type TrackControl struct {
+	Code   TrackCode
+	Track  uint16
+	Output uint8
+	Flags  uint8
+}
+
+func (m TrackControl) Frame() Frame { return frameOf(m) }
+
+func (m TrackControl) AppendBinary(dst []byte) []byte {
+	var data [5]byte
+	data[0] = byte(m.Code)
+	putUint16(data[1:], m.Track)
+	data[3] = m.Output & 0x07
+	data[4] = m.Flags
+
+	return AppendFrame(dst, byte(CMD_TRACK_CONTROL), data[:]...)
+}
+
+func (m TrackControl) String() string {
+	return fmt.Sprintf("%s/%s track=%d output=%d flags=%d",
+		CMD_TRACK_CONTROL, m.Code, m.Track, m.Output, m.Flags,
+	)
+}
+
+func (m *TrackControl) decode(data []byte) error {
+	if err := checkLen(data, 5); err != nil {
+		return err
+	}
+
+	m.Code = TrackCode(data[0])
+	m.Track = uint16At(data[1:])
+	m.Output = data[3]
+	m.Flags = data[4]
+	return nil
+}
+
+// StopAll stops every track currently playing.
+type StopAll struct{}
+
+func (m StopAll) Frame() Frame { return frameOf(m) }
+
+func (StopAll) AppendBinary(dst []byte) []byte {
+	return AppendFrame(dst, byte(CMD_STOP_ALL))
+}
+
+func (*StopAll) decode(data []byte) error { return nil }
+
+// MasterVolume sets the gain of an output.
+type MasterVolume struct {
+	Output uint8
+	Gain   int16
+}
+
+func (m MasterVolume) Frame() Frame { return frameOf(m) }
+
+func (m MasterVolume) AppendBinary(dst []byte) []byte {
+	var data [3]byte
+	data[0] = m.Output & 0x07
+	putInt16(data[1:], m.Gain)
+
+	return AppendFrame(dst, byte(CMD_MASTER_VOLUME), data[:]...)
+}
+
+func (m *MasterVolume) decode(data []byte) error {
+	if err := checkLen(data, 3); err != nil {
+		return err
+	}
+
+	m.Output = data[0]
+	m.Gain = int16At(data[1:])
+	return nil
+}
+
+// GetStatus requests the list of tracks currently playing, answered with a
+// Status response.
+type GetStatus struct{}
+
+func (m GetStatus) Frame() Frame { return frameOf(m) }
+
+func (GetStatus) AppendBinary(dst []byte) []byte {
+	return AppendFrame(dst, byte(CMD_GET_STATUS))
+}
+
+func (*GetStatus) decode(data []byte) error { return nil }
+
+// TrackVolume sets the gain of a track.
+type TrackVolume struct {
+	Track uint16
+	Gain  int16
+}
+
+func (m TrackVolume) Frame() Frame { return frameOf(m) }
+
+func (m TrackVolume) AppendBinary(dst []byte) []byte {
+	var data [4]byte
+	putUint16(data[0:], m.Track)
+	putInt16(data[2:], m.Gain)
+
+	return AppendFrame(dst, byte(CMD_TRACK_VOLUME), data[:]...)
+}
+
+func (m *TrackVolume) decode(data []byte) error {
+	if err := checkLen(data, 4); err != nil {
+		return err
+	}
+
+	m.Track = uint16At(data[0:])
+	m.Gain = int16At(data[2:])
+	return nil
+}
+
+// TrackFade starts a hardware fade of a track to the target gain, over the
+// given number of milliseconds, optionally stopping the track at the end.
+type TrackFade struct {
+	Track  uint16
+	Gain   int16
+	Millis uint16
+	Stop   bool
+}
+
+func (m TrackFade) Frame() Frame { return frameOf(m) }
+
+func (m TrackFade) AppendBinary(dst []byte) []byte {
+	var data [7]byte
+	putUint16(data[0:], m.Track)
+	putInt16(data[2:], m.Gain)
+	putUint16(data[4:], m.Millis)
+	data[6] = boolByte(m.Stop)
+
+	return AppendFrame(dst, byte(CMD_TRACK_FADE), data[:]...)
+}
+
+func (m *TrackFade) decode(data []byte) error {
+	if err := checkLen(data, 7); err != nil {
+		return err
+	}
+
+	m.Track = uint16At(data[0:])
+	m.Gain = int16At(data[2:])
+	m.Millis = uint16At(data[4:])
+	m.Stop = data[6] != 0
+	return nil
+}
+
+// ResumeAllSync resumes all paused tracks within the same audio buffer.
+type ResumeAllSync struct{}
+
+func (m ResumeAllSync) Frame() Frame { return frameOf(m) }
+
+func (ResumeAllSync) AppendBinary(dst []byte) []byte {
+	return AppendFrame(dst, byte(CMD_RESUME_ALL_SYNC))
+}
+
+func (*ResumeAllSync) decode(data []byte) error { return nil }
+
+// SamplerateOffset sets the playback speed of an output.
+type SamplerateOffset struct {
+	Output uint8
+	Offset int16
+}
+
+func (m SamplerateOffset) Frame() Frame { return frameOf(m) }
+
+func (m SamplerateOffset) AppendBinary(dst []byte) []byte {
+	var data [3]byte
+	data[0] = m.Output
+	putInt16(data[1:], m.Offset)
+
+	return AppendFrame(dst, byte(CMD_SAMPLERATE_OFFSET), data[:]...)
+}
+
+func (m *SamplerateOffset) decode(data []byte) error {
+	if err := checkLen(data, 3); err != nil {
+		return err
+	}
+
+	m.Output = data[0]
+	m.Offset = int16At(data[1:])
+	return nil
+}
+
+// SetReporting enables or disables track reports.
+type SetReporting struct {
+	Enable bool
+}
+
+func (m SetReporting) Frame() Frame { return frameOf(m) }
+
+func (m SetReporting) AppendBinary(dst []byte) []byte {
+	return AppendFrame(dst, byte(CMD_SET_REPORTING), boolByte(m.Enable))
+}
+
+func (m *SetReporting) decode(data []byte) error {
+	if err := checkLen(data, 1); err != nil {
+		return err
+	}
+
+	m.Enable = data[0] != 0
+	return nil
+}
+
+// SetTriggerBank sets the trigger bank, from 1 to 32.
+type SetTriggerBank struct {
+	Bank uint8
+}
+
+func (m SetTriggerBank) Frame() Frame { return frameOf(m) }
+
+func (m SetTriggerBank) AppendBinary(dst []byte) []byte {
+	return AppendFrame(dst, byte(CMD_SET_TRIGGER_BANK), m.Bank)
+}
+
+func (m *SetTriggerBank) decode(data []byte) error {
+	if err := checkLen(data, 1); err != nil {
+		return err
+	}
+
+	m.Bank = data[0]
+	return nil
+}
+
+// SetInputMix sets the routing of the audio input, a combination of the
+// IMIX_OUT* flags.
+type SetInputMix struct {
+	Mix uint8
+}
+
+func (m SetInputMix) Frame() Frame { return frameOf(m) }
+
+func (m SetInputMix) AppendBinary(dst []byte) []byte {
+	return AppendFrame(dst, byte(CMD_SET_INPUT_MIX), m.Mix)
+}
+
+func (m *SetInputMix) decode(data []byte) error {
+	if err := checkLen(data, 1); err != nil {
+		return err
+	}
+
+	m.Mix = data[0]
+	return nil
+}
+
+// SetMidiBank sets the MIDI bank, from 1 to 32.
+type SetMidiBank struct {
+	Bank uint8
+}
+
+func (m SetMidiBank) Frame() Frame { return frameOf(m) }
+
+func (m SetMidiBank) AppendBinary(dst []byte) []byte {
+	return AppendFrame(dst, byte(CMD_SET_MIDI_BANK), m.Bank)
+}
+
+func (m *SetMidiBank) decode(data []byte) error {
+	if err := checkLen(data, 1); err != nil {
+		return err
+	}
+
+	m.Bank = data[0]
+	return nil
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// All the multi-byte values of the protocol are little-endian.
+
+func putUint16(b []byte, v uint16) { binary.LittleEndian.PutUint16(b, v) }
+
+func putInt16(b []byte, v int16) { binary.LittleEndian.PutUint16(b, uint16(v)) }
+
+func uint16At(b []byte) uint16 { return binary.LittleEndian.Uint16(b) }
+
+func int16At(b []byte) int16 { return int16(binary.LittleEndian.Uint16(b)) }
@@ -0,0 +1,215 @@
+package protocol
+
+import (
+	"errors"
+	"io"
+)
+
+// Decoder is an incremental decoder of a byte stream into messages. Bytes are
+// fed as they arrive from any source, and complete messages are retrieved
+// with Next. Partial frames are kept buffered until the remaining bytes are
+// fed.
+type Decoder struct {
+	buf    []byte
+	stats  Stats
+	strict bool
+}
+
+// Stats holds the counters of a Decoder, useful to diagnose noisy links.
+type Stats struct {
+	// Frames is the number of valid frames decoded, by frame code.
+	Frames map[byte]int
+	// Resyncs is the number of times the decoder lost the frame boundaries
+	// and had to look for the next start of frame.
+	Resyncs int
+	// Discarded is the number of bytes thrown away while resyncing.
+	Discarded int
+	// BadLength is the number of frames with an out of range length.
+	BadLength int
+	// BadEnd is the number of frames not terminated by EOM.
+	BadEnd int
+	// Malformed is the number of frames with a known code but a payload that
+	// couldn't be decoded.
+	Malformed int
+	// Unknown is the number of frames with an unknown code.
+	Unknown int
+}
+
+// NewDecoder returns a new empty Decoder.
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// SetStrict enables or disables the strict mode. In strict mode, invalid data
+// and frames whose content doesn't match exactly the expected format are
+// reported as a *FrameError, with the raw data attached, instead of being
+// silently skipped or partially decoded.
+func (d *Decoder) SetStrict(strict bool) {
+	d.strict = strict
+}
+
+// Feed appends the given bytes to the decoder buffer.
+func (d *Decoder) Feed(b []byte) {
+	d.buf = append(d.buf, b...)
+}
+
+// Buffered returns the number of bytes fed but not yet decoded.
+func (d *Decoder) Buffered() int {
+	return len(d.buf)
+}
+
+// Reset discards any buffered data.
+func (d *Decoder) Reset() {
+	d.buf = d.buf[:0]
+}
+
+// Next returns the next complete message in the stream. io.EOF is returned
+// when no complete frame is buffered. Frames with an unknown code are
+// consumed and reported with an error wrapping ErrUnknownCode.
+func (d *Decoder) Next() (Message, error) {
+	f, err := d.NextFrame()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Decode(f)
+}
+
+// Decode is like the package level Decode, but the result is accounted in
+// the decoder stats and validated when in strict mode. It is meant to be used
+// along with NextFrame.
+func (d *Decoder) Decode(f Frame) (Message, error) {
+	m, err := Decode(f)
+	if err == nil && d.strict {
+		err = validate(f, m)
+	}
+
+	switch {
+	case errors.Is(err, ErrUnknownCode):
+		d.stats.Unknown++
+	case err != nil:
+		d.stats.Malformed++
+	}
+
+	if err != nil && d.strict {
+		raw, _ := f.MarshalBinary()
+		return nil, &FrameError{Err: err, Raw: raw}
+	}
+
+	return m, err
+}
+
+// NextFrame is like Next but returns the raw frame, without interpreting it.
+// Invalid data in the stream doesn't stop the decoding: the decoder skips to
+// the next SOM1 SOM2 pair and continues from there, the number of bytes
+// thrown away is reported by Stats. In strict mode, the invalid data is also
+// reported as a *FrameError, decoding can continue calling NextFrame again.
+func (d *Decoder) NextFrame() (Frame, error) {
+	var lost bool
+	defer func() {
+		if lost {
+			d.stats.Resyncs++
+		}
+	}()
+
+	for {
+		if n := d.start(); n > 0 {
+			lost = true
+			if err := d.reject(ErrBadStart, n, n); err != nil {
+				return Frame{}, err
+			}
+		}
+
+		if len(d.buf) < 3 {
+			return Frame{}, io.EOF
+		}
+
+		n := int(d.buf[2])
+		if n < frameOverhead || n > MAX_MESSAGE_LEN {
+			d.stats.BadLength++
+			lost = true
+			if err := d.reject(ErrBadLength, 3, 1); err != nil {
+				return Frame{}, err
+			}
+
+			continue
+		}
+
+		if len(d.buf) < n {
+			return Frame{}, io.EOF
+		}
+
+		if d.buf[n-1] != EOM {
+			d.stats.BadEnd++
+			lost = true
+			if err := d.reject(ErrBadEnd, n, 1); err != nil {
+				return Frame{}, err
+			}
+
+			continue
+		}
+
+		f := Frame{
+			Code: d.buf[3],
+			Data: append([]byte(nil), d.buf[4:n-1]...),
+		}
+
+		if d.stats.Frames == nil {
+			d.stats.Frames = make(map[byte]int)
+		}
+
+		d.stats.Frames[f.Code]++
+		d.buf = d.buf[:copy(d.buf, d.buf[n:])]
+		return f, nil
+	}
+}
+
+// reject discards skip bytes of the buffer, which has n invalid bytes at its
+// start. In strict mode, the n bytes are returned as a *FrameError, nil is
+// returned otherwise.
+func (d *Decoder) reject(err error, n, skip int) error {
+	var raw []byte
+	if d.strict {
+		raw = append(raw, d.buf[:n]...)
+	}
+
+	d.discard(skip)
+	if raw == nil {
+		return nil
+	}
+
+	return &FrameError{Err: err, Raw: raw}
+}
+
+// Stats returns a copy of the decoder counters.
+func (d *Decoder) Stats() Stats {
+	s := d.stats
+	s.Frames = make(map[byte]int, len(d.stats.Frames))
+	for code, n := range d.stats.Frames {
+		s.Frames[code] = n
+	}
+
+	return s
+}
+
+// start returns the offset of the next start of frame, or the length of the
+// buffer if there is none. A trailing SOM1 is considered a start of frame,
+// since it may be followed by a SOM2 not fed yet.
+func (d *Decoder) start() int {
+	for i := 0; i < len(d.buf); i++ {
+		if d.buf[i] != SOM1 {
+			continue
+		}
+
+		if i+1 == len(d.buf) || d.buf[i+1] == SOM2 {
+			return i
+		}
+	}
+
+	return len(d.buf)
+}
+
+func (d *Decoder) discard(n int) {
+	d.stats.Discarded += n
+	d.buf = d.buf[:copy(d.buf, d.buf[n:])]
+}
@@ -0,0 +1,170 @@
+package protocol
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestDecoderNext(t *testing.T) {
+	d := NewDecoder()
+	d.Feed([]byte{SOM1, SOM2, 0x09, byte(RSP_TRACK_REPORT)})
+
+	if _, err := d.Next(); err != io.EOF {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	d.Feed([]byte{0x12, 0x00, 0x02, 0x01, EOM})
+	d.Feed([]byte{SOM1, SOM2, 0x08, byte(RSP_SYSTEM_INFO), 0x12, 0x00, 0x10, EOM})
+
+	m, err := d.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TrackReport{Track: 19, Voice: 2, Playing: true}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("got %+v, expected %+v", m, expected)
+	}
+
+	m, err = d.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(m, &SystemInfo{Voices: 18, Tracks: 4096}) {
+		t.Errorf("unexpected message %+v", m)
+	}
+
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if d.Buffered() != 0 {
+		t.Errorf("unexpected buffered bytes %d", d.Buffered())
+	}
+}
+
+func TestDecoderNextResync(t *testing.T) {
+	d := NewDecoder()
+	d.Feed([]byte{0x00, SOM1, SOM2, 0x05, byte(CMD_STOP_ALL), EOM})
+	d.Feed([]byte{SOM1, SOM2, 0x06, byte(CMD_STOP_ALL)})
+	d.Feed([]byte{SOM1, 0x01, SOM1})
+	d.Feed([]byte{SOM2, 0x05, byte(CMD_GET_VERSION), EOM})
+
+	m, err := d.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := m.(*StopAll); !ok {
+		t.Errorf("unexpected message %T", m)
+	}
+
+	m, err = d.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := m.(*GetVersion); !ok {
+		t.Errorf("unexpected message %T", m)
+	}
+
+	stats := d.Stats()
+	if stats.Discarded != 7 || stats.Resyncs != 2 || stats.BadEnd != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	if stats.Frames[byte(CMD_STOP_ALL)] != 1 || stats.Frames[byte(CMD_GET_VERSION)] != 1 {
+		t.Errorf("unexpected frames %v", stats.Frames)
+	}
+}
+
+func TestDecoderNextPartialStart(t *testing.T) {
+	d := NewDecoder()
+	d.Feed([]byte{0x01, 0x02, SOM1})
+
+	if _, err := d.Next(); err != io.EOF {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	d.Feed([]byte{SOM2, 0x05, byte(CMD_STOP_ALL), EOM})
+	if _, err := d.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Stats().Discarded != 2 {
+		t.Errorf("unexpected discarded bytes %d", d.Stats().Discarded)
+	}
+}
+
+func TestDecoderNextUnknownCode(t *testing.T) {
+	d := NewDecoder()
+	d.Feed([]byte{SOM1, SOM2, 0x05, 0x7f, EOM})
+
+	if _, err := d.Next(); !errors.Is(err, ErrUnknownCode) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if d.Stats().Unknown != 1 {
+		t.Errorf("unexpected unknown count %d", d.Stats().Unknown)
+	}
+
+	if d.Buffered() != 0 {
+		t.Errorf("unexpected buffered bytes %d", d.Buffered())
+	}
+}
+
+func TestDecoderStrict(t *testing.T) {
+	d := NewDecoder()
+	d.SetStrict(true)
+	d.Feed([]byte{0x00, 0x01})
+	d.Feed(TrackReport{Track: 1, Voice: MAX_NUM_VOICES}.AppendBinary(nil))
+	d.Feed([]byte{SOM1, SOM2, 0x06, byte(RSP_VERSION_STRING), 'v', EOM})
+	d.Feed([]byte{SOM1, SOM2, 0x06, byte(CMD_STOP_ALL), 0x00, 0x00})
+
+	var ferr *FrameError
+	_, err := d.Next()
+	if !errors.As(err, &ferr) || ferr.Err != ErrBadStart || len(ferr.Raw) != 2 {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	_, err = d.Next()
+	if !errors.As(err, &ferr) || !errors.Is(err, ErrInvalidVoice) || len(ferr.Raw) != 9 {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	_, err = d.Next()
+	if !errors.Is(err, ErrBadLength) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	_, err = d.Next()
+	if !errors.As(err, &ferr) || ferr.Err != ErrBadEnd || len(ferr.Raw) != 6 {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	_, err = d.Next()
+	if !errors.Is(err, ErrBadStart) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, err := d.Next(); err != io.EOF {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestDecoderNotStrict(t *testing.T) {
+	d := NewDecoder()
+	d.Feed([]byte{SOM1, SOM2, 0x06, byte(RSP_VERSION_STRING), 'v', EOM})
+
+	m, err := d.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := m.(*VersionString).Version; v != "v" {
+		t.Errorf("unexpected version %q", v)
+	}
+}
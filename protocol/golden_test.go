@@ -0,0 +1,64 @@
+package protocol
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// golden holds byte sequences as produced, or parsed, by the official
+// Tsunami Arduino library for the equivalent calls.
+var golden = []struct {
+	name string
+	m    Message
+	raw  []byte
+}{
+	{"getVersion", &GetVersion{}, []byte{0xf0, 0xaa, 0x05, 0x01, 0x55}},
+	{"getSysInfo", &GetSysInfo{}, []byte{0xf0, 0xaa, 0x05, 0x02, 0x55}},
+	{"trackPlayPoly(19, 0, false)", &TrackControl{Code: TRK_PLAY_POLY, Track: 19}, []byte{0xf0, 0xaa, 0x0a, 0x03, 0x01, 0x13, 0x00, 0x00, 0x00, 0x55}},
+	{"trackPlaySolo(1002, 3, true)", &TrackControl{Code: TRK_PLAY_SOLO, Track: 1002, Output: 3, Flags: 1}, []byte{0xf0, 0xaa, 0x0a, 0x03, 0x00, 0xea, 0x03, 0x03, 0x01, 0x55}},
+	{"trackLoop(7, true)", &TrackControl{Code: TRK_LOOP_ON, Track: 7}, []byte{0xf0, 0xaa, 0x0a, 0x03, 0x05, 0x07, 0x00, 0x00, 0x00, 0x55}},
+	{"stopAllTracks", &StopAll{}, []byte{0xf0, 0xaa, 0x05, 0x04, 0x55}},
+	{"masterGain(1, -10)", &MasterVolume{Output: 1, Gain: -10}, []byte{0xf0, 0xaa, 0x08, 0x05, 0x01, 0xf6, 0xff, 0x55}},
+	{"masterGain(7, 4)", &MasterVolume{Output: 7, Gain: 4}, []byte{0xf0, 0xaa, 0x08, 0x05, 0x07, 0x04, 0x00, 0x55}},
+	{"trackGain(19, -6)", &TrackVolume{Track: 19, Gain: -6}, []byte{0xf0, 0xaa, 0x09, 0x08, 0x13, 0x00, 0xfa, 0xff, 0x55}},
+	{"trackFade(19, -70, 5000, true)", &TrackFade{Track: 19, Gain: -70, Millis: 5000, Stop: true}, []byte{0xf0, 0xaa, 0x0c, 0x0a, 0x13, 0x00, 0xba, 0xff, 0x88, 0x13, 0x01, 0x55}},
+	{"resumeAllInSync", &ResumeAllSync{}, []byte{0xf0, 0xaa, 0x05, 0x0b, 0x55}},
+	{"samplerateOffset(0, -1000)", &SamplerateOffset{Offset: -1000}, []byte{0xf0, 0xaa, 0x08, 0x0c, 0x00, 0x18, 0xfc, 0x55}},
+	{"setReporting(true)", &SetReporting{Enable: true}, []byte{0xf0, 0xaa, 0x06, 0x0d, 0x01, 0x55}},
+	{"setTriggerBank(2)", &SetTriggerBank{Bank: 2}, []byte{0xf0, 0xaa, 0x06, 0x0e, 0x02, 0x55}},
+	{"setInputMix(IMIX_OUT1 | IMIX_OUT2)", &SetInputMix{Mix: 0x03}, []byte{0xf0, 0xaa, 0x06, 0x0f, 0x03, 0x55}},
+	{"setMidiBank(3)", &SetMidiBank{Bank: 3}, []byte{0xf0, 0xaa, 0x06, 0x10, 0x03, 0x55}},
+	{"track report", &TrackReport{Track: 19, Voice: 2, Playing: true}, []byte{0xf0, 0xaa, 0x09, 0x84, 0x12, 0x00, 0x02, 0x01, 0x55}},
+	{"track report off", &TrackReport{Track: 257, Voice: 17}, []byte{0xf0, 0xaa, 0x09, 0x84, 0x00, 0x01, 0x11, 0x00, 0x55}},
+	{"system info", &SystemInfo{Voices: 18, Tracks: 4096}, []byte{0xf0, 0xaa, 0x08, 0x82, 0x12, 0x00, 0x10, 0x55}},
+	{"version", &VersionString{Version: "Tsunami v1.10s"}, append(
+		append([]byte{0xf0, 0xaa, 0x1b, 0x81}, "Tsunami v1.10s\x00\x00\x00\x00\x00\x00\x00\x00"...), 0x55,
+	)},
+}
+
+func TestGoldenMarshal(t *testing.T) {
+	for _, tc := range golden {
+		b, err := Marshal(tc.m)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+
+		if !bytes.Equal(b, tc.raw) {
+			t.Errorf("%s: got % x, expected % x", tc.name, b, tc.raw)
+		}
+	}
+}
+
+func TestGoldenUnmarshal(t *testing.T) {
+	for _, tc := range golden {
+		m, err := Unmarshal(tc.raw)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+
+		if !reflect.DeepEqual(m, tc.m) {
+			t.Errorf("%s: got %+v, expected %+v", tc.name, m, tc.m)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// Package protocol implements the Tsunami serial wire format.
+//
+// Every message exchanged with the board, in both directions, is a frame with
+// the following layout:
+//
+//	SOM1 SOM2 LEN CODE DATA... EOM
+//
+// where LEN is the total length of the frame, including the start and end
+// markers. The package has no dependencies on the serial port, so it can be
+// reused by simulators, sniffers or any other tool speaking the protocol.
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+const (
+	MAX_MESSAGE_LEN    = 32
+	MAX_NUM_VOICES     = 18
+	VERSION_STRING_LEN = 23
+
+	SOM1 = 0xf0
+	SOM2 = 0xaa
+	EOM  = 0x55
+
+	// frameOverhead is the number of bytes in a frame besides the data:
+	// SOM1, SOM2, LEN, CODE and EOM.
+	frameOverhead = 5
+)
+
+var (
+	ErrFrameTooLong  = errors.New("frame exceeds maximum message length")
+	ErrFrameTooShort = errors.New("frame is too short")
+	ErrBadStart      = errors.New("frame doesn't start with SOM1 SOM2")
+	ErrBadEnd        = errors.New("frame doesn't end with EOM")
+	ErrBadLength     = errors.New("frame length doesn't match its content")
+	ErrUnknownCode   = errors.New("unknown message code")
+	ErrInvalidVoice  = errors.New("invalid voice number")
+)
+
+// FrameError is returned by a Decoder in strict mode, it holds the raw data
+// that caused the error.
+type FrameError struct {
+	// Err is the cause of the error, one of the Err* values.
+	Err error
+	// Raw is the invalid frame, or the invalid bytes found in the stream.
+	Raw []byte
+}
+
+func (e *FrameError) Error() string {
+	return fmt.Sprintf("%s: [% x]", e.Err, e.Raw)
+}
+
+func (e *FrameError) Unwrap() error {
+	return e.Err
+}
+
+// Frame is a single raw message, a code followed by its payload.
+type Frame struct {
+	Code byte
+	Data []byte
+}
+
+// String returns the name of the frame code followed by the data in hex.
+func (f Frame) String() string {
+	if len(f.Data) == 0 {
+		return CodeString(f.Code)
+	}
+
+	return fmt.Sprintf("%s [% x]", CodeString(f.Code), f.Data)
+}
+
+// Len returns the total length of the frame on the wire.
+func (f Frame) Len() int {
+	return len(f.Data) + frameOverhead
+}
+
+// MarshalBinary encodes the frame into its wire representation.
+func (f Frame) MarshalBinary() ([]byte, error) {
+	if f.Len() > MAX_MESSAGE_LEN {
+		return nil, ErrFrameTooLong
+	}
+
+	return AppendFrame(make([]byte, 0, f.Len()), f.Code, f.Data...), nil
+}
+
+// AppendFrame appends to dst the wire representation of a frame with the
+// given code and data, returning the extended buffer. Unlike MarshalBinary,
+// the length of the frame is not validated.
+func AppendFrame(dst []byte, code byte, data ...byte) []byte {
+	dst = append(dst, SOM1, SOM2, byte(len(data)+frameOverhead), code)
+	dst = append(dst, data...)
+	return append(dst, EOM)
+}
+
+// UnmarshalBinary decodes a complete frame, including start and end markers.
+// The data of the resulting frame is a copy of the given buffer.
+func (f *Frame) UnmarshalBinary(b []byte) error {
+	if len(b) < frameOverhead {
+		return ErrFrameTooShort
+	}
+
+	if len(b) > MAX_MESSAGE_LEN {
+		return ErrFrameTooLong
+	}
+
+	if b[0] != SOM1 || b[1] != SOM2 {
+		return ErrBadStart
+	}
+
+	if int(b[2]) != len(b) {
+		return ErrBadLength
+	}
+
+	if b[len(b)-1] != EOM {
+		return ErrBadEnd
+	}
+
+	f.Code = b[3]
+	f.Data = append([]byte(nil), b[4:len(b)-1]...)
+	return nil
+}
+
+// Message is implemented by every command and response of the protocol.
+type Message interface {
+	// Frame returns the raw frame representing the message.
+	Frame() Frame
+	// AppendBinary appends the wire representation of the message to dst,
+	// returning the extended buffer. Reusing dst, messages can be encoded
+	// without any allocation.
+	AppendBinary(dst []byte) []byte
+}
+
+// Marshal returns the wire representation of the given message.
+func Marshal(m Message) ([]byte, error) {
+	b := m.AppendBinary(nil)
+	if len(b) > MAX_MESSAGE_LEN {
+		return nil, ErrFrameTooLong
+	}
+
+	return b, nil
+}
+
+// Describe returns a human readable representation of the message, its code
+// name followed by its fields, eg. "CMD_TRACK_VOLUME {Track:19 Gain:-6}".
+func Describe(m Message) string {
+	if s, ok := m.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	name := CodeString(m.Frame().Code)
+	v := reflect.Indirect(reflect.ValueOf(m))
+	if v.NumField() == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%s %+v", name, v.Interface())
+}
+
+// Unmarshal decodes a complete frame into its command or response struct.
+func Unmarshal(b []byte) (Message, error) {
+	var f Frame
+	if err := f.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return Decode(f)
+}
+
+// Decode interprets the given frame, returning the matching command or
+// response struct.
+func Decode(f Frame) (Message, error) {
+	m := newMessage(f.Code)
+	if m == nil {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCode, f.Code)
+	}
+
+	if err := m.decode(f.Data); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func newMessage(code byte) decoder {
+	if IsResponse(code) {
+		switch Response(code) {
+		case RSP_VERSION_STRING:
+			return &VersionString{}
+		case RSP_SYSTEM_INFO:
+			return &SystemInfo{}
+		case RSP_STATUS:
+			return &Status{}
+		case RSP_TRACK_REPORT:
+			return &TrackReport{}
+		}
+
+		return nil
+	}
+
+	switch Command(code) {
+	case CMD_GET_VERSION:
+		return &GetVersion{}
+	case CMD_GET_SYS_INFO:
+		return &GetSysInfo{}
+	case CMD_TRACK_CONTROL:
+		return &TrackControl{}
+	case CMD_STOP_ALL:
+		return &StopAll{}
+	case CMD_MASTER_VOLUME:
+		return &MasterVolume{}
+	case CMD_GET_STATUS:
+		return &GetStatus{}
+	case CMD_TRACK_VOLUME:
+		return &TrackVolume{}
+	case CMD_TRACK_FADE:
+		return &TrackFade{}
+	case CMD_RESUME_ALL_SYNC:
+		return &ResumeAllSync{}
+	case CMD_SAMPLERATE_OFFSET:
+		return &SamplerateOffset{}
+	case CMD_SET_REPORTING:
+		return &SetReporting{}
+	case CMD_SET_TRIGGER_BANK:
+		return &SetTriggerBank{}
+	case CMD_SET_INPUT_MIX:
+		return &SetInputMix{}
+	case CMD_SET_MIDI_BANK:
+		return &SetMidiBank{}
+	}
+
+	return nil
+}
+
+type decoder interface {
+	Message
+	decode(data []byte) error
+}
+
+// validate checks that the frame matches exactly the format of the decoded
+// message, and that its values are within the valid ranges.
+func validate(f Frame, m Message) error {
+	if n := len(m.AppendBinary(nil)); n != f.Len() {
+		return fmt.Errorf("%w: %d bytes, expected %d", ErrBadLength, f.Len(), n)
+	}
+
+	if r, ok := m.(*TrackReport); ok && r.Voice >= MAX_NUM_VOICES {
+		return fmt.Errorf("%w: %d", ErrInvalidVoice, r.Voice)
+	}
+
+	return nil
+}
+
+// frameOf returns the Frame of a message from its wire representation.
+func frameOf(m Message) Frame {
+	b := m.AppendBinary(nil)
+	return Frame{Code: b[3], Data: b[4 : len(b)-1]}
+}
+
+func checkLen(data []byte, n int) error {
+	if len(data) < n {
+		return ErrFrameTooShort
+	}
+
+	return nil
+}
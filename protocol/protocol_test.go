@@ -0,0 +1,96 @@
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFrameMarshalBinary(t *testing.T) {
+	b, err := Frame{Code: byte(CMD_SET_MIDI_BANK), Data: []byte{2}}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{SOM1, SOM2, 0x06, byte(CMD_SET_MIDI_BANK), 0x02, EOM}
+	if !bytes.Equal(b, expected) {
+		t.Errorf("got % x, expected % x", b, expected)
+	}
+}
+
+func TestFrameMarshalBinaryTooLong(t *testing.T) {
+	_, err := Frame{Data: make([]byte, MAX_MESSAGE_LEN)}.MarshalBinary()
+	if !errors.Is(err, ErrFrameTooLong) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestFrameUnmarshalBinary(t *testing.T) {
+	for _, tc := range []struct {
+		in  []byte
+		err error
+	}{
+		{[]byte{SOM1, SOM2, 0x05, byte(CMD_STOP_ALL), EOM}, nil},
+		{[]byte{SOM1, SOM2, 0x05}, ErrFrameTooShort},
+		{[]byte{SOM1, 0x00, 0x05, byte(CMD_STOP_ALL), EOM}, ErrBadStart},
+		{[]byte{SOM1, SOM2, 0x06, byte(CMD_STOP_ALL), EOM}, ErrBadLength},
+		{[]byte{SOM1, SOM2, 0x05, byte(CMD_STOP_ALL), 0x00}, ErrBadEnd},
+	} {
+		var f Frame
+		if err := f.UnmarshalBinary(tc.in); !errors.Is(err, tc.err) {
+			t.Errorf("% x: got error %v, expected %v", tc.in, err, tc.err)
+		}
+	}
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	for _, m := range []Message{
+		&GetVersion{},
+		&TrackControl{Code: TRK_PLAY_POLY, Track: 1002, Output: 3, Flags: 1},
+		&MasterVolume{Output: 1, Gain: -70},
+		&TrackFade{Track: 19, Gain: -6, Millis: 5000, Stop: true},
+		&VersionString{Version: "Tsunami v1.10s"},
+		&SystemInfo{Voices: 18, Tracks: 4096},
+		&TrackReport{Track: 1, Voice: 3, Playing: true},
+		&Status{Tracks: []uint16{1, 19, 1002}},
+	} {
+		b, err := Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := Unmarshal(b)
+		if err != nil {
+			t.Fatalf("%T: %s", m, err)
+		}
+
+		if !reflect.DeepEqual(out, m) {
+			t.Errorf("%T: got %+v, expected %+v", m, out, m)
+		}
+	}
+}
+
+func TestFrameString(t *testing.T) {
+	m := TrackControl{Code: TRK_PLAY_POLY, Track: 19, Output: 1}
+	if s := m.String(); s != "CMD_TRACK_CONTROL/TRK_PLAY_POLY track=19 output=1 flags=0" {
+		t.Errorf("unexpected string %q", s)
+	}
+
+	if s := (Frame{Code: 0x7f}).String(); s != "Command(127)" {
+		t.Errorf("unexpected string %q", s)
+	}
+
+	if s := (StopAll{}).Frame().String(); s != "CMD_STOP_ALL" {
+		t.Errorf("unexpected string %q", s)
+	}
+}
+
+func BenchmarkAppendBinary(b *testing.B) {
+	buf := make([]byte, 0, MAX_MESSAGE_LEN)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = TrackVolume{Track: 19, Gain: int16(-(i % 70))}.AppendBinary(buf[:0])
+	}
+}
@@ -0,0 +1,122 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// VersionString is the response to GetVersion.
+type VersionString struct {
+	Version string
+}
+
+func (m VersionString) Frame() Frame { return frameOf(m) }
+
+func (m VersionString) AppendBinary(dst []byte) []byte {
+	var data [VERSION_STRING_LEN - 1]byte
+	copy(data[:], m.Version)
+
+	return AppendFrame(dst, byte(RSP_VERSION_STRING), data[:]...)
+}
+
+// decode accepts truncated version strings, they are rejected only by the
+// strict mode of the Decoder.
+func (m *VersionString) decode(data []byte) error {
+	if len(data) > VERSION_STRING_LEN-1 {
+		data = data[:VERSION_STRING_LEN-1]
+	}
+
+	m.Version = strings.TrimRight(string(data), "\x00")
+	return nil
+}
+
+// SystemInfo is the response to GetSysInfo.
+type SystemInfo struct {
+	Voices uint8
+	Tracks uint16
+}
+
+func (m SystemInfo) Frame() Frame { return frameOf(m) }
+
+func (m SystemInfo) AppendBinary(dst []byte) []byte {
+	var data [3]byte
+	data[0] = m.Voices
+	putUint16(data[1:], m.Tracks)
+
+	return AppendFrame(dst, byte(RSP_SYSTEM_INFO), data[:]...)
+}
+
+func (m *SystemInfo) decode(data []byte) error {
+	if err := checkLen(data, 3); err != nil {
+		return err
+	}
+
+	m.Voices = data[0]
+	m.Tracks = uint16At(data[1:])
+	return nil
+}
+
+// TrackReport is sent by the board, when reporting is enabled, every time a
+// track starts or stops. The firmware reports zero-based track numbers, Track
+// is adjusted to match the numbering used by the commands.
+type TrackReport struct {
+	Track   uint16
+	Voice   uint8
+	Playing bool
+}
+
+func (m TrackReport) Frame() Frame { return frameOf(m) }
+
+func (m TrackReport) AppendBinary(dst []byte) []byte {
+	var data [4]byte
+	putUint16(data[0:], m.Track-1)
+	data[2] = m.Voice
+	data[3] = boolByte(m.Playing)
+
+	return AppendFrame(dst, byte(RSP_TRACK_REPORT), data[:]...)
+}
+
+func (m *TrackReport) decode(data []byte) error {
+	if err := checkLen(data, 4); err != nil {
+		return err
+	}
+
+	m.Track = uint16At(data[0:]) + 1
+	m.Voice = data[2]
+	m.Playing = data[3] != 0
+	return nil
+}
+
+// Status lists the tracks currently playing. Track numbers are adjusted like
+// in TrackReport.
+type Status struct {
+	Tracks []uint16
+}
+
+func (m Status) Frame() Frame { return frameOf(m) }
+
+func (m Status) AppendBinary(dst []byte) []byte {
+	start := len(dst)
+	dst = AppendFrame(dst, byte(RSP_STATUS))
+	dst = dst[:len(dst)-1]
+	for _, t := range m.Tracks {
+		dst = binary.LittleEndian.AppendUint16(dst, t-1)
+	}
+
+	dst = append(dst, EOM)
+	dst[start+2] = byte(len(dst) - start)
+	return dst
+}
+
+func (m *Status) decode(data []byte) error {
+	if len(data)%2 != 0 {
+		return ErrFrameTooShort
+	}
+
+	m.Tracks = make([]uint16, 0, len(data)/2)
+	for i := 0; i < len(data); i += 2 {
+		m.Tracks = append(m.Tracks, uint16At(data[i:])+1)
+	}
+
+	return nil
+}
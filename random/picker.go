@@ -0,0 +1,123 @@
+// Package random picks tracks at random for ambient and game sounds,
+// avoiding the repetitions a plain random choice produces.
+package random
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"time"
+)
+
+// ErrNoTracks is returned when there are no tracks to pick from.
+var ErrNoTracks = errors.New("no tracks to pick from")
+
+// Picker picks tracks at random, guaranteeing that a track isn't repeated
+// within the last Window picks. Its state can be saved and restored, so the
+// history survives restarts and a program doesn't start with the same track
+// every time it's launched.
+type Picker struct {
+	tracks []int
+	window int
+	recent []int
+	rand   *rand.Rand
+}
+
+// PickerState is the persistent state of a Picker.
+type PickerState struct {
+	// Recent are the last tracks picked, the newest last.
+	Recent []int `json:"recent"`
+}
+
+// NewPicker returns a picker of the given tracks, not repeating any of the
+// last window picks. The window is limited to the number of tracks minus one.
+func NewPicker(tracks []int, window int) *Picker {
+	if window >= len(tracks) {
+		window = len(tracks) - 1
+	}
+
+	if window < 0 {
+		window = 0
+	}
+
+	return &Picker{
+		tracks: tracks,
+		window: window,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Seed seeds the random generator, making the picks deterministic.
+func (p *Picker) Seed(seed int64) {
+	p.rand.Seed(seed)
+}
+
+// Next returns the next track.
+func (p *Picker) Next() (int, error) {
+	if len(p.tracks) == 0 {
+		return 0, ErrNoTracks
+	}
+
+	excluded := make(map[int]bool, len(p.recent))
+	for _, trk := range p.recent {
+		excluded[trk] = true
+	}
+
+	candidates := make([]int, 0, len(p.tracks))
+	for _, trk := range p.tracks {
+		if !excluded[trk] {
+			candidates = append(candidates, trk)
+		}
+	}
+
+	// the tracks may be repeated in the list, or the restored history may
+	// not match them.
+	if len(candidates) == 0 {
+		candidates = p.tracks
+	}
+
+	trk := candidates[p.rand.Intn(len(candidates))]
+	p.remember(trk)
+	return trk, nil
+}
+
+func (p *Picker) remember(trk int) {
+	if p.window == 0 {
+		return
+	}
+
+	p.recent = append(p.recent, trk)
+	if len(p.recent) > p.window {
+		p.recent = p.recent[len(p.recent)-p.window:]
+	}
+}
+
+// State returns the state of the picker.
+func (p *Picker) State() PickerState {
+	return PickerState{Recent: append([]int(nil), p.recent...)}
+}
+
+// Restore restores a state returned by State.
+func (p *Picker) Restore(s PickerState) {
+	p.recent = nil
+	for _, trk := range s.Recent {
+		p.remember(trk)
+	}
+}
+
+// Save writes the state of the picker as JSON.
+func (p *Picker) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(p.State())
+}
+
+// Load reads a state written by Save.
+func (p *Picker) Load(r io.Reader) error {
+	var s PickerState
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return err
+	}
+
+	p.Restore(s)
+	return nil
+}
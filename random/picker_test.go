@@ -0,0 +1,54 @@
+package random
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPickerWindow(t *testing.T) {
+	p := NewPicker([]int{1, 2, 3, 4, 5}, 3)
+	p.Seed(1)
+
+	var picks []int
+	for i := 0; i < 1000; i++ {
+		trk, err := p.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for j := len(picks) - 3; j >= 0 && j < len(picks); j++ {
+			if picks[j] == trk {
+				t.Fatalf("track %d repeated within the window: %v", trk, picks[len(picks)-3:])
+			}
+		}
+
+		picks = append(picks, trk)
+	}
+}
+
+func TestPickerSaveLoad(t *testing.T) {
+	p := NewPicker([]int{1, 2, 3}, 5)
+	first, _ := p.Next()
+	second, _ := p.Next()
+
+	buf := bytes.NewBuffer(nil)
+	if err := p.Save(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewPicker([]int{1, 2, 3}, 5)
+	if err := restored.Load(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	next, _ := restored.Next()
+	if next == first || next == second {
+		t.Errorf("restored picker should avoid the history, got %d after %d, %d", next, first, second)
+	}
+}
+
+func TestPickerEmpty(t *testing.T) {
+	if _, err := NewPicker(nil, 2).Next(); err != ErrNoTracks {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package random
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// ErrCoolingDown is returned by Trigger.Fire when every sound is cooling
+// down.
+var ErrCoolingDown = errors.New("every sound is cooling down")
+
+// Sound is a track of a Trigger.
+type Sound struct {
+	Track int
+	// Weight is the relative probability of the sound being picked, a
+	// sound weighting 1 among others weighting 19 is picked 5% of the time.
+	// Sounds without weight are never picked.
+	Weight float64
+	// Cooldown is the time after the sound is played during which it isn't
+	// picked again.
+	Cooldown time.Duration
+}
+
+// Trigger plays one of a set of sounds, picked at random according to their
+// weights, like one of many monster growls where the rare one only sounds
+// once in a while.
+type Trigger struct {
+	p      tsunami.Player
+	out    int
+	sounds []Sound
+
+	mu     sync.Mutex
+	rand   *rand.Rand
+	played map[int]time.Time
+	now    func() time.Time
+}
+
+// NewTrigger returns a trigger playing the given sounds on the output.
+func NewTrigger(p tsunami.Player, out int, sounds []Sound) *Trigger {
+	return &Trigger{
+		p:      p,
+		out:    out,
+		sounds: sounds,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		played: make(map[int]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Seed seeds the random generator, making the picks deterministic.
+func (t *Trigger) Seed(seed int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rand.Seed(seed)
+}
+
+// Fire picks a sound and plays it, returning its track. The sounds cooling
+// down are skipped, if all of them are ErrCoolingDown is returned.
+func (t *Trigger) Fire() (int, error) {
+	trk, err := t.Pick()
+	if err != nil {
+		return 0, err
+	}
+
+	return trk, t.p.TrackPlayPoly(trk, t.out, false)
+}
+
+// Pick picks a sound like Fire, starting its cooldown, but without playing
+// it, so the caller can play it its own way.
+func (t *Trigger) Pick() (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.pick()
+	if !ok {
+		return 0, ErrCoolingDown
+	}
+
+	t.played[s.Track] = t.now()
+	return s.Track, nil
+}
+
+// Reset clears the cooldowns.
+func (t *Trigger) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.played = make(map[int]time.Time)
+}
+
+func (t *Trigger) pick() (Sound, bool) {
+	now := t.now()
+
+	var total float64
+	available := make([]Sound, 0, len(t.sounds))
+	for _, s := range t.sounds {
+		if s.Weight <= 0 {
+			continue
+		}
+
+		if at, ok := t.played[s.Track]; ok && now.Sub(at) < s.Cooldown {
+			continue
+		}
+
+		available = append(available, s)
+		total += s.Weight
+	}
+
+	if len(available) == 0 {
+		return Sound{}, false
+	}
+
+	x := t.rand.Float64() * total
+	for _, s := range available {
+		if x < s.Weight {
+			return s, true
+		}
+
+		x -= s.Weight
+	}
+
+	// rounding may leave x just above the last weight.
+	return available[len(available)-1], true
+}
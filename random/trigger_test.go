@@ -0,0 +1,77 @@
+package random
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestTriggerWeights(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	trg := NewTrigger(tsunami.NewTsunamiTransport(port), 2, []Sound{
+		{Track: 1, Weight: 19},
+		{Track: 2, Weight: 1},
+		{Track: 3},
+	})
+	trg.Seed(1)
+
+	counts := make(map[int]int)
+	for i := 0; i < 2000; i++ {
+		trk, err := trg.Fire()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts[trk]++
+	}
+
+	if counts[3] != 0 {
+		t.Errorf("sound without weight picked %d times", counts[3])
+	}
+
+	if counts[2] < 50 || counts[2] > 150 {
+		t.Errorf("rare sound picked %d times out of 2000", counts[2])
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, ok := msgs[0].(*protocol.TrackControl)
+	if !ok || c.Code != tsunami.TRK_PLAY_POLY || c.Output != 2 {
+		t.Errorf("unexpected message %#v", msgs[0])
+	}
+}
+
+func TestTriggerCooldown(t *testing.T) {
+	now := time.Now()
+	trg := NewTrigger(tsunami.NewTsunamiTransport(transport.NewLoopback(nil)), 0, []Sound{
+		{Track: 1, Weight: 1, Cooldown: time.Second},
+		{Track: 2, Weight: 1, Cooldown: time.Second},
+	})
+	trg.now = func() time.Time { return now }
+
+	first, _ := trg.Fire()
+	second, _ := trg.Fire()
+	if first == second {
+		t.Fatalf("track %d played during its cooldown", first)
+	}
+
+	if _, err := trg.Fire(); err != ErrCoolingDown {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if _, err := trg.Fire(); err != nil {
+		t.Fatal(err)
+	}
+
+	trg.Reset()
+	if _, err := trg.Fire(); err != nil {
+		t.Fatal(err)
+	}
+}
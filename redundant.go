@@ -0,0 +1,249 @@
+package tsunami
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Redundant drives a primary and a backup board loaded with identical SD
+// cards. Every command is mirrored to both boards, with the outputs of the
+// backup muted, so when the primary fails the backup is unmuted and the show
+// continues where it was. The failure of the primary is detected by the
+// errors of the commands sent to it, and by Monitor.
+//
+// While the primary is active, the errors of the backup are ignored, and the
+// queries are answered by the primary.
+type Redundant struct {
+	primary, backup Player
+
+	mu         sync.Mutex
+	failed     bool
+	gains      [MaxOutputs]Gain
+	onFailover []func(error)
+}
+
+var _ Player = (*Redundant)(nil)
+
+// NewRedundant returns a Redundant pair of the given boards.
+func NewRedundant(primary, backup Player) *Redundant {
+	return &Redundant{primary: primary, backup: backup}
+}
+
+// OnFailover registers a function to be called when the backup takes over,
+// with the error of the primary causing it.
+func (r *Redundant) OnFailover(fn func(error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onFailover = append(r.onFailover, fn)
+}
+
+// Failed reports whether the primary failed and the backup is active.
+func (r *Redundant) Failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.failed
+}
+
+// Failover switches to the backup, unmuting it. It's called automatically
+// when the primary fails, err is the cause passed to the OnFailover
+// functions.
+func (r *Redundant) Failover(err error) error {
+	r.mu.Lock()
+	if r.failed {
+		r.mu.Unlock()
+		return nil
+	}
+
+	r.failed = true
+	gains, handlers := r.gains, r.onFailover
+	r.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(err)
+	}
+
+	for out, gain := range gains {
+		if err := r.backup.MasterGain(out, gain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Monitor checks the primary every interval, until the context is done,
+// failing over to the backup if it doesn't answer to a status request within
+// the interval. Only boards implementing Status, like Tsunami, are checked.
+func (r *Redundant) Monitor(ctx context.Context, interval time.Duration) error {
+	s, ok := r.primary.(interface {
+		Status(context.Context) ([]int, error)
+	})
+
+	if !ok {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for !r.Failed() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		cctx, cancel := context.WithTimeout(ctx, interval)
+		_, err := s.Status(cctx)
+		cancel()
+
+		if err != nil && ctx.Err() == nil {
+			return r.Failover(err)
+		}
+	}
+
+	return nil
+}
+
+// do runs fn on both boards, returning the error of the active one.
+func (r *Redundant) do(fn func(Player) error) error {
+	if !r.Failed() {
+		if err := fn(r.primary); err != nil {
+			if ferr := r.Failover(err); ferr != nil {
+				return ferr
+			}
+
+			return fn(r.backup)
+		}
+
+		fn(r.backup)
+		return nil
+	}
+
+	return fn(r.backup)
+}
+
+func (r *Redundant) active() Player {
+	if r.Failed() {
+		return r.backup
+	}
+
+	return r.primary
+}
+
+// Start starts both boards and mutes the outputs of the backup.
+func (r *Redundant) Start() error {
+	if err := r.do(Player.Start); err != nil {
+		return err
+	}
+
+	if r.Failed() {
+		return nil
+	}
+
+	for out := 0; out < MaxOutputs; out++ {
+		r.backup.MasterGain(out, MinGain)
+	}
+
+	return nil
+}
+
+// MasterGain sets the gain of the output on the active board, the backup is
+// kept muted until it's needed.
+func (r *Redundant) MasterGain(out int, gain Gain) error {
+	r.mu.Lock()
+	if out >= 0 && out < MaxOutputs {
+		r.gains[out] = gain
+	}
+	r.mu.Unlock()
+
+	return r.do(func(p Player) error {
+		if p == r.backup && !r.Failed() {
+			return p.MasterGain(out, MinGain)
+		}
+
+		return p.MasterGain(out, gain)
+	})
+}
+
+func (r *Redundant) TrackPlaySolo(trk, out int, lock bool) error {
+	return r.do(func(p Player) error { return p.TrackPlaySolo(trk, out, lock) })
+}
+
+func (r *Redundant) TrackPlayPoly(trk, out int, lock bool) error {
+	return r.do(func(p Player) error { return p.TrackPlayPoly(trk, out, lock) })
+}
+
+func (r *Redundant) TrackLoad(trk, out int, lock bool) error {
+	return r.do(func(p Player) error { return p.TrackLoad(trk, out, lock) })
+}
+
+func (r *Redundant) TrackStop(trk int) error {
+	return r.do(func(p Player) error { return p.TrackStop(trk) })
+}
+
+func (r *Redundant) TrackPause(trk int) error {
+	return r.do(func(p Player) error { return p.TrackPause(trk) })
+}
+
+func (r *Redundant) TrackResume(trk int) error {
+	return r.do(func(p Player) error { return p.TrackResume(trk) })
+}
+
+func (r *Redundant) TrackLoop(trk int, enable bool) error {
+	return r.do(func(p Player) error { return p.TrackLoop(trk, enable) })
+}
+
+func (r *Redundant) TrackGain(trk int, gain Gain) error {
+	return r.do(func(p Player) error { return p.TrackGain(trk, gain) })
+}
+
+func (r *Redundant) TrackFade(trk int, gain Gain, d time.Duration, stopFlag bool) error {
+	return r.do(func(p Player) error { return p.TrackFade(trk, gain, d, stopFlag) })
+}
+
+func (r *Redundant) StopAllTracks() error {
+	return r.do(Player.StopAllTracks)
+}
+
+func (r *Redundant) ResumeAllInSync() error {
+	return r.do(Player.ResumeAllInSync)
+}
+
+func (r *Redundant) SamplerateOffset(out, offset int) error {
+	return r.do(func(p Player) error { return p.SamplerateOffset(out, offset) })
+}
+
+func (r *Redundant) SetReporting(enable bool) error {
+	return r.do(func(p Player) error { return p.SetReporting(enable) })
+}
+
+func (r *Redundant) SetTriggerBank(bank int) error {
+	return r.do(func(p Player) error { return p.SetTriggerBank(bank) })
+}
+
+func (r *Redundant) IsTrackPlaying(trk int) bool {
+	return r.active().IsTrackPlaying(trk)
+}
+
+func (r *Redundant) GetVersion() string {
+	return r.active().GetVersion()
+}
+
+func (r *Redundant) GetNumTracks() int {
+	return r.active().GetNumTracks()
+}
+
+// Close closes both boards, returning the first error found.
+func (r *Redundant) Close() error {
+	err := r.primary.Close()
+	if berr := r.backup.Close(); err == nil {
+		err = berr
+	}
+
+	return err
+}
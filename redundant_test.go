@@ -0,0 +1,62 @@
+package tsunami
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+type failingPort struct {
+	bufferPort
+	fail bool
+}
+
+func (p *failingPort) Write(b []byte) (int, error) {
+	if p.fail {
+		return 0, errors.New("unplugged")
+	}
+
+	return p.bufferPort.Write(b)
+}
+
+func TestRedundant(t *testing.T) {
+	primary, backup := &failingPort{}, &bufferPort{}
+	r := NewRedundant(NewTsunamiTransport(primary), NewTsunamiTransport(backup))
+
+	var cause error
+	r.OnFailover(func(err error) { cause = err })
+
+	if err := r.MasterGain(1, -6); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.TrackPlayPoly(19, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected []byte
+	expected = protocol.MasterVolume{Output: 1, Gain: MinGain}.AppendBinary(expected)
+	expected = protocol.TrackControl{Code: TRK_PLAY_POLY, Track: 19, Output: 1}.AppendBinary(expected)
+	if !bytes.Equal(backup.tx, expected) {
+		t.Errorf("backup should be muted, got % x", backup.tx)
+	}
+
+	primary.fail, backup.tx = true, nil
+	if err := r.TrackStop(19); err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.Failed() || cause == nil {
+		t.Fatalf("primary failure should fail over, cause %v", cause)
+	}
+
+	if !bytes.Contains(backup.tx, protocol.MasterVolume{Output: 1, Gain: -6}.AppendBinary(nil)) {
+		t.Errorf("backup should be unmuted, got % x", backup.tx)
+	}
+
+	if !bytes.HasSuffix(backup.tx, protocol.TrackControl{Code: TRK_STOP, Track: 19}.AppendBinary(nil)) {
+		t.Errorf("command should be sent to the backup, got % x", backup.tx)
+	}
+}
@@ -0,0 +1,149 @@
+package tsunami
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Scene is a snapshot of the mixer settings of a board: gains, loop flags,
+// samplerate offsets and banks. Only the settings changed through the
+// library are known, the rest are left out. Scenes can be serialized to JSON
+// to be stored, and loaded back with SetScene.
+type Scene struct {
+	MasterGains map[int]Gain `json:"master_gains,omitempty"`
+	TrackGains  map[int]Gain `json:"track_gains,omitempty"`
+	Loops       map[int]bool `json:"loops,omitempty"`
+	Offsets     map[int]int  `json:"samplerate_offsets,omitempty"`
+	TriggerBank int          `json:"trigger_bank,omitempty"`
+	MidiBank    int          `json:"midi_bank,omitempty"`
+}
+
+// Snapshot captures the current settings into a scene stored with the given
+// name, replacing any scene with the same name, and returns it.
+func (t *Tsunami) Snapshot(name string) Scene {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := Scene{
+		MasterGains: make(map[int]Gain, MaxOutputs),
+		TrackGains:  make(map[int]Gain, len(t.trackGains)),
+		Loops:       make(map[int]bool, len(t.loops)),
+		Offsets:     make(map[int]int, MaxOutputs),
+		TriggerBank: t.triggerBank,
+		MidiBank:    t.midiBank,
+	}
+
+	for out := 0; out < MaxOutputs; out++ {
+		s.MasterGains[out] = t.masterGains[out]
+		s.Offsets[out] = t.offsets[out]
+	}
+
+	for trk, gain := range t.trackGains {
+		s.TrackGains[trk] = gain
+	}
+
+	for trk, loop := range t.loops {
+		s.Loops[trk] = loop
+	}
+
+	t.setScene(name, s)
+	return s
+}
+
+// SetScene stores the scene with the given name, eg. a scene loaded from a
+// file, so it can be recalled with Recall.
+func (t *Tsunami) SetScene(name string, s Scene) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.setScene(name, s)
+}
+
+func (t *Tsunami) setScene(name string, s Scene) {
+	if t.scenes == nil {
+		t.scenes = make(map[string]Scene)
+	}
+
+	t.scenes[name] = s
+}
+
+// Scenes returns the sorted names of the scenes stored.
+func (t *Tsunami) Scenes() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names := make([]string, 0, len(t.scenes))
+	for name := range t.scenes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Recall applies the scene with the given name, see Apply.
+func (t *Tsunami) Recall(name string, fade time.Duration) error {
+	t.mu.Lock()
+	s, ok := t.scenes[name]
+	t.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("unknown scene %q", name)
+	}
+
+	return t.Apply(s, fade)
+}
+
+// Apply sends the settings of the scene to the board. If fade is not zero,
+// the gains are faded in software from their current values during the
+// given duration, the rest of the settings are applied immediately.
+func (t *Tsunami) Apply(s Scene, fade time.Duration) error {
+	for _, out := range sortedKeys(s.MasterGains) {
+		var err error
+		if fade > 0 {
+			err = t.MasterFade(out, s.MasterGains[out], fade)
+		} else {
+			err = t.MasterGain(out, s.MasterGains[out])
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, trk := range sortedKeys(s.TrackGains) {
+		if fade > 0 {
+			t.fader.Track(trk, Fade{From: t.TrackGainOf(trk), To: s.TrackGains[trk], Duration: fade})
+			continue
+		}
+
+		if err := t.TrackGain(trk, s.TrackGains[trk]); err != nil {
+			return err
+		}
+	}
+
+	for _, trk := range sortedKeys(s.Loops) {
+		if err := t.TrackLoop(trk, s.Loops[trk]); err != nil {
+			return err
+		}
+	}
+
+	for _, out := range sortedKeys(s.Offsets) {
+		if err := t.SamplerateOffset(out, s.Offsets[out]); err != nil {
+			return err
+		}
+	}
+
+	if s.TriggerBank != 0 {
+		if err := t.SetTriggerBank(s.TriggerBank); err != nil {
+			return err
+		}
+	}
+
+	if s.MidiBank != 0 {
+		return t.SetMidiBank(s.MidiBank)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package tsunami
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnapshotRecall(t *testing.T) {
+	ts := newTestTsunami()
+	ts.MasterGain(1, -6)
+	ts.TrackGain(19, -3)
+	ts.TrackLoop(19, true)
+	ts.SetTriggerBank(2)
+
+	s := ts.Snapshot("act1")
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded Scene
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(loaded, s) {
+		t.Errorf("scene should survive a JSON round trip, got %+v", loaded)
+	}
+
+	ts.MasterGain(1, 0)
+	ts.TrackGain(19, MinGain)
+	ts.TrackLoop(19, false)
+	ts.SetTriggerBank(3)
+
+	if err := ts.Recall("act1", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(ts.Snapshot("current"), s) {
+		t.Errorf("scene should be restored")
+	}
+
+	ts.TrackGain(19, MinGain)
+	if err := ts.Recall("act1", 5*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	ts.fader.Wait()
+	if g := ts.TrackGainOf(19); g != -3 {
+		t.Errorf("gain should be faded to the scene, got %s", g)
+	}
+
+	if err := ts.Recall("act2", 0); err == nil {
+		t.Errorf("expected error on unknown scene")
+	}
+}
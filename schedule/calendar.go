@@ -0,0 +1,114 @@
+package schedule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Calendar is a set of dates, like the holidays of a venue.
+type Calendar struct {
+	dates map[string]bool
+	// yearly are the dates repeated every year, as month and day.
+	yearly map[string]bool
+}
+
+// NewCalendar returns a calendar of the given dates.
+func NewCalendar(dates ...time.Time) *Calendar {
+	c := &Calendar{dates: make(map[string]bool), yearly: make(map[string]bool)}
+	for _, d := range dates {
+		c.Add(d)
+	}
+
+	return c
+}
+
+// Add adds a date to the calendar.
+func (c *Calendar) Add(date time.Time) {
+	c.dates[date.Format("2006-01-02")] = true
+}
+
+// AddYearly adds a date repeated every year, like Christmas.
+func (c *Calendar) AddYearly(month time.Month, day int) {
+	c.yearly[fmt.Sprintf("%02d-%02d", month, day)] = true
+}
+
+// Contains reports whether the day of t is in the calendar, in the location
+// of t.
+func (c *Calendar) Contains(t time.Time) bool {
+	return c.dates[t.Format("2006-01-02")] || c.yearly[t.Format("01-02")]
+}
+
+// ReadCalendar reads a calendar with a date per line, as YYYY-MM-DD, or
+// MM-DD for the dates repeated every year. Empty lines and the text after a
+// # are ignored, so the dates can be named:
+//
+//	12-25        # christmas
+//	2024-04-01   # easter monday
+func ReadCalendar(r io.Reader) (*Calendar, error) {
+	c := NewCalendar()
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		if d, err := time.Parse("2006-01-02", text); err == nil {
+			c.Add(d)
+			continue
+		}
+
+		d, err := time.Parse("01-02", text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, text)
+		}
+
+		c.AddYearly(d.Month(), d.Day())
+	}
+
+	return c, s.Err()
+}
+
+// Except is a Spec firing as another one, except the days of a calendar,
+// like a closing announcement not played on holidays.
+type Except struct {
+	Spec     Spec
+	Calendar *Calendar
+}
+
+// Next implements Spec.
+func (e Except) Next(after time.Time) time.Time {
+	return filterDays(e.Spec, after, func(t time.Time) bool { return !e.Calendar.Contains(t) })
+}
+
+// Only is a Spec firing as another one, only the days of a calendar.
+type Only struct {
+	Spec     Spec
+	Calendar *Calendar
+}
+
+// Next implements Spec.
+func (o Only) Next(after time.Time) time.Time {
+	return filterDays(o.Spec, after, o.Calendar.Contains)
+}
+
+// filterDays returns the next firing of the spec a day accepted by the
+// filter, giving up after some years.
+func filterDays(spec Spec, after time.Time, accept func(time.Time) bool) time.Time {
+	limit := after.AddDate(5, 0, 0)
+	for t := spec.Next(after); !t.IsZero() && t.Before(limit); t = spec.Next(t) {
+		if accept(t) {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
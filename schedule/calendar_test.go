@@ -0,0 +1,29 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendar(t *testing.T) {
+	c, err := ReadCalendar(strings.NewReader("# holidays\n12-25 # christmas\n\n2024-12-26\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daily, _ := ParseCron("55 17 * * *")
+	base := time.Date(2024, 12, 24, 18, 0, 0, 0, time.UTC)
+
+	if next := (Except{Spec: daily, Calendar: c}).Next(base); next.Day() != 27 {
+		t.Errorf("holidays should be skipped, got %s", next)
+	}
+
+	if next := (Only{Spec: daily, Calendar: c}).Next(base.AddDate(0, 0, 3)); next.Format("2006-01-02") != "2025-12-25" {
+		t.Errorf("only yearly holiday expected, got %s", next)
+	}
+
+	if _, err := ReadCalendar(strings.NewReader("tomorrow\n")); err == nil {
+		t.Errorf("invalid date should fail")
+	}
+}
@@ -0,0 +1,63 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// a wednesday.
+	base := time.Date(2024, 1, 10, 17, 54, 30, 0, time.UTC)
+	for expr, expected := range map[string]string{
+		"55 17 * * *":       "2024-01-10 17:55",
+		"0 * * * *":         "2024-01-10 18:00",
+		"*/15 9-17 * * *":   "2024-01-11 09:00",
+		"0 12 * * mon,fri":  "2024-01-12 12:00",
+		"0 0 1 jan *":       "2025-01-01 00:00",
+		"30 8 15 * 7":       "2024-01-14 08:30",
+		"0 10 29 feb *":     "2024-02-29 10:00",
+		"@hourly":           "2024-01-10 18:00",
+		"54 17 10 1 *":      "2025-01-10 17:54",
+		"0 6-18/6 * * 1-5":  "2024-01-10 18:00",
+		"59 23 31 dec *":    "2024-12-31 23:59",
+		"0 0 * * sun":       "2024-01-14 00:00",
+		"*/20 * * * sat":    "2024-01-13 00:00",
+		"5,10 18 10-11 1 *": "2024-01-10 18:05",
+	} {
+		c, err := ParseCron(expr)
+		if err != nil {
+			t.Errorf("%s: %v", expr, err)
+			continue
+		}
+
+		if next := c.Next(base).Format("2006-01-02 15:04"); next != expected {
+			t.Errorf("%s: got %s, expected %s", expr, next, expected)
+		}
+	}
+
+	c, _ := ParseCron("0 0 31 2 *")
+	if next := c.Next(base); !next.IsZero() {
+		t.Errorf("impossible date should never fire, got %s", next)
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "* * * foo *"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("%q should fail", expr)
+		}
+	}
+}
+
+func TestEvery(t *testing.T) {
+	loc := time.FixedZone("IST", 5*3600+1800)
+	base := time.Date(2024, 1, 10, 17, 54, 30, 0, loc)
+
+	if next := Every(time.Hour).Next(base); !next.Equal(time.Date(2024, 1, 10, 18, 0, 0, 0, loc)) {
+		t.Errorf("unexpected next %s", next)
+	}
+
+	if next := Every(30 * time.Second).Next(base); !next.Equal(base.Add(30 * time.Second)) {
+		t.Errorf("unexpected next on a boundary %s", next)
+	}
+}
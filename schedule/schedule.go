@@ -0,0 +1,274 @@
+// Package schedule fires tracks and cues at configured times of the wall
+// clock, like chimes at the top of the hour or the closing announcement of a
+// venue at 17:55.
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultGrace is how late an entry may fire before it's considered missed.
+const DefaultGrace = time.Minute
+
+// Policy is what a Scheduler does with the firings of an entry missed, while
+// the program wasn't running or the host was suspended.
+type Policy uint8
+
+const (
+	// Skip ignores the firings missed.
+	Skip Policy = iota
+	// CatchUp fires the entry once, no matter how many firings were missed.
+	CatchUp
+)
+
+var policyNames = map[Policy]string{
+	Skip:    "skip",
+	CatchUp: "catch-up",
+}
+
+func (p Policy) String() string {
+	if name, ok := policyNames[p]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Policy(%d)", uint8(p))
+}
+
+// Entry is something to do at the times of a Spec.
+type Entry struct {
+	Name string
+	Spec Spec
+	// From and Until limit the entry to a range of dates, unbounded if
+	// zero.
+	From, Until time.Time
+	Policy      Policy
+	Fire        func() error
+}
+
+// next returns the next firing of the entry after t within its date range,
+// the zero time if none.
+func (e *Entry) next(t time.Time) time.Time {
+	if !e.From.IsZero() && t.Before(e.From) {
+		t = e.From.Add(-time.Nanosecond)
+	}
+
+	next := e.Spec.Next(t)
+	if next.IsZero() || (!e.Until.IsZero() && next.After(e.Until)) {
+		return time.Time{}
+	}
+
+	// stripping the monotonic reading, the wait is measured on the wall
+	// clock.
+	return next.Round(0)
+}
+
+// State is the persistent state of a Scheduler, the last firing of every
+// entry by name, so the firings missed while the program wasn't running are
+// known on restart.
+type State map[string]time.Time
+
+// Scheduler fires entries at the times of their specs.
+type Scheduler struct {
+	// Grace is how late an entry may fire before it's considered missed,
+	// DefaultGrace if zero.
+	Grace time.Duration
+
+	mu      sync.Mutex
+	entries []*Entry
+	last    State
+	onError []func(*Entry, error)
+	now     func() time.Time
+}
+
+// New returns an empty scheduler.
+func New() *Scheduler {
+	return &Scheduler{last: make(State), now: time.Now}
+}
+
+// Add adds an entry to the scheduler, the names must be unique for the state
+// to be restored.
+func (s *Scheduler) Add(e Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, &e)
+}
+
+// OnError registers a function to be called with the errors of the entries
+// fired.
+func (s *Scheduler) OnError(fn func(*Entry, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onError = append(s.onError, fn)
+}
+
+// State returns the state of the scheduler.
+func (s *Scheduler) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := make(State, len(s.last))
+	for name, t := range s.last {
+		st[name] = t
+	}
+
+	return st
+}
+
+// Restore restores a state returned by State, before calling Run.
+func (s *Scheduler) Restore(st State) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, t := range st {
+		s.last[name] = t
+	}
+}
+
+// Save writes the state of the scheduler as JSON.
+func (s *Scheduler) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s.State())
+}
+
+// Load reads a state written by Save.
+func (s *Scheduler) Load(r io.Reader) error {
+	var st State
+	if err := json.NewDecoder(r).Decode(&st); err != nil {
+		return err
+	}
+
+	s.Restore(st)
+	return nil
+}
+
+// Upcoming returns the next firing of every entry, sorted by time, the
+// entries not firing anymore are left out.
+func (s *Scheduler) Upcoming() []Firing {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var firings []Firing
+	for _, e := range s.entries {
+		if t := e.next(now); !t.IsZero() {
+			firings = append(firings, Firing{Entry: e, At: t})
+		}
+	}
+
+	sort.SliceStable(firings, func(i, j int) bool { return firings[i].At.Before(firings[j].At) })
+	return firings
+}
+
+// Firing is a future firing of an entry.
+type Firing struct {
+	Entry *Entry
+	At    time.Time
+}
+
+// Run fires the entries until the context is done. On start, the entries
+// with the CatchUp policy whose firings were missed since the state
+// restored, are fired once.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	now := s.now()
+	next := make(map[*Entry]time.Time, len(s.entries))
+	for _, e := range s.entries {
+		if last, ok := s.last[e.Name]; ok && e.Policy == CatchUp {
+			if missed := e.next(last); !missed.IsZero() && !missed.After(now) {
+				next[e] = missed
+				continue
+			}
+		}
+
+		next[e] = e.next(now)
+	}
+	s.mu.Unlock()
+
+	for {
+		e, at := earliest(next)
+		if e == nil {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		if err := sleepUntil(ctx, at); err != nil {
+			return err
+		}
+
+		now := s.now()
+		if now.Sub(at) <= s.grace() || e.Policy == CatchUp {
+			s.fire(e, now)
+		}
+
+		next[e] = e.next(now)
+	}
+}
+
+func (s *Scheduler) grace() time.Duration {
+	if s.Grace == 0 {
+		return DefaultGrace
+	}
+
+	return s.Grace
+}
+
+func (s *Scheduler) fire(e *Entry, now time.Time) {
+	s.mu.Lock()
+	s.last[e.Name] = now
+	handlers := s.onError
+	s.mu.Unlock()
+
+	if err := e.Fire(); err != nil {
+		for _, fn := range handlers {
+			fn(e, err)
+		}
+	}
+}
+
+// earliest returns the entry firing first, nil if none fires anymore.
+func earliest(next map[*Entry]time.Time) (*Entry, time.Time) {
+	var first *Entry
+	var at time.Time
+	for e, t := range next {
+		if t.IsZero() {
+			continue
+		}
+
+		if first == nil || t.Before(at) {
+			first, at = e, t
+		}
+	}
+
+	return first, at
+}
+
+// sleepUntil waits until the given time of the wall clock or until the
+// context is done. The wall clock is checked regularly, so a change of it,
+// or a suspension of the host, is noticed.
+func sleepUntil(ctx context.Context, t time.Time) error {
+	for {
+		d := time.Until(t)
+		if d <= 0 {
+			return nil
+		}
+
+		if d > time.Minute {
+			d = time.Minute
+		}
+
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type counter struct {
+	mu sync.Mutex
+	n  map[string]int
+}
+
+func (c *counter) fire(name string) func() error {
+	return func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.n[name]++
+		return nil
+	}
+}
+
+func (c *counter) get(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.n[name]
+}
+
+func TestScheduler(t *testing.T) {
+	c := &counter{n: make(map[string]int)}
+	now := time.Now()
+
+	s := New()
+	s.Add(Entry{Name: "once", Spec: Once(now.Add(20 * time.Millisecond)), Fire: c.fire("once")})
+	s.Add(Entry{Name: "past", Spec: Once(now.Add(-time.Second)), Fire: c.fire("past")})
+	s.Add(Entry{Name: "every", Spec: Every(30 * time.Millisecond), Until: now.Add(100 * time.Millisecond), Fire: c.fire("every")})
+	s.Add(Entry{Name: "later", Spec: Every(10 * time.Millisecond), From: now.Add(time.Hour), Fire: c.fire("later")})
+
+	if up := s.Upcoming(); len(up) != 3 || up[len(up)-1].Entry.Name != "later" {
+		t.Errorf("unexpected upcoming %v", up)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	if err := s.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if c.get("once") != 1 || c.get("past") != 0 || c.get("later") != 0 {
+		t.Errorf("unexpected firings %v", c.n)
+	}
+
+	if n := c.get("every"); n < 3 || n > 4 {
+		t.Errorf("unexpected %d firings of every", n)
+	}
+
+	if _, ok := s.State()["once"]; !ok {
+		t.Errorf("state should have the last firing")
+	}
+}
+
+func TestSchedulerCatchUp(t *testing.T) {
+	c := &counter{n: make(map[string]int)}
+
+	old := New()
+	old.Restore(State{
+		"chime": time.Now().Add(-3 * time.Hour),
+		"skip":  time.Now().Add(-3 * time.Hour),
+	})
+
+	var buf bytes.Buffer
+	if err := old.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New()
+	if err := s.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Add(Entry{Name: "chime", Spec: Every(time.Hour), Policy: CatchUp, Fire: c.fire("chime")})
+	s.Add(Entry{Name: "skip", Spec: Every(time.Hour), Policy: Skip, Fire: c.fire("skip")})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	s.Run(ctx)
+
+	if c.get("chime") != 1 || c.get("skip") != 0 {
+		t.Errorf("unexpected firings %v", c.n)
+	}
+}
@@ -0,0 +1,117 @@
+package schedule
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// SunEvent is a moment of the day defined by the position of the sun.
+type SunEvent uint8
+
+const (
+	Sunrise SunEvent = iota
+	Sunset
+	// Dawn and Dusk are the start and end of the civil twilight, with the
+	// sun 6 degrees below the horizon.
+	Dawn
+	Dusk
+)
+
+var sunEventNames = map[SunEvent]string{
+	Sunrise: "sunrise",
+	Sunset:  "sunset",
+	Dawn:    "dawn",
+	Dusk:    "dusk",
+}
+
+func (e SunEvent) String() string {
+	if name, ok := sunEventNames[e]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("SunEvent(%d)", uint8(e))
+}
+
+// Sun is a Spec firing every day at a sun event, plus an offset, at the
+// given location, like half an hour before sunset. The days the event
+// doesn't happen, like the sunsets of the polar summer, are skipped.
+type Sun struct {
+	Event SunEvent
+	// Latitude and Longitude are in degrees, north and east positive.
+	Latitude, Longitude float64
+	Offset              time.Duration
+	// Location is the time zone of the days, the one of the time given to
+	// Next if nil.
+	Location *time.Location
+}
+
+// Next implements Spec.
+func (s Sun) Next(after time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = after.Location()
+	}
+
+	day := after.In(loc)
+	for i := 0; i < 370; i++ {
+		date := time.Date(day.Year(), day.Month(), day.Day()+i, 12, 0, 0, 0, loc)
+		t, ok := s.At(date)
+		if ok && t.After(after) {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// At returns the time of the event, plus the offset, the day of the given
+// date, false if it doesn't happen that day.
+func (s Sun) At(date time.Time) (time.Time, bool) {
+	altitude := -0.833
+	if s.Event == Dawn || s.Event == Dusk {
+		altitude = -6
+	}
+
+	rising := s.Event == Sunrise || s.Event == Dawn
+	t, ok := sunTime(date, s.Latitude, s.Longitude, altitude, rising)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return t.Add(s.Offset).In(date.Location()), true
+}
+
+// sunTime computes the time the sun crosses the given altitude, in degrees,
+// the day of the date at the location, with the NOAA equations.
+func sunTime(date time.Time, lat, lon, altitude float64, rising bool) (time.Time, bool) {
+	rad := math.Pi / 180
+
+	// the julian day of the noon UTC of the date.
+	y, m, d := date.Date()
+	noon := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+	jd := float64(noon.Unix())/86400 + 2440587.5
+
+	// mean solar time at the longitude.
+	n := math.Round(jd - 2451545.0 + 0.0008)
+	j := n - lon/360
+
+	ma := math.Mod(357.5291+0.98560028*j, 360)
+	c := 1.9148*math.Sin(ma*rad) + 0.02*math.Sin(2*ma*rad) + 0.0003*math.Sin(3*ma*rad)
+	l := math.Mod(ma+c+180+102.9372, 360)
+	transit := 2451545.0 + j + 0.0053*math.Sin(ma*rad) - 0.0069*math.Sin(2*l*rad)
+
+	decl := math.Asin(math.Sin(l*rad) * math.Sin(23.44*rad))
+	cosH := (math.Sin(altitude*rad) - math.Sin(lat*rad)*math.Sin(decl)) / (math.Cos(lat*rad) * math.Cos(decl))
+	if cosH < -1 || cosH > 1 {
+		return time.Time{}, false
+	}
+
+	h := math.Acos(cosH) / rad / 360
+	if rising {
+		h = -h
+	}
+
+	secs := (transit + h - 2440587.5) * 86400
+	return time.Unix(0, int64(secs*float64(time.Second))), true
+}
@@ -0,0 +1,41 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSun(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// the almanac gives 06:45 and 21:48 for the summer solstice of 2024.
+	date := time.Date(2024, 6, 21, 0, 0, 0, 0, madrid)
+	for event, expected := range map[SunEvent]time.Time{
+		Sunrise: time.Date(2024, 6, 21, 6, 45, 0, 0, madrid),
+		Sunset:  time.Date(2024, 6, 21, 21, 48, 0, 0, madrid),
+	} {
+		s := Sun{Event: event, Latitude: 40.4168, Longitude: -3.7038, Location: madrid}
+		if next := s.Next(date); next.Sub(expected).Abs() > 3*time.Minute {
+			t.Errorf("%s: got %s, expected %s", event, next, expected)
+		}
+	}
+
+	dusk := Sun{Event: Dusk, Latitude: 40.4168, Longitude: -3.7038, Offset: -time.Hour}
+	sunset := Sun{Event: Sunset, Latitude: 40.4168, Longitude: -3.7038}
+	if d := sunset.Next(date).Sub(dusk.Next(date)); d < 20*time.Minute || d > 40*time.Minute {
+		t.Errorf("unexpected dusk an hour earlier, %s after sunset", d)
+	}
+
+	// no sunset in the polar summer.
+	polar := Sun{Event: Sunset, Latitude: 78.2, Longitude: 15.6}
+	if _, ok := polar.At(time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC)); ok {
+		t.Errorf("there should be no sunset")
+	}
+
+	if next := polar.Next(date); next.Month() != time.August {
+		t.Errorf("unexpected first sunset after the polar summer %s", next)
+	}
+}
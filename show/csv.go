@@ -0,0 +1,176 @@
+package show
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// ImportReport is the result of ImportCSV.
+type ImportReport struct {
+	// Unmapped are the track names of the rows not found in the map, by
+	// cue number. Their cues are imported without the play action.
+	Unmapped map[string]string
+}
+
+// csvColumns are the names accepted for every column of ImportCSV, lower
+// case.
+var csvColumns = map[string][]string{
+	"number": {"number", "cue", "cue number", "q"},
+	"name":   {"name", "cue name", "description"},
+	"track":  {"track", "file", "file target", "target"},
+	"output": {"output", "out"},
+	"gain":   {"gain", "level", "volume"},
+	"fade":   {"fade", "fade in", "duration"},
+	"follow": {"follow", "continue", "post wait", "auto follow"},
+	"notes":  {"notes", "note"},
+}
+
+// ImportCSV converts a CSV exported from a spreadsheet or QLab-style tool
+// into a cue sheet. The first row names the columns, only the cue number and
+// the track are required:
+//
+//	number,name,track,output,gain,fade,follow
+//	1,preshow,10,0,-12,3s,
+//	2,thunder,thunder.wav,1,0,,1.5
+//
+// Tracks are given by number, or by name mapped to their numbers with the
+// tracks map, the names not found are reported. Durations are written like
+// "1.5s" or as seconds, a follow makes the cue fire the next one after it.
+func ImportCSV(r io.Reader, tracks map[string]int) (*CueSheet, *ImportReport, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cols := csvIndexes(header)
+	for _, required := range []string{"number", "track"} {
+		if _, ok := cols[required]; !ok {
+			return nil, nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	sheet := &CueSheet{}
+	report := &ImportReport{Unmapped: make(map[string]string)}
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		field := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+
+			return ""
+		}
+
+		if field("number") == "" {
+			continue
+		}
+
+		c, err := csvCue(field, tracks, report)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		sheet.Cues = append(sheet.Cues, c)
+	}
+
+	return sheet, report, nil
+}
+
+func csvIndexes(header []string) map[string]int {
+	cols := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for col, names := range csvColumns {
+			for _, name := range names {
+				if h == name {
+					cols[col] = i
+				}
+			}
+		}
+	}
+
+	return cols
+}
+
+func csvCue(field func(string) string, tracks map[string]int, report *ImportReport) (Cue, error) {
+	c := Cue{Number: field("number"), Name: field("name"), Notes: field("notes")}
+
+	if follow := field("follow"); follow != "" {
+		d, err := parseDuration(follow)
+		if err != nil {
+			return c, err
+		}
+
+		c.AutoFollow, c.Follow = true, Duration(d)
+	}
+
+	name := field("track")
+	trk, err := strconv.Atoi(name)
+	if err != nil {
+		var ok bool
+		if trk, ok = tracks[name]; !ok {
+			report.Unmapped[c.Number] = name
+			return c, nil
+		}
+	}
+
+	a := Action{Type: Play, Track: trk}
+	if out := field("output"); out != "" {
+		if a.Output, err = strconv.Atoi(out); err != nil {
+			return c, fmt.Errorf("invalid output %q", out)
+		}
+	}
+
+	if gain := field("gain"); gain != "" {
+		g, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(gain, "dB")), 64)
+		if err != nil {
+			return c, fmt.Errorf("invalid gain %q", gain)
+		}
+
+		a.Gain = tsunami.Gain(g)
+	}
+
+	if fade := field("fade"); fade != "" {
+		d, err := parseDuration(fade)
+		if err != nil {
+			return c, err
+		}
+
+		a.Duration = Duration(d)
+	}
+
+	c.Actions = append(c.Actions, a)
+	return c, nil
+}
+
+// parseDuration parses a duration like "1.5s", or as seconds.
+func parseDuration(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,50 @@
+package show
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportCSV(t *testing.T) {
+	data := "Cue,Name,File Target,Out,Level,Fade In,Post Wait\n" +
+		"1,preshow,10,0,-12,3s,\n" +
+		"2,thunder,thunder.wav,1,-3 dB,,1.5\n" +
+		"3,rain,rain.wav,,,,\n" +
+		",,,,,,\n"
+
+	sheet, report, err := ImportCSV(strings.NewReader(data), map[string]int{"thunder.wav": 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Cue{
+		{Number: "1", Name: "preshow", Actions: []Action{
+			{Type: Play, Track: 10, Gain: -12, Duration: Duration(3 * time.Second)},
+		}},
+		{Number: "2", Name: "thunder", AutoFollow: true, Follow: Duration(1500 * time.Millisecond), Actions: []Action{
+			{Type: Play, Track: 20, Output: 1, Gain: -3},
+		}},
+		{Number: "3", Name: "rain"},
+	}
+
+	if !reflect.DeepEqual(sheet.Cues, expected) {
+		t.Errorf("unexpected cues %+v", sheet.Cues)
+	}
+
+	if !reflect.DeepEqual(report.Unmapped, map[string]string{"3": "rain.wav"}) {
+		t.Errorf("unexpected unmapped %v", report.Unmapped)
+	}
+}
+
+func TestImportCSVErrors(t *testing.T) {
+	if _, _, err := ImportCSV(strings.NewReader("name,track\n"), nil); err == nil {
+		t.Errorf("missing number column should fail")
+	}
+
+	_, _, err := ImportCSV(strings.NewReader("number,track,gain\n1,1,loud\n"), nil)
+	if err == nil || err.Error() != `line 2: invalid gain "loud"` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package show
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// CueSheet is a show stored as data, written in JSON:
+//
+//	{
+//	  "title": "Act 1",
+//	  "buses": [{"name": "FOH", "output": 0}, {"name": "lobby", "output": 3}],
+//	  "cues": [
+//	    {"number": "1", "name": "preshow", "actions": [
+//	      {"type": "loop", "track": 10, "loop": true},
+//	      {"type": "play", "track": 10, "gain": -12, "duration": "3s"}
+//	    ]},
+//	    {"number": "2", "notes": "on the blackout", "actions": [
+//	      {"type": "stop", "track": 10, "duration": "5s"},
+//	      {"type": "play", "track": 11, "bus": "lobby", "wait": "2s"}
+//	    ]}
+//	  ]
+//	}
+type CueSheet struct {
+	Title string `json:"title,omitempty"`
+	Notes string `json:"notes,omitempty"`
+	// Buses are the buses the actions may refer to by name.
+	Buses []tsunami.Bus `json:"buses,omitempty"`
+	Cues  []Cue         `json:"cues"`
+}
+
+// ValidationError is returned for an invalid cue sheet, with every problem
+// found.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid cue sheet: " + strings.Join(e.Problems, "; ")
+}
+
+// ReadCueSheet reads a cue sheet in JSON.
+func ReadCueSheet(r io.Reader) (*CueSheet, error) {
+	var s CueSheet
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// LoadCueSheet reads the cue sheet of the file, validates it and returns its
+// cue stack for the board. The tracks are validated against the catalog, if
+// not nil.
+func LoadCueSheet(path string, p tsunami.Player, catalog tsunami.TrackDurations) (*CueStack, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	s, err := ReadCueSheet(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := s.Validate(catalog); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	buses, err := tsunami.NewBuses(s.Buses...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	stack := NewCueStack(p, s.Cues)
+	stack.SetBuses(buses)
+	return stack, nil
+}
+
+// Validate checks the buses, cues and actions of the sheet, and that their
+// tracks are in the catalog, if not nil. The error returned is a *ValidationError.
+func (s *CueSheet) Validate(catalog tsunami.TrackDurations) error {
+	var problems []string
+	problem := func(c Cue, format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("cue %q: ", c.Number)+fmt.Sprintf(format, args...))
+	}
+
+	buses, err := tsunami.NewBuses(s.Buses...)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	numbers := make(map[string]bool, len(s.Cues))
+	for _, c := range s.Cues {
+		if c.Number == "" {
+			problem(c, "missing number")
+		}
+
+		if numbers[c.Number] {
+			problem(c, "duplicated number")
+		}
+
+		numbers[c.Number] = true
+		for _, a := range c.Actions {
+			validateAction(a, catalog, buses, func(format string, args ...interface{}) {
+				problem(c, format, args...)
+			})
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func validateAction(a Action, catalog tsunami.TrackDurations, buses *tsunami.Buses, problem func(string, ...interface{})) {
+	switch a.Type {
+	case Play, Stop, Fade, SetGain, Loop:
+	case StopAll:
+		return
+	default:
+		problem("unknown action %q", a.Type)
+		return
+	}
+
+	if a.Track <= 0 || a.Track > tsunami.MaxTracks {
+		problem("%s: invalid track %d", a.Type, a.Track)
+	} else if catalog != nil {
+		if _, ok := catalog.Duration(a.Track); !ok {
+			problem("%s: track %d not in the catalog", a.Type, a.Track)
+		}
+	}
+
+	if a.Output < 0 || a.Output >= tsunami.MaxOutputs {
+		problem("%s: invalid output %d", a.Type, a.Output)
+	}
+
+	if a.Bus != "" && buses != nil {
+		if _, err := buses.Bus(a.Bus); err != nil {
+			problem("%s: %s", a.Type, err)
+		}
+	}
+
+	if a.Gain < tsunami.MinGain || a.Gain > tsunami.MaxTrackGain {
+		problem("%s: gain %s out of range", a.Type, a.Gain)
+	}
+
+	if a.Type == Fade && a.Duration <= 0 {
+		problem("fade: missing duration")
+	}
+}
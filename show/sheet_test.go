@@ -0,0 +1,85 @@
+package show
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+const sheet = `{
+  "title": "Act 1",
+  "cues": [
+    {"number": "1", "name": "preshow", "actions": [
+      {"type": "loop", "track": 10, "loop": true},
+      {"type": "play", "track": 10, "gain": -12, "duration": "3s"}
+    ]},
+    {"number": "2", "notes": "on the blackout", "auto_follow": true, "follow": "1s", "actions": [
+      {"type": "stop", "track": 10, "duration": "5s"},
+      {"type": "play", "track": 11, "output": 1, "wait": "2s"}
+    ]}
+  ]
+}`
+
+func TestLoadCueSheet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "show.json")
+	if err := os.WriteFile(path, []byte(sheet), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(nil))
+	s, err := LoadCueSheet(path, ts, tsunami.Durations{10: time.Minute, 11: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cues := s.Cues()
+	if len(cues) != 2 || cues[1].Notes != "on the blackout" || cues[1].Follow != Duration(time.Second) {
+		t.Errorf("unexpected cues %+v", cues)
+	}
+
+	if a := cues[1].Actions[1]; a.Output != 1 || a.Wait != Duration(2*time.Second) {
+		t.Errorf("unexpected action %+v", a)
+	}
+
+	_, err = LoadCueSheet(path, ts, tsunami.Durations{10: time.Minute})
+	if err == nil || !strings.Contains(err.Error(), "track 11 not in the catalog") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestCueSheetValidate(t *testing.T) {
+	s := &CueSheet{Cues: []Cue{
+		{Number: "1", Actions: []Action{{Type: "jump"}}},
+		{Number: "1", Actions: []Action{
+			{Type: Play, Track: 0, Output: 8},
+			{Type: Fade, Track: 1, Gain: 20},
+			{Type: StopAll},
+			{Type: Play, Track: 2, Bus: "FOH"},
+		}},
+	}}
+
+	var verr *ValidationError
+	if err := s.Validate(nil); !errors.As(err, &verr) {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []string{
+		`cue "1": unknown action "jump"`,
+		`cue "1": duplicated number`,
+		`cue "1": play: invalid track 0`,
+		`cue "1": play: invalid output 8`,
+		`cue "1": fade: gain +20.0 dB out of range`,
+		`cue "1": fade: missing duration`,
+		`cue "1": play: unknown bus "FOH"`,
+	}
+
+	if strings.Join(verr.Problems, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected problems:\n%s", strings.Join(verr.Problems, "\n"))
+	}
+}
@@ -0,0 +1,368 @@
+// Package show runs theater-style shows on a board: a stack of numbered cues
+// fired one after the other by the operator with Go, as sound operators are
+// used to.
+package show
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// ErrEndOfStack is returned by Go when every cue was fired.
+var ErrEndOfStack = errors.New("end of the cue stack")
+
+// Duration is a time.Duration written in JSON as a string, like "1.5s".
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting a string or a number
+// of seconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var secs float64
+		if err := json.Unmarshal(data, &secs); err != nil {
+			return fmt.Errorf("invalid duration %s", data)
+		}
+
+		*d = Duration(secs * float64(time.Second))
+		return nil
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+// ActionType is the kind of an Action.
+type ActionType string
+
+const (
+	// Play plays the track at the gain of the action, faded in along the
+	// duration if any.
+	Play ActionType = "play"
+	// Stop stops the track, faded out along the duration if any.
+	Stop ActionType = "stop"
+	// Fade fades the track to the gain of the action along the duration.
+	Fade ActionType = "fade"
+	// SetGain sets the gain of the track.
+	SetGain ActionType = "gain"
+	// Loop sets the loop flag of the track.
+	Loop ActionType = "loop"
+	// StopAll stops every track.
+	StopAll ActionType = "stop_all"
+)
+
+// Action is an operation of a cue on the board.
+type Action struct {
+	Type   ActionType `json:"type"`
+	Track  int        `json:"track,omitempty"`
+	Output int        `json:"output,omitempty"`
+	// Bus is the name of the bus the track is played on, instead of
+	// Output, see CueStack.SetBuses.
+	Bus      string       `json:"bus,omitempty"`
+	Gain     tsunami.Gain `json:"gain,omitempty"`
+	Duration Duration     `json:"duration,omitempty"`
+	Loop     bool         `json:"loop,omitempty"`
+	Lock     bool         `json:"lock,omitempty"`
+	// Wait is the delay from the cue being fired to the action.
+	Wait Duration `json:"wait,omitempty"`
+}
+
+func (a Action) run(p tsunami.Player, buses *tsunami.Buses) error {
+	d := time.Duration(a.Duration)
+	switch a.Type {
+	case Play:
+		if d == 0 {
+			if err := p.TrackGain(a.Track, a.Gain); err != nil {
+				return err
+			}
+
+			return a.play(p, buses)
+		}
+
+		if err := p.TrackGain(a.Track, tsunami.MinGain); err != nil {
+			return err
+		}
+
+		if err := a.play(p, buses); err != nil {
+			return err
+		}
+
+		return p.TrackFade(a.Track, a.Gain, d, false)
+	case Stop:
+		if d == 0 {
+			return p.TrackStop(a.Track)
+		}
+
+		return p.TrackFade(a.Track, tsunami.MinGain, d, true)
+	case Fade:
+		return p.TrackFade(a.Track, a.Gain, d, false)
+	case SetGain:
+		return p.TrackGain(a.Track, a.Gain)
+	case Loop:
+		return p.TrackLoop(a.Track, a.Loop)
+	case StopAll:
+		return p.StopAllTracks()
+	}
+
+	return fmt.Errorf("unknown action %q", a.Type)
+}
+
+func (a Action) play(p tsunami.Player, buses *tsunami.Buses) error {
+	if a.Bus == "" {
+		return p.TrackPlayPoly(a.Track, a.Output, a.Lock)
+	}
+
+	if buses == nil {
+		return fmt.Errorf("%w %q", tsunami.ErrUnknownBus, a.Bus)
+	}
+
+	return buses.Play(p, a.Track, a.Bus, a.Lock)
+}
+
+// Cue is an entry of a CueStack.
+type Cue struct {
+	// Number identifies the cue, like "1" or "12.5".
+	Number  string   `json:"number"`
+	Name    string   `json:"name,omitempty"`
+	Notes   string   `json:"notes,omitempty"`
+	Actions []Action `json:"actions,omitempty"`
+	// AutoFollow fires the next cue Follow after this one, instead of
+	// waiting for the next Go.
+	AutoFollow bool     `json:"auto_follow,omitempty"`
+	Follow     Duration `json:"follow,omitempty"`
+}
+
+// CueStack is a list of cues fired in order. A standby pointer marks the
+// next cue to be fired by Go, and can be moved with Back and GotoCue.
+type CueStack struct {
+	p    tsunami.Player
+	cues []Cue
+
+	mu      sync.Mutex
+	standby int
+	buses   *tsunami.Buses
+	tracer  tsunami.Tracer
+	timers  map[*time.Timer]bool
+	onCue   []func(Cue)
+	onError []func(Cue, error)
+}
+
+// NewCueStack returns a stack of the cues for the board, with the first cue
+// in standby.
+func NewCueStack(p tsunami.Player, cues []Cue) *CueStack {
+	return &CueStack{p: p, cues: cues, timers: make(map[*time.Timer]bool)}
+}
+
+// SetBuses sets the buses the actions are played on, by name.
+func (s *CueStack) SetBuses(b *tsunami.Buses) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buses = b
+}
+
+// SetTracer traces every cue fired with a "show.cue" span, see GoContext.
+func (s *CueStack) SetTracer(tr tsunami.Tracer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tracer = tr
+}
+
+// Cues returns the cues of the stack.
+func (s *CueStack) Cues() []Cue {
+	return s.cues
+}
+
+// OnCue registers a function to be called with every cue fired.
+func (s *CueStack) OnCue(fn func(Cue)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onCue = append(s.onCue, fn)
+}
+
+// OnError registers a function to be called with the errors of the actions
+// run after a wait, and of the cues fired by a follow.
+func (s *CueStack) OnError(fn func(Cue, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onError = append(s.onError, fn)
+}
+
+// Standby returns the cue in standby, false at the end of the stack.
+func (s *CueStack) Standby() (Cue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.standby >= len(s.cues) {
+		return Cue{}, false
+	}
+
+	return s.cues[s.standby], true
+}
+
+// Go fires the cue in standby and moves the standby to the next one. If the
+// cue is followed automatically, the next one is fired after its follow
+// time.
+func (s *CueStack) Go() error {
+	return s.GoContext(context.Background())
+}
+
+// GoContext is like Go, tracing the cue with a span child of ctx, see
+// SetTracer. When the board is a *tsunami.Tsunami, the spans of its commands
+// are children of the one of the cue, see tsunami.WithTracer, so a cue fired
+// late can be told apart from commands delayed by the board.
+func (s *CueStack) GoContext(ctx context.Context) error {
+	s.mu.Lock()
+	if s.standby >= len(s.cues) {
+		s.mu.Unlock()
+		return ErrEndOfStack
+	}
+
+	i := s.standby
+	s.standby++
+	s.mu.Unlock()
+
+	return s.fire(ctx, i)
+}
+
+// Back moves the standby to the previous cue, without firing anything.
+func (s *CueStack) Back() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.standby > 0 {
+		s.standby--
+	}
+}
+
+// GotoCue moves the standby to the cue with the given number, without firing
+// anything.
+func (s *CueStack) GotoCue(number string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, c := range s.cues {
+		if c.Number == number {
+			s.standby = i
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown cue %q", number)
+}
+
+// Stop cancels the waits and follows pending and stops every track.
+func (s *CueStack) Stop() error {
+	s.mu.Lock()
+	for timer := range s.timers {
+		timer.Stop()
+	}
+
+	s.timers = make(map[*time.Timer]bool)
+	s.mu.Unlock()
+
+	return s.p.StopAllTracks()
+}
+
+func (s *CueStack) fire(ctx context.Context, i int) (err error) {
+	c := s.cues[i]
+
+	s.mu.Lock()
+	handlers := s.onCue
+	buses := s.buses
+	tracer := s.tracer
+	s.mu.Unlock()
+
+	if tracer != nil {
+		var span tsunami.Span
+		ctx, span = tracer.Start(ctx, "show.cue", "cue", c.Number, "name", c.Name)
+		defer func() { span.End(err) }()
+	}
+
+	p := s.p
+	if t, ok := p.(*tsunami.Tsunami); ok {
+		p = t.WithContext(ctx)
+	}
+
+	for _, fn := range handlers {
+		fn(c)
+	}
+
+	for _, a := range c.Actions {
+		a := a
+		if a.Wait > 0 {
+			s.after(time.Duration(a.Wait), c, func() error { return a.run(p, buses) })
+			continue
+		}
+
+		if e := a.run(p, buses); e != nil && err == nil {
+			err = fmt.Errorf("cue %s: %w", c.Number, e)
+		}
+	}
+
+	if c.AutoFollow {
+		s.after(time.Duration(c.Follow), c, func() error {
+			s.mu.Lock()
+			if s.standby != i+1 {
+				// the operator moved the standby meanwhile.
+				s.mu.Unlock()
+				return nil
+			}
+			s.mu.Unlock()
+
+			if err := s.GoContext(ctx); err != nil && err != ErrEndOfStack {
+				return err
+			}
+
+			return nil
+		})
+	}
+
+	return err
+}
+
+// after calls fn after d, unless the stack is stopped before.
+func (s *CueStack) after(d time.Duration, c Cue, fn func() error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		s.mu.Lock()
+		if !s.timers[timer] {
+			s.mu.Unlock()
+			return
+		}
+
+		delete(s.timers, timer)
+		handlers := s.onError
+		s.mu.Unlock()
+
+		if err := fn(); err != nil {
+			for _, h := range handlers {
+				h(c, err)
+			}
+		}
+	})
+
+	s.timers[timer] = true
+}
@@ -0,0 +1,147 @@
+package show
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestCueStack(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	s := NewCueStack(ts, []Cue{
+		{Number: "1", Actions: []Action{{Type: Play, Track: 1, Output: 2, Gain: -6}}},
+		{Number: "2", Actions: []Action{{Type: Stop, Track: 1, Duration: Duration(time.Second)}}},
+		{Number: "3", Actions: []Action{{Type: StopAll}}},
+	})
+
+	var fired []string
+	s.OnCue(func(c Cue) { fired = append(fired, c.Number) })
+
+	if err := s.Go(); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []protocol.Message{
+		&protocol.TrackVolume{Track: 1, Gain: -6},
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 1, Output: 2},
+	}
+
+	if !reflect.DeepEqual(msgs, expected) {
+		t.Errorf("unexpected messages %v", msgs)
+	}
+
+	if err := s.GotoCue("3"); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Back()
+	if c, _ := s.Standby(); c.Number != "2" {
+		t.Errorf("unexpected standby %s", c.Number)
+	}
+
+	s.Go()
+	s.Go()
+	if err := s.Go(); err != ErrEndOfStack {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if !reflect.DeepEqual(fired, []string{"1", "2", "3"}) {
+		t.Errorf("unexpected cues %v", fired)
+	}
+
+	if err := s.GotoCue("9"); err == nil {
+		t.Errorf("unknown cue should fail")
+	}
+}
+
+func TestCueStackFollow(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	s := NewCueStack(ts, []Cue{
+		{Number: "1", AutoFollow: true, Follow: Duration(20 * time.Millisecond), Actions: []Action{
+			{Type: Play, Track: 1},
+			{Type: Loop, Track: 1, Loop: true, Wait: Duration(10 * time.Millisecond)},
+		}},
+		{Number: "2", Actions: []Action{{Type: Play, Track: 2}}},
+		{Number: "3", Actions: []Action{{Type: Play, Track: 3}}},
+	})
+
+	s.Go()
+	time.Sleep(50 * time.Millisecond)
+
+	if c, _ := s.Standby(); c.Number != "3" {
+		t.Errorf("cue 2 should have been followed, standby %s", c.Number)
+	}
+
+	msgs, _ := port.Messages()
+	var codes []protocol.TrackCode
+	for _, m := range msgs {
+		if c, ok := m.(*protocol.TrackControl); ok {
+			codes = append(codes, c.Code)
+		}
+	}
+
+	expected := []protocol.TrackCode{tsunami.TRK_PLAY_POLY, tsunami.TRK_LOOP_ON, tsunami.TRK_PLAY_POLY}
+	if !reflect.DeepEqual(codes, expected) {
+		t.Errorf("unexpected controls %v", codes)
+	}
+}
+
+type spanKey struct{}
+
+// recordingTracer records the spans as "parent > name".
+type recordingTracer struct {
+	spans []string
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, attrs ...interface{}) (context.Context, tsunami.Span) {
+	parent, _ := ctx.Value(spanKey{}).(string)
+	r.spans = append(r.spans, parent+" > "+name)
+	return context.WithValue(ctx, spanKey{}, name), r
+}
+
+func (r *recordingTracer) End(err error) {}
+
+func TestCueStackTracer(t *testing.T) {
+	tr := &recordingTracer{}
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(nil), tsunami.WithTracer(tr))
+	s := NewCueStack(ts, []Cue{{Number: "1", Actions: []Action{{Type: StopAll}}}})
+	s.SetTracer(tr)
+
+	if err := s.Go(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{" > show.cue", "show.cue > tsunami.command", "tsunami.command > tsunami.write"}
+	if !reflect.DeepEqual(tr.spans, expected) {
+		t.Errorf("unexpected spans %q", tr.spans)
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	var a Action
+	if err := json.Unmarshal([]byte(`{"type":"fade","duration":"1.5s","wait":2}`), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Duration != Duration(1500*time.Millisecond) || a.Wait != Duration(2*time.Second) {
+		t.Errorf("unexpected action %+v", a)
+	}
+
+	data, _ := json.Marshal(a)
+	if string(data) != `{"type":"fade","duration":"1.5s","wait":"2s"}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+}
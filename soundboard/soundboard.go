@@ -0,0 +1,347 @@
+// Package soundboard fires the tracks of a board from the keys of a
+// terminal, with the tracks playing shown on screen, so the technicians can
+// run the effects from a laptop during the rehearsals.
+//
+// Every key is bound to a track, space stops all the tracks, and Ctrl-C or
+// Ctrl-D quit. The terminal is set to raw mode by Run, see MakeRaw, so the
+// keys are read as they are pressed.
+package soundboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// Keys with a meaning of their own, they can't be bound.
+const (
+	KeyStopAll = ' '
+	keyQuit    = 0x03 // Ctrl-C
+	keyEOF     = 0x04 // Ctrl-D
+)
+
+// ErrNotTerminal is returned by MakeRaw for files not being a terminal, or
+// on systems without raw mode support.
+var ErrNotTerminal = errors.New("not a terminal")
+
+// DefaultKeys are the keys of DefaultBindings, by row.
+const DefaultKeys = "1234567890qwertyuiopasdfghjklzxcvbnm"
+
+// Binding binds a key to a track, eg.:
+//
+//	{"key": "a", "track": 19, "output": 0, "label": "door creak"}
+type Binding struct {
+	Key    string `json:"key"`
+	Track  int    `json:"track"`
+	Output int    `json:"output,omitempty"`
+	Lock   bool   `json:"lock,omitempty"`
+	Solo   bool   `json:"solo,omitempty"`
+	// Label is shown on screen, the name of the track if empty and the
+	// board has aliases, see Tsunami.SetAliases.
+	Label string `json:"label,omitempty"`
+}
+
+func (b *Binding) validate() error {
+	r := []rune(b.Key)
+	if len(r) != 1 || r[0] < ' ' || r[0] == KeyStopAll || r[0] == 0x7f {
+		return fmt.Errorf("invalid key %q", b.Key)
+	}
+
+	if b.Track < 1 || b.Track > tsunami.MaxTracks {
+		return fmt.Errorf("key %q: invalid track %d", b.Key, b.Track)
+	}
+
+	if b.Output < 0 || b.Output >= tsunami.MaxOutputs {
+		return fmt.Errorf("key %q: invalid output %d", b.Key, b.Output)
+	}
+
+	return nil
+}
+
+// LoadBindings reads the bindings as a JSON array.
+func LoadBindings(r io.Reader) ([]Binding, error) {
+	var b []Binding
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// DefaultBindings binds the keys of DefaultKeys to the first tracks, on the
+// output.
+func DefaultBindings(out int) []Binding {
+	b := make([]Binding, 0, len(DefaultKeys))
+	for i, k := range DefaultKeys {
+		b = append(b, Binding{Key: string(k), Track: i + 1, Output: out})
+	}
+
+	return b
+}
+
+// Soundboard plays the tracks bound to the keys pressed.
+type Soundboard struct {
+	b        tsunami.Board
+	bindings map[rune]Binding
+	keys     []rune
+	cancel   func()
+
+	mu      sync.Mutex
+	playing map[int]bool
+	last    string
+	err     error
+	changed chan struct{}
+}
+
+// New returns a soundboard of the bindings on the board, subscribed to its
+// track reports to show the tracks playing.
+func New(b tsunami.Board, bindings ...Binding) (*Soundboard, error) {
+	s := &Soundboard{
+		b:        b,
+		bindings: make(map[rune]Binding, len(bindings)),
+		playing:  make(map[int]bool),
+		changed:  make(chan struct{}, 1),
+	}
+
+	for _, bd := range bindings {
+		if err := bd.validate(); err != nil {
+			return nil, err
+		}
+
+		k := []rune(bd.Key)[0]
+		if _, ok := s.bindings[k]; ok {
+			return nil, fmt.Errorf("key %q bound twice", bd.Key)
+		}
+
+		s.bindings[k] = bd
+		s.keys = append(s.keys, k)
+	}
+
+	s.cancel = b.Subscribe(s.handle)
+	return s, nil
+}
+
+// Close unsubscribes the soundboard from the board.
+func (s *Soundboard) Close() error {
+	s.cancel()
+	return nil
+}
+
+// Press runs the action of the key, it returns the error of the board, if
+// any, and ignores the keys not bound.
+func (s *Soundboard) Press(key rune) error {
+	var err error
+	var last string
+	bd, ok := s.bindings[key]
+	switch {
+	case key == KeyStopAll:
+		err, last = s.b.StopAllTracks(), "stop all"
+	case !ok:
+		return nil
+	case bd.Solo:
+		err = s.b.TrackPlaySolo(bd.Track, bd.Output, bd.Lock)
+	default:
+		err = s.b.TrackPlayPoly(bd.Track, bd.Output, bd.Lock)
+	}
+
+	if ok {
+		last = fmt.Sprintf("[%c] %s", key, s.label(bd))
+	}
+
+	s.mu.Lock()
+	s.last, s.err = last, err
+	s.mu.Unlock()
+
+	s.notify()
+	return err
+}
+
+// Render writes the screen: the bindings, marking the tracks playing, the
+// number of voices playing and the last key pressed. The lines end with
+// \r\n, as required by the terminals in raw mode.
+func (s *Soundboard) Render(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	for _, k := range s.keys {
+		bd := s.bindings[k]
+		mark := " "
+		if s.playing[bd.Track] {
+			mark = ">"
+		}
+
+		fmt.Fprintf(&b, "%s [%c] %4d  %s\r\n", mark, k, bd.Track, s.label(bd))
+	}
+
+	b.WriteString("\r\n")
+	voices := fmt.Sprintf("%d", len(s.playing))
+	if v, ok := s.b.(interface{ GetNumVoices() int }); ok && v.GetNumVoices() > 0 {
+		voices += fmt.Sprintf("/%d", v.GetNumVoices())
+	}
+
+	fmt.Fprintf(&b, "voices %s   tracks %s\r\n", voices, s.playingTracks())
+	if s.last != "" {
+		fmt.Fprintf(&b, "last %s", s.last)
+		if s.err != nil {
+			fmt.Fprintf(&b, ": %s", s.err)
+		}
+
+		b.WriteString("\r\n")
+	}
+
+	b.WriteString("space stops all, ctrl-c quits\r\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Run renders the soundboard to out, and plays the keys read from in, like
+// a terminal in raw mode, until Ctrl-C or Ctrl-D are pressed, in is closed
+// or the context is done. The screen is rendered again every time a key is
+// pressed or a track starts or stops.
+func (s *Soundboard) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	keys, stop := make(chan rune), make(chan struct{})
+	defer close(stop)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- readKeys(in, keys, stop)
+	}()
+
+	for {
+		if err := s.Render(out); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-done:
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		case k := <-keys:
+			if k == keyQuit || k == keyEOF {
+				return nil
+			}
+
+			// the errors are shown on screen.
+			s.Press(k)
+		case <-s.changed:
+		}
+	}
+}
+
+// readKeys sends the keys read to the channel until stop is closed, the
+// escape sequences, like the ones of the arrows, are skipped.
+func readKeys(in io.Reader, keys chan<- rune, stop <-chan struct{}) error {
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		s := string(buf[:n])
+		for len(s) > 0 {
+			if s[0] == 0x1b {
+				// ESC [ parameters and a final byte, or ESC and a key.
+				s = s[1:]
+				if strings.HasPrefix(s, "[") {
+					i := strings.IndexFunc(s[1:], func(r rune) bool { return r >= 0x40 && r <= 0x7e })
+					if i < 0 {
+						i = len(s) - 2
+					}
+
+					s = s[i+2:]
+				} else if len(s) > 0 {
+					s = s[1:]
+				}
+
+				continue
+			}
+
+			r := []rune(s)[0]
+			s = s[len(string(r)):]
+			select {
+			case keys <- r:
+			case <-stop:
+				return nil
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Soundboard) handle(msg protocol.Message) {
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	if r.Playing {
+		s.playing[int(r.Track)] = true
+	} else {
+		delete(s.playing, int(r.Track))
+	}
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// notify signals a change of the screen, without blocking.
+func (s *Soundboard) notify() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Soundboard) label(bd Binding) string {
+	if bd.Label != "" {
+		return bd.Label
+	}
+
+	n, ok := s.b.(interface{ TrackName(trk int) string })
+	if !ok {
+		return ""
+	}
+
+	// the tracks without a name are named after their number.
+	if name := n.TrackName(bd.Track); name != fmt.Sprint(bd.Track) {
+		return name
+	}
+
+	return ""
+}
+
+// playingTracks returns the tracks playing, sorted.
+func (s *Soundboard) playingTracks() string {
+	if len(s.playing) == 0 {
+		return "-"
+	}
+
+	trks := make([]int, 0, len(s.playing))
+	for trk := range s.playing {
+		trks = append(trks, trk)
+	}
+
+	sort.Ints(trks)
+	strs := make([]string, len(trks))
+	for i, trk := range trks {
+		strs[i] = fmt.Sprint(trk)
+	}
+
+	return strings.Join(strs, " ")
+}
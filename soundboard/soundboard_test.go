@@ -0,0 +1,111 @@
+package soundboard
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestSoundboard(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	ts.SetAliases(tsunami.Aliases{"door creak": 19})
+
+	s, err := New(ts,
+		Binding{Key: "a", Track: 19, Output: 1},
+		Binding{Key: "b", Track: 20, Solo: true, Label: "thunder"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// arrows and unbound keys are ignored.
+	in := strings.NewReader("a\x1b[Az\x1bOPb ")
+	var out bytes.Buffer
+	if err := s.Run(context.Background(), in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, _ := port.Messages()
+	var got []string
+	for _, msg := range msgs {
+		got = append(got, protocol.Describe(msg))
+	}
+
+	expected := []protocol.Message{
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 19, Output: 1},
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_SOLO, Track: 20},
+		&protocol.StopAll{},
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("unexpected commands %q", got)
+	}
+
+	for i, msg := range expected {
+		if got[i] != protocol.Describe(msg) {
+			t.Errorf("%d: got %q, expected %q", i, got[i], protocol.Describe(msg))
+		}
+	}
+
+	port.Push(&protocol.TrackReport{Track: 19, Playing: true})
+	ts.Update()
+
+	out.Reset()
+	s.Render(&out)
+	for _, line := range []string{
+		"> [a]   19  door creak\r\n",
+		"  [b]   20  thunder\r\n",
+		"voices 1   tracks 19\r\n",
+		"last stop all\r\n",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("missing %q in screen %q", line, out.String())
+		}
+	}
+}
+
+func TestSoundboardQuit(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	s, _ := New(ts, DefaultBindings(0)...)
+
+	r, w := io.Pipe()
+	defer w.Close()
+
+	done := make(chan error)
+	go func() { done <- s.Run(context.Background(), r, io.Discard) }()
+
+	w.Write([]byte("1\x03"))
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if msgs, _ := port.Messages(); len(msgs) != 1 {
+		t.Errorf("unexpected commands %v", msgs)
+	}
+}
+
+func TestNewValidate(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(nil))
+	for _, c := range []struct {
+		b   []Binding
+		err string
+	}{
+		{[]Binding{{Key: " ", Track: 1}}, `invalid key " "`},
+		{[]Binding{{Key: "ab", Track: 1}}, `invalid key "ab"`},
+		{[]Binding{{Key: "a"}}, `key "a": invalid track 0`},
+		{[]Binding{{Key: "a", Track: 1, Output: 8}}, `key "a": invalid output 8`},
+		{[]Binding{{Key: "a", Track: 1}, {Key: "a", Track: 2}}, `key "a" bound twice`},
+	} {
+		if _, err := New(ts, c.b...); err == nil || err.Error() != c.err {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+//go:build !tinygo
+
+package soundboard
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// MakeRaw puts the terminal of the file descriptor, like the one of
+// os.Stdin, in raw mode, so the keys are read as they are pressed, without
+// echo, returning the function restoring its previous state.
+// ErrNotTerminal is returned if it isn't a terminal.
+func MakeRaw(fd uintptr) (restore func() error, err error) {
+	var old syscall.Termios
+	if err := termios(fd, syscall.TCGETS, &old); err != nil {
+		return nil, ErrNotTerminal
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN], raw.Cc[syscall.VTIME] = 1, 0
+
+	if err := termios(fd, syscall.TCSETS, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() error { return termios(fd, syscall.TCSETS, &old) }, nil
+}
+
+func termios(fd uintptr, req uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !tinygo
+
+package soundboard
+
+// MakeRaw puts the terminal of the file descriptor in raw mode, it's only
+// available on Linux, ErrNotTerminal is returned otherwise, and the keys
+// are read when Enter is pressed.
+func MakeRaw(fd uintptr) (restore func() error, err error) {
+	return nil, ErrNotTerminal
+}
@@ -0,0 +1,239 @@
+// Package soundscape turns a board into an ambience generator: a few looping
+// bed tracks play continuously while one-shots, picked from pools, are fired
+// at random intervals, gains and pitches.
+//
+// The one-shots playing are known from the track reports, see
+// tsunami.Tsunami.Subscribe.
+package soundscape
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/random"
+)
+
+// Bed is a track looping during the whole soundscape.
+type Bed struct {
+	Track  int
+	Output int
+	Gain   tsunami.Gain
+}
+
+// Pool is a set of one-shots, one of them is fired after a random interval
+// since the previous one.
+type Pool struct {
+	// Sounds are the tracks of the pool, picked by weight and respecting
+	// their cooldowns, see random.Trigger.
+	Sounds []random.Sound
+	Output int
+	// MinInterval and MaxInterval are the range of the time between two
+	// one-shots.
+	MinInterval, MaxInterval time.Duration
+	// MinGain and MaxGain are the range of the gain of the one-shots.
+	MinGain, MaxGain tsunami.Gain
+	// MinPitch and MaxPitch are the range of the pitch of the one-shots, in
+	// semitones. The pitch is set through the samplerate offset of the
+	// output, so it applies to everything playing on it, and the pool
+	// should have an output of its own. Left at zero, the pitch isn't
+	// changed.
+	MinPitch, MaxPitch float64
+}
+
+// Config is the definition of a soundscape.
+type Config struct {
+	Beds  []Bed
+	Pools []Pool
+	// MaxVoices is the number of voices the soundscape may use, beds
+	// included, a one-shot is skipped when it would exceed it. Zero means
+	// every voice of the board.
+	MaxVoices int
+}
+
+// Soundscape plays a Config on a board.
+type Soundscape struct {
+	p   tsunami.Player
+	cfg Config
+
+	mu      sync.Mutex
+	rand    *rand.Rand
+	playing []int
+}
+
+// New returns a soundscape of the config for the board.
+func New(p tsunami.Player, cfg Config) *Soundscape {
+	if cfg.MaxVoices <= 0 {
+		cfg.MaxVoices = tsunami.MAX_NUM_VOICES
+	}
+
+	return &Soundscape{
+		p:    p,
+		cfg:  cfg,
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Seed seeds the random generator, making the intervals, gains and pitches
+// deterministic.
+func (s *Soundscape) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rand.Seed(seed)
+}
+
+// Run plays the soundscape until the context is done, then every bed and
+// one-shot is stopped.
+func (s *Soundscape) Run(ctx context.Context) error {
+	defer s.stop()
+
+	for _, b := range s.cfg.Beds {
+		if err := s.startBed(b); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(s.cfg.Pools))
+	for _, p := range s.cfg.Pools {
+		go func(p Pool) { errs <- s.runPool(ctx, p) }(p)
+	}
+
+	var err error
+	for range s.cfg.Pools {
+		if e := <-errs; err == nil {
+			err = e
+			cancel()
+		}
+	}
+
+	if err == nil {
+		<-ctx.Done()
+		err = ctx.Err()
+	}
+
+	return err
+}
+
+func (s *Soundscape) startBed(b Bed) error {
+	if err := s.p.TrackGain(b.Track, b.Gain); err != nil {
+		return err
+	}
+
+	if err := s.p.TrackLoop(b.Track, true); err != nil {
+		return err
+	}
+
+	return s.p.TrackPlayPoly(b.Track, b.Output, true)
+}
+
+func (s *Soundscape) runPool(ctx context.Context, p Pool) error {
+	trg := random.NewTrigger(s.p, p.Output, p.Sounds)
+	trg.Seed(s.int63())
+
+	for {
+		if err := sleep(ctx, s.between(float64(p.MinInterval), float64(p.MaxInterval))); err != nil {
+			return err
+		}
+
+		if !s.available() {
+			continue
+		}
+
+		trk, err := trg.Pick()
+		if err == random.ErrCoolingDown {
+			continue
+		}
+
+		if err := s.fire(p, trk); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Soundscape) fire(p Pool, trk int) error {
+	gain := tsunami.Gain(s.between(float64(p.MinGain), float64(p.MaxGain)))
+	if err := s.p.TrackGain(trk, gain); err != nil {
+		return err
+	}
+
+	if p.MinPitch != 0 || p.MaxPitch != 0 {
+		pitch := s.between(p.MinPitch, p.MaxPitch)
+		if err := s.p.SamplerateOffset(p.Output, tsunami.SemitonesToOffset(pitch)); err != nil {
+			return err
+		}
+	}
+
+	if err := s.p.TrackPlayPoly(trk, p.Output, false); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.playing = append(s.playing, trk)
+	s.mu.Unlock()
+	return nil
+}
+
+// available reports whether a one-shot can be fired within the voice budget.
+func (s *Soundscape) available() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	playing := s.playing[:0]
+	for _, trk := range s.playing {
+		if s.p.IsTrackPlaying(trk) {
+			playing = append(playing, trk)
+		}
+	}
+
+	s.playing = playing
+	return len(s.cfg.Beds)+len(s.playing) < s.cfg.MaxVoices
+}
+
+func (s *Soundscape) stop() {
+	for _, b := range s.cfg.Beds {
+		s.p.TrackStop(b.Track)
+	}
+
+	s.mu.Lock()
+	playing := s.playing
+	s.playing = nil
+	s.mu.Unlock()
+
+	for _, trk := range playing {
+		s.p.TrackStop(trk)
+	}
+}
+
+// between returns a random value between min and max.
+func (s *Soundscape) between(min, max float64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return min + (max-min)*s.rand.Float64()
+}
+
+func (s *Soundscape) int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rand.Int63()
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, d float64) error {
+	timer := time.NewTimer(time.Duration(d))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
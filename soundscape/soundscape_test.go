@@ -0,0 +1,75 @@
+package soundscape
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/random"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestSoundscape(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	s := New(tsunami.NewTsunamiTransport(port), Config{
+		Beds: []Bed{{Track: 1, Output: 0, Gain: -6}},
+		Pools: []Pool{{
+			Sounds:      []random.Sound{{Track: 10, Weight: 1}},
+			Output:      1,
+			MinInterval: 5 * time.Millisecond,
+			MaxInterval: 10 * time.Millisecond,
+			MinGain:     -20,
+			MaxGain:     -10,
+			MinPitch:    -2,
+			MaxPitch:    2,
+		}},
+		MaxVoices: 2,
+	})
+	s.Seed(1)
+
+	// the one-shot keeps playing, so the budget allows a single one.
+	port.Push(&protocol.TrackReport{Track: 10, Voice: 1, Playing: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := s.Run(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loops, plays, offsets, stops int
+	for _, m := range msgs {
+		switch m := m.(type) {
+		case *protocol.TrackControl:
+			switch m.Code {
+			case tsunami.TRK_LOOP_ON:
+				loops++
+			case tsunami.TRK_PLAY_POLY:
+				plays++
+			case tsunami.TRK_STOP:
+				stops++
+			}
+		case *protocol.TrackVolume:
+			if m.Track == 10 && (m.Gain < -20 || m.Gain > -10) {
+				t.Errorf("one-shot gain %d out of range", m.Gain)
+			}
+		case *protocol.SamplerateOffset:
+			offsets++
+			limit := int16(tsunami.SemitonesToOffset(2))
+			if m.Output != 1 || m.Offset < -limit || m.Offset > limit {
+				t.Errorf("unexpected offset %#v", m)
+			}
+		}
+	}
+
+	if loops != 1 || plays != 2 || offsets != 1 || stops != 2 {
+		t.Errorf("got %d loops, %d plays, %d offsets and %d stops", loops, plays, offsets, stops)
+	}
+}
@@ -0,0 +1,44 @@
+package tsunami
+
+import "context"
+
+// Tracer starts the spans of a distributed tracing system, like
+// OpenTelemetry, see the oteltrace package. The attributes are key and value
+// pairs, like the ones of Logger.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...interface{}) (context.Context, Span)
+}
+
+// Span is an operation traced by a Tracer.
+type Span interface {
+	// End ends the span, with the error of the operation, if any.
+	End(err error)
+}
+
+// WithTracer traces every command sent to the board with a "tsunami.command"
+// span, and its write to the port with a "tsunami.write" child span. The time
+// between both is spent waiting for the other commands and the rate limit.
+// The commands sent through a view returned by WithContext are children of
+// the span of its context, eg. the one of a cue, see show.CueStack.GoContext.
+func WithTracer(tr Tracer) Option {
+	return func(t *Tsunami) {
+		t.spans = tr
+	}
+}
+
+// WithContext returns a view of the connection sending the commands with the
+// given context, for the spans of WithTracer. The view shares the port and
+// the state of t, closing one closes both.
+func (t *Tsunami) WithContext(ctx context.Context) *Tsunami {
+	return &Tsunami{connection: t.connection, ctx: ctx}
+}
+
+// Context returns the context of the commands sent, see WithContext, the
+// background context if not set.
+func (t *Tsunami) Context() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+
+	return t.ctx
+}
@@ -0,0 +1,67 @@
+package tsunami
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type spanKey struct{}
+
+type failPort struct{ discardPort }
+
+func (failPort) Write(b []byte) (int, error) { return 0, io.ErrClosedPipe }
+
+// recordingTracer records the spans as "parent > name", and their errors.
+type recordingTracer struct {
+	spans []string
+	errs  []error
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, attrs ...interface{}) (context.Context, Span) {
+	parent, _ := ctx.Value(spanKey{}).(string)
+	r.spans = append(r.spans, parent+" > "+name)
+	return context.WithValue(ctx, spanKey{}, name), r
+}
+
+func (r *recordingTracer) End(err error) {
+	r.errs = append(r.errs, err)
+}
+
+func TestWithTracer(t *testing.T) {
+	tr := &recordingTracer{}
+	ts := NewTsunamiTransport(discardPort{}, WithTracer(tr))
+
+	if err := ts.TrackGain(19, -6); err != nil {
+		t.Fatal(err)
+	}
+
+	cue := ts.WithContext(context.WithValue(context.Background(), spanKey{}, "cue"))
+	if err := cue.TrackPlayPoly(19, 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		" > tsunami.command", "tsunami.command > tsunami.write",
+		"cue > tsunami.command", "tsunami.command > tsunami.write",
+	}
+
+	if !reflect.DeepEqual(tr.spans, expected) {
+		t.Errorf("unexpected spans %q", tr.spans)
+	}
+
+	if cue.connection != ts.connection {
+		t.Errorf("the view should share the state of the connection")
+	}
+
+	tr.spans, tr.errs = nil, nil
+	ts = NewTsunamiTransport(failPort{}, WithTracer(tr))
+	if err := ts.StopAllTracks(); err == nil {
+		t.Fatal("the write should fail")
+	}
+
+	if len(tr.errs) != 2 || tr.errs[0] != io.ErrClosedPipe || tr.errs[1] != io.ErrClosedPipe {
+		t.Errorf("the spans should end with the error, got %v", tr.errs)
+	}
+}
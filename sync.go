@@ -0,0 +1,104 @@
+package tsunami
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TrackCue is a track to be played on a board by Manager.PlaySynced.
+type TrackCue struct {
+	Track  int
+	Output int
+	Lock   bool
+}
+
+// latencySamples is the number of round trips measured by MeasureLatency.
+const latencySamples = 5
+
+// MeasureLatency measures the latency of every board registered, as half the
+// median round trip of a status request. The latencies are used by
+// PlaySynced to compensate the differences between boards.
+func (m *Manager) MeasureLatency(ctx context.Context) error {
+	for _, name := range m.Names() {
+		t, _ := m.Get(name)
+
+		rtts := make([]time.Duration, latencySamples)
+		for i := range rtts {
+			start := time.Now()
+			if _, err := t.Status(ctx); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+
+			rtts[i] = time.Since(start)
+		}
+
+		sort.Slice(rtts, func(i, j int) bool { return rtts[i] < rtts[j] })
+
+		m.mu.Lock()
+		m.latency[name] = rtts[len(rtts)/2] / 2
+		m.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Latency returns the latency of the board measured by MeasureLatency, zero
+// if it wasn't measured.
+func (m *Manager) Latency(name string) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.latency[name]
+}
+
+// PlaySynced starts the given cues, by board name, at the same time. The
+// tracks are loaded paused with TrackLoad and then resumed with
+// ResumeAllInSync on every board, the resume command is sent first to the
+// boards with higher latency, delaying the rest by the difference, so the
+// tracks start within a few milliseconds of each other.
+//
+// Any track previously loaded and paused on the boards is also resumed.
+func (m *Manager) PlaySynced(cues map[string]TrackCue) error {
+	type target struct {
+		name    string
+		t       *Tsunami
+		latency time.Duration
+	}
+
+	targets := make([]target, 0, len(cues))
+	for name := range cues {
+		t, ok := m.Get(name)
+		if !ok {
+			return fmt.Errorf("unknown device %q", name)
+		}
+
+		targets = append(targets, target{name, t, m.Latency(name)})
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].latency > targets[j].latency
+	})
+
+	for _, tg := range targets {
+		c := cues[tg.name]
+		if err := tg.t.TrackLoad(c.Track, c.Output, c.Lock); err != nil {
+			return fmt.Errorf("%s: %w", tg.name, err)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	start, max := time.Now(), targets[0].latency
+	for _, tg := range targets {
+		time.Sleep(time.Until(start.Add(max - tg.latency)))
+		if err := tg.t.ResumeAllInSync(); err != nil {
+			return fmt.Errorf("%s: %w", tg.name, err)
+		}
+	}
+
+	return nil
+}
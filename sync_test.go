@@ -0,0 +1,44 @@
+package tsunami
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+func TestPlaySynced(t *testing.T) {
+	m := NewManager()
+	lobby, theater := &bufferPort{}, &bufferPort{}
+	m.Add("lobby", NewTsunamiTransport(lobby))
+	m.Add("theater", NewTsunamiTransport(theater))
+	m.latency["theater"] = 10 * time.Millisecond
+
+	start := time.Now()
+	err := m.PlaySynced(map[string]TrackCue{
+		"lobby":   {Track: 1, Output: 0},
+		"theater": {Track: 2, Output: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("the board with lower latency should be delayed, took %s", elapsed)
+	}
+
+	for port, trk := range map[*bufferPort]int{lobby: 1, theater: 2} {
+		var expected []byte
+		expected = protocol.TrackControl{Code: TRK_LOAD, Track: uint16(trk), Output: uint8(trk - 1)}.AppendBinary(expected)
+		expected = protocol.ResumeAllSync{}.AppendBinary(expected)
+
+		if !bytes.Equal(port.tx, expected) {
+			t.Errorf("track %d: got % x, expected % x", trk, port.tx, expected)
+		}
+	}
+
+	if err := m.PlaySynced(map[string]TrackCue{"foo": {}}); err == nil {
+		t.Errorf("expected error on unknown device")
+	}
+}
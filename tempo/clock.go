@@ -0,0 +1,302 @@
+// Package tempo provides a musical clock, so the tracks of a board can be
+// triggered in time with a tempo, as needed by live looping and interactive
+// music installations.
+package tempo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// ErrStopped is returned when scheduling on a clock not running.
+var ErrStopped = errors.New("clock stopped")
+
+// Division is the grid the triggers are quantized to.
+type Division uint8
+
+const (
+	// None plays at once.
+	None Division = iota
+	Sixteenth
+	Eighth
+	Beat
+	// Bar is a whole bar, of the beats per bar of the clock.
+	Bar
+)
+
+var divisionNames = map[Division]string{
+	None:      "none",
+	Sixteenth: "sixteenth",
+	Eighth:    "eighth",
+	Beat:      "beat",
+	Bar:       "bar",
+}
+
+func (d Division) String() string {
+	if name, ok := divisionNames[d]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Division(%d)", uint8(d))
+}
+
+// beats returns the length of the division in beats.
+func (d Division) beats(beatsPerBar int) float64 {
+	switch d {
+	case Sixteenth:
+		return 0.25
+	case Eighth:
+		return 0.5
+	case Beat:
+		return 1
+	case Bar:
+		return float64(beatsPerBar)
+	}
+
+	return 0
+}
+
+// Clock is a tempo clock, counting beats from its start at a given BPM.
+type Clock struct {
+	p           tsunami.Player
+	beatsPerBar int
+
+	mu      sync.Mutex
+	bpm     float64
+	running bool
+	// the position of the clock is beat at the time origin.
+	origin  time.Time
+	beat    float64
+	timers  map[*time.Timer]bool
+	onError []func(error)
+	now     func() time.Time
+}
+
+// NewClock returns a stopped clock for the board, at the given tempo and
+// beats per bar.
+func NewClock(p tsunami.Player, bpm float64, beatsPerBar int) *Clock {
+	return &Clock{
+		p:           p,
+		bpm:         bpm,
+		beatsPerBar: beatsPerBar,
+		timers:      make(map[*time.Timer]bool),
+		now:         time.Now,
+	}
+}
+
+// Start starts the clock from the first beat.
+func (c *Clock) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.running, c.origin, c.beat = true, c.now(), 0
+}
+
+// Continue restarts the clock from the beat where it was stopped.
+func (c *Clock) Continue() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.running {
+		c.running, c.origin = true, c.now()
+	}
+}
+
+// Stop stops the clock, canceling the triggers scheduled.
+func (c *Clock) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.beat = c.beatAt(c.now())
+	c.running = false
+	for timer := range c.timers {
+		timer.Stop()
+	}
+
+	c.timers = make(map[*time.Timer]bool)
+}
+
+// Running reports whether the clock is running.
+func (c *Clock) Running() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.running
+}
+
+// BPM returns the tempo in beats per minute.
+func (c *Clock) BPM() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.bpm
+}
+
+// SetBPM changes the tempo, the beats already counted are kept.
+func (c *Clock) SetBPM(bpm float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.beat, c.origin = c.beatAt(now), now
+	c.bpm = bpm
+}
+
+// sync sets the position and tempo of the clock, following an external
+// clock.
+func (c *Clock) sync(beat, bpm float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.beat, c.origin = beat, c.now()
+	if bpm > 0 {
+		c.bpm = bpm
+	}
+}
+
+// BeatsPerBar returns the beats of a bar.
+func (c *Clock) BeatsPerBar() int {
+	return c.beatsPerBar
+}
+
+// Beat returns the position of the clock in beats since its start.
+func (c *Clock) Beat() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.beatAt(c.now())
+}
+
+// Next returns the time of the next boundary of the division, or now for
+// None or a clock stopped. A boundary just passed, within a millisecond, is
+// considered current, so a trigger late by the scheduling isn't delayed a
+// whole division.
+func (c *Clock) Next(div Division) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	at, _ := c.next(div)
+	return at
+}
+
+// next returns the time and beat of the next boundary of the division.
+func (c *Clock) next(div Division) (time.Time, float64) {
+	now := c.now()
+	length := div.beats(c.beatsPerBar)
+	if length == 0 || !c.running {
+		return now, c.beatAt(now)
+	}
+
+	next := math.Ceil(c.beatAt(now)/length) * length
+	if c.timeAt(next - length).After(now.Add(-time.Millisecond)) {
+		next -= length
+	}
+
+	return c.timeAt(next), next
+}
+
+// nextAfter is like next, but skipping the boundaries up to the given beat,
+// the ones already handled by the caller. It reports false if the clock is
+// stopped.
+func (c *Clock) nextAfter(div Division, after float64) (time.Time, float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.running {
+		return time.Time{}, 0, false
+	}
+
+	at, beat := c.next(div)
+	if beat <= after {
+		beat = after + div.beats(c.beatsPerBar)
+		at = c.timeAt(beat)
+	}
+
+	return at, beat, true
+}
+
+// beatAt returns the beat at the given time.
+func (c *Clock) beatAt(t time.Time) float64 {
+	if !c.running {
+		return c.beat
+	}
+
+	return c.beat + t.Sub(c.origin).Minutes()*c.bpm
+}
+
+// timeAt returns the time of the given beat.
+func (c *Clock) timeAt(beat float64) time.Time {
+	return c.origin.Add(time.Duration(math.Round((beat - c.beat) / c.bpm * float64(time.Minute))))
+}
+
+// timeOf is timeAt, taking the lock.
+func (c *Clock) timeOf(beat float64) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.timeAt(beat)
+}
+
+// OnError registers a function to be called with the errors of the triggers
+// scheduled.
+func (c *Clock) OnError(fn func(error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onError = append(c.onError, fn)
+}
+
+// PlayQuantized plays the track with TrackPlayPoly at the next boundary of
+// the division, returning at once. The errors playing it are delivered to
+// the functions registered with OnError.
+func (c *Clock) PlayQuantized(trk, out int, quantize Division) error {
+	return c.At(quantize, func() error {
+		return c.p.TrackPlayPoly(trk, out, false)
+	})
+}
+
+// At calls fn at the next boundary of the division, returning at once. The
+// error returned by fn is delivered to the functions registered with
+// OnError.
+func (c *Clock) At(div Division, fn func() error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.running {
+		return ErrStopped
+	}
+
+	at, _ := c.next(div)
+	c.schedule(at, fn)
+	return nil
+}
+
+// schedule calls fn at the given time, unless the clock is stopped before.
+// It must be called with the lock held.
+func (c *Clock) schedule(at time.Time, fn func() error) {
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(at), func() {
+		c.mu.Lock()
+		if !c.timers[timer] {
+			c.mu.Unlock()
+			return
+		}
+
+		delete(c.timers, timer)
+		handlers := c.onError
+		c.mu.Unlock()
+
+		if err := fn(); err != nil {
+			for _, h := range handlers {
+				h(err)
+			}
+		}
+	})
+
+	c.timers[timer] = true
+}
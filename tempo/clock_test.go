@@ -0,0 +1,82 @@
+package tempo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestClockNext(t *testing.T) {
+	now := time.Now()
+	c := NewClock(nil, 120, 4)
+	c.now = func() time.Time { return now }
+	c.Start()
+
+	// at 120 BPM a beat lasts 500ms and a bar 2s.
+	now = now.Add(700 * time.Millisecond)
+	if b := c.Beat(); math.Abs(b-1.4) > 1e-9 {
+		t.Errorf("unexpected beat %v", b)
+	}
+
+	start := now.Add(-700 * time.Millisecond)
+	for div, expected := range map[Division]time.Duration{
+		None:      700 * time.Millisecond,
+		Sixteenth: 750 * time.Millisecond,
+		Eighth:    750 * time.Millisecond,
+		Beat:      time.Second,
+		Bar:       2 * time.Second,
+	} {
+		if next := c.Next(div).Sub(start); next != expected {
+			t.Errorf("%s: got %s, expected %s", div, next, expected)
+		}
+	}
+
+	c.SetBPM(60)
+	if next := c.Next(Beat).Sub(start); next != 1300*time.Millisecond {
+		t.Errorf("after tempo change got %s", next)
+	}
+}
+
+func TestClockPlayQuantized(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	c := NewClock(tsunami.NewTsunamiTransport(port), 600, 4)
+
+	if err := c.PlayQuantized(1, 0, Beat); err != ErrStopped {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	c.Start()
+	time.Sleep(10 * time.Millisecond)
+	if err := c.PlayQuantized(1, 0, Beat); err != nil {
+		t.Fatal(err)
+	}
+
+	if msgs, _ := port.Messages(); len(msgs) != 0 {
+		t.Fatalf("track played before the beat")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(msgs) != 1 {
+		t.Fatalf("unexpected messages %v", msgs)
+	}
+
+	if m, ok := msgs[0].(*protocol.TrackControl); !ok || m.Code != tsunami.TRK_PLAY_POLY || m.Track != 1 {
+		t.Errorf("unexpected message %#v", msgs[0])
+	}
+
+	c.PlayQuantized(2, 0, Bar)
+	c.Stop()
+	time.Sleep(time.Duration(c.BeatsPerBar()) * 100 * time.Millisecond)
+	if msgs, _ := port.Messages(); len(msgs) != 0 {
+		t.Errorf("stopped clock should cancel the triggers, got %v", msgs)
+	}
+}
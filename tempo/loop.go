@@ -0,0 +1,63 @@
+package tempo
+
+import (
+	"context"
+	"math"
+)
+
+// Loop plays a musical loop retriggered from the clock at bar boundaries,
+// instead of relying on the loop flag of the board. Unless the length of the
+// WAV file matches exactly the tempo, a loop played with the flag drifts
+// away from the clock over a long session, retriggered it stays in time.
+type Loop struct {
+	Track  int
+	Output int
+	Lock   bool
+	// Bars is the length of the loop in bars, one if zero.
+	Bars int
+}
+
+// Run plays the loop from the next bar until the context is done, or
+// ErrStopped if the clock stops, then the track is stopped. On every
+// retrigger the previous copy is stopped, so the file should last slightly
+// longer than its bars, and it's cut at the boundary.
+func (l *Loop) Run(ctx context.Context, c *Clock) error {
+	bars := l.Bars
+	if bars <= 0 {
+		bars = 1
+	}
+
+	defer c.p.TrackStop(l.Track)
+
+	length := float64(bars * c.BeatsPerBar())
+	last := math.Inf(-1)
+	for {
+		at, beat, ok := c.nextAfter(Bar, last)
+		if !ok {
+			return ErrStopped
+		}
+
+		if beat < last+length {
+			beat = last + length
+			at = c.timeOf(beat)
+		}
+
+		if err := sleepUntil(ctx, at); err != nil {
+			return err
+		}
+
+		if !c.Running() {
+			return ErrStopped
+		}
+
+		if err := c.p.TrackStop(l.Track); err != nil {
+			return err
+		}
+
+		if err := c.p.TrackPlayPoly(l.Track, l.Output, l.Lock); err != nil {
+			return err
+		}
+
+		last = beat
+	}
+}
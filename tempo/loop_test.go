@@ -0,0 +1,49 @@
+package tempo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestLoop(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	c := NewClock(tsunami.NewTsunamiTransport(port), 2400, 2)
+	c.Start()
+
+	// at 2400 BPM a bar of 2 beats lasts 50ms, the loop 100ms.
+	l := &Loop{Track: 7, Output: 1, Bars: 2}
+	ctx, cancel := context.WithTimeout(context.Background(), 230*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := l.Run(ctx, c); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var plays, stops int
+	for _, msg := range msgs {
+		if c, ok := msg.(*protocol.TrackControl); ok && c.Track == 7 {
+			switch c.Code {
+			case tsunami.TRK_PLAY_POLY:
+				plays++
+			case tsunami.TRK_STOP:
+				stops++
+			}
+		}
+	}
+
+	// retriggered at 0, 100 and 200ms, then stopped.
+	if plays != 3 || stops != 4 {
+		t.Errorf("got %d plays and %d stops in %s", plays, stops, time.Since(start))
+	}
+}
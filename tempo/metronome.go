@@ -0,0 +1,68 @@
+package tempo
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Metronome plays a click track from a clock, on every beat, with a
+// different sample for the first beat of the bars.
+type Metronome struct {
+	c *Clock
+	// Click and Accent are the tracks of the click and the downbeat click,
+	// Accent may be the same as Click.
+	Click, Accent int
+	Output        int
+}
+
+// NewMetronome returns a metronome playing the click tracks on the output,
+// from the given clock.
+func NewMetronome(c *Clock, out, click, accent int) *Metronome {
+	return &Metronome{c: c, Click: click, Accent: accent, Output: out}
+}
+
+// Run plays the clicks until the context is done, or ErrStopped if the clock
+// stops.
+func (m *Metronome) Run(ctx context.Context) error {
+	last := math.Inf(-1)
+	for {
+		at, beat, ok := m.c.nextAfter(Beat, last)
+		if !ok {
+			return ErrStopped
+		}
+
+		if err := sleepUntil(ctx, at); err != nil {
+			return err
+		}
+
+		// the clock may be stopped or changed while waiting.
+		if !m.c.Running() {
+			return ErrStopped
+		}
+
+		trk := m.Click
+		if int(math.Round(beat))%m.c.BeatsPerBar() == 0 {
+			trk = m.Accent
+		}
+
+		if err := m.c.p.TrackPlayPoly(trk, m.Output, false); err != nil {
+			return err
+		}
+
+		last = beat
+	}
+}
+
+// sleepUntil waits until the given time or until the context is done.
+func sleepUntil(ctx context.Context, t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package tempo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestMetronome(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	c := NewClock(tsunami.NewTsunamiTransport(port), 1200, 3)
+	m := NewMetronome(c, 1, 10, 11)
+
+	if err := m.Run(context.Background()); err != ErrStopped {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// at 1200 BPM a beat lasts 50ms.
+	c.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), 320*time.Millisecond)
+	defer cancel()
+
+	if err := m.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var clicks []int
+	for _, msg := range msgs {
+		if c, ok := msg.(*protocol.TrackControl); ok && c.Code == tsunami.TRK_PLAY_POLY {
+			clicks = append(clicks, int(c.Track))
+		}
+	}
+
+	// the beats at 0 to 300ms, the last one may be missed on a busy host.
+	expected := []int{11, 10, 10, 11, 10, 10, 11}
+	if len(clicks) < 6 || len(clicks) > len(expected) || !reflect.DeepEqual(clicks, expected[:len(clicks)]) {
+		t.Errorf("got clicks %v, expected %v", clicks, expected)
+	}
+}
@@ -0,0 +1,151 @@
+package tempo
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// MIDI realtime and system common messages used by the clock sync.
+const (
+	midiClock             = 0xF8
+	midiStart             = 0xFA
+	midiContinue          = 0xFB
+	midiStop              = 0xFC
+	midiSongPosition      = 0xF2
+	midiTicksPerBeat      = 24
+	midiTicksPerSixteenth = 6
+	// midiTempoWindow is the number of ticks the tempo is averaged over,
+	// a beat.
+	midiTempoWindow = midiTicksPerBeat
+)
+
+// MIDISync slaves a clock to the MIDI clock of a sequencer: the clock is
+// started, stopped and continued with it, and its tempo and position follow
+// the clock ticks, 24 per beat. The MIDI bytes can be fed from any MIDI
+// library with Message, or read from a raw MIDI device with Run.
+type MIDISync struct {
+	c *Clock
+
+	mu      sync.Mutex
+	running bool
+	ticks   int
+	times   []time.Time
+	// status and data are the system common message being parsed.
+	status byte
+	data   []byte
+}
+
+// NewMIDISync returns a MIDISync driving the given clock.
+func NewMIDISync(c *Clock) *MIDISync {
+	return &MIDISync{c: c}
+}
+
+// Message handles the bytes of a MIDI message, the ones unrelated to the
+// clock are ignored.
+func (s *MIDISync) Message(msg ...byte) {
+	for _, b := range msg {
+		s.handle(b)
+	}
+}
+
+// Run reads MIDI bytes from r, like a raw MIDI device, until the context is
+// done or reading fails.
+func (s *MIDISync) Run(ctx context.Context, r io.Reader) error {
+	buf := make([]byte, 64)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := r.Read(buf)
+		s.Message(buf[:n]...)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Running reports whether the sequencer is playing.
+func (s *MIDISync) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.running
+}
+
+func (s *MIDISync) handle(b byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case b >= 0xF8:
+		// realtime messages may be interleaved with any other.
+		s.realtime(b)
+	case b&0x80 != 0:
+		s.status, s.data = b, s.data[:0]
+	case s.status == midiSongPosition:
+		s.data = append(s.data, b)
+		if len(s.data) == 2 {
+			s.songPosition(int(s.data[0]) | int(s.data[1])<<7)
+			s.status = 0
+		}
+	}
+}
+
+func (s *MIDISync) realtime(b byte) {
+	switch b {
+	case midiStart:
+		// the first tick after a start or a song position is the position
+		// itself.
+		s.ticks, s.times, s.running = -1, s.times[:0], true
+		s.c.Start()
+	case midiContinue:
+		s.times, s.running = s.times[:0], true
+		s.c.Continue()
+	case midiStop:
+		s.running = false
+		s.c.Stop()
+	case midiClock:
+		if !s.running {
+			return
+		}
+
+		s.ticks++
+		s.times = append(s.times, time.Now())
+		if len(s.times) > midiTempoWindow+1 {
+			s.times = s.times[1:]
+		}
+
+		s.c.sync(float64(s.ticks)/midiTicksPerBeat, s.tempo())
+	}
+}
+
+// songPosition moves to the given position, in sixteenths, it's only
+// allowed while stopped.
+func (s *MIDISync) songPosition(sixteenths int) {
+	if s.running {
+		return
+	}
+
+	ticks := sixteenths * midiTicksPerSixteenth
+	s.ticks = ticks - 1
+	s.c.sync(float64(ticks)/midiTicksPerBeat, 0)
+}
+
+// tempo returns the tempo of the ticks received, or zero if there aren't
+// enough yet.
+func (s *MIDISync) tempo() float64 {
+	if len(s.times) < 2 {
+		return 0
+	}
+
+	d := s.times[len(s.times)-1].Sub(s.times[0])
+	if d <= 0 {
+		return 0
+	}
+
+	beats := float64(len(s.times)-1) / midiTicksPerBeat
+	return beats / d.Minutes()
+}
@@ -0,0 +1,64 @@
+package tempo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMIDISync(t *testing.T) {
+	c := NewClock(nil, 120, 4)
+	s := NewMIDISync(c)
+
+	s.Message(midiStart)
+	if !c.Running() || !s.Running() {
+		t.Fatalf("start should start the clock")
+	}
+
+	// a beat every 48ms, 1250 BPM.
+	for i := 0; i <= 2*midiTicksPerBeat; i++ {
+		s.Message(midiClock)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	if bpm := c.BPM(); bpm < 400 || bpm > 1300 {
+		t.Errorf("unexpected tempo %v", bpm)
+	}
+
+	s.Message(midiStop)
+	if c.Running() {
+		t.Fatalf("stop should stop the clock")
+	}
+
+	if b := c.Beat(); b < 2 || b > 2.1 {
+		t.Errorf("unexpected beat %v", b)
+	}
+
+	// song position of 4 bars, as 64 sixteenths, with a clock interleaved.
+	s.Message(midiSongPosition, 64, midiClock, 0)
+	if b := c.Beat(); b != 16 {
+		t.Errorf("unexpected beat after song position %v", b)
+	}
+
+	s.Message(midiContinue, midiClock)
+	if b := c.Beat(); math.Abs(b-16) > 0.1 {
+		t.Errorf("unexpected beat after continue %v", b)
+	}
+}
+
+func TestMIDISyncRun(t *testing.T) {
+	c := NewClock(nil, 120, 4)
+	s := NewMIDISync(c)
+
+	r := bytes.NewReader([]byte{0x90, 60, 100, midiStart, midiClock})
+	if err := s.Run(context.Background(), r); err != io.EOF {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if !c.Running() {
+		t.Errorf("clock should be running")
+	}
+}
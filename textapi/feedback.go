@@ -0,0 +1,95 @@
+package textapi
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// feedbackQueue is the number of lines queued for a connection, the
+// feedback is dropped for the connections not reading it.
+const feedbackQueue = 64
+
+// session is a connection, its lines are written by a goroutine of its own,
+// so a slow client doesn't block the board.
+type session struct {
+	lines chan string
+	// done is closed when the lines are written, or writing them fails,
+	// with err.
+	done chan struct{}
+	err  error
+	// watch is guarded by the mutex of the server.
+	watch bool
+}
+
+func (s *Server) open(w io.Writer) *session {
+	c := &session{lines: make(chan string, feedbackQueue), done: make(chan struct{})}
+	go func() {
+		defer close(c.done)
+		for line := range c.lines {
+			if _, c.err = io.WriteString(w, line+"\r\n"); c.err != nil {
+				return
+			}
+		}
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c.watch = s.feedback
+	s.sessions[c] = true
+	return c
+}
+
+// close waits for the lines queued to be written.
+func (s *Server) close(c *session) {
+	s.mu.Lock()
+	delete(s.sessions, c)
+	close(c.lines)
+	s.mu.Unlock()
+
+	<-c.done
+}
+
+// reply queues the reply to a command, it returns the error writing the
+// lines, if any.
+func (c *session) reply(line string) error {
+	select {
+	case <-c.done:
+		return c.err
+	case c.lines <- line:
+		return nil
+	}
+}
+
+// broadcast queues the line to the connections watching.
+func (s *Server) broadcast(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.sessions {
+		if !c.watch {
+			continue
+		}
+
+		select {
+		case c.lines <- line:
+		default:
+		}
+	}
+}
+
+func (s *Server) handle(msg protocol.Message) {
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok {
+		return
+	}
+
+	state := "STOPPED"
+	if r.Playing {
+		state = "PLAYING"
+	}
+
+	s.broadcast(fmt.Sprintf("TRACK %d %s", r.Track, state))
+}
@@ -0,0 +1,450 @@
+// Package textapi exposes the command set of a board as a plain-text line
+// protocol over TCP, so show controllers, Crestron or AMX processors, and
+// humans with netcat or telnet can drive it without any client library.
+//
+// Every command is a line, case insensitive, answered with a line starting
+// with OK, followed by the result of the queries, or ERR and the error:
+//
+//	PLAY 19 OUT 0         plays the track, with LOCK and SOLO as options
+//	LOAD 19 OUT 0         loads the track paused, with LOCK as option
+//	STOP 19
+//	PAUSE 19
+//	RESUME 19
+//	GAIN 19 -6
+//	FADE 19 -70 2000      fades the track along milliseconds, or a duration
+//	                      like 2s, with STOP as option
+//	LOOP 19 ON            or OFF
+//	MASTER 0 -6           sets the gain of the output
+//	STOPALL
+//	RESUMEALL
+//	STATUS 19             answers PLAYING or STOPPED
+//	VERSION               answers the version of the board
+//	WATCH ON              or OFF, sends the feedback to the connection
+//	HELP
+//	QUIT                  closes the connection
+//
+// The connections watching receive the changes of the state as lines of
+// their own, interleaved with the replies, so the buttons of the control
+// surfaces follow the board, whoever changed it:
+//
+//	TRACK 19 PLAYING      or STOPPED, requires a tsunami.Board with
+//	                      reporting enabled
+//	GAIN 19 -6            the gain set by a connection
+//	MASTER 0 -6           the gain of the output set by a connection
+//
+// The protocol fits the generic TCP module of Bitfocus Companion, so the
+// board can be played from a Stream Deck: every button sends a command, with
+// \r\n as terminator, and the feedback is received in the response
+// variable of the module, see SetFeedback to send it without WATCH.
+package textapi
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+)
+
+// errQuit is returned by Exec for QUIT.
+var errQuit = errors.New("quit")
+
+const help = "PLAY trk [OUT n] [LOCK] [SOLO], LOAD trk [OUT n] [LOCK], STOP trk, PAUSE trk, " +
+	"RESUME trk, GAIN trk db, FADE trk db ms [STOP], LOOP trk ON|OFF, MASTER out db, " +
+	"STOPALL, RESUMEALL, STATUS trk, VERSION, WATCH ON|OFF, QUIT"
+
+// Server serves the line protocol of a player.
+type Server struct {
+	p      tsunami.Player
+	cancel func()
+
+	mu       sync.Mutex
+	conns    map[net.Conn]bool
+	sessions map[*session]bool
+	feedback bool
+}
+
+// New returns the server of the player. The track reports of the boards
+// implementing tsunami.Board are sent as feedback.
+func New(p tsunami.Player) *Server {
+	s := &Server{p: p, cancel: func() {}, conns: make(map[net.Conn]bool), sessions: make(map[*session]bool)}
+	if b, ok := p.(tsunami.Board); ok {
+		s.cancel = b.Subscribe(s.handle)
+	}
+
+	return s
+}
+
+// Close unsubscribes the server from the board, the connections are closed
+// when the context given to Serve is done.
+func (s *Server) Close() error {
+	s.cancel()
+	return nil
+}
+
+// SetFeedback sets whether the new connections watch the state of the
+// board from the start, as if they sent WATCH ON, for the clients unable to
+// send it on connection.
+func (s *Server) SetFeedback(enable bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.feedback = enable
+}
+
+// ListenAndServe serves the commands of the connections to the address
+// until the context is done, then closes them.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, l)
+}
+
+// Serve is like ListenAndServe, on the listener.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for c := range s.conns {
+			c.Close()
+		}
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+
+		go func() {
+			s.ServeConn(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+// ServeConn runs the commands read from the connection, one per line, until
+// it's closed or QUIT is received.
+func (s *Server) ServeConn(rw io.ReadWriter) error {
+	c := s.open(rw)
+	defer s.close(c)
+
+	scanner := bufio.NewScanner(rw)
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripTelnet(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		result, err := s.exec(c, line)
+		reply := "OK"
+		switch {
+		case err == errQuit:
+			return c.reply("OK")
+		case err != nil:
+			reply = "ERR " + err.Error()
+		case result != "":
+			reply += " " + result
+		}
+
+		if err := c.reply(reply); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// exec is like Exec, for a connection.
+func (s *Server) exec(c *session, line string) (string, error) {
+	args := strings.Fields(strings.ToUpper(line))
+	if len(args) == 0 || args[0] != "WATCH" {
+		return s.Exec(line)
+	}
+
+	if len(args) != 2 || args[1] != "ON" && args[1] != "OFF" {
+		return "", errors.New("expected ON or OFF")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c.watch = args[1] == "ON"
+	return "", nil
+}
+
+// stripTelnet removes the telnet commands, like the option negotiation sent
+// by the telnet clients.
+func stripTelnet(line string) string {
+	if !strings.Contains(line, "\xff") {
+		return line
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] != 0xff {
+			b.WriteByte(line[i])
+			continue
+		}
+
+		// IAC, followed by the command, and the option of WILL, WONT, DO
+		// and DONT.
+		if i+1 < len(line) && line[i+1] >= 0xfb && line[i+1] <= 0xfe {
+			i++
+		}
+
+		i++
+	}
+
+	return b.String()
+}
+
+// Exec runs a command line, returning the result of the queries.
+func (s *Server) Exec(line string) (string, error) {
+	args := strings.Fields(strings.ToUpper(line))
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "PLAY", "LOAD":
+		return "", s.play(cmd, args)
+	case "STOP", "PAUSE", "RESUME":
+		trk, err := track(args, 1)
+		if err != nil {
+			return "", err
+		}
+
+		switch cmd {
+		case "STOP":
+			return "", s.p.TrackStop(trk)
+		case "PAUSE":
+			return "", s.p.TrackPause(trk)
+		}
+
+		return "", s.p.TrackResume(trk)
+	case "GAIN":
+		trk, err := track(args, 2)
+		if err != nil {
+			return "", err
+		}
+
+		g, err := gain(args[1])
+		if err != nil {
+			return "", err
+		}
+
+		if err := s.p.TrackGain(trk, g); err != nil {
+			return "", err
+		}
+
+		s.broadcast(fmt.Sprintf("GAIN %d %s", trk, formatGain(g)))
+		return "", nil
+	case "FADE":
+		return "", s.fade(args)
+	case "LOOP":
+		trk, err := track(args, 2)
+		if err != nil {
+			return "", err
+		}
+
+		switch args[1] {
+		case "ON":
+			return "", s.p.TrackLoop(trk, true)
+		case "OFF":
+			return "", s.p.TrackLoop(trk, false)
+		}
+
+		return "", fmt.Errorf("expected ON or OFF, got %q", args[1])
+	case "MASTER":
+		if len(args) != 2 {
+			return "", errors.New("expected output and gain")
+		}
+
+		out, err := output(args[0])
+		if err != nil {
+			return "", err
+		}
+
+		g, err := gain(args[1])
+		if err != nil {
+			return "", err
+		}
+
+		if err := s.p.MasterGain(out, g); err != nil {
+			return "", err
+		}
+
+		s.broadcast(fmt.Sprintf("MASTER %d %s", out, formatGain(g)))
+		return "", nil
+	case "STOPALL":
+		return "", s.p.StopAllTracks()
+	case "RESUMEALL":
+		return "", s.p.ResumeAllInSync()
+	case "STATUS":
+		trk, err := track(args, 1)
+		if err != nil {
+			return "", err
+		}
+
+		if s.p.IsTrackPlaying(trk) {
+			return "PLAYING", nil
+		}
+
+		return "STOPPED", nil
+	case "VERSION":
+		return s.p.GetVersion(), nil
+	case "WATCH":
+		return "", errors.New("WATCH requires a connection")
+	case "HELP":
+		return help, nil
+	case "QUIT", "EXIT":
+		return "", errQuit
+	}
+
+	return "", fmt.Errorf("unknown command %q", cmd)
+}
+
+func (s *Server) play(cmd string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected track")
+	}
+
+	trk, err := track(args[:1], 1)
+	if err != nil {
+		return err
+	}
+
+	var out int
+	var lock, solo bool
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "OUT":
+			if i++; i == len(args) {
+				return errors.New("expected output")
+			}
+
+			if out, err = output(args[i]); err != nil {
+				return err
+			}
+		case "LOCK":
+			lock = true
+		case "SOLO":
+			if cmd == "LOAD" {
+				return errors.New("SOLO is not an option of LOAD")
+			}
+
+			solo = true
+		default:
+			return fmt.Errorf("unknown option %q", args[i])
+		}
+	}
+
+	switch {
+	case cmd == "LOAD":
+		return s.p.TrackLoad(trk, out, lock)
+	case solo:
+		return s.p.TrackPlaySolo(trk, out, lock)
+	}
+
+	return s.p.TrackPlayPoly(trk, out, lock)
+}
+
+func (s *Server) fade(args []string) error {
+	if len(args) != 3 && (len(args) != 4 || args[3] != "STOP") {
+		return errors.New("expected track, gain, duration and optionally STOP")
+	}
+
+	trk, err := track(args[:1], 1)
+	if err != nil {
+		return err
+	}
+
+	g, err := gain(args[1])
+	if err != nil {
+		return err
+	}
+
+	d, err := duration(args[2])
+	if err != nil {
+		return err
+	}
+
+	return s.p.TrackFade(trk, g, d, len(args) == 4)
+}
+
+// track parses the track of the arguments, checking their number.
+func track(args []string, n int) (int, error) {
+	if len(args) != n {
+		return 0, fmt.Errorf("expected %d arguments, got %d", n, len(args))
+	}
+
+	trk, err := strconv.Atoi(args[0])
+	if err != nil || trk < 1 || trk > tsunami.MaxTracks {
+		return 0, fmt.Errorf("invalid track %q", args[0])
+	}
+
+	return trk, nil
+}
+
+func output(s string) (int, error) {
+	out, err := strconv.Atoi(s)
+	if err != nil || out < 0 || out >= tsunami.MaxOutputs {
+		return 0, fmt.Errorf("invalid output %q", s)
+	}
+
+	return out, nil
+}
+
+func formatGain(g tsunami.Gain) string {
+	return strconv.FormatFloat(float64(g), 'f', -1, 64)
+}
+
+func gain(s string) (tsunami.Gain, error) {
+	g, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid gain %q", s)
+	}
+
+	return tsunami.Gain(g), nil
+}
+
+// duration parses a number of milliseconds, or a duration like 2s.
+func duration(s string) (time.Duration, error) {
+	if ms, err := strconv.Atoi(s); err == nil && ms > 0 {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+
+	d, err := time.ParseDuration(strings.ToLower(s))
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	return d, nil
+}
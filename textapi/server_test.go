@@ -0,0 +1,195 @@
+package textapi
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestServerExec(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	s := New(ts)
+
+	for _, line := range []string{
+		"PLAY 19 OUT 0",
+		"play 20 out 2 lock solo",
+		"LOAD 21",
+		"GAIN 19 -6",
+		"FADE 19 -70 2000 STOP",
+		"FADE 20 -10 1.5s",
+		"LOOP 20 ON",
+		"MASTER 1 -3",
+		"STOPALL",
+	} {
+		if _, err := s.Exec(line); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+	}
+
+	msgs, err := port.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, m := range msgs {
+		got = append(got, protocol.Describe(m))
+	}
+
+	expected := []protocol.Message{
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_POLY, Track: 19},
+		&protocol.TrackControl{Code: tsunami.TRK_PLAY_SOLO, Track: 20, Output: 2, Flags: 1},
+		&protocol.TrackControl{Code: tsunami.TRK_LOAD, Track: 21},
+		&protocol.TrackVolume{Track: 19, Gain: -6},
+		&protocol.TrackFade{Track: 19, Gain: -70, Millis: 2000, Stop: true},
+		&protocol.TrackFade{Track: 20, Gain: -10, Millis: 1500},
+		&protocol.TrackControl{Code: tsunami.TRK_LOOP_ON, Track: 20},
+		&protocol.MasterVolume{Output: 1, Gain: -3},
+		&protocol.StopAll{},
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("unexpected commands %q", got)
+	}
+
+	for i, m := range expected {
+		if got[i] != protocol.Describe(m) {
+			t.Errorf("%d: got %q, expected %q", i, got[i], protocol.Describe(m))
+		}
+	}
+}
+
+func TestServerExecErrors(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(nil))
+	s := New(ts)
+
+	for line, expected := range map[string]string{
+		"JUMP 1":          `unknown command "JUMP"`,
+		"PLAY":            "expected track",
+		"PLAY 0":          `invalid track "0"`,
+		"PLAY 1 OUT 8":    `invalid output "8"`,
+		"PLAY 1 OUT":      "expected output",
+		"PLAY 1 LOUD":     `unknown option "LOUD"`,
+		"LOAD 1 SOLO":     "SOLO is not an option of LOAD",
+		"STOP 1 2":        "expected 1 arguments, got 2",
+		"GAIN 1 loud":     `invalid gain "LOUD"`,
+		"FADE 1 -70":      "expected track, gain, duration and optionally STOP",
+		"FADE 1 -70 0":    `invalid duration "0"`,
+		"LOOP 1 MAYBE":    `expected ON or OFF, got "MAYBE"`,
+		"MASTER 1":        "expected output and gain",
+		"FADE 1 -70 1 GO": "expected track, gain, duration and optionally STOP",
+	} {
+		if _, err := s.Exec(line); err == nil || err.Error() != expected {
+			t.Errorf("%s: unexpected error %v", line, err)
+		}
+	}
+}
+
+func TestServerServe(t *testing.T) {
+	ts := tsunami.NewTsunamiTransport(transport.NewLoopback(nil))
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go New(ts).Serve(ctx, l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	// a telnet client negotiating an option first.
+	conn.Write([]byte("\xff\xfd\x03STOPALL\r\n\r\nSTATUS 19\r\nJUMP\r\nquit\r\n"))
+
+	var lines []string
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	expected := []string{"OK", "OK STOPPED", `ERR unknown command "JUMP"`, "OK"}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected replies %q", lines)
+	}
+}
+
+func TestServerFeedback(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	s := New(ts)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Serve(ctx, l)
+
+	s.SetFeedback(true)
+	watcher, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer watcher.Close()
+	lines := bufio.NewScanner(watcher)
+
+	s.SetFeedback(false)
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+	replies := bufio.NewScanner(conn)
+
+	// a command waits for the sessions to be registered.
+	for _, c := range []struct {
+		conn net.Conn
+		r    *bufio.Scanner
+	}{{watcher, lines}, {conn, replies}} {
+		c.conn.Write([]byte("STATUS 19\r\n"))
+		if !c.r.Scan() || c.r.Text() != "OK STOPPED" {
+			t.Fatalf("unexpected reply %q", c.r.Text())
+		}
+	}
+
+	conn.Write([]byte("GAIN 19 -6.5\r\nMASTER 1 -3\r\nWATCH ON\r\n"))
+	for _, expected := range []string{"OK", "OK", "OK"} {
+		if !replies.Scan() || replies.Text() != expected {
+			t.Fatalf("unexpected reply %q", replies.Text())
+		}
+	}
+
+	port.Push(&protocol.TrackReport{Track: 19, Playing: true})
+	if err := ts.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"GAIN 19 -6.5", "MASTER 1 -3", "TRACK 19 PLAYING"} {
+		if !lines.Scan() || lines.Text() != expected {
+			t.Fatalf("unexpected feedback %q, expected %q", lines.Text(), expected)
+		}
+	}
+
+	if !replies.Scan() || replies.Text() != "TRACK 19 PLAYING" {
+		t.Errorf("unexpected feedback %q", replies.Text())
+	}
+
+	if _, err := s.Exec("WATCH ON"); err == nil {
+		t.Error("WATCH should require a connection")
+	}
+}
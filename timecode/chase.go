@@ -0,0 +1,213 @@
+package timecode
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultDropout is how long without timecode a Chaser considers it
+	// stopped.
+	DefaultDropout = 200 * time.Millisecond
+	// DefaultJump is the largest step forward of the timecode a Chaser
+	// considers continuous, any larger is a jump.
+	DefaultJump = time.Second
+)
+
+// Cue is an action of a timeline.
+type Cue struct {
+	At   time.Duration
+	Name string
+	Fire func() error
+}
+
+// Event is a change of the state of the timecode followed by a Chaser.
+type Event uint8
+
+const (
+	// Locked is sent when the timecode starts, or resumes after stopping.
+	Locked Event = iota
+	// Stopped is sent when no timecode is received for a while.
+	Stopped
+	// Jumped is sent when the timecode moves backwards or skips forward.
+	Jumped
+)
+
+var eventNames = map[Event]string{
+	Locked:  "locked",
+	Stopped: "stopped",
+	Jumped:  "jumped",
+}
+
+func (e Event) String() string {
+	if name, ok := eventNames[e]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Event(%d)", uint8(e))
+}
+
+// Chaser follows a timecode, firing the cues of a timeline when it passes
+// them. When the timecode stops the cues stop too, and when it jumps, like
+// when the show is rewound or started from the middle, the cues are
+// relocated without firing the ones skipped.
+type Chaser struct {
+	fps  int
+	cues []Cue
+
+	// Dropout is how long without timecode the chaser considers it
+	// stopped, DefaultDropout if zero.
+	Dropout time.Duration
+	// Jump is the largest step forward considered continuous,
+	// DefaultJump if zero.
+	Jump time.Duration
+
+	mu      sync.Mutex
+	rolling bool
+	pos     time.Duration
+	last    time.Time
+	next    int
+	onEvent []func(Event, time.Duration)
+}
+
+// NewChaser returns a chaser of the cues, for timecode at the given nominal
+// frame rate.
+func NewChaser(fps int, cues []Cue) *Chaser {
+	cues = append([]Cue(nil), cues...)
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].At < cues[j].At })
+
+	return &Chaser{fps: fps, cues: cues}
+}
+
+// OnEvent registers a function to be called on every change of state, with
+// the position of the timecode.
+func (c *Chaser) OnEvent(fn func(Event, time.Duration)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onEvent = append(c.onEvent, fn)
+}
+
+// Feed updates the chaser with a timecode received, firing the cues reached.
+func (c *Chaser) Feed(tc Timecode) error {
+	return c.FeedPosition(tc.Duration(c.fps))
+}
+
+// FeedPosition is like Feed, with the position of the timecode as a
+// duration.
+func (c *Chaser) FeedPosition(pos time.Duration) error {
+	c.mu.Lock()
+	var events []Event
+	if !c.rolling {
+		c.rolling = true
+		events = append(events, Locked)
+	}
+
+	// resuming where it stopped the cues continue, otherwise they are
+	// relocated.
+	switch {
+	case c.last.IsZero():
+		c.locate(pos)
+	case pos < c.pos || pos-c.pos > c.jump():
+		if len(events) == 0 {
+			events = append(events, Jumped)
+		}
+
+		c.locate(pos)
+	}
+
+	c.pos, c.last = pos, time.Now()
+
+	var cues []Cue
+	for c.next < len(c.cues) && c.cues[c.next].At <= pos {
+		cues = append(cues, c.cues[c.next])
+		c.next++
+	}
+
+	handlers := c.onEvent
+	c.mu.Unlock()
+
+	for _, e := range events {
+		for _, fn := range handlers {
+			fn(e, pos)
+		}
+	}
+
+	var err error
+	for _, cue := range cues {
+		if cue.Fire == nil {
+			continue
+		}
+
+		if e := cue.Fire(); e != nil && err == nil {
+			err = fmt.Errorf("cue %q: %w", cue.Name, e)
+		}
+	}
+
+	return err
+}
+
+// locate moves the next cue to the first one at or after pos.
+func (c *Chaser) locate(pos time.Duration) {
+	c.next = sort.Search(len(c.cues), func(i int) bool { return c.cues[i].At >= pos })
+}
+
+func (c *Chaser) jump() time.Duration {
+	if c.Jump == 0 {
+		return DefaultJump
+	}
+
+	return c.Jump
+}
+
+func (c *Chaser) dropout() time.Duration {
+	if c.Dropout == 0 {
+		return DefaultDropout
+	}
+
+	return c.Dropout
+}
+
+// Position returns the last position of the timecode, and whether it's
+// rolling.
+func (c *Chaser) Position() (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pos, c.rolling
+}
+
+// Watch detects the timecode stopping until the context is done. It must be
+// running for the Stopped events to be sent.
+func (c *Chaser) Watch(ctx context.Context) error {
+	ticker := time.NewTicker(c.dropout() / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.check()
+		}
+	}
+}
+
+func (c *Chaser) check() {
+	c.mu.Lock()
+	if !c.rolling || time.Since(c.last) < c.dropout() {
+		c.mu.Unlock()
+		return
+	}
+
+	c.rolling = false
+	pos, handlers := c.pos, c.onEvent
+	c.mu.Unlock()
+
+	for _, fn := range handlers {
+		fn(Stopped, pos)
+	}
+}
@@ -0,0 +1,86 @@
+package timecode
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestChaser(t *testing.T) {
+	var fired []string
+	cue := func(name string, at time.Duration) Cue {
+		return Cue{At: at, Name: name, Fire: func() error {
+			fired = append(fired, name)
+			return nil
+		}}
+	}
+
+	c := NewChaser(25, []Cue{
+		cue("b", 2*time.Second),
+		cue("a", time.Second),
+		cue("c", 10*time.Second),
+	})
+
+	var events []Event
+	c.OnEvent(func(e Event, pos time.Duration) { events = append(events, e) })
+
+	for _, pos := range []time.Duration{500 * time.Millisecond, time.Second, 1500 * time.Millisecond, 2500 * time.Millisecond} {
+		c.FeedPosition(pos)
+	}
+
+	if !reflect.DeepEqual(fired, []string{"a", "b"}) {
+		t.Errorf("unexpected cues %v", fired)
+	}
+
+	// rewind, the cues are fired again.
+	c.FeedPosition(0)
+	c.FeedPosition(time.Second)
+
+	// skipping forward over c.
+	c.FeedPosition(11 * time.Second)
+
+	if !reflect.DeepEqual(fired, []string{"a", "b", "a"}) {
+		t.Errorf("unexpected cues after jumps %v", fired)
+	}
+
+	if !reflect.DeepEqual(events, []Event{Locked, Jumped, Jumped}) {
+		t.Errorf("unexpected events %v", events)
+	}
+}
+
+func TestChaserDropout(t *testing.T) {
+	c := NewChaser(25, []Cue{{At: 150 * time.Millisecond}})
+	c.Dropout = 20 * time.Millisecond
+
+	var mu sync.Mutex
+	var events []Event
+	c.OnEvent(func(e Event, pos time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, e)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Watch(ctx)
+
+	c.FeedPosition(100 * time.Millisecond)
+	time.Sleep(60 * time.Millisecond)
+
+	if _, rolling := c.Position(); rolling {
+		t.Errorf("timecode should be stopped")
+	}
+
+	// resuming where it stopped isn't a jump.
+	c.FeedPosition(120 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !reflect.DeepEqual(events, []Event{Locked, Stopped, Locked}) {
+		t.Errorf("unexpected events %v", events)
+	}
+}
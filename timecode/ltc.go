@@ -0,0 +1,151 @@
+package timecode
+
+import "encoding/binary"
+
+const (
+	// ltcBits is the length of a LTC frame.
+	ltcBits = 80
+	// ltcThreshold is the hysteresis of the zero crossings of the signal,
+	// filtering the noise around the silence.
+	ltcThreshold = 512
+)
+
+// ltcSync is the sync word ending every LTC frame, as transmitted.
+var ltcSync = [16]byte{0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 1}
+
+// LTCDecoder decodes Linear Timecode from audio, like the output of a sound
+// card capturing the LTC feed, as mono signed 16-bit little-endian PCM
+// written to it, eg. with io.Copy. The bit rate is tracked, so timecode
+// played slightly faster or slower is decoded too.
+type LTCDecoder struct {
+	fn func(Timecode)
+
+	// odd is the first byte of a sample split between writes.
+	odd    []byte
+	level  int
+	count  int
+	period float64
+	half   bool
+	bits   []byte
+}
+
+// NewLTCDecoder returns a decoder of audio at the given sample rate, for
+// timecode at the given nominal frame rate. fn is called with every frame
+// decoded.
+func NewLTCDecoder(sampleRate, fps int, fn func(Timecode)) *LTCDecoder {
+	return &LTCDecoder{
+		fn:     fn,
+		level:  -1,
+		period: float64(sampleRate) / float64(fps*ltcBits),
+		bits:   make([]byte, 0, ltcBits),
+	}
+}
+
+// Write decodes the given PCM data, it never fails.
+func (d *LTCDecoder) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(d.odd) > 0 && len(p) > 0 {
+		d.sample(int16(binary.LittleEndian.Uint16([]byte{d.odd[0], p[0]})))
+		d.odd, p = nil, p[1:]
+	}
+
+	for ; len(p) >= 2; p = p[2:] {
+		d.sample(int16(binary.LittleEndian.Uint16(p)))
+	}
+
+	if len(p) > 0 {
+		d.odd = []byte{p[0]}
+	}
+
+	return n, nil
+}
+
+// Samples decodes the given samples.
+func (d *LTCDecoder) Samples(samples []int16) {
+	for _, s := range samples {
+		d.sample(s)
+	}
+}
+
+func (d *LTCDecoder) sample(s int16) {
+	d.count++
+	switch {
+	case d.level < 0 && s > ltcThreshold:
+		d.level = 1
+	case d.level > 0 && s < -ltcThreshold:
+		d.level = -1
+	default:
+		return
+	}
+
+	d.transition(float64(d.count))
+	d.count = 0
+}
+
+// transition handles a transition of the biphase mark code, a zero has a
+// single transition per bit and a one has another in the middle.
+func (d *LTCDecoder) transition(interval float64) {
+	if interval > d.period*3/4 {
+		// a half bit not completed is a glitch, it's dropped.
+		d.half = false
+		if interval < d.period*3/2 {
+			d.track(interval)
+		}
+
+		d.bit(0)
+		return
+	}
+
+	if !d.half {
+		d.half = true
+		return
+	}
+
+	d.half = false
+	d.bit(1)
+}
+
+// track adjusts the bit period to the last one measured.
+func (d *LTCDecoder) track(period float64) {
+	d.period = d.period*0.9 + period*0.1
+}
+
+func (d *LTCDecoder) bit(b byte) {
+	if len(d.bits) == ltcBits {
+		copy(d.bits, d.bits[1:])
+		d.bits = d.bits[:ltcBits-1]
+	}
+
+	d.bits = append(d.bits, b)
+	if len(d.bits) < ltcBits {
+		return
+	}
+
+	for i, b := range ltcSync {
+		if d.bits[ltcBits-len(ltcSync)+i] != b {
+			return
+		}
+	}
+
+	d.fn(decodeLTC(d.bits))
+	d.bits = d.bits[:0]
+}
+
+func decodeLTC(bits []byte) Timecode {
+	bcd := func(start, n int) int {
+		var v int
+		for i := 0; i < n; i++ {
+			v |= int(bits[start+i]) << i
+		}
+
+		return v
+	}
+
+	return Timecode{
+		Frames:  bcd(0, 4) + 10*bcd(8, 2),
+		Drop:    bits[10] == 1,
+		Seconds: bcd(16, 4) + 10*bcd(24, 3),
+		Minutes: bcd(32, 4) + 10*bcd(40, 3),
+		Hours:   bcd(48, 4) + 10*bcd(56, 2),
+	}
+}
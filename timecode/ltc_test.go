@@ -0,0 +1,78 @@
+package timecode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// encodeLTC returns the LTC audio of the given frames, as 16-bit PCM.
+func encodeLTC(sampleRate, fps int, frames []Timecode) []byte {
+	var bits []byte
+	for _, tc := range frames {
+		frame := make([]byte, ltcBits)
+		bcd := func(start, n, v int) {
+			for i := 0; i < n; i++ {
+				frame[start+i] = byte(v>>i) & 1
+			}
+		}
+
+		bcd(0, 4, tc.Frames%10)
+		bcd(8, 2, tc.Frames/10)
+		if tc.Drop {
+			frame[10] = 1
+		}
+
+		bcd(16, 4, tc.Seconds%10)
+		bcd(24, 3, tc.Seconds/10)
+		bcd(32, 4, tc.Minutes%10)
+		bcd(40, 3, tc.Minutes/10)
+		bcd(48, 4, tc.Hours%10)
+		bcd(56, 2, tc.Hours/10)
+		copy(frame[64:], ltcSync[:])
+		bits = append(bits, frame...)
+	}
+
+	// biphase mark, a transition at every bit, and another in the middle
+	// of the ones.
+	var buf bytes.Buffer
+	perBit := sampleRate / (fps * ltcBits)
+	level := int16(8000)
+	for _, b := range bits {
+		level = -level
+		for i := 0; i < perBit; i++ {
+			if b == 1 && i == perBit/2 {
+				level = -level
+			}
+
+			binary.Write(&buf, binary.LittleEndian, level)
+		}
+	}
+
+	// the transition ending the last bit.
+	binary.Write(&buf, binary.LittleEndian, -level)
+
+	return buf.Bytes()
+}
+
+func TestLTCDecoder(t *testing.T) {
+	frames := []Timecode{
+		{Hours: 10, Minutes: 59, Seconds: 59, Frames: 23},
+		{Hours: 11, Minutes: 0, Seconds: 0, Frames: 0},
+		{Hours: 11, Minutes: 0, Seconds: 0, Frames: 1, Drop: true},
+	}
+
+	var decoded []Timecode
+	d := NewLTCDecoder(48000, 25, func(tc Timecode) { decoded = append(decoded, tc) })
+
+	// written in odd sized chunks, splitting the samples.
+	if _, err := io.CopyBuffer(d, bytes.NewReader(encodeLTC(48000, 25, frames)), make([]byte, 333)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decoded, frames) {
+		t.Errorf("unexpected frames %v", decoded)
+	}
+}
@@ -0,0 +1,169 @@
+package timecode
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// MIDI messages carrying MIDI Time Code.
+const (
+	mtcQuarterFrame = 0xF1
+	mtcSysEx        = 0xF0
+	mtcSysExEnd     = 0xF7
+)
+
+// mtcRates are the frame rates of the rate codes of MTC.
+var mtcRates = [4]int{24, 25, 30, 30}
+
+// MTCDecoder decodes MIDI Time Code, from the quarter frame messages sent
+// while rolling and the full frame messages sent when locating. The MIDI
+// bytes can be fed from any MIDI library with Message, or read from a raw
+// MIDI device with Run.
+type MTCDecoder struct {
+	fn func(Timecode)
+
+	mu     sync.Mutex
+	pieces [8]byte
+	// seen are the pieces received of the current cycle, as a mask.
+	seen   uint8
+	rate   int
+	status byte
+	data   []byte
+}
+
+// NewMTCDecoder returns a decoder calling fn with every timecode received,
+// every two frames while rolling.
+func NewMTCDecoder(fn func(Timecode)) *MTCDecoder {
+	return &MTCDecoder{fn: fn, rate: 30}
+}
+
+// Rate returns the nominal frame rate of the timecode received, 30 for
+// 29.97 fps drop frame, to be used with NewChaser.
+func (d *MTCDecoder) Rate() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.rate
+}
+
+// Message handles the bytes of a MIDI message, the ones unrelated to the
+// timecode are ignored.
+func (d *MTCDecoder) Message(msg ...byte) {
+	for _, b := range msg {
+		if tc, ok := d.handle(b); ok {
+			d.fn(tc)
+		}
+	}
+}
+
+// Run reads MIDI bytes from r, like a raw MIDI device, until the context is
+// done or reading fails.
+func (d *MTCDecoder) Run(ctx context.Context, r io.Reader) error {
+	buf := make([]byte, 64)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := r.Read(buf)
+		d.Message(buf[:n]...)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (d *MTCDecoder) handle(b byte) (Timecode, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch {
+	case b >= 0xF8:
+		// realtime messages may be interleaved with any other.
+		return Timecode{}, false
+	case b == mtcSysExEnd:
+		d.status = 0
+		return d.fullFrame()
+	case b&0x80 != 0:
+		d.status, d.data = b, d.data[:0]
+		return Timecode{}, false
+	case d.status == mtcQuarterFrame:
+		d.status = 0
+		return d.quarterFrame(b)
+	case d.status == mtcSysEx:
+		d.data = append(d.data, b)
+	}
+
+	return Timecode{}, false
+}
+
+// quarterFrame handles a piece of the timecode, the whole of it is known
+// after the eight pieces, two frames after it started.
+func (d *MTCDecoder) quarterFrame(b byte) (Timecode, bool) {
+	piece := b >> 4 & 0x07
+	d.pieces[piece] = b & 0x0F
+	if piece == 0 {
+		d.seen = 0
+	}
+
+	d.seen |= 1 << piece
+	if piece != 7 || d.seen != 0xFF {
+		return Timecode{}, false
+	}
+
+	p := d.pieces
+	code := p[7] >> 1 & 0x03
+	d.rate = mtcRates[code]
+
+	tc := Timecode{
+		Frames:  int(p[0] | (p[1]&0x01)<<4),
+		Seconds: int(p[2] | (p[3]&0x03)<<4),
+		Minutes: int(p[4] | (p[5]&0x03)<<4),
+		Hours:   int(p[6] | (p[7]&0x01)<<4),
+		Drop:    code == 2,
+	}
+
+	return tc.add(2, d.rate), true
+}
+
+// fullFrame handles a full frame SysEx, F0 7F <device> 01 01 hr mn sc fr F7.
+func (d *MTCDecoder) fullFrame() (Timecode, bool) {
+	m := d.data
+	d.data = d.data[:0]
+	if len(m) != 8 || m[0] != 0x7F || m[2] != 0x01 || m[3] != 0x01 {
+		return Timecode{}, false
+	}
+
+	code := m[4] >> 5 & 0x03
+	d.rate = mtcRates[code]
+	d.seen = 0
+
+	return Timecode{
+		Hours:   int(m[4] & 0x1F),
+		Minutes: int(m[5]),
+		Seconds: int(m[6]),
+		Frames:  int(m[7]),
+		Drop:    code == 2,
+	}, true
+}
+
+// add returns the timecode the given frames later.
+func (tc Timecode) add(frames, fps int) Timecode {
+	tc.Frames += frames
+	tc.Seconds += tc.Frames / fps
+	tc.Frames %= fps
+	tc.Minutes += tc.Seconds / 60
+	tc.Seconds %= 60
+	tc.Hours += tc.Minutes / 60
+	tc.Minutes %= 60
+	tc.Hours %= 24
+
+	// the first two frame numbers of every minute but the tenths don't
+	// exist in drop frame.
+	if tc.Drop && tc.Seconds == 0 && tc.Frames < 2 && tc.Minutes%10 != 0 {
+		tc.Frames = 2
+	}
+
+	return tc
+}
@@ -0,0 +1,66 @@
+package timecode
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// quarterFrames returns the quarter frame messages of the timecode, at 25
+// fps.
+func quarterFrames(tc Timecode) []byte {
+	values := []int{
+		tc.Frames & 0x0F, tc.Frames >> 4,
+		tc.Seconds & 0x0F, tc.Seconds >> 4,
+		tc.Minutes & 0x0F, tc.Minutes >> 4,
+		tc.Hours & 0x0F, tc.Hours>>4 | 1<<1,
+	}
+
+	var msgs []byte
+	for piece, v := range values {
+		msgs = append(msgs, mtcQuarterFrame, byte(piece<<4|v))
+	}
+
+	return msgs
+}
+
+func TestMTCDecoder(t *testing.T) {
+	var tcs []Timecode
+	d := NewMTCDecoder(func(tc Timecode) { tcs = append(tcs, tc) })
+
+	// a cycle started in the middle is ignored.
+	d.Message(quarterFrames(Timecode{Hours: 1, Seconds: 59, Frames: 20})[8:]...)
+	d.Message(quarterFrames(Timecode{Hours: 1, Seconds: 59, Frames: 23})...)
+	// full frame at 30 fps drop frame, with a clock interleaved.
+	d.Message(0xF0, 0x7F, 0x7F, 0x01, 0xF8, 0x01, 2<<5|2, 10, 0, 2, 0xF7)
+
+	expected := []Timecode{
+		{Hours: 1, Minutes: 1, Seconds: 0, Frames: 0},
+		{Hours: 2, Minutes: 10, Seconds: 0, Frames: 2, Drop: true},
+	}
+
+	if !reflect.DeepEqual(tcs, expected) {
+		t.Errorf("got %v, expected %v", tcs, expected)
+	}
+
+	if d.Rate() != 30 {
+		t.Errorf("unexpected rate %d", d.Rate())
+	}
+}
+
+func TestMTCDecoderRun(t *testing.T) {
+	c := NewChaser(25, []Cue{{At: time.Hour, Name: "one hour"}})
+	d := NewMTCDecoder(func(tc Timecode) { c.Feed(tc) })
+
+	r := bytes.NewReader(quarterFrames(Timecode{Hours: 1}))
+	if err := d.Run(context.Background(), r); err != io.EOF {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if pos, rolling := c.Position(); !rolling || pos != time.Hour+80*time.Millisecond {
+		t.Errorf("unexpected position %s", pos)
+	}
+}
@@ -0,0 +1,88 @@
+// Package timecode chases SMPTE timecode, firing the cues of a timeline as
+// the timecode reaches them, the usual way of synchronizing the sound of a
+// show with lights, video and rides. The timecode can be decoded from LTC
+// audio, MIDI Time Code, or read as text from any source like a network
+// connection.
+package timecode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Timecode is a SMPTE timecode.
+type Timecode struct {
+	Hours, Minutes, Seconds, Frames int
+	// Drop is set for drop frame timecode, used with 29.97 fps.
+	Drop bool
+}
+
+// Parse parses a timecode as HH:MM:SS:FF, or HH:MM:SS;FF for drop frame.
+func Parse(s string) (Timecode, error) {
+	var tc Timecode
+	var sep byte
+	if _, err := fmt.Sscanf(s, "%02d:%02d:%02d%c%02d", &tc.Hours, &tc.Minutes, &tc.Seconds, &sep, &tc.Frames); err != nil {
+		return tc, fmt.Errorf("invalid timecode %q", s)
+	}
+
+	switch sep {
+	case ':':
+	case ';':
+		tc.Drop = true
+	default:
+		return tc, fmt.Errorf("invalid timecode %q", s)
+	}
+
+	return tc, nil
+}
+
+// String returns the timecode as HH:MM:SS:FF, or HH:MM:SS;FF for drop frame.
+func (tc Timecode) String() string {
+	sep := ':'
+	if tc.Drop {
+		sep = ';'
+	}
+
+	return fmt.Sprintf("%02d:%02d:%02d%c%02d", tc.Hours, tc.Minutes, tc.Seconds, sep, tc.Frames)
+}
+
+// Frame returns the number of frames since zero at the given nominal frame
+// rate, 30 for 29.97 fps. With drop frame the frame numbers skipped are
+// discounted.
+func (tc Timecode) Frame(fps int) int {
+	minutes := tc.Hours*60 + tc.Minutes
+	frame := (minutes*60+tc.Seconds)*fps + tc.Frames
+	if tc.Drop {
+		// two frame numbers are dropped every minute, except every tenth.
+		frame -= 2 * (minutes - minutes/10)
+	}
+
+	return frame
+}
+
+// Duration returns the time since zero at the given nominal frame rate, 30
+// for 29.97 fps.
+func (tc Timecode) Duration(fps int) time.Duration {
+	d := time.Duration(tc.Frame(fps)) * time.Second / time.Duration(fps)
+	if tc.Drop {
+		d = d * 1001 / 1000
+	}
+
+	return d
+}
+
+// Scan reads timecodes from r, one per line as accepted by Parse, calling fn
+// with each of them, like the timecode sent as text by a show controller
+// over the network. The lines not being timecodes are skipped.
+func Scan(r io.Reader, fn func(Timecode)) error {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if tc, err := Parse(s.Text()); err == nil {
+			fn(tc)
+		}
+	}
+
+	return s.Err()
+}
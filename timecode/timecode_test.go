@@ -0,0 +1,61 @@
+package timecode
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tc, err := Parse("01:02:03:04")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tc != (Timecode{Hours: 1, Minutes: 2, Seconds: 3, Frames: 4}) || tc.String() != "01:02:03:04" {
+		t.Errorf("unexpected timecode %v", tc)
+	}
+
+	tc, err = Parse("00:10:00;00")
+	if err != nil || !tc.Drop || tc.String() != "00:10:00;00" {
+		t.Errorf("unexpected drop frame timecode %v, %v", tc, err)
+	}
+
+	for _, s := range []string{"", "01:02:03", "01:02:03.04"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("%q should fail", s)
+		}
+	}
+}
+
+func TestTimecodeDuration(t *testing.T) {
+	tc := Timecode{Minutes: 1, Seconds: 2, Frames: 12}
+	if d := tc.Duration(25); d != 62*time.Second+480*time.Millisecond {
+		t.Errorf("unexpected duration %s", d)
+	}
+
+	// ten minutes of drop frame timecode are exactly 17982 frames.
+	tc = Timecode{Minutes: 10, Drop: true}
+	if f := tc.Frame(30); f != 17982 {
+		t.Errorf("unexpected frame %d", f)
+	}
+
+	if d := tc.Duration(30); d.Round(10*time.Millisecond) != 10*time.Minute {
+		t.Errorf("unexpected drop frame duration %s", d)
+	}
+}
+
+func TestScan(t *testing.T) {
+	var tcs []string
+	err := Scan(strings.NewReader("00:00:01:00\nhello\n00:00:01:01\n"), func(tc Timecode) {
+		tcs = append(tcs, tc.String())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(tcs, []string{"00:00:01:00", "00:00:01:01"}) {
+		t.Errorf("unexpected timecodes %v", tcs)
+	}
+}
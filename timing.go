@@ -0,0 +1,81 @@
+package tsunami
+
+import "time"
+
+// EndsAt returns the estimated time the track will end, false if it isn't
+// playing, it's paused or looping, or its duration is unknown, see
+// SetDurations. Unlike the track reports, it's known in advance, eg. to
+// schedule what follows the track.
+func (t *Tsunami) EndsAt(trk int) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.playbacks[trk]
+	if !ok || p.paused || t.loops[trk] {
+		return time.Time{}, false
+	}
+
+	pos, _ := t.position(trk)
+	rem, ok := t.remaining(trk, pos)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Now().Add(rem), true
+}
+
+// GapAfter returns the time the given gap after the end of the track ends,
+// when the next track should start, see EndsAt.
+func (t *Tsunami) GapAfter(trk int, gap time.Duration) (time.Time, bool) {
+	end, ok := t.EndsAt(trk)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return end.Add(gap), true
+}
+
+// PlayFor plays the track on the output, with TrackPlayPoly, and stops it
+// after the given time, fading it out during the last fade of it, if any.
+// The stop is skipped if the track was stopped or played again meanwhile,
+// or if it ends before, knowing its duration. The returned function cancels
+// the stop.
+func (t *Tsunami) PlayFor(trk, out int, d, fade time.Duration) (cancel func(), err error) {
+	if err := t.TrackPlayPoly(trk, out, false); err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	seq := t.playbacks[trk].seq
+	if t.durations != nil && !t.loops[trk] {
+		if length, ok := t.durations.Duration(trk); ok && length <= d {
+			t.mu.Unlock()
+			return func() {}, nil
+		}
+	}
+	t.mu.Unlock()
+
+	if fade > d {
+		fade = d
+	}
+
+	timer := time.AfterFunc(d-fade, func() {
+		t.mu.Lock()
+		p, ok := t.playbacks[trk]
+		current := ok && p.seq == seq
+		t.mu.Unlock()
+
+		if !current {
+			return
+		}
+
+		if fade > 0 {
+			t.TrackFade(trk, MinGain, fade, true)
+			return
+		}
+
+		t.TrackStop(trk)
+	})
+
+	return func() { timer.Stop() }, nil
+}
@@ -0,0 +1,79 @@
+package tsunami
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestEndsAt(t *testing.T) {
+	ts := NewTsunamiTransport(transport.NewLoopback(nil))
+	ts.SetDurations(Durations{1: time.Minute})
+
+	if _, ok := ts.EndsAt(1); ok {
+		t.Error("track shouldn't be playing")
+	}
+
+	ts.TrackPlayPoly(1, 0, false)
+	end, ok := ts.EndsAt(1)
+	if !ok || time.Until(end) < 59*time.Second || time.Until(end) > time.Minute {
+		t.Errorf("unexpected end %s", end)
+	}
+
+	if at, ok := ts.GapAfter(1, time.Second); !ok || at.Sub(end) < time.Second-time.Millisecond {
+		t.Errorf("unexpected gap end %s", at)
+	}
+
+	ts.TrackLoop(1, true)
+	if _, ok := ts.EndsAt(1); ok {
+		t.Error("looping tracks don't end")
+	}
+
+	ts.TrackPlayPoly(2, 0, false)
+	if _, ok := ts.EndsAt(2); ok {
+		t.Error("the duration of the track is unknown")
+	}
+}
+
+func TestPlayFor(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	ts.SetDurations(Durations{2: 10 * time.Millisecond})
+
+	if _, err := ts.PlayFor(1, 0, 20*time.Millisecond, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ts.PlayFor(2, 0, 20*time.Millisecond, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel, err := ts.PlayFor(3, 0, 20*time.Millisecond, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+
+	expected := []control{
+		{TRK_PLAY_POLY, 1}, {TRK_PLAY_POLY, 2}, {TRK_PLAY_POLY, 3}, {TRK_STOP, 1},
+	}
+
+	if c := controls(t, port); !reflect.DeepEqual(c, expected) {
+		t.Errorf("unexpected controls %v", c)
+	}
+
+	// a track played again isn't stopped by the previous PlayFor.
+	ts.PlayFor(1, 0, 20*time.Millisecond, 0)
+	time.Sleep(10 * time.Millisecond)
+	ts.TrackPlayPoly(1, 0, false)
+	time.Sleep(30 * time.Millisecond)
+
+	expected = []control{{TRK_PLAY_POLY, 1}, {TRK_PLAY_POLY, 1}}
+	if c := controls(t, port); !reflect.DeepEqual(c, expected) {
+		t.Errorf("unexpected controls %v", c)
+	}
+}
@@ -0,0 +1,51 @@
+package tsunami
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+const (
+	directionTX = "TX"
+	directionRX = "RX"
+)
+
+// tracer writes a line per frame exchanged with the board, eg.:
+//
+//	15:04:05.000000 TX f0 aa 06 0d 01 55 CMD_SET_REPORTING {Enable:true}
+type tracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (t *tracer) frame(dir string, raw []byte, m protocol.Message) {
+	t.printf(dir, raw, "%s", protocol.Describe(m))
+}
+
+func (t *tracer) received(f protocol.Frame, m protocol.Message, err error) {
+	raw, _ := f.MarshalBinary()
+	if err != nil {
+		t.printf(directionRX, raw, "%s error: %s", protocol.CodeString(f.Code), err)
+		return
+	}
+
+	t.frame(directionRX, raw, m)
+}
+
+func (t *tracer) failed(err error) {
+	t.printf(directionRX, nil, "error: %s", err)
+}
+
+func (t *tracer) printf(dir string, raw []byte, format string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fmt.Fprintf(t.w, "%s %s % x %s\n",
+		time.Now().Format("15:04:05.000000"), dir, raw,
+		fmt.Sprintf(format, args...),
+	)
+}
@@ -0,0 +1,63 @@
+package tsunami
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+type bufferPort struct {
+	discardPort
+	rx []byte
+	tx []byte
+}
+
+func (p *bufferPort) Read(b []byte) (int, error) {
+	n := copy(b, p.rx)
+	p.rx = p.rx[n:]
+	return n, nil
+}
+
+func (p *bufferPort) Write(b []byte) (int, error) {
+	p.tx = append(p.tx, b...)
+	return len(b), nil
+}
+
+func TestWithTrace(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	port := &bufferPort{}
+	port.rx = protocol.TrackReport{Track: 19, Voice: 1, Playing: true}.AppendBinary(nil)
+
+	ts := newTestTsunami()
+	ts.port = port
+	WithTrace(buf)(ts)
+
+	if err := ts.TrackGain(19, -6); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.StopAllTracks(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ts.IsTrackPlaying(19) {
+		t.Errorf("track 19 should be playing")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("unexpected trace %q", buf.String())
+	}
+
+	for i, expected := range []string{
+		" TX f0 aa 09 08 13 00 fa ff 55 CMD_TRACK_VOLUME {Track:19 Gain:-6}",
+		" TX f0 aa 05 04 55 CMD_STOP_ALL",
+		" RX f0 aa 09 84 12 00 01 01 55 RSP_TRACK_REPORT {Track:19 Voice:1 Playing:true}",
+	} {
+		if !strings.HasSuffix(lines[i], expected) {
+			t.Errorf("line %d: got %q, expected suffix %q", i, lines[i], expected)
+		}
+	}
+}
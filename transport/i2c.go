@@ -0,0 +1,86 @@
+package transport
+
+import (
+	"io"
+	"time"
+)
+
+// I2CDevice is a device on an I2C bus, Tx writes w and then reads len(r)
+// bytes into r in a single transaction. It is implemented by the *i2c.Dev of
+// periph.io:
+//
+//	bus, _ := i2creg.Open("")
+//	tr := transport.NewI2C(&i2c.Dev{Bus: bus, Addr: addr})
+type I2CDevice interface {
+	Tx(w, r []byte) error
+}
+
+// I2C is a transport over the Qwiic/I2C connector of the board. The board is
+// an I2C slave, so it is polled for new data on every Read.
+type I2C struct {
+	dev  I2CDevice
+	poll time.Duration
+	buf  []byte
+}
+
+// i2cChunkLen is the number of bytes requested in every read transaction.
+const i2cChunkLen = 32
+
+// NewI2C returns a transport over the given I2C device.
+func NewI2C(dev I2CDevice) *I2C {
+	return &I2C{
+		dev:  dev,
+		poll: DefaultReadTimeout,
+		buf:  make([]byte, i2cChunkLen),
+	}
+}
+
+// Read polls the board for pending data. If the board has nothing to send,
+// and so it answers only with idle bytes, Read waits the poll interval and
+// returns zero bytes.
+func (c *I2C) Read(b []byte) (int, error) {
+	n := len(b)
+	if n > len(c.buf) {
+		n = len(c.buf)
+	}
+
+	if err := c.dev.Tx(nil, c.buf[:n]); err != nil {
+		return 0, err
+	}
+
+	if isIdle(c.buf[:n]) {
+		time.Sleep(c.poll)
+		return 0, nil
+	}
+
+	return copy(b, c.buf[:n]), nil
+}
+
+// isIdle reports whether data is made only of idle bytes, 0x00 or 0xff.
+func isIdle(data []byte) bool {
+	for _, b := range data {
+		if b != 0x00 && b != 0xff {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Write sends the given bytes in a single write transaction.
+func (c *I2C) Write(b []byte) (int, error) {
+	if err := c.dev.Tx(b, nil); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Close closes the device if it implements io.Closer.
+func (c *I2C) Close() error {
+	if cl, ok := c.dev.(io.Closer); ok {
+		return cl.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package transport
+
+import (
+	"bytes"
+	"testing"
+)
+
+type fakeI2CDevice struct {
+	written []byte
+	pending []byte
+}
+
+func (d *fakeI2CDevice) Tx(w, r []byte) error {
+	d.written = append(d.written, w...)
+	for i := range r {
+		r[i] = 0xff
+	}
+
+	d.pending = d.pending[copy(r, d.pending):]
+	return nil
+}
+
+func TestI2C(t *testing.T) {
+	dev := &fakeI2CDevice{}
+	c := NewI2C(dev)
+	c.poll = 0
+
+	if _, err := c.Write([]byte{0xf0, 0xaa, 0x05, 0x04, 0x55}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(dev.written, []byte{0xf0, 0xaa, 0x05, 0x04, 0x55}) {
+		t.Errorf("unexpected written bytes % x", dev.written)
+	}
+
+	b := make([]byte, 64)
+	if n, err := c.Read(b); n != 0 || err != nil {
+		t.Fatalf("unexpected read %d, %v", n, err)
+	}
+
+	dev.pending = []byte{0xf0, 0xaa, 0x05, 0x84, 0x55}
+	n, err := c.Read(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(b[:n], []byte{0xf0, 0xaa, 0x05, 0x84, 0x55}) {
+		t.Errorf("unexpected read % x", b[:n])
+	}
+}
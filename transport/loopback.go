@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"io"
+	"sync"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// Responder answers the frames written to a Loopback, returning the messages
+// to be read back, if any.
+type Responder func(f protocol.Frame) []protocol.Message
+
+// Script is a Responder answering every command code with a fixed list of
+// messages, eg. the version string to CMD_GET_VERSION.
+type Script map[protocol.Command][]protocol.Message
+
+// Respond implements Responder.
+func (s Script) Respond(f protocol.Frame) []protocol.Message {
+	return s[protocol.Command(f.Code)]
+}
+
+// Loopback is an in-memory transport for tests. The frames written are
+// recorded and passed to a Responder, and its answers are queued to be read
+// back, so a test can assert exactly which frames a sequence of calls
+// produces and feed canned responses.
+type Loopback struct {
+	mu        sync.Mutex
+	responder Responder
+	decoder   *protocol.Decoder
+	frames    []protocol.Frame
+	rx        []byte
+	closed    bool
+}
+
+// NewLoopback returns a new Loopback answering with the given responder,
+// which may be nil if no answers are needed.
+func NewLoopback(r Responder) *Loopback {
+	return &Loopback{responder: r, decoder: protocol.NewDecoder()}
+}
+
+// Read reads the queued answers, it returns zero bytes if there are none.
+func (l *Loopback) Read(b []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	n := copy(b, l.rx)
+	l.rx = l.rx[:copy(l.rx, l.rx[n:])]
+	return n, nil
+}
+
+// Write decodes the frames written, invalid data is ignored.
+func (l *Loopback) Write(b []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	l.decoder.Feed(b)
+	for {
+		f, err := l.decoder.NextFrame()
+		if err == io.EOF {
+			return len(b), nil
+		}
+
+		if err != nil {
+			continue
+		}
+
+		l.frames = append(l.frames, f)
+		if l.responder != nil {
+			l.push(l.responder(f)...)
+		}
+	}
+}
+
+// Push queues the given messages to be read, as if they were sent by the
+// board on its own, like the track reports.
+func (l *Loopback) Push(msgs ...protocol.Message) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.push(msgs...)
+}
+
+func (l *Loopback) push(msgs ...protocol.Message) {
+	for _, m := range msgs {
+		l.rx = m.AppendBinary(l.rx)
+	}
+}
+
+// Frames returns the frames written since the last call to Frames.
+func (l *Loopback) Frames() []protocol.Frame {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	frames := l.frames
+	l.frames = nil
+	return frames
+}
+
+// Messages is like Frames but returns the frames decoded.
+func (l *Loopback) Messages() ([]protocol.Message, error) {
+	frames := l.Frames()
+	msgs := make([]protocol.Message, len(frames))
+	for i, f := range frames {
+		m, err := protocol.Decode(f)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs[i] = m
+	}
+
+	return msgs, nil
+}
+
+// Close closes the transport, any further Read or Write fails.
+func (l *Loopback) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	return nil
+}
@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+func TestLoopback(t *testing.T) {
+	version := &protocol.VersionString{Version: "Tsunami v1.10s"}
+	l := NewLoopback(Script{
+		protocol.CMD_GET_VERSION: {version},
+	}.Respond)
+
+	if _, err := l.Write(protocol.GetVersion{}.AppendBinary(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	report := &protocol.TrackReport{Track: 19, Voice: 1, Playing: true}
+	l.Push(report)
+
+	msgs, err := l.Messages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(msgs, []protocol.Message{&protocol.GetVersion{}}) {
+		t.Errorf("unexpected messages written %v", msgs)
+	}
+
+	d := protocol.NewDecoder()
+	buf := make([]byte, 4)
+	for {
+		n, err := l.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		d.Feed(buf[:n])
+	}
+
+	for _, expected := range []protocol.Message{version, report} {
+		m, err := d.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(m, expected) {
+			t.Errorf("got %v, expected %v", m, expected)
+		}
+	}
+
+	l.Close()
+	if _, err := l.Write(nil); err == nil {
+		t.Errorf("expected error after close")
+	}
+}
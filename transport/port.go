@@ -0,0 +1,72 @@
+//go:build !tinygo && !js
+
+package transport
+
+import (
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// Port is a transport over a serial port, based on go.bug.st/serial. Unlike
+// Serial it doesn't require cgo, behaves better on Windows and allows to list
+// the ports available with ListPorts.
+type Port struct {
+	port     serial.Port
+	deadline time.Time
+}
+
+// OpenPort opens the serial port with the given name, eg. "/dev/ttyUSB0" or
+// "COM3", configured as expected by the board.
+func OpenPort(name string) (*Port, error) {
+	port, err := serial.Open(name, &serial.Mode{BaudRate: DefaultBaudRate})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := port.SetReadTimeout(DefaultReadTimeout); err != nil {
+		port.Close()
+		return nil, err
+	}
+
+	return &Port{port: port}, nil
+}
+
+// ListPorts returns the names of the serial ports available in the system.
+func ListPorts() ([]string, error) {
+	return serial.GetPortsList()
+}
+
+func (p *Port) Read(b []byte) (int, error) {
+	if !p.deadline.IsZero() {
+		timeout := time.Until(p.deadline)
+		if timeout <= 0 {
+			return 0, nil
+		}
+
+		if err := p.port.SetReadTimeout(timeout); err != nil {
+			return 0, err
+		}
+	}
+
+	return p.port.Read(b)
+}
+
+// SetReadDeadline sets the deadline of the following reads, see Deadliner.
+func (p *Port) SetReadDeadline(t time.Time) error {
+	p.deadline = t
+	if t.IsZero() {
+		return p.port.SetReadTimeout(DefaultReadTimeout)
+	}
+
+	return nil
+}
+
+func (p *Port) Write(b []byte) (int, error) {
+	return p.port.Write(b)
+}
+
+// Close closes the serial port.
+func (p *Port) Close() error {
+	return p.port.Close()
+}
@@ -0,0 +1,206 @@
+//go:build !tinygo
+
+package transport
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+)
+
+// Telnet and RFC 2217 protocol bytes.
+const (
+	telnetSE       = 240
+	telnetSB       = 250
+	telnetWILL     = 251
+	telnetWONT     = 252
+	telnetDO       = 253
+	telnetDONT     = 254
+	telnetIAC      = 255
+	telnetBinary   = 0
+	telnetSGA      = 3
+	telnetComPort  = 44
+	comSetBaudrate = 1
+	comSetDatasize = 2
+	comSetParity   = 3
+	comSetStopsize = 4
+	comParityNone  = 1
+	comStopsize1   = 1
+)
+
+// RFC2217 is a transport over a remote serial port, exposed with the Telnet
+// Com Port Control Option (RFC 2217), as done by ser2net in telnet mode. The
+// remote port is configured as expected by the board on connection.
+type RFC2217 struct {
+	tcp *TCP
+
+	// state of the telnet parser, kept between reads.
+	state   int
+	command byte
+	rbuf    []byte
+}
+
+const (
+	stateData = iota
+	stateIAC
+	stateCommand
+	stateSB
+	stateSBIAC
+)
+
+// DialRFC2217 connects to the remote serial port at the given address.
+func DialRFC2217(addr string) (*RFC2217, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewRFC2217(conn)
+	if err := c.negotiate(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// NewRFC2217 returns a transport over an already established connection.
+// Unlike DialRFC2217, the remote port is not configured.
+func NewRFC2217(conn net.Conn) *RFC2217 {
+	return &RFC2217{tcp: NewTCP(conn), rbuf: make([]byte, 64)}
+}
+
+func (c *RFC2217) negotiate() error {
+	baud := make([]byte, 4)
+	binary.BigEndian.PutUint32(baud, DefaultBaudRate)
+
+	msg := []byte{
+		telnetIAC, telnetWILL, telnetBinary,
+		telnetIAC, telnetDO, telnetBinary,
+		telnetIAC, telnetWILL, telnetComPort,
+	}
+
+	msg = appendSubnegotiation(msg, comSetBaudrate, baud...)
+	msg = appendSubnegotiation(msg, comSetDatasize, 8)
+	msg = appendSubnegotiation(msg, comSetParity, comParityNone)
+	msg = appendSubnegotiation(msg, comSetStopsize, comStopsize1)
+
+	_, err := c.tcp.Write(msg)
+	return err
+}
+
+func appendSubnegotiation(dst []byte, cmd byte, value ...byte) []byte {
+	dst = append(dst, telnetIAC, telnetSB, telnetComPort, cmd)
+	dst = appendEscaped(dst, value)
+	return append(dst, telnetIAC, telnetSE)
+}
+
+// appendEscaped appends b to dst doubling any IAC byte.
+func appendEscaped(dst, b []byte) []byte {
+	for _, c := range b {
+		if c == telnetIAC {
+			dst = append(dst, telnetIAC)
+		}
+
+		dst = append(dst, c)
+	}
+
+	return dst
+}
+
+// Read returns the data received from the remote port, with the telnet
+// commands interleaved in the stream removed.
+func (c *RFC2217) Read(b []byte) (int, error) {
+	if len(c.rbuf) < len(b) {
+		c.rbuf = make([]byte, len(b))
+	}
+
+	n, err := c.tcp.Read(c.rbuf[:len(b)])
+	if n == 0 {
+		return 0, err
+	}
+
+	var out int
+	var reply []byte
+	for _, v := range c.rbuf[:n] {
+		switch c.state {
+		case stateData:
+			if v == telnetIAC {
+				c.state = stateIAC
+				continue
+			}
+
+			b[out] = v
+			out++
+		case stateIAC:
+			switch v {
+			case telnetIAC:
+				b[out] = v
+				out++
+				c.state = stateData
+			case telnetWILL, telnetWONT, telnetDO, telnetDONT:
+				c.command = v
+				c.state = stateCommand
+			case telnetSB:
+				c.state = stateSB
+			default:
+				c.state = stateData
+			}
+		case stateCommand:
+			reply = appendReply(reply, c.command, v)
+			c.state = stateData
+		case stateSB:
+			if v == telnetIAC {
+				c.state = stateSBIAC
+			}
+		case stateSBIAC:
+			if v == telnetSE {
+				c.state = stateData
+			} else {
+				c.state = stateSB
+			}
+		}
+	}
+
+	if len(reply) > 0 {
+		if _, werr := c.tcp.Write(reply); werr != nil && err == nil {
+			err = werr
+		}
+	}
+
+	return out, err
+}
+
+// appendReply refuses any option requested by the server besides the ones
+// needed to transfer binary data and control the port.
+func appendReply(dst []byte, cmd, opt byte) []byte {
+	supported := opt == telnetBinary || opt == telnetSGA || opt == telnetComPort
+	switch {
+	case cmd == telnetDO && !supported:
+		return append(dst, telnetIAC, telnetWONT, opt)
+	case cmd == telnetWILL && !supported:
+		return append(dst, telnetIAC, telnetDONT, opt)
+	}
+
+	return dst
+}
+
+// SetReadDeadline sets the deadline of the following reads, see Deadliner.
+func (c *RFC2217) SetReadDeadline(t time.Time) error {
+	return c.tcp.SetReadDeadline(t)
+}
+
+// Write sends the given bytes to the remote port, escaping them as required
+// by the telnet protocol.
+func (c *RFC2217) Write(b []byte) (int, error) {
+	if _, err := c.tcp.Write(appendEscaped(nil, b)); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Close closes the connection.
+func (c *RFC2217) Close() error {
+	return c.tcp.Close()
+}
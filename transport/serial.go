@@ -0,0 +1,38 @@
+//go:build !tinygo && !js
+
+package transport
+
+import "github.com/tarm/serial"
+
+// Serial is a transport over a serial port.
+type Serial struct {
+	port *serial.Port
+}
+
+// OpenSerial opens the serial port with the given name, eg. "/dev/ttyUSB0" or
+// "COM3", configured as expected by the board.
+func OpenSerial(name string) (*Serial, error) {
+	c := &serial.Config{Name: name, Baud: DefaultBaudRate,
+		ReadTimeout: DefaultReadTimeout,
+	}
+
+	port, err := serial.OpenPort(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Serial{port: port}, nil
+}
+
+func (s *Serial) Read(b []byte) (int, error) {
+	return s.port.Read(b)
+}
+
+func (s *Serial) Write(b []byte) (int, error) {
+	return s.port.Write(b)
+}
+
+// Close closes the serial port.
+func (s *Serial) Close() error {
+	return s.port.Close()
+}
@@ -0,0 +1,68 @@
+//go:build !tinygo
+
+package transport
+
+import (
+	"errors"
+	"net"
+	"os"
+	"time"
+)
+
+// TCP is a transport over a raw TCP connection to a TCP-to-serial bridge,
+// such as ser2net in raw mode.
+type TCP struct {
+	conn     net.Conn
+	timeout  time.Duration
+	deadline time.Time
+}
+
+// DialTCP connects to the bridge at the given address, eg. "host:4001".
+func DialTCP(addr string) (*TCP, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTCP(conn), nil
+}
+
+// NewTCP returns a transport over an already established connection.
+func NewTCP(conn net.Conn) *TCP {
+	return &TCP{conn: conn, timeout: DefaultReadTimeout}
+}
+
+// Read reads from the connection, returning zero bytes if no data arrives
+// within the read timeout, or before the read deadline if set.
+func (c *TCP) Read(b []byte) (int, error) {
+	deadline := c.deadline
+	if deadline.IsZero() {
+		deadline = time.Now().Add(c.timeout)
+	}
+
+	if err := c.conn.SetReadDeadline(deadline); err != nil {
+		return 0, err
+	}
+
+	n, err := c.conn.Read(b)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return n, nil
+	}
+
+	return n, err
+}
+
+// SetReadDeadline sets the deadline of the following reads, see Deadliner.
+func (c *TCP) SetReadDeadline(t time.Time) error {
+	c.deadline = t
+	return nil
+}
+
+func (c *TCP) Write(b []byte) (int, error) {
+	return c.conn.Write(b)
+}
+
+// Close closes the connection.
+func (c *TCP) Close() error {
+	return c.conn.Close()
+}
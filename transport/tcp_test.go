@@ -0,0 +1,129 @@
+//go:build !tinygo
+
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTCPReadTimeout(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	c := NewTCP(client)
+	defer c.Close()
+
+	b := make([]byte, 8)
+	if n, err := c.Read(b); n != 0 || err != nil {
+		t.Fatalf("unexpected read %d, %v", n, err)
+	}
+
+	go server.Write([]byte{0xf0, 0xaa})
+
+	n, err := io.ReadAtLeast(readerFunc(c.Read), b, 2)
+	if err != nil || !bytes.Equal(b[:n], []byte{0xf0, 0xaa}) {
+		t.Fatalf("unexpected read % x, %v", b[:n], err)
+	}
+}
+
+func TestTCPReadDeadline(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	c := NewTCP(client)
+	defer c.Close()
+
+	c.SetReadDeadline(time.Now().Add(-time.Second))
+	go server.Write([]byte{0xf0, 0xaa})
+
+	b := make([]byte, 8)
+	if n, err := c.Read(b); n != 0 || err != nil {
+		t.Fatalf("unexpected read %d, %v", n, err)
+	}
+
+	c.SetReadDeadline(time.Time{})
+
+	n, err := io.ReadAtLeast(readerFunc(c.Read), b, 2)
+	if err != nil || !bytes.Equal(b[:n], []byte{0xf0, 0xaa}) {
+		t.Fatalf("unexpected read % x, %v", b[:n], err)
+	}
+}
+
+// tcpPair returns the two ends of a loopback TCP connection.
+func tcpPair(t *testing.T) (client, server net.Conn) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer l.Close()
+
+	client, err = net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err = l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client, server
+}
+
+func TestRFC2217(t *testing.T) {
+	client, server := tcpPair(t)
+	defer server.Close()
+
+	c := NewRFC2217(client)
+	defer c.Close()
+
+	go server.Write([]byte{
+		0xf0, telnetIAC, telnetIAC, // escaped data
+		telnetIAC, telnetDO, 24, // terminal type, refused
+		telnetIAC, telnetSB, telnetComPort, 101, 0, 0, 0xe1, 0, telnetIAC, telnetSE,
+		0x55,
+	})
+
+	b := make([]byte, 32)
+	var got []byte
+	for len(got) < 3 {
+		n, err := c.Read(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, b[:n]...)
+		if n > 0 {
+			// consume the reply to the DO request
+			reply := make([]byte, 3)
+			io.ReadFull(server, reply)
+			if !bytes.Equal(reply, []byte{telnetIAC, telnetWONT, 24}) {
+				t.Errorf("unexpected reply % x", reply)
+			}
+		}
+	}
+
+	if !bytes.Equal(got, []byte{0xf0, 0xff, 0x55}) {
+		t.Errorf("unexpected data % x", got)
+	}
+
+	go c.Write([]byte{0x01, 0xff})
+
+	w := make([]byte, 3)
+	if _, err := io.ReadFull(server, w); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(w, []byte{0x01, 0xff, 0xff}) {
+		t.Errorf("unexpected written data % x", w)
+	}
+}
+
+type readerFunc func([]byte) (int, error)
+
+func (f readerFunc) Read(b []byte) (int, error) { return f(b) }
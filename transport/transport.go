@@ -0,0 +1,39 @@
+// Package transport defines the connection used to talk with the board, and
+// implements it over the different links supported.
+package transport
+
+import (
+	"io"
+	"time"
+)
+
+// Transport is a bidirectional byte stream with the board.
+//
+// Read must not block indefinitely: when no data arrives within a short
+// timeout, a few milliseconds, it should return zero bytes and no error, in
+// the same way a serial port configured with a read timeout does.
+type Transport interface {
+	io.Reader
+	io.Writer
+	io.Closer
+}
+
+// Flusher is implemented by transports buffering the written data, Flush is
+// called after every frame written.
+type Flusher interface {
+	Flush() error
+}
+
+// Deadliner is implemented by transports supporting read deadlines. Once the
+// deadline is reached Read returns zero bytes without waiting, a zero time
+// restores the read timeout of the transport.
+type Deadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// DefaultBaudRate is the baud rate of the Tsunami serial port.
+const DefaultBaudRate = 57600
+
+// DefaultReadTimeout is the read timeout used by the transports of the
+// package.
+const DefaultReadTimeout = 5 * time.Millisecond
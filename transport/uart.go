@@ -0,0 +1,52 @@
+//go:build tinygo
+
+package transport
+
+import (
+	"machine"
+	"time"
+)
+
+// UART is a transport over a UART of a microcontroller, for programs built
+// with TinyGo, the same way the original Arduino library talks to the board.
+type UART struct {
+	uart *machine.UART
+	poll time.Duration
+}
+
+// NewUART configures the given UART with config and returns a transport over
+// it. The zero config uses the default pins of the UART, if BaudRate is not
+// set the baud rate of the board is used.
+func NewUART(uart *machine.UART, config machine.UARTConfig) (*UART, error) {
+	if config.BaudRate == 0 {
+		config.BaudRate = DefaultBaudRate
+	}
+
+	if err := uart.Configure(config); err != nil {
+		return nil, err
+	}
+
+	return &UART{uart: uart, poll: DefaultReadTimeout}, nil
+}
+
+// Read reads the data buffered by the UART. If there is none, Read waits the
+// poll interval and returns zero bytes.
+func (u *UART) Read(b []byte) (int, error) {
+	if u.uart.Buffered() == 0 {
+		time.Sleep(u.poll)
+		if u.uart.Buffered() == 0 {
+			return 0, nil
+		}
+	}
+
+	return u.uart.Read(b)
+}
+
+func (u *UART) Write(b []byte) (int, error) {
+	return u.uart.Write(b)
+}
+
+// Close is a no-op, the UART can't be closed.
+func (u *UART) Close() error {
+	return nil
+}
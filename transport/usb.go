@@ -0,0 +1,75 @@
+//go:build !tinygo && !js
+
+package transport
+
+import (
+	"fmt"
+	"strings"
+
+	"go.bug.st/serial/enumerator"
+)
+
+// FTDIVendorID is the USB vendor ID of FTDI, the maker of the USB serial
+// adapters used to connect the board, like the SparkFun FTDI Basic.
+const FTDIVendorID = "0403"
+
+// PortInfo describes a serial port of the system. The USB fields are empty if
+// the port is not a USB serial adapter.
+type PortInfo struct {
+	// Name is the name of the port, eg. "/dev/ttyUSB0" or "COM3".
+	Name  string
+	IsUSB bool
+	// VID and PID are the USB vendor and product ids, as hex strings.
+	VID string
+	PID string
+	// SerialNumber is the serial number of the USB adapter, it identifies the
+	// physical unit regardless of the port name assigned by the system.
+	SerialNumber string
+	// Product is an OS-dependent description of the port.
+	Product string
+}
+
+// IsFTDI reports whether the port is an FTDI USB serial adapter.
+func (p PortInfo) IsFTDI() bool {
+	return p.IsUSB && strings.EqualFold(p.VID, FTDIVendorID)
+}
+
+// ListPortsInfo is like ListPorts but returns the USB details of every port.
+func ListPortsInfo() ([]PortInfo, error) {
+	details, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return nil, err
+	}
+
+	ports := make([]PortInfo, len(details))
+	for i, d := range details {
+		ports[i] = PortInfo{
+			Name:         d.Name,
+			IsUSB:        d.IsUSB,
+			VID:          d.VID,
+			PID:          d.PID,
+			SerialNumber: d.SerialNumber,
+			Product:      d.Product,
+		}
+	}
+
+	return ports, nil
+}
+
+// FindPort returns the name of the port of the USB adapter with the given
+// serial number, useful to bind a board to a physical unit, since the names
+// assigned by the system may change across reboots.
+func FindPort(serialNumber string) (string, error) {
+	ports, err := ListPortsInfo()
+	if err != nil {
+		return "", err
+	}
+
+	for _, p := range ports {
+		if p.IsUSB && p.SerialNumber == serialNumber {
+			return p.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("no port found with serial number %q", serialNumber)
+}
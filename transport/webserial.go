@@ -0,0 +1,168 @@
+//go:build js && wasm
+
+package transport
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"syscall/js"
+	"time"
+)
+
+// WebSerial is a transport over the Web Serial API of the browser, for Go
+// programs compiled to WebAssembly. The browser asks the user to pick the
+// port, so the connection must be requested from a user gesture, like a
+// click.
+//
+// The methods block waiting on JavaScript promises, so they must not be
+// called from the goroutine running a JavaScript callback.
+type WebSerial struct {
+	port   js.Value
+	reader js.Value
+	writer js.Value
+
+	mu     sync.Mutex
+	buf    []byte
+	err    error
+	notify chan struct{}
+}
+
+// RequestWebSerial asks the user to select a serial port and opens it.
+func RequestWebSerial() (*WebSerial, error) {
+	serial := js.Global().Get("navigator").Get("serial")
+	if serial.IsUndefined() {
+		return nil, errors.New("web serial API not supported by the browser")
+	}
+
+	port, err := await(serial.Call("requestPort"))
+	if err != nil {
+		return nil, err
+	}
+
+	return OpenWebSerial(port)
+}
+
+// OpenWebSerial opens the given SerialPort object, eg. one of the ports
+// returned by navigator.serial.getPorts(), already granted by the user.
+func OpenWebSerial(port js.Value) (*WebSerial, error) {
+	opts := js.Global().Get("Object").New()
+	opts.Set("baudRate", DefaultBaudRate)
+	if _, err := await(port.Call("open", opts)); err != nil {
+		return nil, err
+	}
+
+	s := &WebSerial{
+		port:   port,
+		reader: port.Get("readable").Call("getReader"),
+		writer: port.Get("writable").Call("getWriter"),
+		notify: make(chan struct{}, 1),
+	}
+
+	go s.pump()
+	return s, nil
+}
+
+// pump reads from the port in the background, since a read of the Web
+// Serial API can't be timed out.
+func (s *WebSerial) pump() {
+	for {
+		res, err := await(s.reader.Call("read"))
+		if err == nil && res.Get("done").Bool() {
+			err = io.EOF
+		}
+
+		s.mu.Lock()
+		if err != nil {
+			s.err = err
+		} else {
+			value := res.Get("value")
+			data := make([]byte, value.Get("length").Int())
+			js.CopyBytesToGo(data, value)
+			s.buf = append(s.buf, data...)
+		}
+		s.mu.Unlock()
+
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Read returns the data received, waiting up to DefaultReadTimeout.
+func (s *WebSerial) Read(b []byte) (int, error) {
+	s.mu.Lock()
+	empty := len(s.buf) == 0 && s.err == nil
+	s.mu.Unlock()
+
+	if empty {
+		select {
+		case <-s.notify:
+		case <-time.After(DefaultReadTimeout):
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.buf) == 0 {
+		return 0, s.err
+	}
+
+	n := copy(b, s.buf)
+	s.buf = s.buf[:copy(s.buf, s.buf[n:])]
+	return n, nil
+}
+
+func (s *WebSerial) Write(b []byte) (int, error) {
+	data := js.Global().Get("Uint8Array").New(len(b))
+	js.CopyBytesToJS(data, b)
+	if _, err := await(s.writer.Call("write", data)); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Close releases the stream locks and closes the port.
+func (s *WebSerial) Close() error {
+	s.reader.Call("cancel")
+	s.reader.Call("releaseLock")
+	s.writer.Call("releaseLock")
+
+	_, err := await(s.port.Call("close"))
+	return err
+}
+
+// await waits for the given promise to settle.
+func await(p js.Value) (js.Value, error) {
+	done := make(chan struct{})
+
+	var value js.Value
+	var err error
+	then := js.FuncOf(func(this js.Value, args []js.Value) any {
+		value = args[0]
+		close(done)
+		return nil
+	})
+
+	defer then.Release()
+
+	catch := js.FuncOf(func(this js.Value, args []js.Value) any {
+		err = js.Error{Value: args[0]}
+		close(done)
+		return nil
+	})
+
+	defer catch.Release()
+
+	p.Call("then", then, catch)
+	<-done
+
+	return value, err
+}
@@ -6,69 +6,168 @@
 package tsunami
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/tarm/serial"
+	"github.com/mcuadros/go-tsunami/capture"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
 )
 
 // Tsunami serial connection.
 type Tsunami struct {
-	port *serial.Port
+	*connection
+	// ctx is the context of the commands sent, see WithContext.
+	ctx context.Context
+}
 
+// connection is the state of a connection, shared by the views returned by
+// WithContext.
+type connection struct {
+	port    transport.Transport
+	decoder *protocol.Decoder
+	rmu     sync.Mutex
+	rxbuf   []byte
+	wmu     sync.Mutex
+	txbuf   []byte
+
+	mu          sync.Mutex
+	handlers    []*handler
+	unknown     []func(protocol.Frame)
 	voiceTable  []uint16
-	version     []byte
+	version     string
 	versionRcvd bool
+	firmware    Version
+	firmwareOK  bool
 	numVoices   uint8
 	numTracks   uint16
 	sysinfoRcvd bool
+	status      []uint16
+	statusSeq   int
+	masterGains [MaxOutputs]Gain
+	trackGains  map[int]Gain
+	muted       map[int]Gain
+	mutedTracks map[int]Gain
+	loops       map[int]bool
+	offsets     [MaxOutputs]int
+	triggerBank int
+	midiBank    int
+	scenes      map[string]Scene
+	groups      map[string]*group
+	trims       map[int]Gain
+	normGains   map[int]Gain
+	playbacks   map[int]*playback
+	playSeq     int
+	durations   TrackDurations
+	aliases     Aliases
+	locales     Locales
+	locale      string
+	hooks       []*trackHook
+	hooksActive bool
+	fader       *Fader
+
+	trace     *tracer
+	spans     Tracer
+	log       Logger
+	profile   *Profile
+	strict    bool
+	limit     time.Duration
+	lastWrite time.Time
+	counters  counters
 }
 
-// NewTsunami returns a new Tsuanmi connection to the given port.
-func NewTsunami(portName string) (*Tsunami, error) {
-	c := &serial.Config{Name: portName, Baud: 57600,
-		ReadTimeout: time.Millisecond * 5,
+// Option configures optional behavior of a Tsunami connection.
+type Option func(*Tsunami)
+
+// WithTrace logs every frame sent or received to w, as a timestamped hexdump
+// followed by its decoded meaning.
+func WithTrace(w io.Writer) Option {
+	return func(t *Tsunami) {
+		t.trace = &tracer{w: w}
 	}
+}
 
-	port, err := serial.OpenPort(c)
-	if err != nil {
-		return nil, err
+// WithCapture records every byte written to or read from the port into w,
+// using the format of the capture package. The capture can be played back
+// later with capture.Replayer.
+func WithCapture(w io.Writer) Option {
+	return func(t *Tsunami) {
+		t.port = capture.NewRecorder(t.port, w)
 	}
+}
 
-	return &Tsunami{
-		port:       port,
-		voiceTable: make([]uint16, MAX_NUM_VOICES),
-		version:    make([]byte, VERSION_STRING_LEN),
-	}, nil
+// WithStrict enables the strict validation of the data received from the
+// board. Invalid data and frames not matching exactly the expected format,
+// including track reports with impossible voice numbers and truncated
+// version strings, are not applied to the state; instead Update returns a
+// *protocol.FrameError holding the raw frame.
+func WithStrict() Option {
+	return func(t *Tsunami) {
+		t.strict = true
+		t.decoder.SetStrict(true)
+	}
 }
 
-// Start initialize the serial communications.
-func (t *Tsunami) Start() error {
-	var txbuf = make([]byte, 5)
+// WithProfile applies the profile every time the connection is started,
+// right after Start, so the board always comes up with the intended mix.
+func WithProfile(p *Profile) Option {
+	return func(t *Tsunami) {
+		t.profile = p
+	}
+}
 
-	// Request version string
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x05
-	txbuf[3] = CMD_GET_VERSION
-	txbuf[4] = EOM
+// WithRateLimit spaces the commands sent to the board at least by the given
+// interval, so bursts of commands, like the steps of a software fade, don't
+// overrun the serial port of the board. The commands are delayed, never
+// dropped.
+func WithRateLimit(interval time.Duration) Option {
+	return func(t *Tsunami) {
+		t.limit = interval
+	}
+}
 
-	if _, err := t.port.Write(txbuf); err != nil {
-		return err
+// NewTsunamiTransport returns a new Tsunami connection over the given
+// transport, such as a serial port, a network bridge or a mock.
+func NewTsunamiTransport(tr transport.Transport, opts ...Option) *Tsunami {
+	t := &Tsunami{connection: &connection{
+		port:       tr,
+		decoder:    protocol.NewDecoder(),
+		rxbuf:      make([]byte, 64),
+		txbuf:      make([]byte, 0, MAX_MESSAGE_LEN),
+		voiceTable: make([]uint16, MAX_NUM_VOICES),
+		trackGains: make(map[int]Gain),
+		playbacks:  make(map[int]*playback),
+	}}
+
+	t.counters.created = time.Now()
+	for _, opt := range opts {
+		opt(t)
 	}
 
-	// Request system info
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x05
-	txbuf[3] = CMD_GET_SYS_INFO
-	txbuf[4] = EOM
+	t.fader = NewFader(t)
+	return t
+}
+
+// Start initialize the serial communications. The profile given with
+// WithProfile, if any, is sent to the board.
+func (t *Tsunami) Start() error {
+	if err := send(t, protocol.GetVersion{}); err != nil {
+		return err
+	}
 
-	if _, err := t.port.Write(txbuf); err != nil {
+	if err := send(t, protocol.GetSysInfo{}); err != nil {
 		return err
 	}
 
+	if t.profile != nil {
+		return t.profile.Apply(t)
+	}
+
 	return nil
 }
 
@@ -76,6 +175,10 @@ func (t *Tsunami) Start() error {
 // determine if a particular track is currently playing.
 func (t *Tsunami) IsTrackPlaying(trk int) bool {
 	t.update()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	for i := 0; i < MAX_NUM_VOICES; i++ {
 		if t.voiceTable[i] == uint16(trk) {
 			return true
@@ -87,67 +190,153 @@ func (t *Tsunami) IsTrackPlaying(trk int) bool {
 }
 
 // MasterGain this function immediately sets the gain of the specific stereo
-// output to the specified value. The range for gain is -70 to +4. If audio is
-// playing, you will hear the result immediately. If audio is not playing, the
-// new gain will be used the next time a track is started.
-func (t *Tsunami) MasterGain(out, gain int) error {
-	var txbuf = make([]byte, 8)
+// output to the specified value. The range for gain is -70 to +4, gains out of
+// range are clamped. If audio is playing, you will hear the result
+// immediately. If audio is not playing, the new gain will be used the next
+// time a track is started.
+func (t *Tsunami) MasterGain(out int, gain Gain) error {
+	if err := send(t, protocol.MasterVolume{
+		Output: uint8(out),
+		Gain:   int16(gain.Clamp(MinGain, MaxMasterGain).Int()),
+	}); err != nil {
+		return err
+	}
 
-	vol := uint16(gain)
+	if out >= 0 && out < MaxOutputs {
+		t.mu.Lock()
+		t.masterGains[out] = gain
+		t.mu.Unlock()
+	}
 
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x08
-	txbuf[3] = CMD_MASTER_VOLUME
-	txbuf[4] = byte(out & 0x07)
-	txbuf[5] = byte(vol)
-	txbuf[6] = byte(vol >> 8)
-	txbuf[7] = EOM
+	return nil
+}
 
-	return t.write(txbuf)
+// MasterFade fades the gain of the output from its current value to the
+// given one in the given duration. The board has no master fades, so it's
+// done in software, stepping the master gain with a Fader; it returns
+// immediately, and any fade in progress of the output is replaced.
+//
+// The current gain is the last one set with MasterGain, 0 by default.
+func (t *Tsunami) MasterFade(out int, gain Gain, d time.Duration) error {
+	if out < 0 || out >= MaxOutputs {
+		return fmt.Errorf("invalid output %d", out)
+	}
+
+	t.mu.Lock()
+	from := t.masterGains[out]
+	t.mu.Unlock()
+
+	t.fader.Output(out, Fade{From: from, To: gain, Duration: d})
+	return nil
+}
+
+// MasterFadeAll is like MasterFade for every output, eg. to fade everything
+// to silence at the end of the night.
+func (t *Tsunami) MasterFadeAll(gain Gain, d time.Duration) error {
+	for out := 0; out < MaxOutputs; out++ {
+		if err := t.MasterFade(out, gain, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // SetReporting this function enables or disables track reporting. When enabled,
 // the Tsunami will send a message whenever a track starts or ends, specifying
-// the track number. Provided you call update() periodically, the library will
+// the track number. Provided you call Update() periodically, the library will
 // use these messages to maintain status of all tracks, allowing you to query
 // if particular tracks are playing or not.
 func (t *Tsunami) SetReporting(enable bool) error {
-	var txbuf = make([]byte, 6)
-
-	var e byte
-	if enable {
-		e = 1
-	}
-
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x06
-	txbuf[3] = CMD_SET_REPORTING
-	txbuf[4] = e
-	txbuf[5] = EOM
-
-	return t.write(txbuf)
+	return send(t, protocol.SetReporting{Enable: enable})
 }
 
 // GetVersion this function will return the Tsunami version string.
 // This function requires bi-directional communication with Tsunami.
 func (t *Tsunami) GetVersion() string {
 	t.update()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	if !t.versionRcvd {
 		return ""
 	}
 
-	return strings.TrimSpace(string(t.version))
+	return strings.TrimSpace(t.version)
 }
 
 // GetNumTracks this function will return the Tsunami version.
 // This function requires bi-directional communication with Tsunami.
 func (t *Tsunami) GetNumTracks() int {
 	t.update()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	return int(t.numTracks)
 }
 
+// GetNumVoices returns the number of voices of the board, as reported with
+// the system info, or zero if not received yet.
+// This function requires bi-directional communication with Tsunami.
+func (t *Tsunami) GetNumVoices() int {
+	t.update()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return int(t.numVoices)
+}
+
+// RequestStatus this function requests the list of tracks currently playing.
+// The answer is delivered as a *protocol.Status message to the subscribed
+// functions. Use Status to wait for the answer instead.
+func (t *Tsunami) RequestStatus() error {
+	return send(t, protocol.GetStatus{})
+}
+
+// Status this function requests the list of tracks currently playing and
+// waits for the answer until the context is done. Unlike IsTrackPlaying, it
+// doesn't require reporting to be enabled.
+// This function requires bi-directional communication with Tsunami.
+func (t *Tsunami) Status(ctx context.Context) ([]int, error) {
+	t.mu.Lock()
+	seq := t.statusSeq
+	t.mu.Unlock()
+
+	if err := t.RequestStatus(); err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.update(); err != nil {
+			return nil, err
+		}
+
+		t.mu.Lock()
+		if t.statusSeq != seq {
+			tracks := make([]int, len(t.status))
+			for i, trk := range t.status {
+				tracks[i] = int(trk)
+			}
+
+			t.mu.Unlock()
+			return tracks, nil
+		}
+		t.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // TrackPlaySolo this function stops any and all tracks that are currently
 // playing and starts track number trk from the beginning. The track is routed
 // to the specified stereo output. If lock is true, the track will not be
@@ -219,60 +408,71 @@ func (t *Tsunami) TrackResume(trk int) error {
 // is cleared, in which case it will stop when it reaches the end of the track.
 // This command may be used either before a track is started or while it's playing.
 func (t *Tsunami) TrackLoop(trk int, enable bool) error {
+	code := TRK_LOOP_OFF
 	if enable {
-		return t.trackControl(trk, TRK_LOOP_ON, 0, 0)
+		code = TRK_LOOP_ON
 	}
 
-	return t.trackControl(trk, TRK_LOOP_OFF, 0, 0)
-}
+	if err := t.trackControl(trk, code, 0, 0); err != nil {
+		return err
+	}
 
-func (t *Tsunami) trackControl(trk, code, out, flags int) error {
-	var txbuf = make([]byte, 10)
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x0a
-	txbuf[3] = CMD_TRACK_CONTROL
-	txbuf[4] = byte(code)
-	txbuf[5] = byte(trk)
-	txbuf[6] = byte(trk >> 8)
-	txbuf[7] = byte(out & 0x07)
-	txbuf[8] = byte(flags)
-	txbuf[9] = EOM
+	if t.loops == nil {
+		t.loops = make(map[int]bool)
+	}
 
-	return t.write(txbuf)
+	t.loops[trk] = enable
+	return nil
+}
+
+func (t *Tsunami) trackControl(trk int, code protocol.TrackCode, out, flags int) error {
+	if err := send(t, protocol.TrackControl{
+		Code:   code,
+		Track:  uint16(trk),
+		Output: uint8(out),
+		Flags:  uint8(flags),
+	}); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.notePlayback(trk, code, out)
+	t.mu.Unlock()
+	return nil
 }
 
 // StopAllTracks this commands stops any and all tracks that are currently playing.
 func (t *Tsunami) StopAllTracks() error {
-	var txbuf = make([]byte, 5)
-
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x05
-	txbuf[3] = CMD_STOP_ALL
-	txbuf[4] = EOM
+	if err := send(t, protocol.StopAll{}); err != nil {
+		return err
+	}
 
-	return t.write(txbuf)
+	t.mu.Lock()
+	t.clearPlaybacks()
+	t.mu.Unlock()
+	return nil
 }
 
 // ResumeAllInSync this command resumes all paused tracks within the same audio
 // buffer. Any tracks that were loaded using the TrackLoad() function will
 // start and remain sample locked (in sample sync) with one another.
 func (t *Tsunami) ResumeAllInSync() error {
-	var txbuf = make([]byte, 5)
-
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x05
-	txbuf[3] = CMD_RESUME_ALL_SYNC
-	txbuf[4] = EOM
+	if err := send(t, protocol.ResumeAllSync{}); err != nil {
+		return err
+	}
 
-	return t.write(txbuf)
+	t.mu.Lock()
+	t.resumePlaybacks()
+	t.mu.Unlock()
+	return nil
 }
 
 // TrackGain this function immediately sets the gain of track trk to the
-// specified value. The range for gain is -70 to +10. A value of 0 (no gain)
+// specified value. The range for gain is -70 to +10, gains out of range are
+// clamped, and they are rounded to whole dB. A value of 0 (no gain)
 // plays the track at the nominal value in the wav file. This is the default
 // gain for every track until changed. A value of -70 is completely muted. If
 // the track is playing, you will hear the result immediately. If the track is
@@ -283,22 +483,21 @@ func (t *Tsunami) ResumeAllInSync() error {
 // If you want to fade in or fade out a track, send small changes spaced out at
 // regular intervals. Increment or decrementing by 1 every 20 to 50 msecs
 // produces nice smooth fades. Better yet, use the trackFade() function below.
-func (t *Tsunami) TrackGain(trk, gain int) error {
-	var txbuf = make([]byte, 9)
-
-	vol := uint16(gain)
-
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x09
-	txbuf[3] = CMD_TRACK_VOLUME
-	txbuf[4] = byte(trk)
-	txbuf[5] = byte(trk >> 8)
-	txbuf[6] = byte(vol)
-	txbuf[7] = byte(vol >> 8)
-	txbuf[8] = EOM
+//
+// If the track belongs to any gain group, the trims of the groups are added
+// to the gain sent, see GroupTrim.
+func (t *Tsunami) TrackGain(trk int, gain Gain) error {
+	if err := send(t, protocol.TrackVolume{
+		Track: uint16(trk),
+		Gain:  int16(t.trimmed(trk, gain).Clamp(MinGain, MaxTrackGain).Int()),
+	}); err != nil {
+		return err
+	}
 
-	return t.write(txbuf)
+	t.mu.Lock()
+	t.trackGains[trk] = gain
+	t.mu.Unlock()
+	return nil
 }
 
 // TrackFade this command initiates a hardware volume fade on track number trk
@@ -306,31 +505,41 @@ func (t *Tsunami) TrackGain(trk, gain int) error {
 // the current value to the target gain in the specified number of milliseconds.
 // If the stopFlag is non-zero, the track will be stopped at the completion of
 // the fade (for fade-outs.)
-func (t *Tsunami) TrackFade(trk, gain int, d time.Duration, stopFlag bool) error {
-	var txbuf = make([]byte, 12)
-	vol := uint16(gain)
+func (t *Tsunami) TrackFade(trk int, gain Gain, d time.Duration, stopFlag bool) error {
+	if err := send(t, protocol.TrackFade{
+		Track:  uint16(trk),
+		Gain:   int16(t.trimmed(trk, gain).Clamp(MinGain, MaxTrackGain).Int()),
+		Millis: uint16(d.Milliseconds()),
+		Stop:   stopFlag,
+	}); err != nil {
+		return err
+	}
 
-	stop := 0
-	if stopFlag {
-		stop = 1
+	t.mu.Lock()
+	t.trackGains[trk] = gain
+	t.mu.Unlock()
+	return nil
+}
+
+// PlayWithFadeIn starts the track muted on the given output, with
+// TrackPlayPoly, and fades it up to the gain along the duration, avoiding an
+// abrupt start.
+func (t *Tsunami) PlayWithFadeIn(trk, out int, gain Gain, d time.Duration) error {
+	if err := t.TrackGain(trk, MinGain); err != nil {
+		return err
 	}
 
-	time := d.Milliseconds()
+	if err := t.TrackPlayPoly(trk, out, false); err != nil {
+		return err
+	}
 
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x0c
-	txbuf[3] = CMD_TRACK_FADE
-	txbuf[4] = byte(trk)
-	txbuf[5] = byte(trk >> 8)
-	txbuf[6] = byte(vol)
-	txbuf[7] = byte(vol >> 8)
-	txbuf[8] = byte(time)
-	txbuf[9] = byte(time >> 8)
-	txbuf[10] = byte(stop)
-	txbuf[11] = EOM
+	return t.TrackFade(trk, gain, d, false)
+}
 
-	return t.write(txbuf)
+// StopWithFadeOut fades the track out along the duration, stopping it at the
+// end of the fade, avoiding an abrupt stop.
+func (t *Tsunami) StopWithFadeOut(trk int, d time.Duration) error {
+	return t.TrackFade(trk, MinGain, d, true)
 }
 
 // SamplerateOffset this function immediately sets sample-rate offset, or
@@ -340,37 +549,39 @@ func (t *Tsunami) TrackFade(trk, gain int, d time.Duration, stopFlag bool) error
 // will hear the result immediately. If audio is not playing, the new
 // sample-rate offset will be used the next time a track is started.
 func (t *Tsunami) SamplerateOffset(out, offset int) error {
-	var txbuf = make([]byte, 8)
-
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x08
-	txbuf[3] = CMD_SAMPLERATE_OFFSET
-	txbuf[4] = byte(0)
+	if err := send(t, protocol.SamplerateOffset{
+		Output: uint8(out),
+		Offset: int16(offset),
+	}); err != nil {
+		return err
+	}
 
-	off := uint16(offset)
-	txbuf[5] = byte(off)
-	txbuf[6] = byte(off >> 8)
-	txbuf[7] = EOM
+	if out >= 0 && out < MaxOutputs {
+		t.mu.Lock()
+		t.rebasePlaybacks(out)
+		t.offsets[out] = offset
+		t.mu.Unlock()
+	}
 
-	return t.write(txbuf)
+	return nil
 }
 
 // SetTriggerBank this function sets the trigger bank. The bank range is 1 - 32.
 // Each bank will offset the normal trigger function track assignment by 16.
 // For bank 1, the default, trigger one maps to track 1. For bank 2, trigger 1
 // maps to track 17, trigger 2 to track 18, and so on.
+//
+// Requires firmware v1.10 or newer, ErrUnsupportedFirmware is returned
+// otherwise.
 func (t *Tsunami) SetTriggerBank(bank int) error {
-	var txbuf = make([]byte, 6)
-
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x06
-	txbuf[3] = CMD_SET_TRIGGER_BANK
-	txbuf[4] = byte(bank)
-	txbuf[5] = EOM
+	if err := send(t, protocol.SetTriggerBank{Bank: uint8(bank)}); err != nil {
+		return err
+	}
 
-	return t.write(txbuf)
+	t.mu.Lock()
+	t.triggerBank = bank
+	t.mu.Unlock()
+	return nil
 }
 
 // SetInputMix this function controls the routing of the audio input channels.
@@ -380,34 +591,94 @@ func (t *Tsunami) SetTriggerBank(bank int) error {
 //
 // The routing is immediate and does no ramping, so to avoid pops, be sure that
 // the input is quiet when switching.
+//
+// Requires firmware v1.10 or newer, ErrUnsupportedFirmware is returned
+// otherwise.
 func (t *Tsunami) SetInputMix(mix int) error {
-	var txbuf = make([]byte, 6)
-
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x06
-	txbuf[3] = CMD_SET_INPUT_MIX
-	txbuf[4] = byte(mix)
-	txbuf[5] = EOM
-
-	return t.write(txbuf)
+	return send(t, protocol.SetInputMix{Mix: uint8(mix)})
 }
 
 // SetMidiBank this function sets the MIDI bank. The bank range is 1 - 32. Each
 // bank will offset the MIDI Note number to track assignment by 128. For
 // bank 1, the default, MIDI Note number maps to track 1. For bank 2, MIDI Note
 // number 1 maps to track 129, MIDI Note number 2 to track 130, and so on.
+//
+// Requires firmware v1.10 or newer, ErrUnsupportedFirmware is returned
+// otherwise.
 func (t *Tsunami) SetMidiBank(bank int) error {
-	var txbuf = make([]byte, 6)
+	if err := send(t, protocol.SetMidiBank{Bank: uint8(bank)}); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.midiBank = bank
+	t.mu.Unlock()
+	return nil
+}
+
+// Send writes a raw protocol message to the board. It is meant for commands
+// not covered by the rest of the API, or for drivers of boards speaking a
+// variant of the protocol.
+func (t *Tsunami) Send(m protocol.Message) error {
+	return send(t, m)
+}
+
+// send encodes the message into the scratch buffer and writes it. It is
+// generic, instead of taking a protocol.Message, so the message isn't boxed
+// into an interface, avoiding an allocation per command.
+func send[M protocol.Message](t *Tsunami, m M) (err error) {
+	ctx := t.Context()
+	if t.spans != nil {
+		var span Span
+		ctx, span = t.spans.Start(ctx, "tsunami.command", "command", protocol.Describe(m))
+		defer func() { span.End(err) }()
+	}
+
+	t.wmu.Lock()
+	defer t.wmu.Unlock()
 
-	txbuf[0] = SOM1
-	txbuf[1] = SOM2
-	txbuf[2] = 0x06
-	txbuf[3] = CMD_SET_MIDI_BANK
-	txbuf[4] = byte(bank)
-	txbuf[5] = EOM
+	t.txbuf = m.AppendBinary(t.txbuf[:0])
+	if err := t.checkFirmware(protocol.Command(t.txbuf[3])); err != nil {
+		return err
+	}
+
+	if t.limit > 0 {
+		if d := time.Until(t.lastWrite.Add(t.limit)); d > 0 {
+			time.Sleep(d)
+		}
+
+		t.lastWrite = time.Now()
+	}
+
+	if t.trace != nil {
+		t.trace.frame(directionTX, t.txbuf, m)
+	}
 
-	return t.write(txbuf)
+	if t.log != nil {
+		t.log.Log(LevelDebug, "frame sent", "frame", fmt.Sprintf("% x", t.txbuf))
+		t.log.Log(LevelInfo, "command sent", "command", protocol.Describe(m))
+	}
+
+	if t.spans != nil {
+		_, span := t.spans.Start(ctx, "tsunami.write", "bytes", len(t.txbuf))
+		err = t.write(t.txbuf)
+		span.End(err)
+	} else {
+		err = t.write(t.txbuf)
+	}
+
+	t.counters.written(err)
+	if err != nil && t.log != nil {
+		t.log.Log(LevelError, "write failed", "command", protocol.Describe(m), "error", err)
+	}
+
+	return err
+}
+
+// RateLimit returns the minimum interval between commands set with
+// WithRateLimit, zero if there is no limit.
+func (t *Tsunami) RateLimit() time.Duration {
+	return t.limit
 }
 
 func (t *Tsunami) write(b []byte) error {
@@ -420,155 +691,297 @@ func (t *Tsunami) write(b []byte) error {
 		return fmt.Errorf("unexpected bytes written %d", n)
 	}
 
+	if f, ok := t.port.(transport.Flusher); ok {
+		return f.Flush()
+	}
+
 	return nil
 }
 
+// handler is a function registered with Subscribe, compared by identity on
+// cancel as functions can't be compared.
+type handler struct {
+	fn func(protocol.Message)
+}
+
+// Subscribe registers a function to be called with every message received
+// from the board, such as *protocol.TrackReport or *protocol.VersionString.
+// Messages are only received while Update is being called, either directly,
+// by the functions querying the board state or by Listen. The returned
+// function removes the subscription.
+//
+// The track reports are only sent by the board with reporting enabled, see
+// SetReporting. Everything following the tracks playing, like PlayChain or
+// the Ducker, depends on both.
+func (t *Tsunami) Subscribe(fn func(protocol.Message)) (cancel func()) {
+	h := &handler{fn: fn}
+
+	t.mu.Lock()
+	t.handlers = append(t.handlers, h)
+	t.mu.Unlock()
+
+	return func() { t.unsubscribe(h) }
+}
+
+func (t *Tsunami) unsubscribe(h *handler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// the slice is replaced, not modified, as update iterates over it
+	// without holding the lock.
+	handlers := make([]*handler, 0, len(t.handlers))
+	for _, other := range t.handlers {
+		if other != h {
+			handlers = append(handlers, other)
+		}
+	}
+
+	t.handlers = handlers
+}
+
+// OnUnknown registers a function to be called with every frame received
+// with a code unknown to the library, so new firmware messages can be
+// handled without changes to the library. Like with Subscribe, frames are
+// only received while Update is being called.
+func (t *Tsunami) OnUnknown(fn func(protocol.Frame)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.unknown = append(t.unknown, fn)
+}
+
+// Update reads any pending data from the board, updating the known state and
+// delivering the received messages to the subscribed functions.
+func (t *Tsunami) Update() error {
+	return t.update()
+}
+
+// pollInterval is the interval between the reads of Listen and Status.
+const pollInterval = 5 * time.Millisecond
+
+// Listen calls Update every few milliseconds until the context is cancelled,
+// making the subscribed functions receive the messages as they arrive.
+func (t *Tsunami) Listen(ctx context.Context) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.update(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stats returns the counters of the decoding of the data received from the
+// board, useful to diagnose noisy connections.
+func (t *Tsunami) Stats() protocol.Stats {
+	t.rmu.Lock()
+	defer t.rmu.Unlock()
+
+	return t.decoder.Stats()
+}
+
 func (t *Tsunami) update() error {
-	rxMessage := make([]uint8, MAX_MESSAGE_LEN)
-	var rxCount byte
-	var rxLen byte
-	var rxMsgReady bool
+	msgs, unknown, err := t.read()
+	t.counters.read(len(msgs)+len(unknown), err)
+	if len(msgs) == 0 && len(unknown) == 0 {
+		return err
+	}
+
+	t.mu.Lock()
+	for _, msg := range msgs {
+		t.apply(msg)
+	}
+
+	handlers, unknownHandlers := t.handlers, t.unknown
+	t.mu.Unlock()
+
+	for _, msg := range msgs {
+		for _, h := range handlers {
+			h.fn(msg)
+		}
+	}
+
+	for _, f := range unknown {
+		for _, fn := range unknownHandlers {
+			fn(f)
+		}
+	}
 
-	txbuf := make([]byte, 50)
+	return err
+}
+
+// maxReadTime bounds the time spent by read draining the port, on the
+// transports implementing transport.Deadliner, so a board streaming without
+// pause doesn't keep it reading forever.
+const maxReadTime = 50 * time.Millisecond
+
+// read decodes the pending data, returning the valid messages and the frames
+// with unknown codes.
+func (t *Tsunami) read() (msgs []protocol.Message, unknown []protocol.Frame, err error) {
+	t.rmu.Lock()
+	defer t.rmu.Unlock()
+
+	if d, ok := t.port.(transport.Deadliner); ok {
+		d.SetReadDeadline(time.Now().Add(maxReadTime))
+		defer d.SetReadDeadline(time.Time{})
+	}
 
 	for {
-		n, _ := t.port.Read(txbuf)
+		n, _ := t.port.Read(t.rxbuf)
 		if n == 0 {
 			break
 		}
 
-		for _, dat := range txbuf[:n] {
-			if (rxCount == 0) && (dat == SOM1) {
-				rxCount++
-			} else if rxCount == 1 {
-				if dat == SOM2 {
-					rxCount++
-				} else {
-					rxCount = 0
-					return fmt.Errorf("bad msg 1")
-				}
-			} else if rxCount == 2 {
-				if dat <= MAX_MESSAGE_LEN {
-					rxCount++
-					rxLen = dat - 1
-				} else {
-					rxCount = 0
-					return fmt.Errorf("bad msg 2")
-				}
-			} else if (rxCount > 2) && (rxCount < rxLen) {
-				rxMessage[rxCount-3] = dat
-				rxCount++
-			} else if rxCount == rxLen {
-				if dat == EOM {
-					rxMsgReady = true
-				} else {
-					rxCount = 0
-					return fmt.Errorf("bad msg 3")
-				}
-			} else {
-				rxCount = 0
-				return fmt.Errorf("bad msg 4")
+		t.decoder.Feed(t.rxbuf[:n])
+	}
+
+	for {
+		f, ferr := t.decoder.NextFrame()
+		if ferr == io.EOF {
+			return msgs, unknown, err
+		}
+
+		if ferr != nil {
+			// only in strict mode, invalid data in the stream.
+			if t.trace != nil {
+				t.trace.failed(ferr)
 			}
 
-			if rxMsgReady {
-				switch rxMessage[0] {
-
-				case RSP_TRACK_REPORT:
-					track := uint16(rxMessage[2])
-					track = (track << 8) + uint16(rxMessage[1]) + 1
-					voice := rxMessage[3]
-					if voice < MAX_NUM_VOICES {
-						if rxMessage[4] == 0 {
-							if track == t.voiceTable[voice] {
-								t.voiceTable[voice] = 0xffff
-							}
-						} else {
-							t.voiceTable[voice] = track
-						}
-					}
-					// ==========================
-					//fmt.Printf("Track %d", track)
-					//if rxMessage[4] == 0 {
-					//	fmt.Println(" off")
-					//} else {
-					//	fmt.Println(" on")
-					//}
-					// ==========================
-
-				case RSP_VERSION_STRING:
-					for i := 0; i < (VERSION_STRING_LEN - 1); i++ {
-						t.version[i] = rxMessage[i+1]
-					}
-
-					t.version[VERSION_STRING_LEN-1] = 0
-					t.versionRcvd = true
-
-					// ==========================
-					//fmt.Println(string(t.version), t.versionRcvd)
-					// ==========================
-
-				case RSP_SYSTEM_INFO:
-					t.numVoices = byte(rxMessage[1])
-					t.numTracks = uint16(rxMessage[3])
-					t.numTracks = (t.numTracks << 8) + uint16(rxMessage[2])
-					t.sysinfoRcvd = true
-
-					// ==========================
-					//fmt.Println("sysinfoRcvd", t.numVoices, t.numTracks)
-					// ==========================
-				}
+			if t.log != nil {
+				t.log.Log(LevelError, "invalid data received", "error", ferr)
+			}
+
+			if err == nil {
+				err = ferr
+			}
+
+			continue
+		}
+
+		msg, derr := t.decoder.Decode(f)
+		if t.trace != nil {
+			t.trace.received(f, msg, derr)
+		}
 
-				rxCount = 0
-				rxLen = 0
-				rxMsgReady = false
+		if t.log != nil {
+			t.logReceived(f, derr)
+		}
 
-			} // if (rxMsgReady)
-		} // while (TsunamiSerial.available() > 0)
+		if errors.Is(derr, protocol.ErrUnknownCode) {
+			unknown = append(unknown, f)
+		}
+
+		if derr != nil {
+			// frames with unknown codes or unexpected payloads are
+			// skipped, the decoder is already positioned at the next one;
+			// in strict mode the error is reported.
+			if t.strict && err == nil {
+				err = derr
+			}
+
+			continue
+		}
+
+		msgs = append(msgs, msg)
 	}
+}
 
-	return nil
+func (t *Tsunami) logReceived(f protocol.Frame, err error) {
+	raw, _ := f.MarshalBinary()
+	if err != nil {
+		t.log.Log(LevelError, "frame skipped", "frame", fmt.Sprintf("% x", raw), "error", err)
+		return
+	}
+
+	t.log.Log(LevelDebug, "frame received", "frame", fmt.Sprintf("% x", raw))
+}
+
+func (t *Tsunami) apply(msg protocol.Message) {
+	switch m := msg.(type) {
+	case *protocol.TrackReport:
+		if m.Voice < MAX_NUM_VOICES {
+			if !m.Playing {
+				if m.Track == t.voiceTable[m.Voice] {
+					t.voiceTable[m.Voice] = 0xffff
+				}
+
+				delete(t.playbacks, int(m.Track))
+			} else {
+				t.voiceTable[m.Voice] = m.Track
+			}
+		}
+	case *protocol.VersionString:
+		t.version = m.Version
+		t.versionRcvd = true
+		t.firmware, t.firmwareOK = parseFirmware(m.Version)
+	case *protocol.Status:
+		t.status = m.Tracks
+		t.statusSeq++
+	case *protocol.SystemInfo:
+		t.numVoices = m.Voices
+		t.numTracks = m.Tracks
+		t.sysinfoRcvd = true
+	}
 }
 
 // Close should be called to close the connection with the port.
 func (t *Tsunami) Close() error {
+	t.fader.Close()
 	return t.port.Close()
 }
 
+// MaxOutputs is the number of outputs of the board when configured as mono,
+// four stereo pairs otherwise.
+const MaxOutputs = 8
+
+// MaxTracks is the highest track number supported by the board, tracks are
+// numbered from 1.
+const MaxTracks = 4096
+
+// Protocol constants, see the protocol package.
 const (
-	CMD_GET_VERSION       = 1
-	CMD_GET_SYS_INFO      = 2
-	CMD_TRACK_CONTROL     = 3
-	CMD_STOP_ALL          = 4
-	CMD_MASTER_VOLUME     = 5
-	CMD_TRACK_VOLUME      = 8
-	CMD_TRACK_FADE        = 10
-	CMD_RESUME_ALL_SYNC   = 11
-	CMD_SAMPLERATE_OFFSET = 12
-	CMD_SET_REPORTING     = 13
-	CMD_SET_TRIGGER_BANK  = 14
-	CMD_SET_INPUT_MIX     = 15
-	CMD_SET_MIDI_BANK     = 16
-
-	TRK_PLAY_SOLO      = 0
-	TRK_PLAY_POLY      = 1
-	TRK_PAUSE          = 2
-	TRK_RESUME         = 3
-	TRK_STOP           = 4
-	TRK_LOOP_ON        = 5
-	TRK_LOOP_OFF       = 6
-	TRK_LOAD           = 7
-	RSP_VERSION_STRING = 129
-	RSP_SYSTEM_INFO    = 130
-	RSP_STATUS         = 131
-	RSP_TRACK_REPORT   = 132
-
-	MAX_MESSAGE_LEN    = 32
-	MAX_NUM_VOICES     = 18
-	VERSION_STRING_LEN = 23
-
-	SOM1 = 0xf0
-	SOM2 = 0xaa
-	EOM  = 0x55
+	CMD_GET_VERSION       = protocol.CMD_GET_VERSION
+	CMD_GET_SYS_INFO      = protocol.CMD_GET_SYS_INFO
+	CMD_TRACK_CONTROL     = protocol.CMD_TRACK_CONTROL
+	CMD_STOP_ALL          = protocol.CMD_STOP_ALL
+	CMD_MASTER_VOLUME     = protocol.CMD_MASTER_VOLUME
+	CMD_GET_STATUS        = protocol.CMD_GET_STATUS
+	CMD_TRACK_VOLUME      = protocol.CMD_TRACK_VOLUME
+	CMD_TRACK_FADE        = protocol.CMD_TRACK_FADE
+	CMD_RESUME_ALL_SYNC   = protocol.CMD_RESUME_ALL_SYNC
+	CMD_SAMPLERATE_OFFSET = protocol.CMD_SAMPLERATE_OFFSET
+	CMD_SET_REPORTING     = protocol.CMD_SET_REPORTING
+	CMD_SET_TRIGGER_BANK  = protocol.CMD_SET_TRIGGER_BANK
+	CMD_SET_INPUT_MIX     = protocol.CMD_SET_INPUT_MIX
+	CMD_SET_MIDI_BANK     = protocol.CMD_SET_MIDI_BANK
+
+	TRK_PLAY_SOLO      = protocol.TRK_PLAY_SOLO
+	TRK_PLAY_POLY      = protocol.TRK_PLAY_POLY
+	TRK_PAUSE          = protocol.TRK_PAUSE
+	TRK_RESUME         = protocol.TRK_RESUME
+	TRK_STOP           = protocol.TRK_STOP
+	TRK_LOOP_ON        = protocol.TRK_LOOP_ON
+	TRK_LOOP_OFF       = protocol.TRK_LOOP_OFF
+	TRK_LOAD           = protocol.TRK_LOAD
+	RSP_VERSION_STRING = protocol.RSP_VERSION_STRING
+	RSP_SYSTEM_INFO    = protocol.RSP_SYSTEM_INFO
+	RSP_STATUS         = protocol.RSP_STATUS
+	RSP_TRACK_REPORT   = protocol.RSP_TRACK_REPORT
+
+	MAX_MESSAGE_LEN    = protocol.MAX_MESSAGE_LEN
+	MAX_NUM_VOICES     = protocol.MAX_NUM_VOICES
+	VERSION_STRING_LEN = protocol.VERSION_STRING_LEN
+
+	SOM1 = protocol.SOM1
+	SOM2 = protocol.SOM2
+	EOM  = protocol.EOM
 
 	IMIX_OUT1 = 0x01
 	IMIX_OUT2 = 0x02
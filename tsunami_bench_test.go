@@ -0,0 +1,58 @@
+package tsunami
+
+import (
+	"testing"
+	"time"
+)
+
+type discardPort struct{}
+
+func (discardPort) Read(b []byte) (int, error)  { return 0, nil }
+func (discardPort) Write(b []byte) (int, error) { return len(b), nil }
+func (discardPort) Close() error                { return nil }
+
+func newTestTsunami() *Tsunami {
+	return NewTsunamiTransport(discardPort{})
+}
+
+func BenchmarkTrackGain(b *testing.B) {
+	t := newTestTsunami()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := t.TrackGain(19, Gain(-(i % 70))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTrackFade(b *testing.B) {
+	t := newTestTsunami()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := t.TrackFade(19, Gain(-(i % 70)), time.Second, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTrackPlayPoly(b *testing.B) {
+	t := newTestTsunami()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := t.TrackPlayPoly(19, 0, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	t := newTestTsunami()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t.update()
+	}
+}
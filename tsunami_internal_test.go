@@ -0,0 +1,91 @@
+package tsunami
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestOnUnknown(t *testing.T) {
+	port := &bufferPort{}
+	port.rx = protocol.AppendFrame(nil, 0x90, 0x01, 0x02)
+
+	var frames []protocol.Frame
+	ts := newTestTsunami()
+	ts.port = port
+	ts.OnUnknown(func(f protocol.Frame) {
+		frames = append(frames, f)
+	})
+
+	if err := ts.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(frames) != 1 || frames[0].Code != 0x90 || !bytes.Equal(frames[0].Data, []byte{1, 2}) {
+		t.Errorf("unexpected frames %v", frames)
+	}
+}
+
+func TestSubscribeCancel(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+
+	var a, b int
+	cancel := ts.Subscribe(func(protocol.Message) { a++ })
+	ts.Subscribe(func(protocol.Message) { b++ })
+
+	report := func() {
+		port.Push(&protocol.TrackReport{Track: 19, Playing: true})
+		if err := ts.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report()
+	cancel()
+	cancel()
+	report()
+
+	if a != 1 || b != 2 {
+		t.Errorf("unexpected calls %d and %d", a, b)
+	}
+}
+
+// streamPort returns frames without pause until its read deadline.
+type streamPort struct {
+	discardPort
+	deadline time.Time
+}
+
+func (p *streamPort) Read(b []byte) (int, error) {
+	if !p.deadline.IsZero() && time.Now().After(p.deadline) {
+		return 0, nil
+	}
+
+	return copy(b, protocol.AppendFrame(nil, 0x90)), nil
+}
+
+func (p *streamPort) SetReadDeadline(t time.Time) error {
+	p.deadline = t
+	return nil
+}
+
+func TestUpdateReadDeadline(t *testing.T) {
+	port := &streamPort{}
+	ts := newTestTsunami()
+	ts.port = port
+
+	var frames int
+	ts.OnUnknown(func(protocol.Frame) { frames++ })
+
+	if err := ts.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	if frames == 0 || !port.deadline.IsZero() {
+		t.Errorf("unexpected %d frames, deadline %v", frames, port.deadline)
+	}
+}
@@ -5,32 +5,65 @@ import (
 	"time"
 
 	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
 )
 
 func ExampleTsunami() {
-	ts, err := tsunami.NewTsunami("/dev/ttyUSB0")
-	if err != nil {
-		panic(err)
-	}
+	// a loopback transport answering like a board, use NewTsunami with the
+	// name of the serial port, eg. "/dev/ttyUSB0", to talk with a real one.
+	port := transport.NewLoopback(transport.Script{
+		protocol.CMD_GET_VERSION:  {&protocol.VersionString{Version: "Tsunami v1.10s"}},
+		protocol.CMD_GET_SYS_INFO: {&protocol.SystemInfo{Voices: 18, Tracks: 1002}},
+	}.Respond)
 
+	ts := tsunami.NewTsunamiTransport(port)
 	defer ts.Close()
 
 	if err := ts.Start(); err != nil {
 		panic(err)
 	}
 
-	trackNum := 1002
-
+	fmt.Println(ts.GetVersion())
 	fmt.Println(ts.GetNumTracks())
 
-	ts.TrackGain(trackNum, 70)                      // muted
+	trackNum := 19
+
+	ts.TrackGain(trackNum, -70)                     // muted
 	ts.TrackPlaySolo(trackNum, 0, false)            // track = 19 (aka "19.WAV"), output = 0 (aka "1L")
-	ts.TrackFade(trackNum, 0, time.Second*5, false) // track 19, fade to gain of 0,
+	ts.TrackFade(trackNum, 0, time.Second*5, false) // track 19, fade to gain of 0
+
+	msgs, _ := port.Messages()
+	for _, m := range msgs {
+		fmt.Println(protocol.Describe(m))
+	}
+
+	// Output:
+	// Tsunami v1.10s
+	// 1002
+	// CMD_GET_VERSION
+	// CMD_GET_SYS_INFO
+	// CMD_TRACK_VOLUME {Track:19 Gain:-70}
+	// CMD_TRACK_CONTROL/TRK_PLAY_SOLO track=19 output=0 flags=0
+	// CMD_TRACK_FADE {Track:19 Gain:0 Millis:5000 Stop:false}
+}
+
+func ExampleTsunami_PlayWithFadeIn() {
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	defer ts.Close()
+
+	ts.PlayWithFadeIn(19, 0, 0, time.Second*5) // track 19, output 1L, fade up to 0 dB
+	ts.StopWithFadeOut(19, time.Second*5)      // track 19, fade out and stop
+
+	msgs, _ := port.Messages()
+	for _, m := range msgs {
+		fmt.Println(protocol.Describe(m))
+	}
 
-	fmt.Println("Track 19 stopped.")
 	// Output:
-	// Fading IN track 19 right now...
-	// Gain set to unity (0)! Playing for 5 seconds...
-	// Fading OUT track 19 right now...
-	// Track 19 stopped.
+	// CMD_TRACK_VOLUME {Track:19 Gain:-70}
+	// CMD_TRACK_CONTROL/TRK_PLAY_POLY track=19 output=0 flags=0
+	// CMD_TRACK_FADE {Track:19 Gain:0 Millis:5000 Stop:false}
+	// CMD_TRACK_FADE {Track:19 Gain:-70 Millis:5000 Stop:true}
 }
@@ -0,0 +1,21 @@
+//go:build tinygo
+
+package tsunami
+
+import (
+	"machine"
+
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// NewTsunamiUART returns a new Tsunami connection over the given UART of a
+// microcontroller, for programs built with TinyGo. See transport.NewUART for
+// the config.
+func NewTsunamiUART(uart *machine.UART, config machine.UARTConfig, opts ...Option) (*Tsunami, error) {
+	port, err := transport.NewUART(uart, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTsunamiTransport(port, opts...), nil
+}
@@ -0,0 +1,143 @@
+package tsunami
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// ErrUnsupportedFirmware is returned when a command is not supported by the
+// firmware version running on the board.
+var ErrUnsupportedFirmware = errors.New("command not supported by firmware")
+
+// Version is a parsed firmware version string, eg. "Tsunami v1.10s".
+type Version struct {
+	// Board is the name of the board, eg. "Tsunami".
+	Board string
+	Major int
+	Minor int
+	// Variant is the suffix following the version number, the Tsunami
+	// firmware comes in stereo ("s") and mono ("m") variants.
+	Variant string
+}
+
+var versionRegexp = regexp.MustCompile(`^(.*?)\s*v(\d+)\.(\d+)(\S*)$`)
+
+// ParseVersion parses a version string as returned by GetVersion.
+func ParseVersion(s string) (Version, error) {
+	m := versionRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version string %q", s)
+	}
+
+	major, _ := strconv.Atoi(m[2])
+	minor, _ := strconv.Atoi(m[3])
+	return Version{Board: m[1], Major: major, Minor: minor, Variant: m[4]}, nil
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is older, equal or newer
+// than the given version number.
+func (v Version) Compare(major, minor int) int {
+	switch {
+	case v.Major < major, v.Major == major && v.Minor < minor:
+		return -1
+	case v.Major == major && v.Minor == minor:
+		return 0
+	}
+
+	return 1
+}
+
+// AtLeast reports whether v is equal or newer than the given version number.
+func (v Version) AtLeast(major, minor int) bool {
+	return v.Compare(major, minor) >= 0
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%s v%d.%02d%s", v.Board, v.Major, v.Minor, v.Variant)
+}
+
+func parseFirmware(s string) (Version, bool) {
+	v, err := ParseVersion(strings.TrimSpace(s))
+	return v, err == nil
+}
+
+// minFirmware is the first Tsunami firmware version supporting each command,
+// commands not listed are supported by every version.
+var minFirmware = map[protocol.Command][2]int{
+	protocol.CMD_SET_TRIGGER_BANK: {1, 10},
+	protocol.CMD_SET_INPUT_MIX:    {1, 10},
+	protocol.CMD_SET_MIDI_BANK:    {1, 10},
+}
+
+// Firmware returns the parsed firmware version of the board. The boolean is
+// false if the version wasn't received yet, or it couldn't be parsed.
+// This function requires bi-directional communication with Tsunami.
+func (t *Tsunami) Firmware() (Version, bool) {
+	t.update()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.firmware, t.firmwareOK
+}
+
+// Supports reports whether the firmware of the board supports the given
+// command. If the firmware version is unknown, the command is assumed to be
+// supported.
+func (t *Tsunami) Supports(cmd protocol.Command) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.supports(cmd)
+}
+
+// SupportsTrackFade reports whether the firmware supports TrackFade.
+func (t *Tsunami) SupportsTrackFade() bool {
+	return t.Supports(protocol.CMD_TRACK_FADE)
+}
+
+// SupportsTriggerBank reports whether the firmware supports SetTriggerBank.
+func (t *Tsunami) SupportsTriggerBank() bool {
+	return t.Supports(protocol.CMD_SET_TRIGGER_BANK)
+}
+
+// SupportsInputMix reports whether the firmware supports SetInputMix.
+func (t *Tsunami) SupportsInputMix() bool {
+	return t.Supports(protocol.CMD_SET_INPUT_MIX)
+}
+
+// SupportsMidiBank reports whether the firmware supports SetMidiBank.
+func (t *Tsunami) SupportsMidiBank() bool {
+	return t.Supports(protocol.CMD_SET_MIDI_BANK)
+}
+
+func (t *Tsunami) supports(cmd protocol.Command) bool {
+	if !t.firmwareOK || t.firmware.Board != "Tsunami" {
+		return true
+	}
+
+	min, ok := minFirmware[cmd]
+	if !ok {
+		return true
+	}
+
+	return t.firmware.AtLeast(min[0], min[1])
+}
+
+func (t *Tsunami) checkFirmware(cmd protocol.Command) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.supports(cmd) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s requires v%d.%02d, board runs %s",
+		ErrUnsupportedFirmware, cmd, minFirmware[cmd][0], minFirmware[cmd][1], t.firmware,
+	)
+}
@@ -0,0 +1,72 @@
+package tsunami
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+func TestParseVersion(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		expected Version
+	}{
+		{"Tsunami v1.10s", Version{"Tsunami", 1, 10, "s"}},
+		{"Tsunami v1.02m", Version{"Tsunami", 1, 2, "m"}},
+		{"WAV Trigger v1.34", Version{"WAV Trigger", 1, 34, ""}},
+	} {
+		v, err := ParseVersion(tc.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v != tc.expected {
+			t.Errorf("%q: got %+v, expected %+v", tc.in, v, tc.expected)
+		}
+	}
+
+	if _, err := ParseVersion("foo"); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	v := Version{Major: 1, Minor: 10}
+	if v.Compare(1, 2) != 1 || v.Compare(1, 10) != 0 || v.Compare(2, 0) != -1 {
+		t.Errorf("unexpected comparison")
+	}
+
+	if !v.AtLeast(1, 10) || v.AtLeast(1, 11) {
+		t.Errorf("unexpected comparison")
+	}
+}
+
+func TestUnsupportedFirmware(t *testing.T) {
+	port := &bufferPort{}
+	port.rx = protocol.VersionString{Version: "Tsunami v1.00s"}.AppendBinary(nil)
+
+	ts := newTestTsunami()
+	ts.port = port
+
+	if !ts.SupportsMidiBank() {
+		t.Errorf("unknown firmware should be assumed to support everything")
+	}
+
+	v, ok := ts.Firmware()
+	if !ok || v.String() != "Tsunami v1.00s" {
+		t.Fatalf("unexpected firmware %v", v)
+	}
+
+	if ts.SupportsMidiBank() || !ts.SupportsTrackFade() {
+		t.Errorf("unexpected capabilities")
+	}
+
+	if err := ts.SetMidiBank(2); !errors.Is(err, ErrUnsupportedFirmware) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := ts.TrackPlayPoly(1, 0, false); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
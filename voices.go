@@ -0,0 +1,223 @@
+package tsunami
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// Voice is a voice of the board in use.
+type Voice struct {
+	Voice int
+	Track int
+	// Priority and Category are the ones given to Acquire for the track,
+	// zero and empty for the tracks played without it.
+	Priority int
+	Category string
+	Since    time.Time
+}
+
+// VoiceManager keeps track of the voices of the board in use, from the track
+// reports, and allocates them by priority, so the application decides what to
+// stop before the firmware steals a voice on its own. The voices in use are
+// taken from the track reports, see Subscribe.
+type VoiceManager struct {
+	b      Board
+	size   int
+	cancel func()
+
+	mu       sync.Mutex
+	voices   map[int]Voice
+	acquired map[int]Voice
+	locked   map[string]bool
+	margin   int
+	onNear   []func(used, size int)
+}
+
+// NewVoiceManager returns a VoiceManager for the given board, subscribed to
+// its messages. size is the number of voices of the board, if zero it's
+// taken from the board, when known, or MAX_NUM_VOICES.
+func NewVoiceManager(b Board, size int) *VoiceManager {
+	if size <= 0 {
+		if v, ok := b.(interface{ GetNumVoices() int }); ok {
+			size = v.GetNumVoices()
+		}
+	}
+
+	if size <= 0 {
+		size = MAX_NUM_VOICES
+	}
+
+	m := &VoiceManager{
+		b:        b,
+		size:     size,
+		voices:   make(map[int]Voice),
+		acquired: make(map[int]Voice),
+		locked:   make(map[string]bool),
+	}
+
+	m.cancel = b.Subscribe(m.handle)
+	return m
+}
+
+// Close unsubscribes the VoiceManager from the board, the voices in use
+// aren't updated anymore.
+func (m *VoiceManager) Close() error {
+	m.cancel()
+	return nil
+}
+
+// Lock protects the tracks of the category, they are never stolen by
+// Acquire.
+func (m *VoiceManager) Lock(category string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.locked[category] = true
+}
+
+// Unlock reverts Lock.
+func (m *VoiceManager) Unlock(category string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.locked, category)
+}
+
+// OnNearLimit registers a function to be called when the voices playing
+// reach size minus margin, the moment to warn that the board is close to
+// stealing voices. It's called again only after the count drops below the
+// threshold. The margin is shared by every function registered.
+func (m *VoiceManager) OnNearLimit(margin int, fn func(used, size int)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.margin = margin
+	m.onNear = append(m.onNear, fn)
+}
+
+// Voices returns the voices in use, sorted by voice number.
+func (m *VoiceManager) Voices() []Voice {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	voices := make([]Voice, 0, len(m.voices))
+	for _, v := range m.voices {
+		voices = append(voices, v)
+	}
+
+	sort.Slice(voices, func(i, j int) bool { return voices[i].Voice < voices[j].Voice })
+	return voices
+}
+
+// Free returns the number of voices free, not counting the ones acquired
+// for tracks not yet reported as playing.
+func (m *VoiceManager) Free() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.free()
+}
+
+func (m *VoiceManager) free() int {
+	return m.size - len(m.voices) - len(m.acquired)
+}
+
+// Acquire reserves a voice for the track, which should be played right after.
+// If no voice is free, the voice playing the oldest track with the lowest
+// priority, lower than the given one and not of a locked category, is
+// stopped and returned. If there is none ErrRefused is returned. The
+// reservation ends when the track is reported as playing, or with Release.
+func (m *VoiceManager) Acquire(trk, priority int, category string) (*Voice, error) {
+	m.mu.Lock()
+	v := Voice{Voice: -1, Track: trk, Priority: priority, Category: category, Since: time.Now()}
+
+	if m.free() > 0 {
+		m.acquired[trk] = v
+		m.mu.Unlock()
+		return nil, nil
+	}
+
+	victim := m.victim(priority)
+	if victim == nil {
+		m.mu.Unlock()
+		return nil, ErrRefused
+	}
+
+	delete(m.voices, victim.Voice)
+	m.acquired[trk] = v
+	m.mu.Unlock()
+
+	return victim, m.b.TrackStop(victim.Track)
+}
+
+// Release cancels the reservation of Acquire, when the track isn't played
+// after all.
+func (m *VoiceManager) Release(trk int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.acquired, trk)
+}
+
+func (m *VoiceManager) victim(priority int) *Voice {
+	var victim *Voice
+	for _, v := range m.voices {
+		if v.Priority >= priority || m.locked[v.Category] {
+			continue
+		}
+
+		if victim == nil || v.Priority < victim.Priority ||
+			(v.Priority == victim.Priority && v.Since.Before(victim.Since)) {
+			v := v
+			victim = &v
+		}
+	}
+
+	return victim
+}
+
+func (m *VoiceManager) handle(msg protocol.Message) {
+	r, ok := msg.(*protocol.TrackReport)
+	if !ok {
+		return
+	}
+
+	m.mu.Lock()
+	threshold := m.size - m.margin
+	before := len(m.voices)
+	m.update(r)
+	used := len(m.voices)
+	handlers := m.onNear
+	m.mu.Unlock()
+
+	if before >= threshold || used < threshold {
+		return
+	}
+
+	for _, fn := range handlers {
+		fn(used, m.size)
+	}
+}
+
+func (m *VoiceManager) update(r *protocol.TrackReport) {
+	voice, trk := int(r.Voice), int(r.Track)
+	if !r.Playing {
+		if v, ok := m.voices[voice]; ok && v.Track == trk {
+			delete(m.voices, voice)
+		}
+
+		return
+	}
+
+	v, ok := m.acquired[trk]
+	delete(m.acquired, trk)
+	if !ok {
+		v = Voice{Track: trk, Since: time.Now()}
+	}
+
+	v.Voice = voice
+	m.voices[voice] = v
+}
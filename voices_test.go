@@ -0,0 +1,68 @@
+package tsunami
+
+import (
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestVoiceManager(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	ts := NewTsunamiTransport(port)
+	m := NewVoiceManager(ts, 3)
+	m.Lock("dialog")
+
+	play := func(trk, priority int, category string) *Voice {
+		v, err := m.Acquire(trk, priority, category)
+		if err != nil {
+			t.Fatalf("acquiring track %d: %v", trk, err)
+		}
+
+		port.Push(&protocol.TrackReport{Track: uint16(trk), Voice: uint8(trk), Playing: true})
+		if err := ts.Update(); err != nil {
+			t.Fatal(err)
+		}
+
+		return v
+	}
+
+	play(1, 1, "dialog")
+	play(2, 2, "")
+	play(3, 1, "")
+	if m.Free() != 0 || len(m.Voices()) != 3 {
+		t.Fatalf("unexpected %d free voices, %v", m.Free(), m.Voices())
+	}
+
+	// track 1 has the lowest priority but it's locked.
+	if v := play(4, 5, "sfx"); v == nil || v.Track != 3 {
+		t.Errorf("unexpected victim %v", v)
+	}
+
+	if _, err := m.Acquire(5, 2, ""); err != ErrRefused {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	port.Push(&protocol.TrackReport{Track: 2, Voice: 2, Playing: false})
+	if err := ts.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Free() != 1 {
+		t.Errorf("unexpected %d free voices", m.Free())
+	}
+
+	if _, err := m.Acquire(5, 0, ""); err != nil || m.Free() != 0 {
+		t.Errorf("acquired voice should be reserved, %v", err)
+	}
+
+	m.Release(5)
+	if m.Free() != 1 {
+		t.Errorf("released voice should be free")
+	}
+
+	msgs, _ := port.Messages()
+	if c, ok := msgs[len(msgs)-1].(*protocol.TrackControl); !ok || c.Code != TRK_STOP || c.Track != 3 {
+		t.Errorf("victim should be stopped, got %v", msgs)
+	}
+}
@@ -0,0 +1,181 @@
+//go:build !tinygo && !js
+
+package tsunami
+
+import (
+	"context"
+	"time"
+
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// WatchInterval is the interval between scans of the serial ports, on systems
+// where the changes are not notified by the kernel.
+var WatchInterval = time.Second
+
+// WatchEventType is the type of a WatchEvent.
+type WatchEventType int
+
+const (
+	// PortAttached is raised when a port matching the watcher appears.
+	PortAttached WatchEventType = iota
+	// PortDetached is raised when a port watched disappears.
+	PortDetached
+)
+
+func (t WatchEventType) String() string {
+	if t == PortAttached {
+		return "attached"
+	}
+
+	return "detached"
+}
+
+// WatchEvent is a change in the serial ports of the system.
+type WatchEvent struct {
+	Type WatchEventType
+	Port transport.PortInfo
+}
+
+// Watcher detects when boards are plugged and unplugged. On Linux it relies
+// on the kernel uevents, the same used by udev, the ports are polled every
+// WatchInterval on other systems.
+type Watcher struct {
+	match func(transport.PortInfo) bool
+	list  func() ([]transport.PortInfo, error)
+	ports map[string]transport.PortInfo
+}
+
+// NewWatcher returns a watcher of the ports for which match returns true, eg.
+// a port with a given USB serial number. If match is nil, every FTDI adapter
+// is watched.
+func NewWatcher(match func(transport.PortInfo) bool) *Watcher {
+	if match == nil {
+		match = transport.PortInfo.IsFTDI
+	}
+
+	return &Watcher{
+		match: match,
+		list:  transport.ListPortsInfo,
+		ports: make(map[string]transport.PortInfo),
+	}
+}
+
+// Watch calls fn for every port attached or detached until the context is
+// done. The ports already present when Watch is called are reported as
+// attached.
+func (w *Watcher) Watch(ctx context.Context, fn func(WatchEvent)) error {
+	changes := notify(ctx, WatchInterval)
+	for {
+		if err := w.scan(fn); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changes:
+		}
+	}
+}
+
+// Attach is like Watch but opens a connection to every board attached, and
+// closes it when the board is detached. fn is called after the connection is
+// started on attach, and after it is closed on detach. The connections still
+// open are closed when the context is done.
+func (w *Watcher) Attach(ctx context.Context, fn func(*Tsunami, WatchEvent), opts ...Option) error {
+	boards := make(map[string]*Tsunami)
+	defer func() {
+		for _, t := range boards {
+			t.Close()
+		}
+	}()
+
+	return w.Watch(ctx, func(ev WatchEvent) {
+		name := ev.Port.Name
+		switch ev.Type {
+		case PortAttached:
+			t, err := NewTsunamiPort(name, opts...)
+			if err != nil {
+				return
+			}
+
+			if err := t.Start(); err != nil {
+				t.Close()
+				return
+			}
+
+			boards[name] = t
+			fn(t, ev)
+		case PortDetached:
+			t, ok := boards[name]
+			if !ok {
+				return
+			}
+
+			delete(boards, name)
+			t.Close()
+			fn(t, ev)
+		}
+	})
+}
+
+// scan lists the ports and reports the differences with the previous scan.
+func (w *Watcher) scan(fn func(WatchEvent)) error {
+	ports, err := w.list()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(ports))
+	for _, p := range ports {
+		if !w.match(p) {
+			continue
+		}
+
+		seen[p.Name] = true
+		if _, ok := w.ports[p.Name]; ok {
+			continue
+		}
+
+		w.ports[p.Name] = p
+		fn(WatchEvent{Type: PortAttached, Port: p})
+	}
+
+	for name, p := range w.ports {
+		if seen[name] {
+			continue
+		}
+
+		delete(w.ports, name)
+		fn(WatchEvent{Type: PortDetached, Port: p})
+	}
+
+	return nil
+}
+
+// poll returns a channel receiving a value every interval until the context
+// is done.
+func poll(ctx context.Context, interval time.Duration) <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			select {
+			case ch <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
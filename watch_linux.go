@@ -0,0 +1,54 @@
+//go:build !tinygo
+
+package tsunami
+
+import (
+	"bytes"
+	"context"
+	"syscall"
+	"time"
+)
+
+// ueventSettle is the time waited after a uevent, so the device node is
+// created before the ports are listed.
+const ueventSettle = 250 * time.Millisecond
+
+// notify returns a channel receiving a value every time the serial ports may
+// have changed. The kernel uevents of the tty subsystem are used, falling
+// back to polling if the netlink socket can't be opened.
+func notify(ctx context.Context, interval time.Duration) <-chan struct{} {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW, syscall.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return poll(ctx, interval)
+	}
+
+	addr := &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: 1}
+	tv := syscall.NsecToTimeval(int64(interval))
+	if err := syscall.Bind(fd, addr); err != nil ||
+		syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv) != nil {
+		syscall.Close(fd)
+		return poll(ctx, interval)
+	}
+
+	ch := make(chan struct{})
+	go func() {
+		defer syscall.Close(fd)
+
+		buf := make([]byte, 4096)
+		for ctx.Err() == nil {
+			n, _, err := syscall.Recvfrom(fd, buf, 0)
+			if err != nil || !bytes.Contains(buf[:n], []byte("SUBSYSTEM=tty")) {
+				continue
+			}
+
+			time.Sleep(ueventSettle)
+			select {
+			case ch <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !tinygo && !js
+
+package tsunami
+
+import (
+	"context"
+	"time"
+)
+
+// notify returns a channel receiving a value every time the serial ports may
+// have changed.
+func notify(ctx context.Context, interval time.Duration) <-chan struct{} {
+	return poll(ctx, interval)
+}
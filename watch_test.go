@@ -0,0 +1,41 @@
+//go:build !tinygo && !js
+
+package tsunami
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestWatcherScan(t *testing.T) {
+	ftdi := transport.PortInfo{Name: "/dev/ttyUSB0", IsUSB: true, VID: "0403", SerialNumber: "A1"}
+	other := transport.PortInfo{Name: "/dev/ttyACM0", IsUSB: true, VID: "2341"}
+
+	var ports []transport.PortInfo
+	w := NewWatcher(nil)
+	w.list = func() ([]transport.PortInfo, error) { return ports, nil }
+
+	var events []WatchEvent
+	record := func(ev WatchEvent) { events = append(events, ev) }
+
+	for _, step := range []struct {
+		ports    []transport.PortInfo
+		expected []WatchEvent
+	}{
+		{nil, nil},
+		{[]transport.PortInfo{ftdi, other}, []WatchEvent{{PortAttached, ftdi}}},
+		{[]transport.PortInfo{ftdi}, nil},
+		{nil, []WatchEvent{{PortDetached, ftdi}}},
+	} {
+		ports, events = step.ports, nil
+		if err := w.scan(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(events, step.expected) {
+			t.Errorf("ports %v: got events %v, expected %v", step.ports, events, step.expected)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !tinygo && !js
+
+package wavtrigger
+
+import "github.com/mcuadros/go-tsunami"
+
+// NewWavTrigger returns a new WAV Trigger connection to the given port. The
+// options of the tsunami package, such as tsunami.WithTrace, are supported.
+func NewWavTrigger(portName string, opts ...tsunami.Option) (*WavTrigger, error) {
+	t, err := tsunami.NewTsunami(portName, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WavTrigger{t: t}, nil
+}
@@ -0,0 +1,130 @@
+package wavtrigger
+
+import (
+	"encoding/binary"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+// The commands of the WAV Trigger with a code different from the Tsunami
+// one, or missing on it.
+const (
+	// CMD_AMP_POWER turns on or off the on-board amplifier.
+	CMD_AMP_POWER protocol.Command = 9
+	// CMD_TRACK_CONTROL_EX is CMD_TRACK_CONTROL with a lock flag, see
+	// TrackControl.
+	CMD_TRACK_CONTROL_EX protocol.Command = 13
+	// CMD_SET_REPORTING enables or disables track reports, 13 on the
+	// Tsunami.
+	CMD_SET_REPORTING protocol.Command = 14
+	// CMD_SET_TRIGGER_BANK sets the trigger bank, 14 on the Tsunami.
+	CMD_SET_TRIGGER_BANK protocol.Command = 15
+)
+
+// TrackControl is the WAV Trigger variant of protocol.TrackControl, without
+// output. The lock flag is only sent when set, as CMD_TRACK_CONTROL_EX, since
+// it isn't understood by firmware versions older than v1.30.
+type TrackControl struct {
+	Code  protocol.TrackCode
+	Track uint16
+	Lock  bool
+}
+
+func (m TrackControl) Frame() protocol.Frame {
+	data := binary.LittleEndian.AppendUint16([]byte{byte(m.Code)}, m.Track)
+	if m.Lock {
+		return protocol.Frame{Code: byte(CMD_TRACK_CONTROL_EX), Data: append(data, 1)}
+	}
+
+	return protocol.Frame{Code: byte(protocol.CMD_TRACK_CONTROL), Data: data}
+}
+
+func (m TrackControl) AppendBinary(dst []byte) []byte {
+	return appendFrame(dst, m)
+}
+
+// MasterVolume is the WAV Trigger variant of protocol.MasterVolume, without
+// output.
+type MasterVolume struct {
+	Gain int16
+}
+
+func (m MasterVolume) Frame() protocol.Frame {
+	return protocol.Frame{
+		Code: byte(protocol.CMD_MASTER_VOLUME),
+		Data: binary.LittleEndian.AppendUint16(nil, uint16(m.Gain)),
+	}
+}
+
+func (m MasterVolume) AppendBinary(dst []byte) []byte {
+	return appendFrame(dst, m)
+}
+
+// SamplerateOffset is the WAV Trigger variant of protocol.SamplerateOffset,
+// without output.
+type SamplerateOffset struct {
+	Offset int16
+}
+
+func (m SamplerateOffset) Frame() protocol.Frame {
+	return protocol.Frame{
+		Code: byte(protocol.CMD_SAMPLERATE_OFFSET),
+		Data: binary.LittleEndian.AppendUint16(nil, uint16(m.Offset)),
+	}
+}
+
+func (m SamplerateOffset) AppendBinary(dst []byte) []byte {
+	return appendFrame(dst, m)
+}
+
+// AmpPower turns on or off the on-board amplifier.
+type AmpPower struct {
+	Enable bool
+}
+
+func (m AmpPower) Frame() protocol.Frame {
+	return protocol.Frame{Code: byte(CMD_AMP_POWER), Data: []byte{boolByte(m.Enable)}}
+}
+
+func (m AmpPower) AppendBinary(dst []byte) []byte {
+	return appendFrame(dst, m)
+}
+
+// SetReporting is the WAV Trigger variant of protocol.SetReporting.
+type SetReporting struct {
+	Enable bool
+}
+
+func (m SetReporting) Frame() protocol.Frame {
+	return protocol.Frame{Code: byte(CMD_SET_REPORTING), Data: []byte{boolByte(m.Enable)}}
+}
+
+func (m SetReporting) AppendBinary(dst []byte) []byte {
+	return appendFrame(dst, m)
+}
+
+// SetTriggerBank is the WAV Trigger variant of protocol.SetTriggerBank.
+type SetTriggerBank struct {
+	Bank uint8
+}
+
+func (m SetTriggerBank) Frame() protocol.Frame {
+	return protocol.Frame{Code: byte(CMD_SET_TRIGGER_BANK), Data: []byte{m.Bank}}
+}
+
+func (m SetTriggerBank) AppendBinary(dst []byte) []byte {
+	return appendFrame(dst, m)
+}
+
+func appendFrame(dst []byte, m protocol.Message) []byte {
+	f := m.Frame()
+	return protocol.AppendFrame(dst, f.Code, f.Data...)
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,27 @@
+package wavtrigger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+)
+
+func TestAppendBinary(t *testing.T) {
+	for _, tc := range []struct {
+		m        protocol.Message
+		expected []byte
+	}{
+		{TrackControl{Code: protocol.TRK_PLAY_POLY, Track: 300}, []byte{0xf0, 0xaa, 0x08, 0x03, 0x01, 0x2c, 0x01, 0x55}},
+		{TrackControl{Code: protocol.TRK_PLAY_SOLO, Track: 1, Lock: true}, []byte{0xf0, 0xaa, 0x09, 0x0d, 0x00, 0x01, 0x00, 0x01, 0x55}},
+		{MasterVolume{Gain: -10}, []byte{0xf0, 0xaa, 0x07, 0x05, 0xf6, 0xff, 0x55}},
+		{SamplerateOffset{Offset: 1000}, []byte{0xf0, 0xaa, 0x07, 0x0c, 0xe8, 0x03, 0x55}},
+		{AmpPower{Enable: true}, []byte{0xf0, 0xaa, 0x06, 0x09, 0x01, 0x55}},
+		{SetReporting{Enable: true}, []byte{0xf0, 0xaa, 0x06, 0x0e, 0x01, 0x55}},
+		{SetTriggerBank{Bank: 3}, []byte{0xf0, 0xaa, 0x06, 0x0f, 0x03, 0x55}},
+	} {
+		if b := tc.m.AppendBinary(nil); !bytes.Equal(b, tc.expected) {
+			t.Errorf("%T: got % x, expected % x", tc.m, b, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Package wavtrigger implements the serial control of the Robertsonics WAV
+// Trigger, the predecessor of the Tsunami.
+//
+// The WAV Trigger speaks almost the same serial protocol than the Tsunami,
+// but it has a single stereo output, so the commands routing tracks to an
+// output are encoded without it. The driver reuses the connection handling
+// of the tsunami package, and implements the tsunami.Player interface so
+// applications can target either board.
+package wavtrigger
+
+import (
+	"context"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// WavTrigger serial connection.
+type WavTrigger struct {
+	t *tsunami.Tsunami
+}
+
+var _ tsunami.Player = (*WavTrigger)(nil)
+
+// NewWavTriggerTransport returns a new WAV Trigger connection over the given
+// transport.
+func NewWavTriggerTransport(tr transport.Transport, opts ...tsunami.Option) *WavTrigger {
+	return &WavTrigger{t: tsunami.NewTsunamiTransport(tr, opts...)}
+}
+
+// Start initialize the serial communications.
+func (w *WavTrigger) Start() error {
+	return w.t.Start()
+}
+
+// TrackPlaySolo stops any and all tracks that are currently playing and
+// starts track number trk from the beginning. The output is ignored.
+func (w *WavTrigger) TrackPlaySolo(trk, out int, lock bool) error {
+	return w.trackControl(trk, protocol.TRK_PLAY_SOLO, lock)
+}
+
+// TrackPlayPoly starts track number trk from the beginning, blending it with
+// any other tracks that are currently playing. The output is ignored.
+func (w *WavTrigger) TrackPlayPoly(trk, out int, lock bool) error {
+	return w.trackControl(trk, protocol.TRK_PLAY_POLY, lock)
+}
+
+// TrackLoad loads track number trk and pauses it at the beginning of the
+// track. The output is ignored.
+func (w *WavTrigger) TrackLoad(trk, out int, lock bool) error {
+	return w.trackControl(trk, protocol.TRK_LOAD, lock)
+}
+
+// TrackStop stops track number trk if it's currently playing.
+func (w *WavTrigger) TrackStop(trk int) error {
+	return w.trackControl(trk, protocol.TRK_STOP, false)
+}
+
+// TrackPause pauses track number trk if it's currently playing.
+func (w *WavTrigger) TrackPause(trk int) error {
+	return w.trackControl(trk, protocol.TRK_PAUSE, false)
+}
+
+// TrackResume resumes track number trk if it's currently paused.
+func (w *WavTrigger) TrackResume(trk int) error {
+	return w.trackControl(trk, protocol.TRK_RESUME, false)
+}
+
+// TrackLoop enables or disables the loop flag for track trk.
+func (w *WavTrigger) TrackLoop(trk int, enable bool) error {
+	if enable {
+		return w.trackControl(trk, protocol.TRK_LOOP_ON, false)
+	}
+
+	return w.trackControl(trk, protocol.TRK_LOOP_OFF, false)
+}
+
+func (w *WavTrigger) trackControl(trk int, code protocol.TrackCode, lock bool) error {
+	return w.t.Send(TrackControl{Code: code, Track: uint16(trk), Lock: lock})
+}
+
+// TrackGain sets the gain of track trk. The range for gain is -70 to +10.
+func (w *WavTrigger) TrackGain(trk int, gain tsunami.Gain) error {
+	return w.t.TrackGain(trk, gain)
+}
+
+// TrackFade initiates a hardware volume fade on track number trk.
+func (w *WavTrigger) TrackFade(trk int, gain tsunami.Gain, d time.Duration, stopFlag bool) error {
+	return w.t.TrackFade(trk, gain, d, stopFlag)
+}
+
+// PlayWithFadeIn starts the track muted and fades it up to the gain along
+// the duration.
+func (w *WavTrigger) PlayWithFadeIn(trk int, gain tsunami.Gain, d time.Duration) error {
+	return w.t.PlayWithFadeIn(trk, 0, gain, d)
+}
+
+// StopWithFadeOut fades the track out along the duration, stopping it at the
+// end of the fade.
+func (w *WavTrigger) StopWithFadeOut(trk int, d time.Duration) error {
+	return w.t.StopWithFadeOut(trk, d)
+}
+
+// StopAllTracks stops any and all tracks that are currently playing.
+func (w *WavTrigger) StopAllTracks() error {
+	return w.t.StopAllTracks()
+}
+
+// ResumeAllInSync resumes all paused tracks within the same audio buffer.
+func (w *WavTrigger) ResumeAllInSync() error {
+	return w.t.ResumeAllInSync()
+}
+
+// MasterGain sets the gain of the output. The range for gain is -70 to +4.
+// The output is ignored, the WAV Trigger has a single one.
+func (w *WavTrigger) MasterGain(out int, gain tsunami.Gain) error {
+	return w.t.Send(MasterVolume{
+		Gain: int16(gain.Clamp(tsunami.MinGain, tsunami.MaxMasterGain).Int()),
+	})
+}
+
+// SamplerateOffset sets sample-rate offset, or playback speed / pitch. The
+// output is ignored, the WAV Trigger has a single one.
+func (w *WavTrigger) SamplerateOffset(out, offset int) error {
+	return w.t.Send(SamplerateOffset{Offset: int16(offset)})
+}
+
+// AmpPower turns on or off the on-board amplifier.
+func (w *WavTrigger) AmpPower(enable bool) error {
+	return w.t.Send(AmpPower{Enable: enable})
+}
+
+// SetReporting enables or disables track reporting.
+func (w *WavTrigger) SetReporting(enable bool) error {
+	return w.t.Send(SetReporting{Enable: enable})
+}
+
+// SetTriggerBank sets the trigger bank. The bank range is 1 - 32.
+func (w *WavTrigger) SetTriggerBank(bank int) error {
+	return w.t.Send(SetTriggerBank{Bank: uint8(bank)})
+}
+
+// IsTrackPlaying if reporting has been enabled, this function can be used to
+// determine if a particular track is currently playing.
+func (w *WavTrigger) IsTrackPlaying(trk int) bool {
+	return w.t.IsTrackPlaying(trk)
+}
+
+// GetVersion returns the WAV Trigger version string.
+func (w *WavTrigger) GetVersion() string {
+	return w.t.GetVersion()
+}
+
+// GetNumTracks returns the number of tracks on the SD card.
+func (w *WavTrigger) GetNumTracks() int {
+	return w.t.GetNumTracks()
+}
+
+// Subscribe registers a function to be called with every message received
+// from the board, see tsunami.Tsunami.Subscribe.
+func (w *WavTrigger) Subscribe(fn func(protocol.Message)) (cancel func()) {
+	return w.t.Subscribe(fn)
+}
+
+// Update reads any pending data from the board.
+func (w *WavTrigger) Update() error {
+	return w.t.Update()
+}
+
+// Listen calls Update continuously until the context is cancelled.
+func (w *WavTrigger) Listen(ctx context.Context) error {
+	return w.t.Listen(ctx)
+}
+
+// Close should be called to close the connection with the port.
+func (w *WavTrigger) Close() error {
+	return w.t.Close()
+}
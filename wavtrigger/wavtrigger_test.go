@@ -0,0 +1,41 @@
+package wavtrigger
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+func TestWavTriggerFrames(t *testing.T) {
+	port := transport.NewLoopback(nil)
+	w := NewWavTriggerTransport(port)
+
+	if err := w.SetReporting(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.SetTriggerBank(3); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.TrackPlayPoly(12, 0, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.TrackPlayPoly(12, 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []protocol.Frame{
+		{Code: byte(CMD_SET_REPORTING), Data: []byte{1}},
+		{Code: byte(CMD_SET_TRIGGER_BANK), Data: []byte{3}},
+		{Code: byte(CMD_TRACK_CONTROL_EX), Data: []byte{byte(protocol.TRK_PLAY_POLY), 12, 0, 1}},
+		{Code: byte(protocol.CMD_TRACK_CONTROL), Data: []byte{byte(protocol.TRK_PLAY_POLY), 12, 0}},
+	}
+
+	if frames := port.Frames(); !reflect.DeepEqual(frames, expected) {
+		t.Errorf("unexpected frames %v", frames)
+	}
+}
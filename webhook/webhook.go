@@ -0,0 +1,304 @@
+// Package webhook calls HTTP endpoints on the events of a board, the tracks
+// starting and ending, the loss of the connection and the cues fired, so
+// external systems, like lighting servers or logging backends, can react to
+// them.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/show"
+)
+
+// RetryBackoff is the delay before the first retry of a failed call,
+// doubled on every retry.
+var RetryBackoff = time.Second
+
+// EventType is the type of an Event.
+type EventType string
+
+const (
+	// TrackStarted is fired when the board reports a track starting.
+	TrackStarted EventType = "track.start"
+	// TrackEnded is fired when the board reports a track ending.
+	TrackEnded EventType = "track.end"
+	// ConnectionLost is fired with the error losing the connection to the
+	// board, see Dispatcher.Lost.
+	ConnectionLost EventType = "connection.lost"
+	// CueFired is fired when a cue of a stack is fired, see
+	// Dispatcher.WatchCues.
+	CueFired EventType = "cue"
+)
+
+// Event is the data of a call, available to the templates of the bodies.
+// Only the fields of its type are set.
+type Event struct {
+	Type  EventType `json:"type"`
+	Time  time.Time `json:"time"`
+	Track int       `json:"track,omitempty"`
+	Voice int       `json:"voice,omitempty"`
+	Cue   string    `json:"cue,omitempty"`
+	Name  string    `json:"name,omitempty"`
+	Error string    `json:"error,omitempty"`
+}
+
+// Hook is an endpoint called on events, eg.:
+//
+//	{
+//	  "url": "http://lights.local/api/trigger",
+//	  "events": ["cue"],
+//	  "body": "{\"scene\": {{json .Cue}}}",
+//	  "retries": 3
+//	}
+type Hook struct {
+	URL string `json:"url"`
+	// Events are the types of the events calling the hook, every one if
+	// empty.
+	Events []EventType `json:"events,omitempty"`
+	// Method is the method of the request, POST if empty.
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is a text/template of the body, executed with the Event, with
+	// a json function quoting the values. The Event as JSON if empty.
+	Body string `json:"body,omitempty"`
+	// Retries is the number of retries of the calls failing, by a network
+	// error or a 5xx or 429 response.
+	Retries int `json:"retries,omitempty"`
+}
+
+func (h *Hook) matches(t EventType) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+
+	for _, e := range h.Events {
+		if e == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LoadHooks reads the hooks as a JSON array.
+func LoadHooks(r io.Reader) ([]Hook, error) {
+	var hooks []Hook
+	if err := json.NewDecoder(r).Decode(&hooks); err != nil {
+		return nil, err
+	}
+
+	return hooks, nil
+}
+
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+type hook struct {
+	Hook
+	body *template.Template
+}
+
+// Dispatcher calls the hooks on the events. The calls are made in the
+// background, one goroutine per call.
+type Dispatcher struct {
+	hooks  []*hook
+	client *http.Client
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	onError []func(Hook, error)
+	unsubs  []func()
+}
+
+// New returns a dispatcher of the hooks, validating them.
+func New(hooks ...Hook) (*Dispatcher, error) {
+	d := &Dispatcher{client: &http.Client{Timeout: 10 * time.Second}}
+	for _, h := range hooks {
+		if h.URL == "" {
+			return nil, errors.New("hook without URL")
+		}
+
+		hk := &hook{Hook: h}
+		if h.Body != "" {
+			t, err := template.New(h.URL).Funcs(funcs).Parse(h.Body)
+			if err != nil {
+				return nil, fmt.Errorf("hook %s: %w", h.URL, err)
+			}
+
+			hk.body = t
+		}
+
+		d.hooks = append(d.hooks, hk)
+	}
+
+	return d, nil
+}
+
+// OnError registers a function to be called with the errors of the calls,
+// after the retries.
+func (d *Dispatcher) OnError(fn func(Hook, error)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.onError = append(d.onError, fn)
+}
+
+// Watch fires the events of the track reports of the board, see
+// tsunami.Tsunami.Subscribe.
+func (d *Dispatcher) Watch(b tsunami.Board) {
+	cancel := b.Subscribe(func(msg protocol.Message) {
+		r, ok := msg.(*protocol.TrackReport)
+		if !ok {
+			return
+		}
+
+		e := Event{Type: TrackEnded, Time: time.Now(), Track: int(r.Track), Voice: int(r.Voice)}
+		if r.Playing {
+			e.Type = TrackStarted
+		}
+
+		d.Fire(e)
+	})
+
+	d.mu.Lock()
+	d.unsubs = append(d.unsubs, cancel)
+	d.mu.Unlock()
+}
+
+// WatchCues fires the events of the cues fired by the stack.
+func (d *Dispatcher) WatchCues(s *show.CueStack) {
+	s.OnCue(func(c show.Cue) {
+		d.Fire(Event{Type: CueFired, Time: time.Now(), Cue: c.Number, Name: c.Name})
+	})
+}
+
+// Lost fires the loss of the connection to the board, with its cause. It's
+// meant to be called with the error returned by Tsunami.Listen, or to be
+// registered with Redundant.OnFailover.
+func (d *Dispatcher) Lost(err error) {
+	e := Event{Type: ConnectionLost, Time: time.Now()}
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	d.Fire(e)
+}
+
+// Fire calls the hooks of the event.
+func (d *Dispatcher) Fire(e Event) {
+	for _, h := range d.hooks {
+		if !h.matches(e.Type) {
+			continue
+		}
+
+		h := h
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+
+			if err := d.call(h, e); err != nil {
+				d.mu.Lock()
+				handlers := d.onError
+				d.mu.Unlock()
+
+				for _, fn := range handlers {
+					fn(h.Hook, err)
+				}
+			}
+		}()
+	}
+}
+
+// Wait waits for the calls in progress, with their retries.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Close stops watching the boards given to Watch, and waits for the calls in
+// progress.
+func (d *Dispatcher) Close() error {
+	d.mu.Lock()
+	unsubs := d.unsubs
+	d.unsubs = nil
+	d.mu.Unlock()
+
+	for _, cancel := range unsubs {
+		cancel()
+	}
+
+	d.Wait()
+	return nil
+}
+
+func (d *Dispatcher) call(h *hook, e Event) error {
+	var body bytes.Buffer
+	if h.body == nil {
+		if err := json.NewEncoder(&body).Encode(e); err != nil {
+			return err
+		}
+	} else if err := h.body.Execute(&body, e); err != nil {
+		return fmt.Errorf("hook %s: %w", h.URL, err)
+	}
+
+	method := h.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	backoff := RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := d.do(h, method, body.Bytes())
+		if err == nil {
+			return nil
+		}
+
+		if !retry || attempt >= h.Retries {
+			return fmt.Errorf("hook %s: %w", h.URL, err)
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// do makes a request, reporting whether it should be retried if it failed.
+func (d *Dispatcher) do(h *hook, method string, body []byte) (bool, error) {
+	req, err := http.NewRequest(method, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return false, nil
+}
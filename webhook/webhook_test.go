@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcuadros/go-tsunami"
+	"github.com/mcuadros/go-tsunami/protocol"
+	"github.com/mcuadros/go-tsunami/show"
+	"github.com/mcuadros/go-tsunami/transport"
+)
+
+// recorder is an endpoint recording the bodies received, failing the first
+// fails requests.
+type recorder struct {
+	mu     sync.Mutex
+	fails  int
+	bodies []string
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fails > 0 {
+		r.fails--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	r.bodies = append(r.bodies, req.Header.Get("X-Token")+" "+string(body))
+}
+
+func TestDispatcher(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	hooks, err := LoadHooks(strings.NewReader(`[
+	  {"url": "` + srv.URL + `", "events": ["track.start"], "headers": {"X-Token": "secret"}},
+	  {"url": "` + srv.URL + `", "events": ["cue", "connection.lost"], "body": "{\"cue\": {{json .Cue}}, \"error\": {{json .Error}}}"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New(hooks...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := transport.NewLoopback(nil)
+	ts := tsunami.NewTsunamiTransport(port)
+	d.Watch(ts)
+
+	port.Push(&protocol.TrackReport{Track: 12, Voice: 2, Playing: true})
+	port.Push(&protocol.TrackReport{Track: 12, Voice: 2, Playing: false})
+	ts.Update()
+	d.Wait()
+
+	s := show.NewCueStack(ts, []show.Cue{{Number: "3.5"}})
+	d.WatchCues(s)
+	s.Go()
+	d.Wait()
+
+	d.Lost(errors.New("port closed"))
+	d.Wait()
+
+	if len(rec.bodies) != 3 {
+		t.Fatalf("unexpected calls %q", rec.bodies)
+	}
+
+	var e Event
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(rec.bodies[0], "secret ")), &e); err != nil {
+		t.Fatal(err)
+	}
+
+	if e.Type != TrackStarted || e.Track != 12 || e.Voice != 2 {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	expected := []string{` {"cue": "3.5", "error": ""}`, ` {"cue": "", "error": "port closed"}`}
+	for i, body := range expected {
+		if rec.bodies[i+1] != body {
+			t.Errorf("unexpected body %q", rec.bodies[i+1])
+		}
+	}
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	defer func(d time.Duration) { RetryBackoff = d }(RetryBackoff)
+	RetryBackoff = time.Millisecond
+
+	rec := &recorder{fails: 3}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	var errs []error
+	for _, retries := range []int{1, 2} {
+		d, err := New(Hook{URL: srv.URL, Retries: retries})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d.OnError(func(h Hook, err error) { errs = append(errs, err) })
+		d.Fire(Event{Type: TrackEnded, Track: 1})
+		d.Wait()
+	}
+
+	if len(rec.bodies) != 1 || len(errs) != 1 || !strings.Contains(errs[0].Error(), "503") {
+		t.Errorf("unexpected calls %q, errors %v", rec.bodies, errs)
+	}
+
+	if _, err := New(Hook{URL: srv.URL, Body: "{{"}); err == nil {
+		t.Error("invalid template should fail")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build js && wasm
+
+package tsunami
+
+import "github.com/mcuadros/go-tsunami/transport"
+
+// NewTsunamiWebSerial returns a new Tsunami connection to a board plugged
+// into the computer running the browser, using the Web Serial API. The user
+// is asked to select the port, so it must be called from a user gesture.
+func NewTsunamiWebSerial(opts ...Option) (*Tsunami, error) {
+	port, err := transport.RequestWebSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTsunamiTransport(port, opts...), nil
+}